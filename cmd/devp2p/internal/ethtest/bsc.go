@@ -0,0 +1,349 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package ethtest
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/protocols/bsc"
+	"github.com/ethereum/go-ethereum/internal/utesting"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// ReadBsc reads a bsc/2 message from the connection.
+func (c *Conn) ReadBsc() (any, error) {
+	c.SetReadDeadline(time.Now().Add(timeout))
+	for {
+		code, data, _, err := c.Conn.Read()
+		if err != nil {
+			return nil, err
+		}
+		if code == pingMsg {
+			c.Write(baseProto, pongMsg, []byte{})
+			continue
+		}
+		if c.getProto(code) != bscProto {
+			// Read until bsc message.
+			continue
+		}
+		code -= c.protoOffset(bscProto)
+
+		var msg any
+		switch int(code) {
+		case bsc.BscCapMsg:
+			msg = new(bsc.BscCapPacket)
+		case bsc.VotesMsg:
+			msg = new(bsc.VotesPacket)
+		case bsc.GetBlocksByRangeMsg:
+			msg = new(bsc.GetBlocksByRangePacket)
+		case bsc.BlocksByRangeMsg:
+			msg = new(bsc.BlocksByRangePacket)
+		default:
+			panic(fmt.Errorf("unhandled bsc code: %d", code))
+		}
+		if err := rlp.DecodeBytes(data, msg); err != nil {
+			return nil, fmt.Errorf("could not rlp decode message: %v", err)
+		}
+		return msg, nil
+	}
+}
+
+// waitForDisconnect reads from the connection until the node drops it. It
+// fails if the connection is still alive after the read timeout.
+func (c *Conn) waitForDisconnect() error {
+	c.SetReadDeadline(time.Now().Add(timeout))
+	for {
+		code, _, _, err := c.Conn.Read()
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				return errors.New("node did not disconnect")
+			}
+			// Client may have disconnected without sending disconnect msg.
+			return nil
+		}
+		switch code {
+		case discMsg:
+			return nil
+		case pingMsg:
+			c.Write(baseProto, pongMsg, []byte{})
+		}
+	}
+}
+
+// expectNoVotes checks that the node doesn't send any votes on the connection
+// for the duration of the read timeout, or until the connection is dropped.
+func (c *Conn) expectNoVotes() error {
+	c.SetReadDeadline(time.Now().Add(timeout))
+	for {
+		code, _, _, err := c.Conn.Read()
+		if err != nil {
+			return nil
+		}
+		switch {
+		case code == pingMsg:
+			c.Write(baseProto, pongMsg, []byte{})
+		case code == c.protoOffset(bscProto)+bsc.VotesMsg:
+			return errors.New("received unexpected votes")
+		}
+	}
+}
+
+func (c *Conn) bscRequest(code uint64, msg any) (any, error) {
+	if err := c.Write(bscProto, code, msg); err != nil {
+		return nil, fmt.Errorf("could not write to connection: %v", err)
+	}
+	return c.ReadBsc()
+}
+
+// peerBsc dials the node and peers with it over eth and bsc/2.
+func (s *Suite) peerBsc(t *utesting.T) *Conn {
+	conn, err := s.dialBsc()
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	if err := conn.peer(s.chain, nil); err != nil {
+		conn.Close()
+		t.Fatalf("peering failed: %v", err)
+	}
+	return conn
+}
+
+func (s *Suite) TestBscStatus(t *utesting.T) {
+	t.Log(`This test performs the eth status exchange along with the bsc/2 BscCap handshake.`)
+
+	conn := s.peerBsc(t)
+	conn.Close()
+}
+
+func (s *Suite) TestBscMaliciousCap(t *utesting.T) {
+	t.Log(`This test sends malformed BscCap handshakes to the node and expects a disconnect.`)
+
+	tests := []struct {
+		code uint64
+		msg  any
+		desc string
+	}{
+		{
+			code: bsc.BscCapMsg,
+			msg:  &bsc.BscCapPacket{ProtocolVersion: bsc.Bsc1, Extra: rlp.RawValue{0x00}},
+			desc: "protocol version mismatch",
+		},
+		{
+			code: bsc.BscCapMsg,
+			msg:  []byte{0x01, 0x02},
+			desc: "undecodable handshake",
+		},
+		{
+			code: bsc.VotesMsg,
+			msg:  &bsc.VotesPacket{},
+			desc: "votes before handshake",
+		},
+	}
+	for _, tc := range tests {
+		t.Logf("%v", tc.desc)
+
+		conn, err := s.dialBsc()
+		if err != nil {
+			t.Fatalf("dial failed: %v", err)
+		}
+		if err := conn.handshake(); err != nil {
+			conn.Close()
+			t.Fatalf("handshake failed: %v", err)
+		}
+		if err := conn.Write(bscProto, tc.code, tc.msg); err != nil {
+			conn.Close()
+			t.Fatalf("could not write to connection: %v", err)
+		}
+		if err := conn.waitForDisconnect(); err != nil {
+			t.Errorf("%v: %v", tc.desc, err)
+		}
+		conn.Close()
+	}
+}
+
+func (s *Suite) TestBscVotes(t *utesting.T) {
+	t.Log(`This test sends votes with forged signatures to the node. The node must not
+relay them, neither back to the sender nor to its other peers.`)
+
+	sender := s.peerBsc(t)
+	defer sender.Close()
+	receiver := s.peerBsc(t)
+	defer receiver.Close()
+
+	head := s.chain.Head()
+	votes := make([]*types.VoteEnvelope, 2)
+	for i := range votes {
+		votes[i] = &types.VoteEnvelope{
+			Data: &types.VoteData{
+				SourceNumber: head.NumberU64() - 1,
+				SourceHash:   head.ParentHash(),
+				TargetNumber: head.NumberU64(),
+				TargetHash:   head.Hash(),
+			},
+		}
+		copy(votes[i].VoteAddress[:], randBuf(types.BLSPublicKeyLength))
+		copy(votes[i].Signature[:], randBuf(types.BLSSignatureLength))
+	}
+	if err := sender.Write(bscProto, bsc.VotesMsg, &bsc.VotesPacket{Votes: votes}); err != nil {
+		t.Fatalf("could not write to connection: %v", err)
+	}
+	if err := receiver.expectNoVotes(); err != nil {
+		t.Fatalf("invalid votes relayed to other peer: %v", err)
+	}
+	// The sender may get dropped for its misbehaviour, but must not receive
+	// its own votes back.
+	if err := sender.expectNoVotes(); err != nil {
+		t.Fatalf("invalid votes relayed back to sender: %v", err)
+	}
+}
+
+func (s *Suite) TestBscMalformedVotes(t *utesting.T) {
+	t.Log(`This test sends an undecodable Votes message to the node and expects a disconnect.`)
+
+	conn := s.peerBsc(t)
+	defer conn.Close()
+
+	if err := conn.Write(bscProto, bsc.VotesMsg, []any{randBuf(32)}); err != nil {
+		t.Fatalf("could not write to connection: %v", err)
+	}
+	if err := conn.waitForDisconnect(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (s *Suite) TestBscGetBlocksByRange(t *utesting.T) {
+	t.Log(`This test requests block ranges from the node and checks the returned blocks
+against the test chain.`)
+
+	conn := s.peerBsc(t)
+	defer conn.Close()
+
+	head := uint64(s.chain.Len() - 1)
+	tests := []struct {
+		req  bsc.GetBlocksByRangePacket
+		want []uint64 // expected block numbers
+		desc string
+	}{
+		{
+			req:  bsc.GetBlocksByRangePacket{StartBlockHeight: head, StartBlockHash: s.chain.Head().Hash(), Count: 3},
+			want: []uint64{head, head - 1, head - 2},
+			desc: "range from the head, by hash",
+		},
+		{
+			req:  bsc.GetBlocksByRangePacket{StartBlockHeight: 10, Count: 4},
+			want: []uint64{10, 9, 8, 7},
+			desc: "range by height",
+		},
+		{
+			req:  bsc.GetBlocksByRangePacket{StartBlockHeight: 5, StartBlockHash: s.chain.GetBlock(5).Hash(), Count: 1},
+			want: []uint64{5},
+			desc: "single block",
+		},
+		{
+			req:  bsc.GetBlocksByRangePacket{StartBlockHeight: 2, StartBlockHash: s.chain.GetBlock(2).Hash(), Count: 10},
+			want: []uint64{2, 1, 0},
+			desc: "range reaching past genesis",
+		},
+		{
+			req:  bsc.GetBlocksByRangePacket{StartBlockHeight: head, Count: bsc.MaxRequestRangeBlocksCount},
+			want: countDown(head, bsc.MaxRequestRangeBlocksCount),
+			desc: "maximum count",
+		},
+	}
+	for i, tc := range tests {
+		tc.req.RequestId = uint64(i) + 1
+		resp, err := conn.bscRequest(bsc.GetBlocksByRangeMsg, &tc.req)
+		if err != nil {
+			t.Fatalf("%v: request failed: %v", tc.desc, err)
+		}
+		res, ok := resp.(*bsc.BlocksByRangePacket)
+		if !ok {
+			t.Fatalf("%v: unexpected response %T", tc.desc, resp)
+		}
+		if res.RequestId != tc.req.RequestId {
+			t.Fatalf("%v: wrong request id: have %d, want %d", tc.desc, res.RequestId, tc.req.RequestId)
+		}
+		if len(res.Blocks) != len(tc.want) {
+			t.Fatalf("%v: wrong number of blocks: have %d, want %d", tc.desc, len(res.Blocks), len(tc.want))
+		}
+		for j, block := range res.Blocks {
+			want := s.chain.GetBlock(int(tc.want[j]))
+			if block.Header == nil || block.Header.Hash() != want.Hash() {
+				t.Fatalf("%v: wrong block %d: want %d (%x)", tc.desc, j, tc.want[j], want.Hash())
+			}
+			if len(block.Txs) != len(want.Transactions()) {
+				t.Fatalf("%v: wrong transactions in block %d: have %d, want %d", tc.desc, tc.want[j], len(block.Txs), len(want.Transactions()))
+			}
+		}
+	}
+}
+
+func (s *Suite) TestBscGetBlocksByRangeInvalid(t *utesting.T) {
+	t.Log(`This test sends invalid GetBlocksByRange requests to the node and expects
+to be disconnected.`)
+
+	head := uint64(s.chain.Len() - 1)
+	tests := []struct {
+		req  bsc.GetBlocksByRangePacket
+		desc string
+	}{
+		{
+			req:  bsc.GetBlocksByRangePacket{StartBlockHeight: head, StartBlockHash: s.chain.Head().Hash(), Count: 0},
+			desc: "zero count",
+		},
+		{
+			req:  bsc.GetBlocksByRangePacket{StartBlockHeight: head, StartBlockHash: s.chain.Head().Hash(), Count: bsc.MaxRequestRangeBlocksCount + 1},
+			desc: "count above limit",
+		},
+		{
+			req:  bsc.GetBlocksByRangePacket{StartBlockHeight: head, StartBlockHash: common.Hash{0x01}, Count: 1},
+			desc: "unknown start hash",
+		},
+		{
+			req:  bsc.GetBlocksByRangePacket{StartBlockHeight: head + 1000, Count: 1},
+			desc: "unknown start height",
+		},
+	}
+	for i, tc := range tests {
+		t.Logf("%v", tc.desc)
+
+		conn := s.peerBsc(t)
+		tc.req.RequestId = uint64(i) + 1
+		if err := conn.Write(bscProto, bsc.GetBlocksByRangeMsg, &tc.req); err != nil {
+			conn.Close()
+			t.Fatalf("could not write to connection: %v", err)
+		}
+		if err := conn.waitForDisconnect(); err != nil {
+			t.Errorf("%v: %v", tc.desc, err)
+		}
+		conn.Close()
+	}
+}
+
+// countDown returns the n numbers descending from start.
+func countDown(start uint64, n int) []uint64 {
+	res := make([]uint64, n)
+	for i := range res {
+		res[i] = start - uint64(i)
+	}
+	return res
+}
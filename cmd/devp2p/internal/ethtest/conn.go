@@ -26,8 +26,10 @@ import (
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/protocols/bsc"
 	"github.com/ethereum/go-ethereum/eth/protocols/eth"
 	"github.com/ethereum/go-ethereum/eth/protocols/snap"
+	"github.com/ethereum/go-ethereum/eth/protocols/trust"
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/p2p/rlpx"
 	"github.com/ethereum/go-ethereum/rlp"
@@ -84,15 +86,41 @@ func (s *Suite) dialSnap() (*Conn, error) {
 	return conn, nil
 }
 
+// dialBsc creates a connection with bsc/2 capability.
+func (s *Suite) dialBsc() (*Conn, error) {
+	conn, err := s.dial()
+	if err != nil {
+		return nil, fmt.Errorf("dial failed: %v", err)
+	}
+	conn.caps = append(conn.caps, p2p.Cap{Name: bsc.ProtocolName, Version: bsc.Bsc2})
+	conn.ourHighestBscProtoVersion = bsc.Bsc2
+	return conn, nil
+}
+
+// dialTrust creates a connection with trust/1 capability.
+func (s *Suite) dialTrust() (*Conn, error) {
+	conn, err := s.dial()
+	if err != nil {
+		return nil, fmt.Errorf("dial failed: %v", err)
+	}
+	conn.caps = append(conn.caps, p2p.Cap{Name: trust.ProtocolName, Version: trust.Trust1})
+	conn.ourHighestTrustProtoVersion = trust.Trust1
+	return conn, nil
+}
+
 // Conn represents an individual connection with a peer
 type Conn struct {
 	*rlpx.Conn
-	ourKey                     *ecdsa.PrivateKey
-	negotiatedProtoVersion     uint
-	negotiatedSnapProtoVersion uint
-	ourHighestProtoVersion     uint
-	ourHighestSnapProtoVersion uint
-	caps                       []p2p.Cap
+	ourKey                      *ecdsa.PrivateKey
+	negotiatedProtoVersion      uint
+	negotiatedSnapProtoVersion  uint
+	negotiatedBscProtoVersion   uint
+	negotiatedTrustProtoVersion uint
+	ourHighestProtoVersion      uint
+	ourHighestSnapProtoVersion  uint
+	ourHighestBscProtoVersion   uint
+	ourHighestTrustProtoVersion uint
+	caps                        []p2p.Cap
 }
 
 // Read reads a packet from the connection.
@@ -113,7 +141,7 @@ func (c *Conn) ReadMsg(proto Proto, code uint64, msg any) error {
 		if err != nil {
 			return err
 		}
-		if protoOffset(proto)+code == got {
+		if c.protoOffset(proto)+code == got {
 			return rlp.DecodeBytes(data, msg)
 		}
 	}
@@ -126,7 +154,7 @@ func (c *Conn) Write(proto Proto, code uint64, msg any) error {
 	if err != nil {
 		return err
 	}
-	_, err = c.Conn.Write(protoOffset(proto)+code, payload)
+	_, err = c.Conn.Write(c.protoOffset(proto)+code, payload)
 	return err
 }
 
@@ -142,11 +170,11 @@ func (c *Conn) ReadEth() (any, error) {
 			c.Write(baseProto, pongMsg, []byte{})
 			continue
 		}
-		if getProto(code) != ethProto {
+		if c.getProto(code) != ethProto {
 			// Read until eth message.
 			continue
 		}
-		code -= baseProtoLen
+		code -= c.protoOffset(ethProto)
 
 		var msg any
 		switch int(code) {
@@ -190,11 +218,11 @@ func (c *Conn) ReadSnap() (any, error) {
 		if err != nil {
 			return nil, err
 		}
-		if getProto(code) != snapProto {
+		if c.getProto(code) != snapProto {
 			// Read until snap message.
 			continue
 		}
-		code -= baseProtoLen + ethProtoLen
+		code -= c.protoOffset(snapProto)
 
 		var msg any
 		switch int(code) {
@@ -271,6 +299,12 @@ func (c *Conn) handshake() error {
 		if c.ourHighestSnapProtoVersion != c.negotiatedSnapProtoVersion {
 			return fmt.Errorf("could not negotiate snap protocol (remote caps: %v, local snap version: %v)", msg.Caps, c.ourHighestSnapProtoVersion)
 		}
+		if c.ourHighestBscProtoVersion != c.negotiatedBscProtoVersion {
+			return fmt.Errorf("could not negotiate bsc protocol (remote caps: %v, local bsc version: %v)", msg.Caps, c.ourHighestBscProtoVersion)
+		}
+		if c.ourHighestTrustProtoVersion != c.negotiatedTrustProtoVersion {
+			return fmt.Errorf("could not negotiate trust protocol (remote caps: %v, local trust version: %v)", msg.Caps, c.ourHighestTrustProtoVersion)
+		}
 		return nil
 	default:
 		return fmt.Errorf("bad handshake: got msg code %d", code)
@@ -282,6 +316,8 @@ func (c *Conn) handshake() error {
 func (c *Conn) negotiateEthProtocol(caps []p2p.Cap) {
 	var highestEthVersion uint
 	var highestSnapVersion uint
+	var highestBscVersion uint
+	var highestTrustVersion uint
 	for _, capability := range caps {
 		switch capability.Name {
 		case "eth":
@@ -292,22 +328,44 @@ func (c *Conn) negotiateEthProtocol(caps []p2p.Cap) {
 			if capability.Version > highestSnapVersion && capability.Version <= c.ourHighestSnapProtoVersion {
 				highestSnapVersion = capability.Version
 			}
+		case bsc.ProtocolName:
+			if capability.Version > highestBscVersion && capability.Version <= c.ourHighestBscProtoVersion {
+				highestBscVersion = capability.Version
+			}
+		case trust.ProtocolName:
+			if capability.Version > highestTrustVersion && capability.Version <= c.ourHighestTrustProtoVersion {
+				highestTrustVersion = capability.Version
+			}
 		}
 	}
 	c.negotiatedProtoVersion = highestEthVersion
 	c.negotiatedSnapProtoVersion = highestSnapVersion
+	c.negotiatedBscProtoVersion = highestBscVersion
+	c.negotiatedTrustProtoVersion = highestTrustVersion
 }
 
-// statusExchange performs a `Status` message exchange with the given node.
+// statusExchange performs a `Status` message exchange with the given node. If
+// bsc was negotiated, the `BscCap` handshake is completed as well, as the node
+// runs both of them concurrently.
 func (c *Conn) statusExchange(chain *Chain, status *eth.StatusPacket) error {
-loop:
-	for {
+	var (
+		statusDone bool
+		bscDone    = c.negotiatedBscProtoVersion == 0
+	)
+	for !statusDone || !bscDone {
 		code, data, err := c.Read()
 		if err != nil {
 			return fmt.Errorf("failed to read from connection: %w", err)
 		}
+		if !bscDone && code == c.protoOffset(bscProto)+bsc.BscCapMsg {
+			if err := c.bscCapExchange(data); err != nil {
+				return err
+			}
+			bscDone = true
+			continue
+		}
 		switch code {
-		case eth.StatusMsg + protoOffset(ethProto):
+		case eth.StatusMsg + c.protoOffset(ethProto):
 			msg := new(eth.StatusPacket)
 			if err := rlp.DecodeBytes(data, &msg); err != nil {
 				return fmt.Errorf("error decoding status packet: %w", err)
@@ -340,7 +398,7 @@ loop:
 			if err := c.Write(ethProto, eth.StatusMsg, status); err != nil {
 				return fmt.Errorf("write to connection failed: %v", err)
 			}
-		case eth.UpgradeStatusMsg + protoOffset(ethProto):
+		case eth.UpgradeStatusMsg + c.protoOffset(ethProto):
 			msg := new(eth.UpgradeStatusPacket)
 			if err := rlp.DecodeBytes(data, &msg); err != nil {
 				return fmt.Errorf("error decoding status packet: %w", err)
@@ -348,7 +406,7 @@ loop:
 			if err := c.Write(ethProto, eth.UpgradeStatusMsg, msg); err != nil {
 				return fmt.Errorf("write to connection failed: %v", err)
 			}
-			break loop
+			statusDone = true
 		case discMsg:
 			var msg []p2p.DiscReason
 			if rlp.DecodeBytes(data, &msg); len(msg) == 0 {
@@ -366,3 +424,22 @@ loop:
 
 	return nil
 }
+
+// bscCapExchange answers the `BscCap` handshake message of the node.
+func (c *Conn) bscCapExchange(data []byte) error {
+	msg := new(bsc.BscCapPacket)
+	if err := rlp.DecodeBytes(data, msg); err != nil {
+		return fmt.Errorf("error decoding bsc cap packet: %w", err)
+	}
+	if msg.ProtocolVersion != c.negotiatedBscProtoVersion {
+		return fmt.Errorf("wrong bsc protocol version: have %v, want %v", msg.ProtocolVersion, c.negotiatedBscProtoVersion)
+	}
+	capPacket := &bsc.BscCapPacket{
+		ProtocolVersion: c.negotiatedBscProtoVersion,
+		Extra:           rlp.RawValue{0x00},
+	}
+	if err := c.Write(bscProto, bsc.BscCapMsg, capPacket); err != nil {
+		return fmt.Errorf("write to connection failed: %v", err)
+	}
+	return nil
+}
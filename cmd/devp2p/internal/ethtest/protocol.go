@@ -31,9 +31,11 @@ const (
 
 // Unexported devp2p protocol lengths from p2p package.
 const (
-	baseProtoLen = 16
-	ethProtoLen  = 17
-	snapProtoLen = 8
+	baseProtoLen  = 16
+	ethProtoLen   = 17
+	snapProtoLen  = 8
+	bscProtoLen   = 4 // bsc/2
+	trustProtoLen = 2
 )
 
 // Unexported handshake structure from p2p/peer.go.
@@ -55,34 +57,50 @@ const (
 	baseProto Proto = iota
 	ethProto
 	snapProto
+	bscProto
+	trustProto
 )
 
-// getProto returns the protocol a certain message code is associated with
-// (assuming the negotiated capabilities are exactly {eth,snap})
-func getProto(code uint64) Proto {
-	switch {
-	case code < baseProtoLen:
-		return baseProto
-	case code < baseProtoLen+ethProtoLen:
-		return ethProto
-	case code < baseProtoLen+ethProtoLen+snapProtoLen:
-		return snapProto
-	default:
-		panic("unhandled msg code beyond last protocol")
+// protocols returns the protocols running on the connection along with the
+// size of their message spaces, in the order their messages are laid out in
+// the code space. Like in the p2p package, the capabilities are sorted by name.
+func (c *Conn) protocols() ([]Proto, []uint64) {
+	protos, lengths := []Proto{baseProto}, []uint64{baseProtoLen}
+	if c.negotiatedBscProtoVersion != 0 {
+		protos, lengths = append(protos, bscProto), append(lengths, bscProtoLen)
 	}
+	protos, lengths = append(protos, ethProto), append(lengths, ethProtoLen)
+	if c.negotiatedSnapProtoVersion != 0 {
+		protos, lengths = append(protos, snapProto), append(lengths, snapProtoLen)
+	}
+	if c.negotiatedTrustProtoVersion != 0 {
+		protos, lengths = append(protos, trustProto), append(lengths, trustProtoLen)
+	}
+	return protos, lengths
+}
+
+// getProto returns the protocol a certain message code is associated with.
+func (c *Conn) getProto(code uint64) Proto {
+	protos, lengths := c.protocols()
+	for i, proto := range protos {
+		if code < lengths[i] {
+			return proto
+		}
+		code -= lengths[i]
+	}
+	panic("unhandled msg code beyond last protocol")
 }
 
 // protoOffset will return the offset at which the specified protocol's messages
 // begin.
-func protoOffset(proto Proto) uint64 {
-	switch proto {
-	case baseProto:
-		return 0
-	case ethProto:
-		return baseProtoLen
-	case snapProto:
-		return baseProtoLen + ethProtoLen
-	default:
-		panic("unhandled protocol")
+func (c *Conn) protoOffset(proto Proto) uint64 {
+	var offset uint64
+	protos, lengths := c.protocols()
+	for i, p := range protos {
+		if p == proto {
+			return offset
+		}
+		offset += lengths[i]
 	}
+	panic("unhandled protocol")
 }
@@ -94,6 +94,24 @@ func (s *Suite) SnapTests() []utesting.Test {
 	}
 }
 
+func (s *Suite) BscTests() []utesting.Test {
+	return []utesting.Test{
+		{Name: "Status", Fn: s.TestBscStatus},
+		{Name: "MaliciousCap", Fn: s.TestBscMaliciousCap},
+		{Name: "Votes", Fn: s.TestBscVotes},
+		{Name: "MalformedVotes", Fn: s.TestBscMalformedVotes},
+		{Name: "GetBlocksByRange", Fn: s.TestBscGetBlocksByRange},
+		{Name: "GetBlocksByRangeInvalid", Fn: s.TestBscGetBlocksByRangeInvalid},
+	}
+}
+
+func (s *Suite) TrustTests() []utesting.Test {
+	return []utesting.Test{
+		{Name: "Status", Fn: s.TestTrustStatus},
+		{Name: "RequestRoot", Fn: s.TestTrustRequestRoot},
+	}
+}
+
 func (s *Suite) TestStatus(t *utesting.T) {
 	t.Log(`This test is just a sanity check. It performs an eth protocol handshake.`)
 
@@ -849,7 +867,7 @@ func (s *Suite) TestBlobViolations(t *utesting.T) {
 		if code, _, err := conn.Read(); err != nil {
 			t.Fatalf("expected disconnect on blob violation, got err: %v", err)
 		} else if code != discMsg {
-			if code == protoOffset(ethProto)+eth.NewPooledTransactionHashesMsg {
+			if code == conn.protoOffset(ethProto)+eth.NewPooledTransactionHashesMsg {
 				// sometimes we'll get a blob transaction hashes announcement before the disconnect
 				// because blob transactions are scheduled to be fetched right away.
 				if code, _, err = conn.Read(); err != nil {
@@ -0,0 +1,174 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package ethtest
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/protocols/trust"
+	"github.com/ethereum/go-ethereum/internal/utesting"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// ReadTrust reads a trust/1 message from the connection.
+func (c *Conn) ReadTrust() (any, error) {
+	c.SetReadDeadline(time.Now().Add(timeout))
+	for {
+		code, data, _, err := c.Conn.Read()
+		if err != nil {
+			return nil, err
+		}
+		if code == pingMsg {
+			c.Write(baseProto, pongMsg, []byte{})
+			continue
+		}
+		if c.getProto(code) != trustProto {
+			// Read until trust message.
+			continue
+		}
+		code -= c.protoOffset(trustProto)
+
+		var msg any
+		switch int(code) {
+		case trust.RequestRootMsg:
+			msg = new(trust.RootRequestPacket)
+		case trust.RespondRootMsg:
+			msg = new(trust.RootResponsePacket)
+		default:
+			panic(fmt.Errorf("unhandled trust code: %d", code))
+		}
+		if err := rlp.DecodeBytes(data, msg); err != nil {
+			return nil, fmt.Errorf("could not rlp decode message: %v", err)
+		}
+		return msg, nil
+	}
+}
+
+func (s *Suite) TestTrustStatus(t *utesting.T) {
+	t.Log(`This test performs the eth status exchange on a connection running trust/1.`)
+
+	conn, err := s.dialTrust()
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer conn.Close()
+	if err := conn.peer(s.chain, nil); err != nil {
+		t.Fatalf("peering failed: %v", err)
+	}
+}
+
+func (s *Suite) TestTrustRequestRoot(t *utesting.T) {
+	t.Log(`This test requests state root verifications from the node and checks the
+returned statuses.`)
+
+	conn, err := s.dialTrust()
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer conn.Close()
+	if err := conn.peer(s.chain, nil); err != nil {
+		t.Fatalf("peering failed: %v", err)
+	}
+
+	var (
+		head     = s.chain.Head()
+		number   = head.NumberU64()
+		unknown  = common.Hash{0x01}
+		diffHash = common.Hash{0x02} // not the diff hash of any block
+		// maxForkDist is the distance from the head within which a node
+		// can't tell apart an unknown block from a fork.
+		maxForkDist uint64 = 11
+	)
+	tests := []struct {
+		req    trust.RootRequestPacket
+		known  bool               // whether the block is known to the node
+		status types.VerifyStatus // expected status for unknown blocks
+		root   common.Hash        // expected root for known blocks
+		desc   string
+	}{
+		{
+			req:   trust.RootRequestPacket{BlockNumber: number, BlockHash: head.Hash(), DiffHash: diffHash},
+			known: true,
+			root:  head.Root(),
+			desc:  "head block",
+		},
+		{
+			req:   trust.RootRequestPacket{BlockNumber: number - 20, BlockHash: s.chain.GetBlock(int(number - 20)).Hash(), DiffHash: diffHash},
+			known: true,
+			root:  s.chain.GetBlock(int(number - 20)).Root(),
+			desc:  "older block",
+		},
+		{
+			req:    trust.RootRequestPacket{BlockNumber: number + 1, BlockHash: unknown},
+			status: types.StatusBlockNewer,
+			desc:   "block above head",
+		},
+		{
+			req:    trust.RootRequestPacket{BlockNumber: number + maxForkDist + 1, BlockHash: unknown},
+			status: types.StatusBlockTooNew,
+			desc:   "block far above head",
+		},
+		{
+			req:    trust.RootRequestPacket{BlockNumber: number, BlockHash: unknown},
+			status: types.StatusPossibleFork,
+			desc:   "unknown block close to head",
+		},
+		{
+			req:    trust.RootRequestPacket{BlockNumber: number - maxForkDist - 1, BlockHash: unknown},
+			status: types.StatusImpossibleFork,
+			desc:   "unknown block far below head",
+		},
+	}
+	for i, tc := range tests {
+		tc.req.RequestId = uint64(i) + 1
+		if err := conn.Write(trustProto, trust.RequestRootMsg, &tc.req); err != nil {
+			t.Fatalf("%v: could not write to connection: %v", tc.desc, err)
+		}
+		msg, err := conn.ReadTrust()
+		if err != nil {
+			t.Fatalf("%v: could not read response: %v", tc.desc, err)
+		}
+		res, ok := msg.(*trust.RootResponsePacket)
+		if !ok {
+			t.Fatalf("%v: unexpected response %T", tc.desc, msg)
+		}
+		if res.RequestId != tc.req.RequestId {
+			t.Fatalf("%v: wrong request id: have %d, want %d", tc.desc, res.RequestId, tc.req.RequestId)
+		}
+		if res.BlockNumber != tc.req.BlockNumber || res.BlockHash != tc.req.BlockHash {
+			t.Fatalf("%v: response for wrong block: have %d (%x)", tc.desc, res.BlockNumber, res.BlockHash)
+		}
+		if tc.known {
+			// If the node still has the diff layer of the block, it must notice
+			// the bogus diff hash. Otherwise it can only verify the root partially.
+			switch res.Status.Code {
+			case types.StatusDiffHashMismatch.Code:
+			case types.StatusPartiallyVerified.Code:
+				if res.Root != tc.root {
+					t.Fatalf("%v: wrong root: have %x, want %x", tc.desc, res.Root, tc.root)
+				}
+			default:
+				t.Fatalf("%v: unexpected status: %#x (%v)", tc.desc, res.Status.Code, res.Status.Msg)
+			}
+		} else if res.Status.Code != tc.status.Code {
+			t.Fatalf("%v: wrong status: have %#x (%v), want %#x", tc.desc, res.Status.Code, res.Status.Msg, tc.status.Code)
+		}
+	}
+}
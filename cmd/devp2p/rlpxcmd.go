@@ -38,6 +38,8 @@ var (
 			rlpxPingCommand,
 			rlpxEthTestCommand,
 			rlpxSnapTestCommand,
+			rlpxBscTestCommand,
+			rlpxTrustTestCommand,
 		},
 	}
 	rlpxPingCommand = &cli.Command{
@@ -73,6 +75,34 @@ var (
 			testNodeEngineFlag,
 		},
 	}
+	rlpxBscTestCommand = &cli.Command{
+		Name:      "bsc-test",
+		Usage:     "Runs bsc protocol tests against a node",
+		ArgsUsage: "",
+		Action:    rlpxBscTest,
+		Flags: []cli.Flag{
+			testPatternFlag,
+			testTAPFlag,
+			testChainDirFlag,
+			testNodeFlag,
+			testNodeJWTFlag,
+			testNodeEngineFlag,
+		},
+	}
+	rlpxTrustTestCommand = &cli.Command{
+		Name:      "trust-test",
+		Usage:     "Runs trust protocol tests against a node",
+		ArgsUsage: "",
+		Action:    rlpxTrustTest,
+		Flags: []cli.Flag{
+			testPatternFlag,
+			testTAPFlag,
+			testChainDirFlag,
+			testNodeFlag,
+			testNodeJWTFlag,
+			testNodeEngineFlag,
+		},
+	}
 )
 
 func rlpxPing(ctx *cli.Context) error {
@@ -134,6 +164,26 @@ func rlpxSnapTest(ctx *cli.Context) error {
 	return runTests(ctx, suite.SnapTests())
 }
 
+// rlpxBscTest runs the bsc protocol test suite.
+func rlpxBscTest(ctx *cli.Context) error {
+	p := cliTestParams(ctx)
+	suite, err := ethtest.NewSuite(p.node, p.chainDir, p.engineAPI, p.jwt)
+	if err != nil {
+		exit(err)
+	}
+	return runTests(ctx, suite.BscTests())
+}
+
+// rlpxTrustTest runs the trust protocol test suite.
+func rlpxTrustTest(ctx *cli.Context) error {
+	p := cliTestParams(ctx)
+	suite, err := ethtest.NewSuite(p.node, p.chainDir, p.engineAPI, p.jwt)
+	if err != nil {
+		exit(err)
+	}
+	return runTests(ctx, suite.TrustTests())
+}
+
 type testParams struct {
 	node      *enode.Node
 	engineAPI string
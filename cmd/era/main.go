@@ -24,10 +24,10 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/parlia"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/internal/era"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
@@ -48,7 +48,7 @@ var (
 	networkFlag = &cli.StringFlag{
 		Name:  "network",
 		Usage: "network name associated with era1 files",
-		Value: "mainnet",
+		Value: "bsc",
 	}
 	eraSizeFlag = &cli.IntFlag{
 		Name:  "size",
@@ -78,10 +78,9 @@ var (
 		Action:    info,
 	}
 	verifyCommand = &cli.Command{
-		Name:      "verify",
-		ArgsUsage: "<expected>",
-		Usage:     "verifies each era1 against expected accumulator root",
-		Action:    verify,
+		Name:   "verify",
+		Usage:  "verifies the Parlia seals and contents of each era1 file",
+		Action: verify,
 	}
 )
 
@@ -121,8 +120,12 @@ func block(ctx *cli.Context) error {
 	if err != nil {
 		return fmt.Errorf("error reading block %d: %w", num, err)
 	}
+	config, err := chainConfig(ctx.String(networkFlag.Name))
+	if err != nil {
+		return err
+	}
 	// Convert block to JSON and print.
-	val := ethapi.RPCMarshalBlock(block, ctx.Bool(txsFlag.Name), ctx.Bool(txsFlag.Name), params.MainnetChainConfig)
+	val := ethapi.RPCMarshalBlock(block, ctx.Bool(txsFlag.Name), ctx.Bool(txsFlag.Name), config)
 	b, err := json.MarshalIndent(val, "", "  ")
 	if err != nil {
 		return fmt.Errorf("error marshaling json: %w", err)
@@ -180,51 +183,39 @@ func open(ctx *cli.Context, epoch uint64) (*era.Era, error) {
 }
 
 // verify checks each era1 file in a directory to ensure it is well-formed and
-// that the accumulator matches the expected value.
+// that all blocks are sealed by their Parlia validators.
 func verify(ctx *cli.Context) error {
-	if ctx.Args().Len() != 1 {
-		return errors.New("missing accumulators file")
+	if ctx.Args().Len() != 0 {
+		return errors.New("unexpected arguments")
 	}
-
-	roots, err := readHashes(ctx.Args().First())
-	if err != nil {
-		return fmt.Errorf("unable to read expected roots file: %w", err)
-	}
-
 	var (
 		dir      = ctx.String(dirFlag.Name)
 		network  = ctx.String(networkFlag.Name)
 		start    = time.Now()
 		reported = time.Now()
+		parent   *types.Header
 	)
-
+	config, err := chainConfig(network)
+	if err != nil {
+		return err
+	}
 	entries, err := era.ReadDir(dir, network)
 	if err != nil {
 		return fmt.Errorf("error reading %s: %w", dir, err)
 	}
-
-	if len(entries) != len(roots) {
-		return errors.New("number of era1 files should match the number of accumulator hashes")
+	if len(entries) == 0 {
+		return fmt.Errorf("no era1 files found in %s", dir)
 	}
-
-	// Verify each epoch matches the expected root.
-	for i, want := range roots {
+	for i, name := range entries {
 		// Wrap in function so defers don't stack.
 		err := func() error {
-			name := entries[i]
 			e, err := era.Open(filepath.Join(dir, name))
 			if err != nil {
 				return fmt.Errorf("error opening era1 file %s: %w", name, err)
 			}
 			defer e.Close()
-			// Read accumulator and check against expected.
-			if got, err := e.Accumulator(); err != nil {
-				return fmt.Errorf("error retrieving accumulator for %s: %w", name, err)
-			} else if got != want {
-				return fmt.Errorf("invalid root %s: got %s, want %s", name, got, want)
-			}
-			// Recompute accumulator.
-			if err := checkAccumulator(e); err != nil {
+
+			if parent, err = checkEra(e, config.ChainID, parent); err != nil {
 				return fmt.Errorf("error verify era1 file %s: %w", name, err)
 			}
 			// Give the user some feedback that something is happening.
@@ -238,88 +229,75 @@ func verify(ctx *cli.Context) error {
 			return err
 		}
 	}
-
 	return nil
 }
 
-// checkAccumulator verifies the accumulator matches the data in the Era.
-func checkAccumulator(e *era.Era) error {
-	var (
-		err    error
-		want   common.Hash
-		td     *big.Int
-		tds    = make([]*big.Int, 0)
-		hashes = make([]common.Hash, 0)
-	)
-	if want, err = e.Accumulator(); err != nil {
-		return fmt.Errorf("error reading accumulator: %w", err)
-	}
-	if td, err = e.InitialTD(); err != nil {
-		return fmt.Errorf("error reading total difficulty: %w", err)
-	}
+// checkEra verifies the blocks in the Era are sealed by their coinbase and
+// consistent with their headers. The parent is the last header of the
+// previous Era, nil for the first one. The last header of the Era is returned.
+func checkEra(e *era.Era, chainId *big.Int, parent *types.Header) (*types.Header, error) {
 	it, err := era.NewIterator(e)
 	if err != nil {
-		return fmt.Errorf("error making era iterator: %w", err)
+		return nil, fmt.Errorf("error making era iterator: %w", err)
 	}
 	// To fully verify an era the following attributes must be checked:
 	//   1) the block index is constructed correctly
-	//   2) the tx root matches the value in the block
-	//   3) the receipts root matches the value in the block
-	//   4) the starting total difficulty value is correct
-	//   5) the accumulator is correct by recomputing it locally, which verifies
-	//      the blocks are all correct (via hash)
+	//   2) the blocks link up to each other and to the previous era
+	//   3) the header is sealed by its coinbase
+	//   4) the tx root matches the value in the block
+	//   5) the receipts root matches the value in the block
+	//   6) the blob sidecars belong to the blob transactions of the block and
+	//      match their blob hashes and KZG proofs
 	//
-	// The attributes 1), 2), and 3) are checked for each block. 4) and 5) require
-	// accumulation across the entire set and are verified at the end.
+	// Whether the coinbase was an active validator can only be checked against
+	// the validator set, which is done by the consensus engine on import.
 	for it.Next() {
 		// 1) next() walks the block index, so we're able to implicitly verify it.
 		if it.Error() != nil {
-			return fmt.Errorf("error reading block %d: %w", it.Number(), err)
+			return nil, fmt.Errorf("error reading block %d: %w", it.Number(), it.Error())
 		}
 		block, receipts, err := it.BlockAndReceipts()
-		if it.Error() != nil {
-			return fmt.Errorf("error reading block %d: %w", it.Number(), err)
+		if err != nil {
+			return nil, fmt.Errorf("error reading block %d: %w", it.Number(), err)
+		}
+		// 2) check the block links to its parent.
+		if parent != nil && (block.ParentHash() != parent.Hash() || block.NumberU64() != parent.Number.Uint64()+1) {
+			return nil, fmt.Errorf("block %d does not link to parent %d [%x]", block.NumberU64(), parent.Number, parent.Hash())
+		}
+		// 3) recover the sealer, the genesis block is not sealed.
+		if block.NumberU64() != 0 {
+			if err := parlia.VerifySealSigner(block.Header(), chainId); err != nil {
+				return nil, fmt.Errorf("invalid seal of block %d: %w", block.NumberU64(), err)
+			}
 		}
-		// 2) recompute tx root and verify against header.
+		// 4) recompute tx root and verify against header.
 		tr := types.DeriveSha(block.Transactions(), trie.NewStackTrie(nil))
 		if tr != block.TxHash() {
-			return fmt.Errorf("tx root in block %d mismatch: want %s, got %s", block.NumberU64(), block.TxHash(), tr)
+			return nil, fmt.Errorf("tx root in block %d mismatch: want %s, got %s", block.NumberU64(), block.TxHash(), tr)
 		}
-		// 3) recompute receipt root and check value against block.
+		// 5) recompute receipt root and check value against block.
 		rr := types.DeriveSha(receipts, trie.NewStackTrie(nil))
 		if rr != block.ReceiptHash() {
-			return fmt.Errorf("receipt root in block %d mismatch: want %s, got %s", block.NumberU64(), block.ReceiptHash(), rr)
+			return nil, fmt.Errorf("receipt root in block %d mismatch: want %s, got %s", block.NumberU64(), block.ReceiptHash(), rr)
 		}
-		hashes = append(hashes, block.Hash())
-		td.Add(td, block.Difficulty())
-		tds = append(tds, new(big.Int).Set(td))
-	}
-	// 4+5) Verify accumulator and total difficulty.
-	got, err := era.ComputeAccumulator(hashes, tds)
-	if err != nil {
-		return fmt.Errorf("error computing accumulator: %w", err)
+		// 6) check the sidecars against the blob transactions.
+		if err := era.VerifySidecars(block); err != nil {
+			return nil, err
+		}
+		parent = block.Header()
 	}
-	if got != want {
-		return fmt.Errorf("expected accumulator root does not match calculated: got %s, want %s", got, want)
+	if it.Error() != nil {
+		return nil, it.Error()
 	}
-	return nil
+	return parent, nil
 }
 
-// readHashes reads a file of newline-delimited hashes.
-func readHashes(f string) ([]common.Hash, error) {
-	b, err := os.ReadFile(f)
-	if err != nil {
-		return nil, errors.New("unable to open accumulators file")
-	}
-	s := strings.Split(string(b), "\n")
-	// Remove empty last element, if present.
-	if s[len(s)-1] == "" {
-		s = s[:len(s)-1]
-	}
-	// Convert to hashes.
-	r := make([]common.Hash, len(s))
-	for i := range s {
-		r[i] = common.HexToHash(s[i])
+// chainConfig returns the chain configuration of the named network.
+func chainConfig(network string) (*params.ChainConfig, error) {
+	for _, config := range []*params.ChainConfig{params.BSCChainConfig, params.ChapelChainConfig, params.RialtoChainConfig} {
+		if params.NetworkNames[config.ChainID.String()] == network {
+			return config, nil
+		}
 	}
-	return r, nil
+	return nil, fmt.Errorf("unknown network %q", network)
 }
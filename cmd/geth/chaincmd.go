@@ -179,7 +179,7 @@ be gzipped.`,
 		),
 		Description: `
 The import-history command will import blocks and their corresponding receipts
-from Era archives.
+and blob sidecars from Era archives, verifying the Parlia seal of every header.
 `,
 	}
 	exportHistoryCommand = &cli.Command{
@@ -190,7 +190,8 @@ from Era archives.
 		Flags:     slices.Concat(utils.DatabaseFlags),
 		Description: `
 The export-history command will export blocks and their corresponding receipts
-into Era archives. Eras are typically packaged in steps of 8192 blocks.
+and blob sidecars into Era archives. Eras are typically packaged in steps of 8192
+blocks.
 `,
 	}
 	importPreimagesCommand = &cli.Command{
@@ -492,6 +493,7 @@ func initNetwork(ctx *cli.Context) error {
 	if enableSentryNode {
 		for i := 0; i < len(sentryConfigs); i++ {
 			sentryConfigs[i].Node.P2P.ProxyedValidatorAddresses = accounts[i]
+			sentryConfigs[i].Node.P2P.ProxyedValidatorNodeIDs = []enode.ID{nodeIDs[i]}
 		}
 	}
 	if ctx.Bool(utils.InitEVNValidatorWhitelist.Name) {
@@ -693,6 +695,8 @@ func dumpGenesis(ctx *cli.Context) error {
 	if stack.CheckIfMultiDataBase() && err == nil {
 		stateDiskDb := utils.MakeStateDataBase(ctx, stack, true, false)
 		db.SetStateStore(stateDiskDb)
+	}
+	if stack.CheckIfSeparateBlockStore() && err == nil {
 		blockDb := utils.MakeBlockDatabase(ctx, stack, true, false)
 		db.SetBlockStore(blockDb)
 	}
@@ -851,7 +855,7 @@ func importHistory(ctx *cli.Context) error {
 	if utils.IsNetworkPreset(ctx) {
 		switch {
 		case ctx.Bool(utils.BSCMainnetFlag.Name):
-			network = "mainnet"
+			network = "bsc"
 		case ctx.Bool(utils.ChapelFlag.Name):
 			network = "chapel"
 		}
@@ -24,6 +24,7 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"runtime"
 	"slices"
 	"strconv"
 	"strings"
@@ -36,10 +37,13 @@ import (
 	"github.com/ethereum/go-ethereum/console/prompt"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state/snapshot"
+	"github.com/ethereum/go-ethereum/core/txpool/blobpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/remotedb"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/trie"
 	"github.com/ethereum/go-ethereum/triedb"
@@ -94,6 +98,11 @@ Remove blockchain and state databases`,
 			dbTrieGetCmd,
 			dbTrieDeleteCmd,
 			dbInspectHistoryCmd,
+			dbExportBlobsCmd,
+			dbImportBlobsCmd,
+			dbSplitBlockStoreCmd,
+			dbMergeBlockStoreCmd,
+			dbInspectBlobPoolCmd,
 		},
 	}
 	dbInspectCmd = &cli.Command{
@@ -137,8 +146,11 @@ a data corruption.`,
 			utils.ForceFlag,
 			utils.AncientFlag,
 		},
-		Usage:       "Convert Hash-Base to Path-Base trie node.",
-		Description: `This command iterates the entire trie node database and convert the hash-base node to path-base node.`,
+		Usage: "Convert Hash-Base to Path-Base trie node.",
+		Description: `This command iterates the entire trie node database and convert the hash-base node to path-base node.
+The account trie is split into shards converted by <jobnum> workers in parallel (the number of CPUs by default),
+along with the storage tries. The converted shards are checkpointed in the database, an interrupted conversion is
+resumed by running the command again. Use --force to discard the checkpoint and start over.`,
 	}
 	dbTrieGetCmd = &cli.Command{
 		Action:    dbTrieGet,
@@ -192,12 +204,13 @@ corruption if it is aborted during execution'!`,
 	dbGetCmd = &cli.Command{
 		Action:    dbGet,
 		Name:      "get",
-		Usage:     "Show the value of a database key",
-		ArgsUsage: "<hex-encoded key>",
+		Usage:     "Show the values of database keys",
+		ArgsUsage: "<hex-encoded key> [<hex-encoded key> ...]",
 		Flags: slices.Concat([]cli.Flag{
 			utils.SyncModeFlag,
 		}, utils.NetworkFlags, utils.DatabaseFlags),
-		Description: "This command looks up the specified database key from the database.",
+		Description: `This command looks up the specified database keys from the database.
+The keys are retrieved in batches if the database is a remote one (--remotedb).`,
 	}
 	dbDeleteCmd = &cli.Command{
 		Action:    dbDelete,
@@ -311,6 +324,87 @@ of ancientStore, will also displays the reserved number of blocks in ancientStor
 		}, utils.NetworkFlags, utils.DatabaseFlags),
 		Description: "This command queries the history of the account or storage slot within the specified block range",
 	}
+	dbExportBlobsCmd = &cli.Command{
+		Action:    exportBlobs,
+		Name:      "export-blobs",
+		Usage:     "Export blob sidecars into archive files",
+		ArgsUsage: "<dir> [<first> <last>]",
+		Flags: slices.Concat([]cli.Flag{
+			&cli.Uint64Flag{
+				Name:  "step",
+				Usage: "number of blocks covered by each archive file",
+				Value: 8192,
+			},
+		}, utils.NetworkFlags, utils.DatabaseFlags),
+		Description: `
+The export-blobs command writes the blob sidecars of the canonical blocks within
+the given range (default: the entire chain) into archive files, which can be
+restored later with import-blobs.`,
+	}
+	dbImportBlobsCmd = &cli.Command{
+		Action:    importBlobs,
+		Name:      "import-blobs",
+		Usage:     "Import blob sidecars from archive files",
+		ArgsUsage: "<dir>",
+		Flags:     slices.Concat(utils.NetworkFlags, utils.DatabaseFlags),
+		Description: `
+The import-blobs command restores the blob sidecars from the archive files in the
+given directory, as written by export-blobs or by the blob archival policy before
+pruning. Sidecars of non-canonical blocks are skipped.`,
+	}
+	blockStoreStageFlag = &cli.BoolFlag{
+		Name:  "stage",
+		Usage: "Only copy the block data, leaving the database layout unchanged",
+	}
+	dbSplitBlockStoreCmd = &cli.Command{
+		Action:    splitBlockStore,
+		Name:      "split-blockstore",
+		Usage:     "Move the block data into a separate block store",
+		ArgsUsage: "[<dir>]",
+		Flags:     slices.Concat([]cli.Flag{blockStoreStageFlag}, utils.NetworkFlags, utils.DatabaseFlags),
+		Description: `
+The split-blockstore command moves the block data (headers, bodies, receipts,
+blob sidecars and the chain freezer) out of the chain database into a separate
+block store in chaindata/block, the layout created by --multidatabase. If <dir>
+is given, the block store is created there instead, e.g. on another disk, and
+linked from chaindata/block. If the block store is separated already, it is
+moved to <dir>.
+
+The migration is checkpointed and resumes where it stopped if interrupted. With
+--stage the data is only copied and the node keeps its current layout, so it can
+be restarted in between. A later run catches up with the changes made since and
+switches over, keeping the downtime short.`,
+	}
+	dbMergeBlockStoreCmd = &cli.Command{
+		Action:    mergeBlockStore,
+		Name:      "merge-blockstore",
+		Usage:     "Move the block data of a separate block store back into the chain database",
+		ArgsUsage: "",
+		Flags:     slices.Concat([]cli.Flag{blockStoreStageFlag}, utils.NetworkFlags, utils.DatabaseFlags),
+		Description: `
+The merge-blockstore command is the reverse of split-blockstore, moving the block
+data of the separate block store back into the chain database and removing the
+block store afterwards.
+
+The migration is checkpointed and resumes where it stopped if interrupted. With
+--stage the data is only copied and the node keeps its current layout.`,
+	}
+	dbInspectBlobPoolCmd = &cli.Command{
+		Action:    inspectBlobPool,
+		Name:      "inspect-blobpool",
+		Usage:     "Inspect the storage usage and eviction order of the blob pool",
+		ArgsUsage: "",
+		Flags: slices.Concat([]cli.Flag{
+			utils.BlobPoolDataDirFlag,
+			utils.BlobPoolDataCapFlag,
+		}, utils.NetworkFlags, utils.DatabaseFlags),
+		Description: `
+The inspect-blobpool command lists the blob transactions persisted by the blob
+pool of a stopped node, along with their storage usage and eviction priority as
+of the current chain head, as well as the included but not yet finalized ones
+kept in the limbo. When the pool exceeds its data cap, the last transaction of
+the account with the lowest rank is evicted first.`,
+	}
 )
 
 func removeDB(ctx *cli.Context) error {
@@ -624,6 +718,8 @@ func dbStats(ctx *cli.Context) error {
 	if stack.CheckIfMultiDataBase() {
 		fmt.Println("show stats of state store")
 		showDBStats(db.StateStore())
+	}
+	if stack.CheckIfSeparateBlockStore() {
 		fmt.Println("show stats of block store")
 		showDBStats(db.BlockStore())
 	}
@@ -643,6 +739,8 @@ func dbCompact(ctx *cli.Context) error {
 	if stack.CheckIfMultiDataBase() {
 		fmt.Println("show stats of state store")
 		showDBStats(db.StateStore())
+	}
+	if stack.CheckIfSeparateBlockStore() {
 		fmt.Println("show stats of block store")
 		showDBStats(db.BlockStore())
 	}
@@ -658,6 +756,8 @@ func dbCompact(ctx *cli.Context) error {
 			log.Error("Compact err", "error", err)
 			return err
 		}
+	}
+	if stack.CheckIfSeparateBlockStore() {
 		if err := db.BlockStore().Compact(nil, nil); err != nil {
 			log.Error("Compact err", "error", err)
 			return err
@@ -669,6 +769,8 @@ func dbCompact(ctx *cli.Context) error {
 	if stack.CheckIfMultiDataBase() {
 		fmt.Println("show stats of state store after compaction")
 		showDBStats(db.StateStore())
+	}
+	if stack.CheckIfSeparateBlockStore() {
 		fmt.Println("show stats of block store after compaction")
 		showDBStats(db.BlockStore())
 	}
@@ -677,7 +779,7 @@ func dbCompact(ctx *cli.Context) error {
 
 // dbGet shows the value of a given database key
 func dbGet(ctx *cli.Context) error {
-	if ctx.NArg() != 1 {
+	if ctx.NArg() < 1 {
 		return fmt.Errorf("required arguments: %v", ctx.Command.ArgsUsage)
 	}
 	stack, _ := makeConfigNode(ctx)
@@ -686,19 +788,49 @@ func dbGet(ctx *cli.Context) error {
 	db := utils.MakeChainDatabase(ctx, stack, true, false)
 	defer db.Close()
 
-	key, err := common.ParseHexOrString(ctx.Args().Get(0))
-	if err != nil {
-		log.Info("Could not decode the key", "error", err)
-		return err
+	keys := make([][]byte, ctx.NArg())
+	for i, arg := range ctx.Args().Slice() {
+		key, err := common.ParseHexOrString(arg)
+		if err != nil {
+			log.Info("Could not decode the key", "error", err)
+			return err
+		}
+		keys[i] = key
 	}
-	opDb := db
-	if stack.CheckIfMultiDataBase() {
-		keyType := rawdb.DataTypeByKey(key)
-		if keyType == rawdb.StateDataType {
-			opDb = db.StateStore()
-		} else if keyType == rawdb.BlockDataType {
-			opDb = db.BlockStore()
+	// Retrieve the values from a remote database in batches, rather than
+	// making a request per key.
+	if remote, ok := db.(*remotedb.Database); ok {
+		values, err := remote.GetMany(keys)
+		if err != nil {
+			log.Info("Get operation failed", "error", err)
+			return err
 		}
+		for i, key := range keys {
+			if values[i] == nil {
+				fmt.Printf("key %#x: not found\n", key)
+				continue
+			}
+			fmt.Printf("key %#x: %#x\n", key, values[i])
+		}
+		return nil
+	}
+	for _, key := range keys {
+		if err := dbGetKey(stack, db, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dbGetKey shows the value of a database key, looked up in the store the key
+// belongs to.
+func dbGetKey(stack *node.Node, db ethdb.Database, key []byte) error {
+	opDb := db
+	keyType := rawdb.DataTypeByKey(key)
+	if stack.CheckIfMultiDataBase() && keyType == rawdb.StateDataType {
+		opDb = db.StateStore()
+	} else if stack.CheckIfSeparateBlockStore() && keyType == rawdb.BlockDataType {
+		opDb = db.BlockStore()
 	}
 
 	data, err := opDb.Get(key)
@@ -866,13 +998,11 @@ func dbDelete(ctx *cli.Context) error {
 		return err
 	}
 	opDb := db
-	if stack.CheckIfMultiDataBase() {
-		keyType := rawdb.DataTypeByKey(key)
-		if keyType == rawdb.StateDataType {
-			opDb = db.StateStore()
-		} else if keyType == rawdb.BlockDataType {
-			opDb = db.BlockStore()
-		}
+	keyType := rawdb.DataTypeByKey(key)
+	if stack.CheckIfMultiDataBase() && keyType == rawdb.StateDataType {
+		opDb = db.StateStore()
+	} else if stack.CheckIfSeparateBlockStore() && keyType == rawdb.BlockDataType {
+		opDb = db.BlockStore()
 	}
 
 	data, err := opDb.Get(key)
@@ -991,13 +1121,11 @@ func dbPut(ctx *cli.Context) error {
 	}
 
 	opDb := db
-	if stack.CheckIfMultiDataBase() {
-		keyType := rawdb.DataTypeByKey(key)
-		if keyType == rawdb.StateDataType {
-			opDb = db.StateStore()
-		} else if keyType == rawdb.BlockDataType {
-			opDb = db.BlockStore()
-		}
+	keyType := rawdb.DataTypeByKey(key)
+	if stack.CheckIfMultiDataBase() && keyType == rawdb.StateDataType {
+		opDb = db.StateStore()
+	} else if stack.CheckIfSeparateBlockStore() && keyType == rawdb.BlockDataType {
+		opDb = db.BlockStore()
 	}
 
 	data, err = opDb.Get(key)
@@ -1095,7 +1223,7 @@ func freezerInspect(ctx *cli.Context) error {
 	stack, _ := makeConfigNode(ctx)
 	ancient := stack.ResolveAncient("chaindata", ctx.String(utils.AncientFlag.Name))
 	stack.Close()
-	return rawdb.InspectFreezerTable(ancient, freezer, table, start, end, stack.CheckIfMultiDataBase())
+	return rawdb.InspectFreezerTable(ancient, freezer, table, start, end, stack.CheckIfMultiDataBase(), stack.CheckIfSeparateBlockStore())
 }
 
 func importLDBdata(ctx *cli.Context) error {
@@ -1273,7 +1401,7 @@ func hbss2pbss(ctx *cli.Context) error {
 		}
 	} else {
 		// by default
-		jobnum = 1000
+		jobnum = uint64(runtime.NumCPU())
 	}
 
 	force := ctx.Bool(utils.ForceFlag.Name)
@@ -1326,19 +1454,18 @@ func hbss2pbss(ctx *cli.Context) error {
 			return errors.New("Empty root hash.")
 		}
 
-		id := trie.StateTrieID(trieRootHash)
-		theTrie, err := trie.New(id, triedb)
+		if force {
+			rawdb.DeleteHbss2PbssProgress(triedb.Disk())
+		}
+		h2p, err := trie.NewHbss2Pbss(triedb, triedb.Disk(), trieRootHash, *blockNumber, int(jobnum))
 		if err != nil {
-			log.Error("fail to new trie tree", "err", err, "rootHash", err, trieRootHash.String())
+			log.Error("fail to new hash2pbss", "err", err, "rootHash", trieRootHash.String())
 			return err
 		}
-
-		h2p, err := trie.NewHbss2Pbss(theTrie, triedb, trieRootHash, *blockNumber, jobnum)
-		if err != nil {
-			log.Error("fail to new hash2pbss", "err", err, "rootHash", err, trieRootHash.String())
+		if err := h2p.Run(); err != nil {
+			log.Error("Convert hbss to pbss failed, rerun to resume", "err", err)
 			return err
 		}
-		h2p.Run()
 	} else {
 		log.Info("Convert hbss to pbss success. Nothing to do.")
 	}
@@ -1519,3 +1646,461 @@ func inspectHistory(ctx *cli.Context) error {
 	}
 	return inspectStorage(triedb, start, end, address, slot, ctx.Bool("raw"))
 }
+
+func exportBlobs(ctx *cli.Context) error {
+	if ctx.NArg() != 1 && ctx.NArg() != 3 {
+		return fmt.Errorf("required arguments: %v", ctx.Command.ArgsUsage)
+	}
+	step := ctx.Uint64("step")
+	if step == 0 {
+		return errors.New("step must be positive")
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, true, false)
+	defer db.Close()
+
+	head := rawdb.ReadHeadBlock(db)
+	if head == nil {
+		return errors.New("head block is missing")
+	}
+	first, last := uint64(0), head.NumberU64()
+	if ctx.NArg() == 3 {
+		var err error
+		if first, err = strconv.ParseUint(ctx.Args().Get(1), 10, 64); err != nil {
+			return fmt.Errorf("invalid first block: %v", err)
+		}
+		if last, err = strconv.ParseUint(ctx.Args().Get(2), 10, 64); err != nil {
+			return fmt.Errorf("invalid last block: %v", err)
+		}
+		if first > last {
+			return fmt.Errorf("first block %d beyond last block %d", first, last)
+		}
+	}
+	interrupt, stop := make(chan os.Signal, 1), make(chan struct{})
+	signal.Notify(interrupt, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(interrupt)
+	go func() {
+		if _, ok := <-interrupt; ok {
+			log.Info("Interrupted during blob export, stopping at next batch")
+			close(stop)
+		}
+	}()
+	return utils.ExportBlobs(db, ctx.Args().Get(0), first, last, step, stop)
+}
+
+func importBlobs(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return fmt.Errorf("required arguments: %v", ctx.Command.ArgsUsage)
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, false, false)
+	defer db.Close()
+
+	interrupt, stop := make(chan os.Signal, 1), make(chan struct{})
+	signal.Notify(interrupt, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(interrupt)
+	go func() {
+		if _, ok := <-interrupt; ok {
+			log.Info("Interrupted during blob import, stopping at next file")
+			close(stop)
+		}
+	}()
+	return utils.ImportBlobs(db, ctx.Args().Get(0), stop)
+}
+
+// blockStoreInterrupt returns a channel closed once the process is interrupted,
+// along with a function releasing the signal handler.
+func blockStoreInterrupt() (chan struct{}, func()) {
+	interrupt, stop := make(chan os.Signal, 1), make(chan struct{})
+	signal.Notify(interrupt, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		if _, ok := <-interrupt; ok {
+			log.Info("Interrupted during block store migration, stopping at next checkpoint")
+			close(stop)
+		}
+	}()
+	return stop, func() { signal.Stop(interrupt) }
+}
+
+// checkBlockStoreTarget ensures the given directory is either empty or holds
+// the block store of an earlier, interrupted migration.
+func checkBlockStoreTarget(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(entries) > 0 && rawdb.PreexistingDatabase(dir) == "" {
+		return fmt.Errorf("target directory %s is not empty", dir)
+	}
+	return nil
+}
+
+// restoreBlockStore puts back the block store if moving it was interrupted
+// right after it was renamed away.
+func restoreBlockStore(blockDir string) error {
+	if _, err := os.Lstat(blockDir); !os.IsNotExist(err) {
+		return nil
+	}
+	if !common.FileExist(blockDir + ".old") {
+		return nil
+	}
+	log.Warn("Restoring block store of an interrupted move", "dir", blockDir)
+	return os.Rename(blockDir+".old", blockDir)
+}
+
+// removeBlockStore drops the block store directory, along with the directory
+// it links to, if any.
+func removeBlockStore(blockDir string) error {
+	info, err := os.Lstat(blockDir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		target, err := filepath.EvalSymlinks(blockDir)
+		if err != nil {
+			return err
+		}
+		if err := os.Remove(blockDir); err != nil {
+			return err
+		}
+		return os.RemoveAll(target)
+	}
+	// Rename the directory first, a partially deleted block store must not
+	// be picked up by the node.
+	if err := os.Rename(blockDir, blockDir+".removed"); err != nil {
+		return err
+	}
+	return os.RemoveAll(blockDir + ".removed")
+}
+
+func splitBlockStore(ctx *cli.Context) error {
+	if ctx.NArg() > 1 {
+		return fmt.Errorf("max 1 argument: %v", ctx.Command.ArgsUsage)
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	var (
+		blockDir     = filepath.Join(stack.ResolvePath("chaindata"), "block")
+		chainFreezer = rawdb.ChainFreezerDir(stack.ResolveAncient("chaindata", ctx.String(utils.AncientFlag.Name)))
+		stage        = ctx.Bool(blockStoreStageFlag.Name)
+		target       string
+	)
+	if ctx.NArg() == 1 {
+		dir, err := filepath.Abs(ctx.Args().Get(0))
+		if err != nil {
+			return err
+		}
+		target = dir
+	}
+	if err := restoreBlockStore(blockDir); err != nil {
+		return err
+	}
+	stop, release := blockStoreInterrupt()
+	defer release()
+
+	chaindb, err := stack.OpenDatabase("chaindata", 0, 0, "", false)
+	if err != nil {
+		return err
+	}
+	defer chaindb.Close()
+
+	if stack.CheckIfSeparateBlockStore() {
+		blockdb, err := stack.OpenDatabase("chaindata/block", 0, 0, "", false)
+		if err != nil {
+			return err
+		}
+		// Finish the clean up of a split interrupted after the switch over
+		if progress := rawdb.ReadBlockStoreMigration(blockdb); progress != nil && progress.Switched {
+			if err := finishBlockStoreSplit(chaindb, blockdb, chainFreezer, stop); err != nil {
+				blockdb.Close()
+				return err
+			}
+		}
+		blockdb.Close()
+		if target == "" {
+			return errors.New("block store is separated already")
+		}
+		return moveBlockStore(blockDir, target, stage, stop)
+	}
+	staging := target
+	if staging == "" {
+		staging = blockDir + ".staging"
+	}
+	if err := checkBlockStoreTarget(staging); err != nil {
+		return err
+	}
+	stagingdb, err := stack.OpenDatabase(staging, 0, 0, "", false)
+	if err != nil {
+		return err
+	}
+	progress := rawdb.ReadBlockStoreMigration(stagingdb)
+	if progress == nil || !progress.Switched {
+		log.Info("Copying block data", "dir", staging)
+		if err := rawdb.SyncBlockStore(chaindb, stagingdb, stop); err != nil {
+			stagingdb.Close()
+			return err
+		}
+		if err := utils.SyncDir(chainFreezer, filepath.Join(staging, "ancient", rawdb.ChainFreezerName), stop); err != nil {
+			stagingdb.Close()
+			return err
+		}
+		if stage {
+			stagingdb.Close()
+			log.Info("Staged block store", "dir", staging)
+			return nil
+		}
+		// Mark the block store as complete along with the checkpoint, so that a
+		// partial copy is never taken for a split block store.
+		progress = rawdb.ReadBlockStoreMigration(stagingdb)
+		progress.Switched = true
+		batch := stagingdb.NewBatch()
+		rawdb.WriteBlockStoreMigration(batch, progress)
+		rawdb.WriteBlockStoreSplit(batch)
+		if err := batch.Write(); err != nil {
+			stagingdb.Close()
+			return err
+		}
+	}
+	stagingdb.Close()
+
+	// Switch over to the separate block store
+	if target == "" {
+		err = os.Rename(staging, blockDir)
+	} else {
+		err = os.Symlink(target, blockDir)
+	}
+	if err != nil {
+		return err
+	}
+	log.Info("Switched to separate block store", "dir", staging)
+
+	blockdb, err := stack.OpenDatabase("chaindata/block", 0, 0, "", false)
+	if err != nil {
+		return err
+	}
+	defer blockdb.Close()
+	return finishBlockStoreSplit(chaindb, blockdb, chainFreezer, stop)
+}
+
+// finishBlockStoreSplit drops the block data left in the chain database after
+// the switch over to the separate block store.
+func finishBlockStoreSplit(chaindb, blockdb ethdb.Database, chainFreezer string, stop chan struct{}) error {
+	if err := rawdb.DeleteBlockStoreData(chaindb, stop); err != nil {
+		return err
+	}
+	if err := rawdb.ResetChainFreezer(chainFreezer); err != nil {
+		return err
+	}
+	rawdb.DeleteBlockStoreMigration(blockdb)
+	log.Info("Split block store")
+	return nil
+}
+
+// moveBlockStore moves the separate block store to the target directory,
+// linking it from the chain database afterwards.
+func moveBlockStore(blockDir, target string, stage bool, stop chan struct{}) error {
+	src, err := filepath.EvalSymlinks(blockDir)
+	if err != nil {
+		return err
+	}
+	if src == target {
+		log.Info("Block store is in place already", "dir", target)
+		return nil
+	}
+	if err := checkBlockStoreTarget(target); err != nil {
+		return err
+	}
+	log.Info("Copying block store", "from", src, "to", target)
+	if err := utils.SyncDir(src, target, stop); err != nil {
+		return err
+	}
+	if stage {
+		log.Info("Staged block store", "dir", target)
+		return nil
+	}
+	info, err := os.Lstat(blockDir)
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		err = os.Remove(blockDir)
+	} else {
+		src = blockDir + ".old"
+		err = os.Rename(blockDir, src)
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Symlink(target, blockDir); err != nil {
+		return err
+	}
+	log.Info("Moved block store", "dir", target)
+	return os.RemoveAll(src)
+}
+
+func mergeBlockStore(ctx *cli.Context) error {
+	if ctx.NArg() != 0 {
+		return fmt.Errorf("no arguments expected: %v", ctx.Command.ArgsUsage)
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	var (
+		blockDir     = filepath.Join(stack.ResolvePath("chaindata"), "block")
+		chainFreezer = rawdb.ChainFreezerDir(stack.ResolveAncient("chaindata", ctx.String(utils.AncientFlag.Name)))
+		stage        = ctx.Bool(blockStoreStageFlag.Name)
+	)
+	if err := restoreBlockStore(blockDir); err != nil {
+		return err
+	}
+	stop, release := blockStoreInterrupt()
+	defer release()
+
+	chaindb, err := stack.OpenDatabase("chaindata", 0, 0, "", false)
+	if err != nil {
+		return err
+	}
+	defer chaindb.Close()
+
+	progress := rawdb.ReadBlockStoreMigration(chaindb)
+	if !stack.CheckIfSeparateBlockStore() {
+		// Finish the clean up of a merge interrupted after the switch over
+		if progress != nil && progress.Switched {
+			return finishBlockStoreMerge(chaindb, blockDir, chainFreezer)
+		}
+		return errors.New("block store is not separated")
+	}
+	if stack.CheckIfMultiDataBase() {
+		return errors.New("block store of a multi-database can't be merged without its state store")
+	}
+	if progress == nil || !progress.Switched {
+		blockdb, err := stack.OpenDatabase("chaindata/block", 0, 0, "", false)
+		if err != nil {
+			return err
+		}
+		log.Info("Copying block data", "dir", blockDir)
+		err = rawdb.SyncBlockStore(blockdb, chaindb, stop)
+		blockdb.Close()
+		if err != nil {
+			return err
+		}
+		// The chain freezer of the chain database is unused while the block store
+		// is separated, stage the copy next to it.
+		blockFreezer := rawdb.ChainFreezerDir(filepath.Join(blockDir, "ancient"))
+		if err := utils.SyncDir(blockFreezer, chainFreezer+".staging", stop); err != nil {
+			return err
+		}
+		if stage {
+			log.Info("Staged block data in chain database")
+			return nil
+		}
+		progress = rawdb.ReadBlockStoreMigration(chaindb)
+		progress.Switched = true
+		rawdb.WriteBlockStoreMigration(chaindb, progress)
+	}
+	return finishBlockStoreMerge(chaindb, blockDir, chainFreezer)
+}
+
+// finishBlockStoreMerge moves the staged chain freezer into place and drops the
+// separate block store.
+func finishBlockStoreMerge(chaindb ethdb.Database, blockDir, chainFreezer string) error {
+	if staging := chainFreezer + ".staging"; common.FileExist(staging) {
+		if err := os.RemoveAll(chainFreezer); err != nil {
+			return err
+		}
+		if err := os.Rename(staging, chainFreezer); err != nil {
+			return err
+		}
+	}
+	if err := removeBlockStore(blockDir); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(blockDir + ".removed"); err != nil {
+		return err
+	}
+	rawdb.DeleteBlockStoreMigration(chaindb)
+	log.Info("Merged block store")
+	return nil
+}
+
+func inspectBlobPool(ctx *cli.Context) error {
+	stack, cfg := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, true, false)
+	defer db.Close()
+
+	head := rawdb.ReadHeadHeader(db)
+	if head == nil {
+		return errors.New("head header is missing")
+	}
+	config := rawdb.ReadChainConfig(db, rawdb.ReadCanonicalHash(db, 0))
+	if config == nil {
+		return errors.New("chain config is missing")
+	}
+	poolConfig := cfg.Eth.BlobPool
+	poolConfig.Datadir = stack.ResolvePath(poolConfig.Datadir)
+
+	status, err := blobpool.InspectStore(poolConfig, config, head)
+	if err != nil {
+		return err
+	}
+	addrs := make([]common.Address, 0, len(status.Accounts))
+	for addr := range status.Accounts {
+		addrs = append(addrs, addr)
+	}
+	slices.SortFunc(addrs, func(a, b common.Address) int {
+		return status.Accounts[a].EvictionRank - status.Accounts[b].EvictionRank
+	})
+	var (
+		txs   [][]string
+		count int
+	)
+	for _, addr := range addrs {
+		account := status.Accounts[addr]
+		for _, tx := range account.Txs {
+			txs = append(txs, []string{
+				fmt.Sprintf("%d", account.EvictionRank), addr.Hex(), fmt.Sprintf("%d", tx.Nonce), tx.Hash.Hex(),
+				fmt.Sprintf("%d", tx.Blobs), common.StorageSize(tx.Size).String(), fmt.Sprintf("%d", tx.Priority),
+			})
+		}
+		count += len(account.Txs)
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Rank", "Account", "Nonce", "Transaction", "Blobs", "Size", "Priority"})
+	table.AppendBulk(txs)
+	table.Render()
+
+	limbo := make([][]string, 0, len(status.Limbo))
+	for _, tx := range status.Limbo {
+		limbo = append(limbo, []string{fmt.Sprintf("%d", tx.Block), tx.Hash.Hex()})
+	}
+	table = tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Limbo block", "Transaction"})
+	table.AppendBulk(limbo)
+	table.Render()
+
+	table = tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Field", "Value"})
+	table.AppendBulk([][]string{
+		{"head", fmt.Sprintf("%d (%x)", head.Number, head.Hash())},
+		{"datacap", common.StorageSize(status.Datacap).String()},
+		{"stored", common.StorageSize(status.Stored).String()},
+		{"accounts", fmt.Sprintf("%d", len(status.Accounts))},
+		{"transactions", fmt.Sprintf("%d", count)},
+		{"limbo", fmt.Sprintf("%d", len(status.Limbo))},
+	})
+	table.Render()
+	return nil
+}
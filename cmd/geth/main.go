@@ -70,6 +70,9 @@ var (
 		utils.DisableSnapProtocolFlag,
 		utils.EnableTrustProtocolFlag,
 		utils.RangeLimitFlag,
+		utils.DBWriteAPIFlag,
+		utils.ArrivalsFlag,
+		utils.ArrivalsFileFlag,
 		utils.USBFlag,
 		utils.SmartCardDaemonPathFlag,
 		utils.RialtoHash,
@@ -94,8 +97,17 @@ var (
 		utils.TxPoolAccountQueueFlag,
 		utils.TxPoolGlobalQueueFlag,
 		utils.TxPoolOverflowPoolSlotsFlag,
+		utils.TxPoolOverflowPoolDirFlag,
 		utils.TxPoolLifetimeFlag,
 		utils.TxPoolReannounceTimeFlag,
+		utils.TxPoolSenderRateLimitFlag,
+		utils.TxPoolSenderRateBurstFlag,
+		utils.TxPoolContractRateLimitFlag,
+		utils.TxPoolContractRateBurstFlag,
+		utils.TxPoolPeerRateLimitFlag,
+		utils.TxPoolPeerRateBurstFlag,
+		utils.TxPoolBlocklistFlag,
+		utils.TxPoolSelectorFilterFlag,
 		utils.BlobPoolDataDirFlag,
 		utils.BlobPoolDataCapFlag,
 		utils.BlobPoolPriceBumpFlag,
@@ -108,8 +120,14 @@ var (
 		utils.TxLookupLimitFlag, // deprecated
 		utils.TransactionHistoryFlag,
 		utils.StateHistoryFlag,
+		utils.HistoricStateDepthFlag,
+		utils.BlockHistoryFlag,
+		utils.ColdHistoryDirFlag,
+		utils.ColdHistoryAgeFlag,
+		utils.ColdHistoryCacheFlag,
 		utils.PathDBSyncFlag,
 		utils.JournalFileFlag,
+		utils.JournalCheckpointFlag,
 		utils.LightServeFlag,       // deprecated
 		utils.LightIngressFlag,     // deprecated
 		utils.LightEgressFlag,      // deprecated
@@ -188,6 +206,10 @@ var (
 		utils.LogDebugFlag,
 		utils.LogBacktraceAtFlag,
 		utils.BlobExtraReserveFlag,
+		utils.BlobKeepAllFlag,
+		utils.BlobKeepAddressesFlag,
+		utils.BlobKeepBlocksFlag,
+		utils.BlobArchiveDirFlag,
 		// utils.BeaconApiFlag,
 		// utils.BeaconApiHeaderFlag,
 		// utils.BeaconThresholdFlag,
@@ -203,10 +225,10 @@ var (
 		utils.HTTPListenAddrFlag,
 		utils.HTTPPortFlag,
 		utils.HTTPCORSDomainFlag,
-		// utils.AuthListenFlag,
-		// utils.AuthPortFlag,
-		// utils.AuthVirtualHostsFlag,
-		// utils.JWTSecretFlag,
+		utils.AuthListenFlag,
+		utils.AuthPortFlag,
+		utils.AuthVirtualHostsFlag,
+		utils.JWTSecretFlag,
 		utils.HTTPVirtualHostsFlag,
 		utils.GraphQLEnabledFlag,
 		utils.GraphQLCORSDomainFlag,
@@ -294,6 +316,8 @@ func init() {
 		blsCommand,
 		// See verkle.go
 		verkleCommand,
+		// See txpoolcmd.go
+		txpoolCommand,
 	}
 	if logTestCommand != nil {
 		app.Commands = append(app.Commands, logTestCommand)
@@ -368,7 +368,7 @@ func pruneBlock(ctx *cli.Context) error {
 	if !ctx.IsSet(utils.AncientFlag.Name) {
 		return errors.New("datadir.ancient must be set")
 	} else {
-		if stack.CheckIfMultiDataBase() {
+		if stack.CheckIfSeparateBlockStore() {
 			ancientPath := ctx.String(utils.AncientFlag.Name)
 			index := strings.LastIndex(ancientPath, "/ancient/chain")
 			if index != -1 {
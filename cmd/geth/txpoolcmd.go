@@ -0,0 +1,197 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	txpoolCommand = &cli.Command{
+		Name:      "txpool",
+		Usage:     "Transaction pool operations on a running node",
+		ArgsUsage: "",
+		Subcommands: []*cli.Command{
+			txpoolDumpCmd,
+			txpoolLoadCmd,
+		},
+	}
+	txpoolDumpCmd = &cli.Command{
+		Action:    txpoolDump,
+		Name:      "dump",
+		Usage:     "Export all transactions of a running node's pool into a file",
+		ArgsUsage: "<dumpfile> [endpoint]",
+		Flags:     []cli.Flag{utils.DataDirFlag, utils.HttpHeaderFlag},
+		Description: `
+Exports all pending and queued transactions of the node's pool, including the
+blob transactions with their sidecars, into a file as a stream of RLP encoded
+transactions. The transactions are retrieved page by page. The node is reached
+on the given endpoint, or on the IPC endpoint in the data directory by default.`,
+	}
+	txpoolLoadCmd = &cli.Command{
+		Action:    txpoolLoad,
+		Name:      "load",
+		Usage:     "Import the transactions of a dump file into a running node's pool",
+		ArgsUsage: "<dumpfile> [endpoint]",
+		Flags:     []cli.Flag{utils.DataDirFlag, utils.HttpHeaderFlag},
+		Description: `
+Imports the transactions of a file created by 'geth txpool dump' into the node's
+pool, page by page. The transactions are validated by the pool as any other
+submitted ones. The node is reached on the given endpoint, or on the IPC endpoint
+in the data directory by default.`,
+	}
+)
+
+// txpoolTimeout is the time allowance of each transaction pool export and import
+// request, which may carry a large amount of blob data.
+const txpoolTimeout = 5 * time.Minute
+
+// dialTxPoolNode connects to the node at the endpoint given as second argument,
+// or at the IPC endpoint in the data directory if omitted.
+func dialTxPoolNode(ctx *cli.Context) *rpc.Client {
+	if ctx.Args().Len() < 1 || ctx.Args().Len() > 2 {
+		utils.Fatalf("This command requires a dump file and an optional endpoint.")
+	}
+	endpoint := ctx.Args().Get(1)
+	if endpoint == "" {
+		cfg := defaultNodeConfig()
+		utils.SetDataDir(ctx, &cfg)
+		endpoint = cfg.IPCEndpoint()
+	}
+	client, err := utils.DialRPCWithHeaders(endpoint, ctx.StringSlice(utils.HttpHeaderFlag.Name))
+	if err != nil {
+		utils.Fatalf("Unable to attach to remote geth: %v", err)
+	}
+	return client
+}
+
+func txpoolDump(ctx *cli.Context) error {
+	client := dialTxPoolNode(ctx)
+	defer client.Close()
+
+	file := ctx.Args().First()
+	out, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	var (
+		cursor *eth.TxPoolCursor
+		count  int
+		size   int
+	)
+	for {
+		rctx, cancel := context.WithTimeout(context.Background(), txpoolTimeout)
+		var page eth.TxPoolExportPage
+		err := client.CallContext(rctx, &page, "txpool_exportAll", cursor)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("failed to export transaction pool: %v", err)
+		}
+		if _, err := out.Write(page.Transactions); err != nil {
+			return err
+		}
+		count += page.Count
+		size += len(page.Transactions)
+
+		if page.Next == nil {
+			break
+		}
+		cursor = page.Next
+	}
+	log.Info("Dumped transaction pool", "file", file, "transactions", count, "size", common.StorageSize(size))
+	return nil
+}
+
+// txpoolLoadPageSize is the soft limit of the size of the transactions imported
+// in a single request, keeping the requests below the HTTP body size limit.
+const txpoolLoadPageSize = 1024 * 1024
+
+func txpoolLoad(ctx *cli.Context) error {
+	client := dialTxPoolNode(ctx)
+	defer client.Close()
+
+	file := ctx.Args().First()
+	in, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	var (
+		stream   = rlp.NewStream(bufio.NewReader(in), 0)
+		page     []byte
+		imported int
+		known    int
+		rejected int
+	)
+	flush := func() error {
+		if len(page) == 0 {
+			return nil
+		}
+		rctx, cancel := context.WithTimeout(context.Background(), txpoolTimeout)
+		defer cancel()
+
+		var result eth.TxPoolImportResult
+		if err := client.CallContext(rctx, &result, "txpool_importAll", hexutil.Bytes(page)); err != nil {
+			return fmt.Errorf("failed to import transaction pool: %v", err)
+		}
+		for hash, reason := range result.Errors {
+			log.Debug("Transaction rejected", "hash", hash, "err", reason)
+		}
+		imported += result.Imported
+		known += result.Known
+		rejected += len(result.Errors)
+		page = page[:0]
+		return nil
+	}
+	for {
+		blob, err := stream.Raw()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return fmt.Errorf("failed to read dump: %v", err)
+		}
+		if len(page) > 0 && len(page)+len(blob) > txpoolLoadPageSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+		page = append(page, blob...)
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+	log.Info("Loaded transaction pool", "file", file, "imported", imported, "known", known, "rejected", rejected)
+	return nil
+}
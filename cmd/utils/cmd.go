@@ -242,7 +242,8 @@ func readList(filename string) ([]string, error) {
 }
 
 // ImportHistory imports Era1 files containing historical block information,
-// starting from genesis.
+// starting from genesis. Every header is verified by the consensus engine and
+// blob sidecars carried by the archives are imported along with the blocks.
 func ImportHistory(chain *core.BlockChain, db ethdb.Database, dir string, network string) error {
 	if chain.CurrentSnapBlock().Number.BitLen() != 0 {
 		return errors.New("history import only supported when starting from genesis")
@@ -305,6 +306,14 @@ func ImportHistory(chain *core.BlockChain, db ethdb.Database, dir string, networ
 				if err != nil {
 					return fmt.Errorf("error reading receipts %d: %w", it.Number(), err)
 				}
+				// Verify the seal against the chain imported so far, the archive
+				// itself can't be trusted.
+				if err := chain.Engine().VerifyHeader(chain, block.Header()); err != nil {
+					return fmt.Errorf("error verifying header %d: %w", it.Number(), err)
+				}
+				if err := era.VerifySidecars(block); err != nil {
+					return fmt.Errorf("error verifying sidecars %d: %w", it.Number(), err)
+				}
 				if status, err := chain.HeaderChain().InsertHeaderChain([]*types.Header{block.Header()}, start, forker); err != nil {
 					return fmt.Errorf("error inserting header %d: %w", it.Number(), err)
 				} else if status != core.CanonStatTy {
@@ -402,7 +411,7 @@ func ExportAppendChain(blockchain *core.BlockChain, fn string, first uint64, las
 }
 
 // ExportHistory exports blockchain history into the specified directory,
-// following the Era format.
+// following the Era format extended with the blob sidecars of each block.
 func ExportHistory(bc *core.BlockChain, dir string, first, last, step uint64) error {
 	log.Info("Exporting blockchain history", "dir", dir)
 	if head := bc.CurrentBlock().Number.Uint64(); head < last {
@@ -441,6 +450,9 @@ func ExportHistory(bc *core.BlockChain, dir string, first, last, step uint64) er
 				if block == nil {
 					return fmt.Errorf("export failed on #%d: not found", n)
 				}
+				if sidecars := bc.GetSidecarsByHash(block.Hash()); sidecars != nil {
+					block = block.WithSidecars(sidecars)
+				}
 				receipts := bc.GetReceiptsByHash(block.Hash())
 				if receipts == nil {
 					return fmt.Errorf("export failed on #%d: receipts not found", n)
@@ -488,6 +500,116 @@ func ExportHistory(bc *core.BlockChain, dir string, first, last, step uint64) er
 	return nil
 }
 
+// ExportBlobs exports the blob sidecars of the canonical blocks [first, last]
+// into archive files of step blocks each, in the specified directory.
+func ExportBlobs(db ethdb.Database, dir string, first, last, step uint64, interrupt chan struct{}) error {
+	log.Info("Exporting blob sidecars", "dir", dir, "first", first, "last", last)
+
+	var (
+		start    = time.Now()
+		reported = time.Now()
+		exported int
+	)
+	for i := first; i <= last; i += step {
+		end := min(i+step-1, last)
+
+		var entries []*rawdb.BlobArchiveEntry
+		for n := i; n <= end; n++ {
+			hash := rawdb.ReadCanonicalHash(db, n)
+			if hash == (common.Hash{}) {
+				return fmt.Errorf("export failed on #%d: canonical hash not found", n)
+			}
+			data := rawdb.ReadBlobSidecarsRLP(db, hash, n)
+			if len(data) == 0 || bytes.Equal(data, rlp.EmptyList) {
+				continue
+			}
+			entries = append(entries, &rawdb.BlobArchiveEntry{Number: n, Hash: hash, Sidecars: data})
+		}
+		if len(entries) > 0 {
+			if _, err := rawdb.WriteBlobArchive(dir, i, end, entries); err != nil {
+				return err
+			}
+			exported += len(entries)
+		}
+		select {
+		case <-interrupt:
+			return errors.New("interrupted")
+		default:
+		}
+		if time.Since(reported) >= 8*time.Second {
+			log.Info("Exporting blob sidecars", "number", end, "blocks", exported, "elapsed", common.PrettyDuration(time.Since(start)))
+			reported = time.Now()
+		}
+		if end == last {
+			break
+		}
+	}
+	log.Info("Exported blob sidecars", "dir", dir, "blocks", exported, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// ImportBlobs restores the blob sidecars from all archive files in the given
+// directory into the key-value store. Sidecars of blocks which are no longer
+// canonical or which are still stored locally are skipped.
+func ImportBlobs(db ethdb.Database, dir string, interrupt chan struct{}) error {
+	files, err := rawdb.BlobArchiveFiles(dir)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no blob archives found in %s", dir)
+	}
+	log.Info("Importing blob sidecars", "dir", dir, "files", len(files))
+
+	var (
+		start                       = time.Now()
+		batch                       = db.BlockStore().NewBatch()
+		imported, present, unlinked int
+	)
+	for _, file := range files {
+		err := rawdb.ReadBlobArchive(file, func(entry *rawdb.BlobArchiveEntry) error {
+			if rawdb.ReadCanonicalHash(db, entry.Number) != entry.Hash {
+				unlinked++
+				return nil
+			}
+			if len(rawdb.ReadBlobSidecarsRLP(db, entry.Hash, entry.Number)) > 0 {
+				present++
+				return nil
+			}
+			var sidecars types.BlobSidecars
+			if err := rlp.DecodeBytes(entry.Sidecars, &sidecars); err != nil {
+				return fmt.Errorf("invalid blob sidecars of block %d: %v", entry.Number, err)
+			}
+			rawdb.WriteBlobSidecars(batch, entry.Hash, entry.Number, sidecars)
+			imported++
+
+			if batch.ValueSize() > ethdb.IdealBatchSize {
+				if err := batch.Write(); err != nil {
+					return err
+				}
+				batch.Reset()
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		select {
+		case <-interrupt:
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			return errors.New("interrupted")
+		default:
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	log.Info("Imported blob sidecars", "imported", imported, "present", present, "noncanonical", unlinked, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
 // ImportPreimages imports a batch of exported hash preimages into the database.
 // It's a part of the deprecated functionality, should be removed in the future.
 func ImportPreimages(db ethdb.Database, fn string) error {
@@ -864,3 +986,99 @@ func ExportChaindata(fn string, kind string, iter ChainDataIterator, interrupt c
 		"elapsed", common.PrettyDuration(time.Since(start)))
 	return nil
 }
+
+// SyncDir mirrors the directory src into dst, copying the files which are
+// missing or differ in size or modification time, and deleting the ones src
+// doesn't have. Files are replaced atomically, an interrupted sync is resumed
+// by simply running it again.
+func SyncDir(src, dst string, interrupt chan struct{}) error {
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(src)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	present := make(map[string]bool)
+	for _, entry := range entries {
+		select {
+		case <-interrupt:
+			return errors.New("interrupted")
+		default:
+		}
+		var (
+			name    = entry.Name()
+			srcPath = filepath.Join(src, name)
+			dstPath = filepath.Join(dst, name)
+		)
+		present[name] = true
+		if entry.IsDir() {
+			if err := SyncDir(srcPath, dstPath, interrupt); err != nil {
+				return err
+			}
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			continue
+		}
+		if have, err := os.Stat(dstPath); err == nil && have.Size() == info.Size() && have.ModTime().Equal(info.ModTime()) {
+			continue
+		}
+		if err := syncFile(srcPath, dstPath, info); err != nil {
+			return err
+		}
+		log.Debug("Copied file", "src", srcPath, "dst", dstPath, "size", common.StorageSize(info.Size()))
+	}
+	// Drop everything which is gone from the source
+	entries, err = os.ReadDir(dst)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if !present[entry.Name()] {
+			if err := os.RemoveAll(filepath.Join(dst, entry.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// syncFile copies the file src into dst through a temporary file, carrying
+// over the modification time.
+func syncFile(src, dst string, info os.FileInfo) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.CreateTemp(filepath.Dir(dst), filepath.Base(dst)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(out.Name())
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(out.Name(), info.Mode().Perm()); err != nil {
+		return err
+	}
+	if err := os.Chtimes(out.Name(), info.ModTime(), info.ModTime()); err != nil {
+		return err
+	}
+	return os.Rename(out.Name(), dst)
+}
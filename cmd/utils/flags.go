@@ -123,6 +123,21 @@ var (
 		Usage:    "Enable 5000 blocks limit for range query",
 		Category: flags.APICategory,
 	}
+	DBWriteAPIFlag = &cli.BoolFlag{
+		Name:     "rpc.dbwrite",
+		Usage:    "Enable the database write API (dbwrite namespace) on the authenticated RPC endpoint (--authrpc.*)",
+		Category: flags.APICategory,
+	}
+	ArrivalsFlag = &cli.BoolFlag{
+		Name:     "arrivals",
+		Usage:    "Record when blocks and votes are first seen on the network (streamed via eth_subscribe(\"arrivals\"))",
+		Category: flags.MetricsCategory,
+	}
+	ArrivalsFileFlag = &cli.StringFlag{
+		Name:     "arrivals.file",
+		Usage:    "JSON lines file to append the recorded block and vote arrivals to (implies --arrivals)",
+		Category: flags.MetricsCategory,
+	}
 	DiffFlag = flags.DirectoryFlag{
 		Name:     "datadir.diff",
 		Usage:    "Data directory for difflayer segments (default = inside chaindata)",
@@ -133,6 +148,17 @@ var (
 		Usage:    "URL for remote database",
 		Category: flags.LoggingCategory,
 	}
+	RemoteDBWriteFlag = &cli.StringFlag{
+		Name:     "remotedb.write",
+		Usage:    "URL of the authenticated RPC endpoint (--authrpc.*) of the remote database node to write the database via",
+		Category: flags.APICategory,
+	}
+	RemoteDBJWTSecretFlag = &cli.StringFlag{
+		Name:      "remotedb.jwtsecret",
+		Usage:     "Path to the JWT secret of the authenticated RPC endpoint given by --remotedb.write",
+		TakesFile: true,
+		Category:  flags.APICategory,
+	}
 	DBEngineFlag = &cli.StringFlag{
 		Name:     "db.engine",
 		Usage:    "Backing database implementation to use ('pebble' or 'leveldb')",
@@ -375,18 +401,52 @@ var (
 		Value:    false,
 		Category: flags.StateCategory,
 	}
+	JournalCheckpointFlag = &cli.Uint64Flag{
+		Name:     "journal.checkpoint",
+		Usage:    "Number of blocks between incremental checkpoints of the in-memory pbss layers, kept to survive an unclean shutdown (0 = disabled)",
+		Value:    ethconfig.Defaults.JournalCheckpoint,
+		Category: flags.StateCategory,
+	}
 	StateHistoryFlag = &cli.Uint64Flag{
 		Name:     "history.state",
 		Usage:    "Number of recent blocks to retain state history for (default = 90,000 blocks, 0 = entire chain)",
 		Value:    ethconfig.Defaults.StateHistory,
 		Category: flags.StateCategory,
 	}
+	HistoricStateDepthFlag = &cli.Uint64Flag{
+		Name:     "history.statedepth",
+		Usage:    "Maximum number of state histories aggregated to serve a historical state in pbss, older states are rejected (0 = no limit)",
+		Value:    ethconfig.Defaults.HistoricStateDepth,
+		Category: flags.StateCategory,
+	}
 	TransactionHistoryFlag = &cli.Uint64Flag{
 		Name:     "history.transactions",
 		Usage:    "Number of recent blocks to maintain transactions index for (default = about one year, 0 = entire chain)",
 		Value:    ethconfig.Defaults.TransactionHistory,
 		Category: flags.StateCategory,
 	}
+	BlockHistoryFlag = &cli.Uint64Flag{
+		Name:     "history.blocks",
+		Usage:    "Number of recent blocks to keep headers, bodies and receipts for, older ones are pruned while running (default = 0, entire chain)",
+		Category: flags.BlockHistoryCategory,
+	}
+	ColdHistoryDirFlag = &flags.DirectoryFlag{
+		Name:     "history.cold.dir",
+		Usage:    "Directory of the object store that old ancient segments are offloaded to (default = disabled)",
+		Category: flags.BlockHistoryCategory,
+	}
+	ColdHistoryAgeFlag = &cli.Uint64Flag{
+		Name:     "history.cold.age",
+		Usage:    "Number of recent blocks whose ancient data is always kept locally, older segments are offloaded",
+		Value:    ethconfig.Defaults.ColdHistoryAge,
+		Category: flags.BlockHistoryCategory,
+	}
+	ColdHistoryCacheFlag = &cli.IntFlag{
+		Name:     "history.cold.cache",
+		Usage:    "Memory allowance (MB) to use for caching offloaded ancient segments",
+		Value:    ethconfig.Defaults.ColdHistoryCache,
+		Category: flags.BlockHistoryCategory,
+	}
 	// Beacon client light sync settings
 	BeaconApiFlag = &cli.StringSliceFlag{
 		Name:     "beacon.api",
@@ -504,6 +564,12 @@ var (
 		Value:    ethconfig.Defaults.TxPool.OverflowPoolSlots,
 		Category: flags.TxPoolCategory,
 	}
+	TxPoolOverflowPoolDirFlag = &cli.StringFlag{
+		Name:     "txpool.overflowpooldir",
+		Usage:    "Directory to persist the overflow pool in across restarts (kept in memory only if empty)",
+		Value:    ethconfig.Defaults.TxPool.OverflowPoolDir,
+		Category: flags.TxPoolCategory,
+	}
 	TxPoolLifetimeFlag = &cli.DurationFlag{
 		Name:     "txpool.lifetime",
 		Usage:    "Maximum amount of time non-executable transaction are queued",
@@ -516,6 +582,52 @@ var (
 		Value:    ethconfig.Defaults.TxPool.ReannounceTime,
 		Category: flags.TxPoolCategory,
 	}
+	TxPoolSenderRateLimitFlag = &cli.Float64Flag{
+		Name:     "txpool.senderratelimit",
+		Usage:    "Transactions per second accepted from a single sender (0 = unlimited)",
+		Value:    ethconfig.Defaults.TxPool.SenderRateLimit,
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolSenderRateBurstFlag = &cli.Uint64Flag{
+		Name:     "txpool.senderrateburst",
+		Usage:    "Maximum burst of transactions accepted from a single sender",
+		Value:    ethconfig.Defaults.TxPool.SenderRateBurst,
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolContractRateLimitFlag = &cli.Float64Flag{
+		Name:     "txpool.contractratelimit",
+		Usage:    "Transactions per second accepted to a single contract (0 = unlimited)",
+		Value:    ethconfig.Defaults.TxPool.ContractRateLimit,
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolContractRateBurstFlag = &cli.Uint64Flag{
+		Name:     "txpool.contractrateburst",
+		Usage:    "Maximum burst of transactions accepted to a single contract",
+		Value:    ethconfig.Defaults.TxPool.ContractRateBurst,
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolPeerRateLimitFlag = &cli.Float64Flag{
+		Name:     "txpool.peerratelimit",
+		Usage:    "Transactions per second accepted from a single peer (0 = unlimited)",
+		Value:    ethconfig.Defaults.TxPool.PeerRateLimit,
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolPeerRateBurstFlag = &cli.Uint64Flag{
+		Name:     "txpool.peerrateburst",
+		Usage:    "Maximum burst of transactions accepted from a single peer",
+		Value:    ethconfig.Defaults.TxPool.PeerRateBurst,
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolBlocklistFlag = &cli.StringFlag{
+		Name:     "txpool.blocklist",
+		Usage:    "File of addresses, one per line, barred from sending, receiving or delegating pooled transactions (reloaded when changed)",
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolSelectorFilterFlag = &cli.StringFlag{
+		Name:     "txpool.selectorfilter",
+		Usage:    "File of 4 byte function selectors, one per line, barred from being called by pooled transactions (reloaded when changed)",
+		Category: flags.TxPoolCategory,
+	}
 	// Blob transaction pool settings
 	BlobPoolDataDirFlag = &cli.StringFlag{
 		Name:     "blobpool.datadir",
@@ -1240,6 +1352,26 @@ Please note that --` + MetricsHTTPFlag.Name + ` must be set to start the server.
 		Value:    params.DefaultExtraReserveForBlobRequests,
 		Category: flags.MiscCategory,
 	}
+	BlobKeepAllFlag = &cli.BoolFlag{
+		Name:     "blob.keep-all",
+		Usage:    "Keep all blob sidecars, never pruning them (same as --blob.extra-reserve 0)",
+		Category: flags.MiscCategory,
+	}
+	BlobKeepAddressesFlag = &cli.StringFlag{
+		Name:     "blob.keep-addresses",
+		Usage:    "Comma separated addresses whose blob transactions' sidecars are kept after pruning",
+		Category: flags.MiscCategory,
+	}
+	BlobKeepBlocksFlag = &cli.Uint64Flag{
+		Name:     "blob.keep-blocks",
+		Usage:    "Number of recent blocks whose sidecars kept by --blob.keep-addresses are retained (0 = as long as the block)",
+		Category: flags.MiscCategory,
+	}
+	BlobArchiveDirFlag = &flags.DirectoryFlag{
+		Name:     "blob.archive",
+		Usage:    "Directory to export blob sidecars to before pruning them, in files of 16384 blocks (default = disabled)",
+		Category: flags.MiscCategory,
+	}
 
 	// Fake beacon
 	FakeBeaconEnabledFlag = &cli.BoolFlag{
@@ -1274,6 +1406,8 @@ var (
 		DataDirFlag,
 		AncientFlag,
 		RemoteDBFlag,
+		RemoteDBWriteFlag,
+		RemoteDBJWTSecretFlag,
 		DBEngineFlag,
 		StateSchemeFlag,
 		HttpHeaderFlag,
@@ -1877,12 +2011,39 @@ func setTxPool(ctx *cli.Context, cfg *legacypool.Config) {
 	if ctx.IsSet(TxPoolOverflowPoolSlotsFlag.Name) {
 		cfg.OverflowPoolSlots = ctx.Uint64(TxPoolOverflowPoolSlotsFlag.Name)
 	}
+	if ctx.IsSet(TxPoolOverflowPoolDirFlag.Name) {
+		cfg.OverflowPoolDir = ctx.String(TxPoolOverflowPoolDirFlag.Name)
+	}
 	if ctx.IsSet(TxPoolLifetimeFlag.Name) {
 		cfg.Lifetime = ctx.Duration(TxPoolLifetimeFlag.Name)
 	}
 	if ctx.IsSet(TxPoolReannounceTimeFlag.Name) {
 		cfg.ReannounceTime = ctx.Duration(TxPoolReannounceTimeFlag.Name)
 	}
+	if ctx.IsSet(TxPoolSenderRateLimitFlag.Name) {
+		cfg.SenderRateLimit = ctx.Float64(TxPoolSenderRateLimitFlag.Name)
+	}
+	if ctx.IsSet(TxPoolSenderRateBurstFlag.Name) {
+		cfg.SenderRateBurst = ctx.Uint64(TxPoolSenderRateBurstFlag.Name)
+	}
+	if ctx.IsSet(TxPoolContractRateLimitFlag.Name) {
+		cfg.ContractRateLimit = ctx.Float64(TxPoolContractRateLimitFlag.Name)
+	}
+	if ctx.IsSet(TxPoolContractRateBurstFlag.Name) {
+		cfg.ContractRateBurst = ctx.Uint64(TxPoolContractRateBurstFlag.Name)
+	}
+	if ctx.IsSet(TxPoolPeerRateLimitFlag.Name) {
+		cfg.PeerRateLimit = ctx.Float64(TxPoolPeerRateLimitFlag.Name)
+	}
+	if ctx.IsSet(TxPoolPeerRateBurstFlag.Name) {
+		cfg.PeerRateBurst = ctx.Uint64(TxPoolPeerRateBurstFlag.Name)
+	}
+	if ctx.IsSet(TxPoolBlocklistFlag.Name) {
+		cfg.Blocklist = ctx.String(TxPoolBlocklistFlag.Name)
+	}
+	if ctx.IsSet(TxPoolSelectorFilterFlag.Name) {
+		cfg.SelectorFilter = ctx.String(TxPoolSelectorFilterFlag.Name)
+	}
 }
 
 func setBlobPool(ctx *cli.Context, cfg *blobpool.Config) {
@@ -2002,6 +2163,11 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	// Avoid conflicting network flags
 	CheckExclusive(ctx, BSCMainnetFlag, DeveloperFlag)
 	CheckExclusive(ctx, DeveloperFlag, ExternalSignerFlag) // Can't use both ephemeral unlocked and external signer
+	// Pruned ancients can't be truncated
+	CheckExclusive(ctx, PruneAncientDataFlag, BlockHistoryFlag)
+	// Offloaded ancients must stay available
+	CheckExclusive(ctx, PruneAncientDataFlag, ColdHistoryDirFlag)
+	CheckExclusive(ctx, BlockHistoryFlag, ColdHistoryDirFlag)
 
 	// Set configurations from CLI flags
 	setEtherbase(ctx, cfg)
@@ -2083,6 +2249,16 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.IsSet(RangeLimitFlag.Name) {
 		cfg.RangeLimit = ctx.Bool(RangeLimitFlag.Name)
 	}
+	if ctx.IsSet(DBWriteAPIFlag.Name) {
+		cfg.DBWriteAPI = ctx.Bool(DBWriteAPIFlag.Name)
+	}
+	if ctx.IsSet(ArrivalsFlag.Name) {
+		cfg.Arrivals.Enabled = ctx.Bool(ArrivalsFlag.Name)
+	}
+	if ctx.IsSet(ArrivalsFileFlag.Name) {
+		cfg.Arrivals.Enabled = true
+		cfg.Arrivals.File = ctx.String(ArrivalsFileFlag.Name)
+	}
 	if ctx.IsSet(CacheNoPrefetchFlag.Name) {
 		cfg.NoPrefetch = ctx.Bool(CacheNoPrefetchFlag.Name)
 	}
@@ -2095,6 +2271,9 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.IsSet(StateHistoryFlag.Name) {
 		cfg.StateHistory = ctx.Uint64(StateHistoryFlag.Name)
 	}
+	if ctx.IsSet(HistoricStateDepthFlag.Name) {
+		cfg.HistoricStateDepth = ctx.Uint64(HistoricStateDepthFlag.Name)
+	}
 	scheme, err := ParseCLIAndConfigStateScheme(ctx.String(StateSchemeFlag.Name), cfg.StateScheme)
 	if err != nil {
 		Fatalf("%v", err)
@@ -2112,12 +2291,27 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 		log.Warn("The flag --txlookuplimit is deprecated and will be removed, please use --history.transactions")
 		cfg.TransactionHistory = ctx.Uint64(TxLookupLimitFlag.Name)
 	}
+	if ctx.IsSet(BlockHistoryFlag.Name) {
+		cfg.BlockHistory = ctx.Uint64(BlockHistoryFlag.Name)
+	}
+	if ctx.IsSet(ColdHistoryDirFlag.Name) {
+		cfg.ColdHistoryDir = ctx.String(ColdHistoryDirFlag.Name)
+	}
+	if ctx.IsSet(ColdHistoryAgeFlag.Name) {
+		cfg.ColdHistoryAge = ctx.Uint64(ColdHistoryAgeFlag.Name)
+	}
+	if ctx.IsSet(ColdHistoryCacheFlag.Name) {
+		cfg.ColdHistoryCache = ctx.Int(ColdHistoryCacheFlag.Name)
+	}
 	if ctx.IsSet(PathDBSyncFlag.Name) {
 		cfg.PathSyncFlush = true
 	}
 	if ctx.IsSet(JournalFileFlag.Name) {
 		cfg.JournalFileEnabled = true
 	}
+	if ctx.IsSet(JournalCheckpointFlag.Name) {
+		cfg.JournalCheckpoint = ctx.Uint64(JournalCheckpointFlag.Name)
+	}
 
 	if ctx.String(GCModeFlag.Name) == "archive" && cfg.TransactionHistory != 0 {
 		cfg.TransactionHistory = 0
@@ -2326,6 +2520,24 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 		}
 		cfg.BlobExtraReserve = extraReserve
 	}
+	if ctx.Bool(BlobKeepAllFlag.Name) {
+		cfg.BlobExtraReserve = 0
+	}
+	if ctx.IsSet(BlobKeepAddressesFlag.Name) {
+		for _, account := range strings.Split(ctx.String(BlobKeepAddressesFlag.Name), ",") {
+			if trimmed := strings.TrimSpace(account); !common.IsHexAddress(trimmed) {
+				Fatalf("Invalid account in --blob.keep-addresses: %s", trimmed)
+			} else {
+				cfg.BlobKeepAddresses = append(cfg.BlobKeepAddresses, common.HexToAddress(trimmed))
+			}
+		}
+	}
+	if ctx.IsSet(BlobKeepBlocksFlag.Name) {
+		cfg.BlobKeepBlocks = ctx.Uint64(BlobKeepBlocksFlag.Name)
+	}
+	if ctx.IsSet(BlobArchiveDirFlag.Name) {
+		cfg.BlobArchiveDir = ctx.String(BlobArchiveDirFlag.Name)
+	}
 	// VM tracing config.
 	if ctx.IsSet(VMTraceFlag.Name) {
 		if name := ctx.String(VMTraceFlag.Name); name != "" {
@@ -2477,7 +2689,7 @@ func parseDBFeatures(cfg *ethconfig.Config, stack *node.Node) string {
 	} else if cfg.StateScheme == rawdb.HashScheme {
 		features = append(features, "HBSS")
 	}
-	if stack.CheckIfMultiDataBase() {
+	if stack.CheckIfMultiDataBase() || stack.CheckIfSeparateBlockStore() {
 		features = append(features, "MultiDB")
 	}
 	if cfg.PruneAncientData {
@@ -2584,18 +2796,17 @@ func MakeChainDatabase(ctx *cli.Context, stack *node.Node, readonly, disableFree
 	)
 	switch {
 	case ctx.IsSet(RemoteDBFlag.Name):
-		log.Info("Using remote db", "url", ctx.String(RemoteDBFlag.Name), "headers", len(ctx.StringSlice(HttpHeaderFlag.Name)))
-		client, err := DialRPCWithHeaders(ctx.String(RemoteDBFlag.Name), ctx.StringSlice(HttpHeaderFlag.Name))
-		if err != nil {
-			break
-		}
-		chainDb = remotedb.New(client)
+		log.Info("Using remote db", "url", ctx.String(RemoteDBFlag.Name), "write", ctx.String(RemoteDBWriteFlag.Name), "headers", len(ctx.StringSlice(HttpHeaderFlag.Name)))
+		chainDb, err = dialRemoteDB(ctx)
 	default:
 		chainDb, err = stack.OpenDatabaseWithFreezer("chaindata", cache, handles, ctx.String(AncientFlag.Name), "", readonly, disableFreeze, false, false)
 		// set the separate state database
 		if stack.CheckIfMultiDataBase() && err == nil {
 			stateDiskDb := MakeStateDataBase(ctx, stack, readonly, false)
 			chainDb.SetStateStore(stateDiskDb)
+		}
+		// set the separate block database
+		if stack.CheckIfSeparateBlockStore() && err == nil {
 			blockDb := MakeBlockDatabase(ctx, stack, readonly, false)
 			chainDb.SetBlockStore(blockDb)
 		}
@@ -2625,6 +2836,9 @@ func MakeBlockDatabase(ctx *cli.Context, stack *node.Node, readonly, disableFree
 	if err != nil {
 		Fatalf("Failed to open separate block database: %v", err)
 	}
+	if err := stack.CheckBlockStore(blockDb); err != nil {
+		Fatalf("Failed to open separate block database: %v", err)
+	}
 	return blockDb
 }
 
@@ -2658,7 +2872,7 @@ func IsNetworkPreset(ctx *cli.Context) bool {
 	return false
 }
 
-func DialRPCWithHeaders(endpoint string, headers []string) (*rpc.Client, error) {
+func DialRPCWithHeaders(endpoint string, headers []string, opts ...rpc.ClientOption) (*rpc.Client, error) {
 	if endpoint == "" {
 		return nil, errors.New("endpoint must be specified")
 	}
@@ -2667,7 +2881,6 @@ func DialRPCWithHeaders(endpoint string, headers []string) (*rpc.Client, error)
 		// these prefixes.
 		endpoint = endpoint[4:]
 	}
-	var opts []rpc.ClientOption
 	if len(headers) > 0 {
 		customHeaders := make(http.Header)
 		for _, h := range headers {
@@ -2682,6 +2895,51 @@ func DialRPCWithHeaders(endpoint string, headers []string) (*rpc.Client, error)
 	return rpc.DialOptions(context.Background(), endpoint, opts...)
 }
 
+// dialRemoteDB connects to the remote database given by the flags. The database
+// is read via the --remotedb endpoint, and written via the authenticated one given
+// by --remotedb.write if any, as the remote node serves the writes there only.
+func dialRemoteDB(ctx *cli.Context) (ethdb.Database, error) {
+	if ctx.IsSet(RemoteDBJWTSecretFlag.Name) && !ctx.IsSet(RemoteDBWriteFlag.Name) {
+		return nil, fmt.Errorf("--%s requires --%s", RemoteDBJWTSecretFlag.Name, RemoteDBWriteFlag.Name)
+	}
+	headers := ctx.StringSlice(HttpHeaderFlag.Name)
+	client, err := DialRPCWithHeaders(ctx.String(RemoteDBFlag.Name), headers)
+	if err != nil {
+		return nil, err
+	}
+	if !ctx.IsSet(RemoteDBWriteFlag.Name) {
+		return remotedb.New(client), nil
+	}
+	var opts []rpc.ClientOption
+	if ctx.IsSet(RemoteDBJWTSecretFlag.Name) {
+		secret, err := readJWTSecret(ctx.String(RemoteDBJWTSecretFlag.Name))
+		if err != nil {
+			client.Close()
+			return nil, err
+		}
+		opts = append(opts, rpc.WithHTTPAuth(node.NewJWTAuth([32]byte(secret))))
+	}
+	writer, err := DialRPCWithHeaders(ctx.String(RemoteDBWriteFlag.Name), headers, opts...)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	return remotedb.NewWithWriter(client, writer), nil
+}
+
+// readJWTSecret loads the hex-encoded JWT secret from the given file.
+func readJWTSecret(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	secret := common.FromHex(strings.TrimSpace(string(data)))
+	if len(secret) != 32 {
+		return nil, fmt.Errorf("invalid JWT secret in %s", path)
+	}
+	return secret, nil
+}
+
 func MakeGenesis(ctx *cli.Context) *core.Genesis {
 	var genesis *core.Genesis
 	switch {
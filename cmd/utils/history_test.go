@@ -181,4 +181,76 @@ func TestHistoryImportAndExport(t *testing.T) {
 	if have, want := imported.CurrentHeader(), chain.CurrentHeader(); have.Hash() != want.Hash() {
 		t.Fatalf("imported chain does not match expected, have (%d, %s) want (%d, %s)", have.Number, have.Hash(), want.Number, want.Hash())
 	}
+
+	// Headers failing consensus verification must abort the import.
+	db3, err := rawdb.NewDatabaseWithFreezer(rawdb.NewMemoryDatabase(), "", "", false, false, false, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db3.Close()
+	})
+	genesis.MustCommit(db3, triedb.NewDatabase(db3, triedb.HashDefaults))
+	rejecting, err := core.NewBlockChain(db3, nil, genesis, nil, ethash.NewFakeFailer(count/2), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("unable to initialize chain: %v", err)
+	}
+	if err := ImportHistory(rejecting, db3, dir, "mainnet"); err == nil {
+		t.Fatal("import of unverifiable header succeeded")
+	}
+	if head := rejecting.CurrentHeader().Number.Uint64(); head >= count/2 {
+		t.Fatalf("rejected header imported: head %d", head)
+	}
+}
+
+func TestBlobsExportAndImport(t *testing.T) {
+	var (
+		db     = rawdb.NewMemoryDatabase()
+		dir    = t.TempDir()
+		hashes []common.Hash
+		want   = make(map[uint64][]byte)
+	)
+	for i := uint64(0); i < 10; i++ {
+		hash := common.Hash{byte(i + 1)}
+		rawdb.WriteCanonicalHash(db, hash, i)
+		hashes = append(hashes, hash)
+
+		// Only every third block carries blobs.
+		if i%3 == 0 {
+			sidecars := types.BlobSidecars{{BlockNumber: new(big.Int).SetUint64(i), BlockHash: hash, TxHash: common.Hash{byte(i)}}}
+			rawdb.WriteBlobSidecars(db, hash, i, sidecars)
+			want[i] = rawdb.ReadBlobSidecarsRLP(db, hash, i)
+		}
+	}
+	if err := ExportBlobs(db, dir, 0, 9, 4, nil); err != nil {
+		t.Fatal(err)
+	}
+	files, err := rawdb.BlobArchiveFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("wrong number of archives: have %d, want 3", len(files))
+	}
+	// Drop the sidecars and reorg one of the blocks away before restoring.
+	for number := range want {
+		rawdb.DeleteBlobSidecars(db, hashes[number], number)
+	}
+	rawdb.WriteCanonicalHash(db, common.Hash{0xff}, 6)
+
+	if err := ImportBlobs(db, dir, nil); err != nil {
+		t.Fatal(err)
+	}
+	for number, blob := range want {
+		have := rawdb.ReadBlobSidecarsRLP(db, hashes[number], number)
+		if number == 6 {
+			if len(have) != 0 {
+				t.Errorf("block %d: non-canonical sidecars imported", number)
+			}
+			continue
+		}
+		if !bytes.Equal(have, blob) {
+			t.Errorf("block %d: sidecars mismatch", number)
+		}
+	}
 }
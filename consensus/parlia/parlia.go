@@ -220,6 +220,29 @@ func ecrecover(header *types.Header, sigCache *lru.ARCCache, chainId *big.Int) (
 	return signer, nil
 }
 
+// VerifySealSigner checks that the header carries a valid seal produced by its
+// coinbase. Unlike VerifySeal it doesn't check the signer against the validator
+// set, so it can be used on archived headers without the chain they belong to.
+func VerifySealSigner(header *types.Header, chainId *big.Int) error {
+	if header.Number.Sign() == 0 {
+		return errUnknownBlock
+	}
+	if len(header.Extra) < extraSeal {
+		return errMissingSignature
+	}
+	signature := header.Extra[len(header.Extra)-extraSeal:]
+	pubkey, err := crypto.Ecrecover(types.SealHash(header, chainId).Bytes(), signature)
+	if err != nil {
+		return err
+	}
+	var signer common.Address
+	copy(signer[:], crypto.Keccak256(pubkey[1:])[12:])
+	if signer != header.Coinbase {
+		return errCoinBaseMisMatch
+	}
+	return nil
+}
+
 // ParliaRLP returns the rlp bytes which needs to be signed for the parlia
 // sealing. The RLP to sign consists of the entire header apart from the 65 byte signature
 // contained at the end of the extra data.
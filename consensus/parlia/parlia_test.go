@@ -858,3 +858,37 @@ func (c *mockParlia) FinalizeAndAssemble(chain consensus.ChainHeaderReader, head
 func (c *mockParlia) CalcDifficulty(chain consensus.ChainHeaderReader, time uint64, parent *types.Header) *big.Int {
 	return big.NewInt(1)
 }
+
+func TestVerifySealSigner(t *testing.T) {
+	var (
+		key, _  = crypto.GenerateKey()
+		chainId = params.ChapelChainConfig.ChainID
+		header  = &types.Header{
+			Number:   big.NewInt(10),
+			Coinbase: crypto.PubkeyToAddress(key.PublicKey),
+			Extra:    make([]byte, extraVanity+extraSeal),
+		}
+	)
+	sig, err := crypto.Sign(types.SealHash(header, chainId).Bytes(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	copy(header.Extra[len(header.Extra)-extraSeal:], sig)
+	if err := VerifySealSigner(header, chainId); err != nil {
+		t.Fatalf("valid seal rejected: %v", err)
+	}
+	// A seal of another chain or signer is rejected.
+	if err := VerifySealSigner(header, params.BSCChainConfig.ChainID); err == nil {
+		t.Fatal("seal of another chain accepted")
+	}
+	other := types.CopyHeader(header)
+	other.Coinbase = common.Address{0x01}
+	if err := VerifySealSigner(other, chainId); err != errCoinBaseMisMatch {
+		t.Fatalf("wrong error for foreign coinbase: have %v, want %v", err, errCoinBaseMisMatch)
+	}
+	other = types.CopyHeader(header)
+	other.Extra = other.Extra[:extraVanity]
+	if err := VerifySealSigner(other, chainId); err != errMissingSignature {
+		t.Fatalf("wrong error for missing seal: have %v, want %v", err, errMissingSignature)
+	}
+}
@@ -173,10 +173,12 @@ type CacheConfig struct {
 	TriesInMemory       uint64        // How many tries keeps in memory
 	NoTries             bool          // Insecure settings. Do not have any tries in databases if enabled.
 	StateHistory        uint64        // Number of blocks from head whose state histories are reserved.
+	HistoricStateDepth  uint64        // Maximum number of state histories aggregated to serve a historical state (0 = no limit)
 	StateScheme         string        // Scheme used to store ethereum states and merkle tree nodes on top
 	PathSyncFlush       bool          // Whether sync flush the trienodebuffer of pathdb to disk.
 	JournalFilePath     string
 	JournalFile         bool
+	JournalCheckpoint   uint64 // Number of blocks between incremental checkpoints of the pathdb layers
 
 	SnapshotNoBuild bool // Whether the background generation is allowed
 	SnapshotWait    bool // Wait for snapshot construction on startup. TODO(karalabe): This is a dirty hack for testing, nuke it
@@ -203,6 +205,9 @@ func (c *CacheConfig) triedbConfig(isVerkle bool) *triedb.Config {
 			WriteBufferSize: c.TrieDirtyLimit * 1024 * 1024,
 			JournalFilePath: c.JournalFilePath,
 			JournalFile:     c.JournalFile,
+
+			CheckpointInterval: c.JournalCheckpoint,
+			HistoricStateDepth: c.HistoricStateDepth,
 		}
 	}
 	return config
@@ -17,6 +17,7 @@
 package core
 
 import (
+	"context"
 	"errors"
 	"math/big"
 
@@ -121,6 +122,23 @@ func (bc *BlockChain) GetHeadersFrom(number, count uint64) []rlp.RawValue {
 	return bc.hc.GetHeadersFrom(number, count)
 }
 
+// GetBlockNumber retrieves the block number belonging to the given hash from
+// the database. It's still known for blocks whose history was pruned.
+func (bc *BlockChain) GetBlockNumber(hash common.Hash) *uint64 {
+	return bc.hc.GetBlockNumber(hash)
+}
+
+// HistoryPruningCutoff returns the number of the oldest block whose header,
+// body and receipts are still stored, 0 if no block history was pruned. The
+// genesis block is always retained.
+func (bc *BlockChain) HistoryPruningCutoff() uint64 {
+	tail, err := bc.db.BlockStore().Tail()
+	if err != nil {
+		return 0
+	}
+	return tail
+}
+
 // GetBody retrieves a block body (transactions and uncles) from the database by
 // hash, caching it if found.
 func (bc *BlockChain) GetBody(hash common.Hash) *types.Body {
@@ -432,6 +450,13 @@ func (bc *BlockChain) StateAt(root common.Hash) (*state.StateDB, error) {
 	return stateDb, err
 }
 
+// HistoricState returns a historic state specified by the given root, which
+// is resolved from the state histories. It's only supported in path scheme,
+// and the returned state is only meant for reads.
+func (bc *BlockChain) HistoricState(ctx context.Context, root common.Hash) (*state.StateDB, error) {
+	return state.New(root, state.NewHistoricDatabase(ctx, bc.db, bc.triedb))
+}
+
 // Config retrieves the chain's fork configuration.
 func (bc *BlockChain) Config() *params.ChainConfig { return bc.chainConfig }
 
@@ -4484,3 +4484,63 @@ func TestEIP7702(t *testing.T) {
 		t.Fatalf("addr2 storage wrong: expected %d, got %d", fortyTwo, actual)
 	}
 }
+
+// Tests that the chain freezer prunes the block history outside of the
+// configured window and the blockchain reports the pruning cutoff.
+func TestBlockHistoryPruning(t *testing.T) {
+	var (
+		gspec    = &Genesis{Config: params.TestChainConfig, BaseFee: big.NewInt(params.InitialBaseFee)}
+		engine   = ethash.NewFaker()
+		_, bs, _ = GenerateChainWithGenesis(gspec, engine, 300, nil)
+	)
+	db, err := rawdb.NewDatabaseWithFreezer(rawdb.NewMemoryDatabase(), t.TempDir(), "", false, false, false, false, false)
+	if err != nil {
+		t.Fatalf("Failed to create database with ancient backend: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.SetupFreezerEnv(&ethdb.FreezerEnv{ChainCfg: gspec.Config, BlockHistory: 200}); err != nil {
+		t.Fatalf("Failed to setup freezer env: %v", err)
+	}
+	chain, err := NewBlockChain(db, DefaultCacheConfigWithScheme(rawdb.HashScheme), gspec, nil, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if n, err := chain.InsertChain(bs); err != nil {
+		t.Fatalf("block %d: failed to insert into chain: %v", n, err)
+	}
+	if cutoff := chain.HistoryPruningCutoff(); cutoff != 0 {
+		t.Fatalf("history pruned before freezing: cutoff %d", cutoff)
+	}
+	// Freeze all but the last 16 blocks, the window reaches back further. It
+	// still covers the recent states written out on shutdown.
+	type freezer interface {
+		Freeze(threshold uint64) error
+	}
+	if err := db.(freezer).Freeze(16); err != nil {
+		t.Fatalf("Failed to freeze: %v", err)
+	}
+	want := uint64(300 - 200 + 1)
+	if cutoff := chain.HistoryPruningCutoff(); cutoff != want {
+		t.Fatalf("wrong pruning cutoff: have %d, want %d", cutoff, want)
+	}
+	for _, number := range []uint64{1, want - 1} {
+		if block := chain.GetBlockByNumber(number); block != nil {
+			t.Errorf("block %d not pruned", number)
+		}
+		if receipts := rawdb.ReadRawReceipts(db, bs[number-1].Hash(), number); receipts != nil {
+			t.Errorf("receipts of block %d not pruned", number)
+		}
+	}
+	for _, number := range []uint64{0, want, 300} {
+		if block := chain.GetBlockByNumber(number); block == nil {
+			t.Errorf("block %d missing", number)
+		}
+	}
+	// Pruned blocks are still identified by hash.
+	if number := chain.GetBlockNumber(bs[0].Hash()); number == nil || *number != 1 {
+		t.Errorf("number of pruned block unknown")
+	}
+}
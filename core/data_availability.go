@@ -22,8 +22,10 @@ var (
 	daCheckTimer = metrics.NewRegisteredTimer("chain/dacheck", nil)
 )
 
-// validateBlobSidecar it is same as validateBlobSidecar in core/txpool/validation.go
-func validateBlobSidecar(hashes []common.Hash, sidecar *types.BlobSidecar) error {
+// ValidateBlobSidecar checks the sidecar against the blob hashes of its transaction,
+// verifying the KZG commitments and proofs. It is the same as validateBlobSidecar
+// in core/txpool/validation.go.
+func ValidateBlobSidecar(hashes []common.Hash, sidecar *types.BlobSidecar) error {
 	if len(sidecar.Blobs) != len(hashes) {
 		return fmt.Errorf("invalid number of %d blobs compared to %d blob hashes", len(sidecar.Blobs), len(hashes))
 	}
@@ -121,7 +123,7 @@ func IsDataAvailable(chain consensus.ChainHeaderReader, block *types.Block) (err
 		if sidecars[i].TxIndex != blobTxIndexes[i] {
 			return fmt.Errorf("sidecar's TxIndex mismatch with expected transaction, want: %v, have: %v", sidecars[i].TxIndex, blobTxIndexes[i])
 		}
-		if err := validateBlobSidecar(tx.BlobHashes(), sidecars[i]); err != nil {
+		if err := ValidateBlobSidecar(tx.BlobHashes(), sidecars[i]); err != nil {
 			return err
 		}
 	}
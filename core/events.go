@@ -35,6 +35,13 @@ type RemovedLogsEvent struct{ Logs []*types.Log }
 // NewVoteEvent is posted when a batch of votes enters the vote pool.
 type NewVoteEvent struct{ Vote *types.VoteEnvelope }
 
+// InvalidVoteEvent is posted when a vote is rejected by the vote pool, either
+// because its signature is forged or because it doesn't come from a validator.
+type InvalidVoteEvent struct {
+	Vote         *types.VoteEnvelope
+	BadSignature bool
+}
+
 // FinalizedHeaderEvent is posted when a finalized header is reached.
 type FinalizedHeaderEvent struct{ Header *types.Header }
 
@@ -0,0 +1,42 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package history describes the block history retained by a node.
+package history
+
+import "github.com/ethereum/go-ethereum/common/hexutil"
+
+// PrunedHistoryError is returned when the requested block history was pruned
+// by the node.
+type PrunedHistoryError struct{}
+
+func (e *PrunedHistoryError) Error() string  { return "pruned history unavailable" }
+func (e *PrunedHistoryError) ErrorCode() int { return 4444 }
+
+// Range is an inclusive range of block numbers.
+type Range struct {
+	From hexutil.Uint64 `json:"from"`
+	To   hexutil.Uint64 `json:"to"`
+}
+
+// PrunedRanges returns the ranges of blocks pruned from a chain whose oldest
+// available block, apart from the always retained genesis, is cutoff.
+func PrunedRanges(cutoff uint64) []Range {
+	if cutoff <= 1 {
+		return []Range{}
+	}
+	return []Range{{From: 1, To: hexutil.Uint64(cutoff - 1)}}
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
 )
 
 // ReadPreimage retrieves a single preimage of the provided hash.
@@ -160,6 +161,52 @@ func DeleteTrieJournal(db ethdb.KeyValueWriter) {
 	}
 }
 
+// Hbss2PbssProgress is the checkpoint of an unfinished hash to path scheme
+// conversion of the state trie.
+type Hbss2PbssProgress struct {
+	Root         common.Hash // State root being converted
+	Number       uint64      // Block number of the converted state
+	Done         []byte      // Bitmap of the account trie shards already converted
+	Accounts     uint64      // Number of accounts in the converted shards
+	AccountNodes uint64      // Number of account trie nodes in the converted shards
+	StorageNodes uint64      // Number of storage trie nodes in the converted shards
+}
+
+// ReadHbss2PbssProgress retrieves the checkpoint of an unfinished hash to path
+// scheme conversion, or nil if there is none.
+func ReadHbss2PbssProgress(db ethdb.KeyValueReader) *Hbss2PbssProgress {
+	data, _ := db.Get(hbss2pbssProgressKey)
+	if len(data) == 0 {
+		return nil
+	}
+	var progress Hbss2PbssProgress
+	if err := rlp.DecodeBytes(data, &progress); err != nil {
+		log.Error("Invalid hbss2pbss conversion checkpoint", "err", err)
+		return nil
+	}
+	return &progress
+}
+
+// WriteHbss2PbssProgress stores the checkpoint of a hash to path scheme
+// conversion.
+func WriteHbss2PbssProgress(db ethdb.KeyValueWriter, progress *Hbss2PbssProgress) {
+	data, err := rlp.EncodeToBytes(progress)
+	if err != nil {
+		log.Crit("Failed to encode hbss2pbss conversion checkpoint", "err", err)
+	}
+	if err := db.Put(hbss2pbssProgressKey, data); err != nil {
+		log.Crit("Failed to store hbss2pbss conversion checkpoint", "err", err)
+	}
+}
+
+// DeleteHbss2PbssProgress removes the checkpoint of a finished hash to path
+// scheme conversion.
+func DeleteHbss2PbssProgress(db ethdb.KeyValueWriter) {
+	if err := db.Delete(hbss2pbssProgressKey); err != nil {
+		log.Crit("Failed to delete hbss2pbss conversion checkpoint", "err", err)
+	}
+}
+
 // ReadStateHistoryMeta retrieves the metadata corresponding to the specified
 // state history. Compute the position of state history in freezer by minus
 // one since the id of first state history starts from one(zero for initial
@@ -103,6 +103,9 @@ func inspectFreezers(db ethdb.Database) ([]freezerInfo, error) {
 
 			// TODO(Nathan): handle VerkleStateFreezerName
 			file, err := os.Open(filepath.Join(datadir, MerkleStateFreezerName))
+			if os.IsNotExist(err) {
+				continue // the state freezer is kept in a separate store
+			}
 			if err != nil {
 				return nil, err
 			}
@@ -136,21 +139,21 @@ func inspectFreezers(db ethdb.Database) ([]freezerInfo, error) {
 // ancient indicates the path of root ancient directory where the chain freezer can
 // be opened. Start and end specify the range for dumping out indexes.
 // Note this function can only be used for debugging purposes.
-func InspectFreezerTable(ancient string, freezerName string, tableName string, start, end int64, multiDatabase bool) error {
+func InspectFreezerTable(ancient string, freezerName string, tableName string, start, end int64, separateState, separateBlock bool) error {
 	var (
 		path   string
 		tables map[string]bool
 	)
 	switch freezerName {
 	case ChainFreezerName:
-		if multiDatabase {
+		if separateBlock {
 			path, tables = resolveChainFreezerDir(filepath.Dir(ancient)+"/block/ancient"), chainFreezerNoSnappy
 		} else {
 			path, tables = resolveChainFreezerDir(ancient), chainFreezerNoSnappy
 		}
 
 	case MerkleStateFreezerName, VerkleStateFreezerName:
-		if multiDatabase {
+		if separateState {
 			path, tables = filepath.Join(filepath.Dir(ancient)+"/state/ancient", freezerName), stateFreezerNoSnappy
 		} else {
 			path, tables = filepath.Join(ancient, freezerName), stateFreezerNoSnappy
@@ -0,0 +1,117 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// blobArchiveSegment is the number of blocks covered by a blob archive file.
+// Sidecars are exported and pruned in whole segments, so that the archive
+// doesn't end up as a large number of small files.
+var blobArchiveSegment = uint64(16384)
+
+// BlobArchiveEntry is a single record of a blob archive file, holding the blob
+// sidecars of one block.
+type BlobArchiveEntry struct {
+	Number   uint64
+	Hash     common.Hash
+	Sidecars rlp.RawValue
+}
+
+// blobArchiveName returns the file name of the archive covering the blocks
+// [from, to].
+func blobArchiveName(from, to uint64) string {
+	return fmt.Sprintf("blobs-%010d-%010d.rlp", from, to)
+}
+
+// WriteBlobArchive writes the given entries, which must be ordered by number,
+// into a new archive file in dir covering the blocks [from, to]. The file is
+// written to a temporary location first and moved into place once complete.
+func WriteBlobArchive(dir string, from, to uint64, entries []*BlobArchiveEntry) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, blobArchiveName(from, to))
+	f, err := os.CreateTemp(dir, blobArchiveName(from, to)+".*.tmp")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+
+	w := bufio.NewWriter(f)
+	for _, entry := range entries {
+		if err := rlp.Encode(w, entry); err != nil {
+			f.Close()
+			return "", err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+	return path, os.Rename(f.Name(), path)
+}
+
+// ReadBlobArchive iterates over all entries of an archive file.
+func ReadBlobArchive(path string, fn func(*BlobArchiveEntry) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	stream := rlp.NewStream(bufio.NewReader(f), 0)
+	for {
+		entry := new(BlobArchiveEntry)
+		if err := stream.Decode(entry); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("invalid blob archive %s: %v", path, err)
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+}
+
+// BlobArchiveFiles returns the archive files in dir, ordered by block number.
+func BlobArchiveFiles(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "blobs-*-*.rlp"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
@@ -0,0 +1,262 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+func TestBlobArchivalPolicy(t *testing.T) {
+	var (
+		config  = params.MergedTestChainConfig
+		signer  = types.LatestSigner(config)
+		key1, _ = crypto.GenerateKey()
+		key2, _ = crypto.GenerateKey()
+		watchA  = common.Address{0xaa}
+		otherB  = common.Address{0xbb}
+
+		blocks   []*types.Block
+		receipts []types.Receipts
+	)
+	// Blocks 0-3 are sent by key1, 4-7 by key2. Even blocks go to the watched
+	// address, odd ones to another one.
+	for i := 0; i < 8; i++ {
+		key, to := key1, otherB
+		if i >= 4 {
+			key = key2
+		}
+		if i%2 == 0 {
+			to = watchA
+		}
+		tx := types.MustSignNewTx(key, signer, &types.BlobTx{
+			ChainID:    uint256.MustFromBig(config.ChainID),
+			Nonce:      uint64(i),
+			To:         to,
+			BlobFeeCap: uint256.NewInt(1),
+			BlobHashes: []common.Hash{{0x01}},
+		})
+		header := &types.Header{Number: big.NewInt(int64(i)), Time: uint64(i)}
+		block := types.NewBlockWithHeader(header).WithBody(types.Body{Transactions: types.Transactions{tx}})
+		sidecar := &types.BlobSidecar{
+			BlockNumber: header.Number,
+			BlockHash:   block.Hash(),
+			TxIndex:     0,
+			TxHash:      tx.Hash(),
+		}
+		blocks = append(blocks, block.WithSidecars(types.BlobSidecars{sidecar}))
+		receipts = append(receipts, nil)
+	}
+	db, err := NewDatabaseWithFreezer(NewMemoryDatabase(), t.TempDir(), "", false, true, false, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := WriteAncientBlocksWithBlobs(db, blocks, receipts, big.NewInt(1)); err != nil {
+		t.Fatal(err)
+	}
+	var (
+		frdb = db.(*freezerdb)
+		f    = frdb.AncientStore.(*chainFreezer)
+		dir  = t.TempDir()
+		env  = &ethdb.FreezerEnv{
+			ChainCfg:          config,
+			BlobKeepAddresses: []common.Address{watchA, crypto.PubkeyToAddress(key2.PublicKey)},
+			BlobArchiveDir:    dir,
+		}
+	)
+	want := make([][]byte, len(blocks))
+	for i, block := range blocks {
+		want[i] = ReadBlobSidecarsRLP(db, block.Hash(), block.NumberU64())
+	}
+	if err := f.archiveBlobs(frdb.KeyValueStore, env, 6, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.TruncateTableTail(ChainFreezerBlobSidecarTable, 6); err != nil {
+		t.Fatal(err)
+	}
+	if tail := f.blobTableTail(8); tail != 6 {
+		t.Fatalf("wrong blob table tail: have %d, want 6", tail)
+	}
+	// Sidecars sent to or by the watched addresses are retained.
+	for i, block := range blocks {
+		have := ReadBlobSidecarsRLP(db, block.Hash(), block.NumberU64())
+		kept := i >= 4 || i%2 == 0
+		switch {
+		case kept && !bytes.Equal(have, want[i]):
+			t.Errorf("block %d: sidecars not retained", i)
+		case !kept && len(have) != 0:
+			t.Errorf("block %d: sidecars not pruned", i)
+		}
+	}
+	// All pruned sidecars are exported.
+	files, err := BlobArchiveFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("wrong number of archives: have %d, want 1", len(files))
+	}
+	var number uint64
+	err = ReadBlobArchive(files[0], func(entry *BlobArchiveEntry) error {
+		if entry.Number != number || entry.Hash != blocks[number].Hash() || !bytes.Equal(entry.Sidecars, want[number]) {
+			t.Errorf("wrong archive entry %d", number)
+		}
+		number++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if number != 6 {
+		t.Fatalf("wrong number of archived blocks: have %d, want 6", number)
+	}
+	// Nothing is left to archive below the new tail.
+	if err := f.archiveBlobs(frdb.KeyValueStore, env, 6, true); err != nil {
+		t.Fatal(err)
+	}
+	if files, _ := BlobArchiveFiles(dir); len(files) != 1 {
+		t.Fatalf("archive rewritten: have %d files, want 1", len(files))
+	}
+}
+
+// newBlobTestFreezer creates a freezer holding n blocks, each with a blob
+// transaction sent to the given address.
+func newBlobTestFreezer(t *testing.T, n int, to common.Address) (ethdb.Database, *chainFreezer, []*types.Block) {
+	var (
+		config   = params.MergedTestChainConfig
+		signer   = types.LatestSigner(config)
+		key, _   = crypto.GenerateKey()
+		blocks   []*types.Block
+		receipts []types.Receipts
+	)
+	for i := 0; i < n; i++ {
+		tx := types.MustSignNewTx(key, signer, &types.BlobTx{
+			ChainID:    uint256.MustFromBig(config.ChainID),
+			Nonce:      uint64(i),
+			To:         to,
+			BlobFeeCap: uint256.NewInt(1),
+			BlobHashes: []common.Hash{{0x01}},
+		})
+		header := &types.Header{Number: big.NewInt(int64(i)), Time: uint64(i)}
+		block := types.NewBlockWithHeader(header).WithBody(types.Body{Transactions: types.Transactions{tx}})
+		sidecar := &types.BlobSidecar{BlockNumber: header.Number, BlockHash: block.Hash(), TxHash: tx.Hash()}
+		blocks = append(blocks, block.WithSidecars(types.BlobSidecars{sidecar}))
+		receipts = append(receipts, nil)
+	}
+	db, err := NewDatabaseWithFreezer(NewMemoryDatabase(), t.TempDir(), "", false, true, false, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if _, err := WriteAncientBlocksWithBlobs(db, blocks, receipts, big.NewInt(1)); err != nil {
+		t.Fatal(err)
+	}
+	return db, db.(*freezerdb).AncientStore.(*chainFreezer), blocks
+}
+
+// Tests that the sidecars are exported into one archive file per segment.
+func TestBlobArchiveSegments(t *testing.T) {
+	defer func(old uint64) { blobArchiveSegment = old }(blobArchiveSegment)
+	blobArchiveSegment = 4
+
+	db, f, _ := newBlobTestFreezer(t, 12, common.Address{0xbb})
+	env := &ethdb.FreezerEnv{ChainCfg: params.MergedTestChainConfig, BlobArchiveDir: t.TempDir()}
+	if err := f.archiveBlobs(db.(*freezerdb).KeyValueStore, env, 10, false); err != nil {
+		t.Fatal(err)
+	}
+	files, err := BlobArchiveFiles(env.BlobArchiveDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{blobArchiveName(0, 3), blobArchiveName(4, 7), blobArchiveName(8, 9)}
+	if len(files) != len(want) {
+		t.Fatalf("wrong number of archives: have %d, want %d", len(files), len(want))
+	}
+	for i, file := range files {
+		if filepath.Base(file) != want[i] {
+			t.Errorf("archive %d: have %s, want %s", i, filepath.Base(file), want[i])
+		}
+	}
+}
+
+// Tests that pruning the block history exports the sidecars of the discarded
+// blocks and drops the sidecars kept for them.
+func TestBlobArchiveHistoryPruning(t *testing.T) {
+	defer func(old uint64) { blobArchiveSegment = old }(blobArchiveSegment)
+	blobArchiveSegment = 4
+
+	var (
+		watched      = common.Address{0xaa}
+		db, f, chain = newBlobTestFreezer(t, 12, watched)
+		kvdb         = db.(*freezerdb).KeyValueStore
+		env          = &ethdb.FreezerEnv{
+			ChainCfg:          params.MergedTestChainConfig,
+			BlobKeepAddresses: []common.Address{watched},
+			BlobArchiveDir:    t.TempDir(),
+			BlockHistory:      5,
+		}
+	)
+	// Prune the blob table below block 4, keeping the sidecars of the watched
+	// address in the key-value store.
+	if err := f.archiveBlobs(kvdb, env, 4, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.TruncateTableTail(ChainFreezerBlobSidecarTable, 4); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 4; i++ {
+		if len(ReadBlobSidecarsRLP(db, chain[i].Hash(), uint64(i))) == 0 {
+			t.Fatalf("block %d: sidecars not kept", i)
+		}
+	}
+	// The history window ends at block 7, the tail is aligned down to block 4.
+	f.tryPruneHistory(kvdb, env, 11)
+	if tail, _ := f.Tail(); tail != 4 {
+		t.Fatalf("wrong tail: have %d, want 4", tail)
+	}
+	f.tryPruneKeptBlobs(kvdb, env, 11)
+	for i := 0; i < 4; i++ {
+		if data, _ := kvdb.Get(blockBlobSidecarsKey(uint64(i), chain[i].Hash())); len(data) != 0 {
+			t.Errorf("block %d: kept sidecars not pruned", i)
+		}
+	}
+	files, err := BlobArchiveFiles(env.BlobArchiveDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || filepath.Base(files[0]) != blobArchiveName(0, 3) {
+		t.Fatalf("wrong archives: %v", files)
+	}
+	// Pruning the history further exports the blob table below the new tail.
+	f.tryPruneHistory(kvdb, env, 14)
+	if tail, _ := f.Tail(); tail != 8 {
+		t.Fatalf("wrong tail: have %d, want 8", tail)
+	}
+	if files, _ := BlobArchiveFiles(env.BlobArchiveDir); len(files) != 2 || filepath.Base(files[1]) != blobArchiveName(4, 7) {
+		t.Fatalf("wrong archives: %v", files)
+	}
+}
@@ -0,0 +1,296 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// blockStoreMetaKeys are the singleton keys kept alongside the block data,
+// tracking the chain head and the state of the chain freezer.
+var blockStoreMetaKeys = [][]byte{
+	headHeaderKey, headFinalizedBlockKey, headBlockKey, headFastBlockKey,
+	offSetOfCurrentAncientFreezer, offSetOfLastAncientFreezer, frozenOfAncientDBKey, pruneAncientKey,
+}
+
+// blockStorePrefixes are the key prefixes the block data is stored under, in
+// ascending order. Not all keys under these prefixes are block data, the exact
+// classification is done by DataTypeByKey.
+var blockStorePrefixes = [][]byte{headerNumberPrefix, blockBodyPrefix, headerPrefix, blockReceiptsPrefix}
+
+// errBlockStoreInterrupted is returned if a block store migration is aborted.
+var errBlockStoreInterrupted = errors.New("block store migration interrupted")
+
+// BlockStoreMigration is the checkpoint of a block store split or merge. It is
+// kept in the database the block data is migrated into.
+type BlockStoreMigration struct {
+	Head     common.Hash // Head header of the source store when the current pass started
+	Next     []byte      // First key not yet synced by the current pass
+	Synced   bool        // Whether the current pass completed
+	Switched bool        // Whether the database layout has been switched over
+}
+
+// ReadBlockStoreMigration retrieves the checkpoint of an unfinished block store
+// migration, or nil if there is none.
+func ReadBlockStoreMigration(db ethdb.KeyValueReader) *BlockStoreMigration {
+	data, _ := db.Get(blockStoreMigrationKey)
+	if len(data) == 0 {
+		return nil
+	}
+	var migration BlockStoreMigration
+	if err := rlp.DecodeBytes(data, &migration); err != nil {
+		log.Error("Invalid block store migration checkpoint", "err", err)
+		return nil
+	}
+	return &migration
+}
+
+// WriteBlockStoreMigration stores the checkpoint of a block store migration.
+func WriteBlockStoreMigration(db ethdb.KeyValueWriter, migration *BlockStoreMigration) {
+	data, err := rlp.EncodeToBytes(migration)
+	if err != nil {
+		log.Crit("Failed to encode block store migration checkpoint", "err", err)
+	}
+	if err := db.Put(blockStoreMigrationKey, data); err != nil {
+		log.Crit("Failed to store block store migration checkpoint", "err", err)
+	}
+}
+
+// DeleteBlockStoreMigration removes the checkpoint of a finished block store
+// migration.
+func DeleteBlockStoreMigration(db ethdb.KeyValueWriter) {
+	if err := db.Delete(blockStoreMigrationKey); err != nil {
+		log.Crit("Failed to delete block store migration checkpoint", "err", err)
+	}
+}
+
+// ReadBlockStoreSplit reports whether the block store was split off on its own
+// and completely copied.
+func ReadBlockStoreSplit(db ethdb.KeyValueReader) bool {
+	ok, _ := db.Has(blockStoreSplitKey)
+	return ok
+}
+
+// WriteBlockStoreSplit marks the block store as split off on its own and
+// completely copied.
+func WriteBlockStoreSplit(db ethdb.KeyValueWriter) {
+	if err := db.Put(blockStoreSplitKey, []byte{1}); err != nil {
+		log.Crit("Failed to store block store split marker", "err", err)
+	}
+}
+
+// ChainFreezerDir returns the directory of the chain freezer within the given
+// root ancient directory.
+func ChainFreezerDir(ancient string) string {
+	return resolveChainFreezerDir(ancient)
+}
+
+// ResetChainFreezer replaces the chain freezer in the given directory with an
+// empty one, used to drop the chain segments moved to another store.
+func ResetChainFreezer(dir string) error {
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	f, err := NewFreezer(dir, "", false, 0, freezerTableSize, chainFreezerNoSnappy)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// SyncBlockStore copies the block data held in the key-value store src into
+// dst, deleting any block data from dst which src doesn't have. The progress is
+// checkpointed into dst, an interrupted sync resumes where it stopped unless
+// the head of src moved in between, in which case a new pass is started. Data
+// synced by the previous pass is left in place and skipped cheaply.
+func SyncBlockStore(src ethdb.KeyValueStore, dst ethdb.KeyValueStore, interrupt chan struct{}) error {
+	head, _ := src.Get(headHeaderKey)
+	progress := ReadBlockStoreMigration(dst)
+	if progress == nil || progress.Head != common.BytesToHash(head) {
+		progress = &BlockStoreMigration{Head: common.BytesToHash(head)}
+	}
+	if progress.Synced {
+		return nil
+	}
+	var (
+		batch  = dst.NewBatch()
+		start  = time.Now()
+		logged = time.Now()
+
+		copied  int
+		deleted int
+	)
+	flush := func(next []byte) error {
+		progress.Next = next
+		WriteBlockStoreMigration(batch, progress)
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		batch.Reset()
+		return nil
+	}
+	for _, prefix := range blockStorePrefixes {
+		// Skip the prefixes completed before the interruption
+		if bytes.Compare(prefix, progress.Next) < 0 && !bytes.HasPrefix(progress.Next, prefix) {
+			continue
+		}
+		var from []byte
+		if bytes.HasPrefix(progress.Next, prefix) {
+			from = progress.Next[len(prefix):]
+		}
+		var (
+			srcIt = src.NewIterator(prefix, from)
+			dstIt = dst.NewIterator(prefix, from)
+		)
+		srcOk, dstOk := nextBlockStoreKey(srcIt), nextBlockStoreKey(dstIt)
+		for srcOk || dstOk {
+			var key []byte
+			switch {
+			case !dstOk || (srcOk && bytes.Compare(srcIt.Key(), dstIt.Key()) < 0):
+				key = common.CopyBytes(srcIt.Key())
+				batch.Put(key, srcIt.Value())
+				copied++
+				srcOk = nextBlockStoreKey(srcIt)
+
+			case !srcOk || bytes.Compare(srcIt.Key(), dstIt.Key()) > 0:
+				key = common.CopyBytes(dstIt.Key())
+				batch.Delete(key)
+				deleted++
+				dstOk = nextBlockStoreKey(dstIt)
+
+			default:
+				key = common.CopyBytes(srcIt.Key())
+				if !bytes.Equal(srcIt.Value(), dstIt.Value()) {
+					batch.Put(key, srcIt.Value())
+					copied++
+				}
+				srcOk, dstOk = nextBlockStoreKey(srcIt), nextBlockStoreKey(dstIt)
+			}
+			if batch.ValueSize() < ethdb.IdealBatchSize {
+				continue
+			}
+			// Checkpoint the first key after the current one
+			if err := flush(append(key, 0)); err != nil {
+				srcIt.Release()
+				dstIt.Release()
+				return err
+			}
+			select {
+			case <-interrupt:
+				srcIt.Release()
+				dstIt.Release()
+				return errBlockStoreInterrupted
+			default:
+			}
+			if time.Since(logged) > 8*time.Second {
+				log.Info("Syncing block store", "at", common.Bytes2Hex(key), "copied", copied, "deleted", deleted, "elapsed", common.PrettyDuration(time.Since(start)))
+				logged = time.Now()
+			}
+		}
+		err := srcIt.Error()
+		if err == nil {
+			err = dstIt.Error()
+		}
+		srcIt.Release()
+		dstIt.Release()
+		if err != nil {
+			return err
+		}
+	}
+	for _, key := range blockStoreMetaKeys {
+		if value, _ := src.Get(key); len(value) > 0 {
+			batch.Put(key, value)
+		} else {
+			batch.Delete(key)
+		}
+	}
+	progress.Synced = true
+	if err := flush(nil); err != nil {
+		return err
+	}
+	log.Info("Synced block store", "copied", copied, "deleted", deleted, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// DeleteBlockStoreData removes all block data from the given key-value store,
+// used to drop the leftovers once the block data was migrated to another store.
+func DeleteBlockStoreData(db ethdb.KeyValueStore, interrupt chan struct{}) error {
+	var (
+		batch   = db.NewBatch()
+		start   = time.Now()
+		logged  = time.Now()
+		deleted int
+	)
+	for _, prefix := range blockStorePrefixes {
+		it := db.NewIterator(prefix, nil)
+		for nextBlockStoreKey(it) {
+			batch.Delete(it.Key())
+			deleted++
+
+			if batch.ValueSize() < ethdb.IdealBatchSize {
+				continue
+			}
+			if err := batch.Write(); err != nil {
+				it.Release()
+				return err
+			}
+			batch.Reset()
+
+			select {
+			case <-interrupt:
+				it.Release()
+				return errBlockStoreInterrupted
+			default:
+			}
+			if time.Since(logged) > 8*time.Second {
+				log.Info("Deleting migrated block data", "deleted", deleted, "elapsed", common.PrettyDuration(time.Since(start)))
+				logged = time.Now()
+			}
+		}
+		err := it.Error()
+		it.Release()
+		if err != nil {
+			return err
+		}
+	}
+	for _, key := range blockStoreMetaKeys {
+		batch.Delete(key)
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	log.Info("Deleted migrated block data", "deleted", deleted, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// nextBlockStoreKey advances the iterator to the next key holding block data.
+func nextBlockStoreKey(it ethdb.Iterator) bool {
+	for it.Next() {
+		if DataTypeByKey(it.Key()) == BlockDataType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// blockStoreContent collects all block data of the given store.
+func blockStoreContent(db ethdb.KeyValueStore) map[string][]byte {
+	content := make(map[string][]byte)
+	it := db.NewIterator(nil, nil)
+	defer it.Release()
+	for it.Next() {
+		if DataTypeByKey(it.Key()) == BlockDataType {
+			content[string(it.Key())] = common.CopyBytes(it.Value())
+		}
+	}
+	return content
+}
+
+func TestSyncBlockStore(t *testing.T) {
+	var (
+		src  = NewMemoryDatabase()
+		dst  = NewMemoryDatabase()
+		head *types.Header
+	)
+	// Write enough headers to span multiple batches
+	for i := 0; i < 300; i++ {
+		head = &types.Header{Number: big.NewInt(int64(i)), Extra: make([]byte, 1024)}
+		WriteHeader(src, head)
+		WriteCanonicalHash(src, head.Hash(), head.Number.Uint64())
+	}
+	WriteHeadHeaderHash(src, head.Hash())
+	WriteCode(src, common.Hash{0x01}, []byte{0x02})
+
+	// Stale block data in the destination is deleted, anything else is left
+	WriteCanonicalHash(dst, common.Hash{0xff}, 1000)
+	WriteCode(dst, common.Hash{0x03}, []byte{0x04})
+
+	interrupt := make(chan struct{})
+	close(interrupt)
+	if err := SyncBlockStore(src, dst, interrupt); !errors.Is(err, errBlockStoreInterrupted) {
+		t.Fatalf("unexpected error: have %v, want %v", err, errBlockStoreInterrupted)
+	}
+	progress := ReadBlockStoreMigration(dst)
+	if progress == nil || progress.Synced || len(progress.Next) == 0 {
+		t.Fatalf("unexpected checkpoint: %+v", progress)
+	}
+	if err := SyncBlockStore(src, dst, nil); err != nil {
+		t.Fatal(err)
+	}
+	if progress := ReadBlockStoreMigration(dst); progress == nil || !progress.Synced || progress.Head != head.Hash() {
+		t.Fatalf("unexpected checkpoint: %+v", progress)
+	}
+	want, have := blockStoreContent(src), blockStoreContent(dst)
+	if len(have) != len(want) {
+		t.Fatalf("block data mismatch: have %d items, want %d", len(have), len(want))
+	}
+	for key, value := range want {
+		if !bytes.Equal(have[key], value) {
+			t.Fatalf("block data mismatch at %x", key)
+		}
+	}
+	if ReadCode(dst, common.Hash{0x01}) != nil {
+		t.Fatal("non-block data copied")
+	}
+	if ReadCode(dst, common.Hash{0x03}) == nil {
+		t.Fatal("non-block data deleted")
+	}
+	// A moved head starts a new pass
+	WriteHeadHeaderHash(src, common.Hash{0xaa})
+	DeleteCanonicalHash(src, 0)
+	if err := SyncBlockStore(src, dst, nil); err != nil {
+		t.Fatal(err)
+	}
+	if ReadCanonicalHash(dst, 0) != (common.Hash{}) || ReadHeadHeaderHash(dst) != (common.Hash{0xaa}) {
+		t.Fatal("changes of the source not synced")
+	}
+	// Drop the block data from the source once migrated
+	if err := DeleteBlockStoreData(src, nil); err != nil {
+		t.Fatal(err)
+	}
+	if left := blockStoreContent(src); len(left) != 0 {
+		t.Fatalf("block data left: %d items", len(left))
+	}
+	if ReadCode(src, common.Hash{0x01}) == nil {
+		t.Fatal("non-block data deleted")
+	}
+}
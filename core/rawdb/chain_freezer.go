@@ -17,9 +17,11 @@
 package rawdb
 
 import (
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"math/big"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -62,6 +64,8 @@ type chainFreezer struct {
 	freezeEnv    atomic.Value
 	waitEnvTimes int
 
+	cold atomic.Pointer[coldTier] // Remote tier holding offloaded segments, nil if disabled
+
 	multiDatabase bool
 }
 
@@ -390,8 +394,14 @@ func (f *chainFreezer) freeze(db ethdb.KeyValueStore) {
 		env, _ := f.freezeEnv.Load().(*ethdb.FreezerEnv)
 		// try prune blob data after cancun fork
 		if isCancun(env, head.Number, head.Time) {
-			f.tryPruneBlobAncientTable(env, *number)
+			f.tryPruneBlobAncientTable(db, env, *number)
 		}
+		// try prune the block history outside of the retained window
+		f.tryPruneHistory(db, env, *number)
+		// try prune the sidecars kept by the archival policy past their retention
+		f.tryPruneKeptBlobs(db, env, *number)
+		// try offload the sealed segments older than the cold age
+		f.tryOffloadCold(*number)
 
 		// Avoid database thrashing with tiny writes
 		if frozen-first < freezerBatchLimit {
@@ -400,7 +410,7 @@ func (f *chainFreezer) freeze(db ethdb.KeyValueStore) {
 	}
 }
 
-func (f *chainFreezer) tryPruneBlobAncientTable(env *ethdb.FreezerEnv, num uint64) {
+func (f *chainFreezer) tryPruneBlobAncientTable(db ethdb.KeyValueStore, env *ethdb.FreezerEnv, num uint64) {
 	extraReserve := getBlobExtraReserveFromEnv(env)
 	// It means that there is no need for pruning
 	if extraReserve == 0 {
@@ -411,7 +421,15 @@ func (f *chainFreezer) tryPruneBlobAncientTable(env *ethdb.FreezerEnv, num uint6
 		return
 	}
 	expectTail := num - reserveThreshold
+	if env != nil && env.BlobArchiveDir != "" {
+		// Prune whole segments only, so that each archive file covers one
+		expectTail -= expectTail % blobArchiveSegment
+	}
 	start := time.Now()
+	if err := f.archiveBlobs(db, env, expectTail, true); err != nil {
+		log.Error("Cannot archive blobs before pruning", "block", num, "expectTail", expectTail, "err", err)
+		return
+	}
 	if _, err := f.TruncateTableTail(ChainFreezerBlobSidecarTable, expectTail); err != nil {
 		log.Error("Cannot prune blob ancient", "block", num, "expectTail", expectTail, "err", err)
 		return
@@ -419,6 +437,262 @@ func (f *chainFreezer) tryPruneBlobAncientTable(env *ethdb.FreezerEnv, num uint6
 	log.Debug("Chain freezer prune useless blobs, now ancient data is", "from", expectTail, "to", num, "cost", common.PrettyDuration(time.Since(start)))
 }
 
+// blobTableTail returns the first block whose sidecars are stored in the blob
+// table, or limit if there are none below it.
+func (f *chainFreezer) blobTableTail(limit uint64) uint64 {
+	lo, _ := f.AncientStore.Tail()
+	hi := limit
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		if ok, _ := f.AncientStore.HasAncient(ChainFreezerBlobSidecarTable, mid); ok {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	return lo
+}
+
+// archiveBlobs applies the blob archival policy to the sidecars about to be
+// pruned below the given tail: all of them are exported to the archive directory,
+// one file per segment, and if keep is set, the ones of the watched addresses are
+// moved to the key-value store.
+func (f *chainFreezer) archiveBlobs(db ethdb.KeyValueStore, env *ethdb.FreezerEnv, tail uint64, keep bool) error {
+	keep = keep && env != nil && len(env.BlobKeepAddresses) > 0
+	if env == nil || (!keep && env.BlobArchiveDir == "") {
+		return nil
+	}
+	from := f.blobTableTail(tail)
+	if from >= tail {
+		return nil
+	}
+	var (
+		entries []*BlobArchiveEntry
+		segment = from
+		batch   = db.NewBatch()
+		kept    int
+	)
+	for number := from; number < tail; number++ {
+		// Export the sidecars collected so far at the end of each segment
+		if number > from && number%blobArchiveSegment == 0 {
+			if err := exportBlobs(env.BlobArchiveDir, segment, number-1, entries); err != nil {
+				return err
+			}
+			entries, segment = nil, number
+		}
+		data, err := f.Ancient(ChainFreezerBlobSidecarTable, number)
+		if err != nil {
+			return err
+		}
+		var sidecars types.BlobSidecars
+		if err := rlp.DecodeBytes(data, &sidecars); err != nil {
+			return fmt.Errorf("invalid blob sidecars of block %d: %v", number, err)
+		}
+		if len(sidecars) == 0 {
+			continue
+		}
+		hash, err := f.Ancient(ChainFreezerHashTable, number)
+		if err != nil {
+			return err
+		}
+		if env.BlobArchiveDir != "" {
+			entries = append(entries, &BlobArchiveEntry{Number: number, Hash: common.BytesToHash(hash), Sidecars: data})
+		}
+
+		if keep {
+			watched, err := f.watchedBlobs(env, number, sidecars)
+			if err != nil {
+				return err
+			}
+			if len(watched) > 0 {
+				WriteBlobSidecars(batch, common.BytesToHash(hash), number, watched)
+				kept += len(watched)
+			}
+		}
+	}
+	if err := exportBlobs(env.BlobArchiveDir, segment, tail-1, entries); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	if kept > 0 {
+		log.Info("Kept blob sidecars of watched addresses", "from", from, "to", tail-1, "sidecars", kept)
+	}
+	return nil
+}
+
+// exportBlobs writes the sidecars of the blocks [from, to] into a new archive
+// file in dir, if there are any and exporting is enabled.
+func exportBlobs(dir string, from, to uint64, entries []*BlobArchiveEntry) error {
+	if dir == "" || len(entries) == 0 {
+		return nil
+	}
+	path, err := WriteBlobArchive(dir, from, to, entries)
+	if err != nil {
+		return err
+	}
+	log.Info("Exported blob sidecars", "from", from, "to", to, "blocks", len(entries), "file", path)
+	return nil
+}
+
+// watchedBlobs filters the sidecars of the given block down to the ones whose
+// transaction is sent from or to one of the watched addresses.
+func (f *chainFreezer) watchedBlobs(env *ethdb.FreezerEnv, number uint64, sidecars types.BlobSidecars) (types.BlobSidecars, error) {
+	data, err := f.Ancient(ChainFreezerHeaderTable, number)
+	if err != nil {
+		return nil, err
+	}
+	header := new(types.Header)
+	if err := rlp.DecodeBytes(data, header); err != nil {
+		return nil, fmt.Errorf("invalid header of block %d: %v", number, err)
+	}
+	if data, err = f.Ancient(ChainFreezerBodiesTable, number); err != nil {
+		return nil, err
+	}
+	body := new(types.Body)
+	if err := rlp.DecodeBytes(data, body); err != nil {
+		return nil, fmt.Errorf("invalid body of block %d: %v", number, err)
+	}
+	var (
+		signer  = types.MakeSigner(env.ChainCfg, header.Number, header.Time)
+		watched types.BlobSidecars
+	)
+	for _, sidecar := range sidecars {
+		if sidecar.TxIndex >= uint64(len(body.Transactions)) {
+			continue
+		}
+		tx := body.Transactions[sidecar.TxIndex]
+		if to := tx.To(); to != nil && slices.Contains(env.BlobKeepAddresses, *to) {
+			watched = append(watched, sidecar)
+			continue
+		}
+		if from, err := types.Sender(signer, tx); err == nil && slices.Contains(env.BlobKeepAddresses, from) {
+			watched = append(watched, sidecar)
+		}
+	}
+	return watched, nil
+}
+
+// tryPruneHistory truncates the tail of the freezer, discarding all blocks
+// older than the block history window from the given head. The sidecars of
+// the discarded blocks are exported first if the archival policy asks for it.
+func (f *chainFreezer) tryPruneHistory(db ethdb.KeyValueStore, env *ethdb.FreezerEnv, num uint64) {
+	if env == nil || env.BlockHistory == 0 || num < env.BlockHistory {
+		return
+	}
+	expectTail := num - env.BlockHistory + 1
+	if frozen, _ := f.Ancients(); expectTail > frozen {
+		expectTail = frozen
+	}
+	if env.BlobArchiveDir != "" {
+		// Prune whole segments only, so that each archive file covers one
+		expectTail -= expectTail % blobArchiveSegment
+	}
+	if old, _ := f.Tail(); expectTail <= old {
+		return
+	}
+	start := time.Now()
+	// The sidecars of the discarded blocks are exported, but not kept, since
+	// their blocks are gone.
+	if err := f.archiveBlobs(db, env, expectTail, false); err != nil {
+		log.Error("Cannot archive blobs before pruning block history", "block", num, "expectTail", expectTail, "err", err)
+		return
+	}
+	old, err := f.TruncateTail(expectTail)
+	if err != nil {
+		log.Error("Cannot prune block history", "block", num, "expectTail", expectTail, "err", err)
+		return
+	}
+	if old < expectTail {
+		log.Info("Pruned block history", "from", old, "to", expectTail-1, "elapsed", common.PrettyDuration(time.Since(start)))
+	}
+}
+
+// tryPruneKeptBlobs deletes the sidecars kept in the key-value store by the
+// archival policy once their blocks fall out of the block history window or
+// out of the retention of kept sidecars.
+func (f *chainFreezer) tryPruneKeptBlobs(db ethdb.KeyValueStore, env *ethdb.FreezerEnv, num uint64) {
+	if env == nil {
+		return
+	}
+	limit, _ := f.Tail()
+	if env.BlobKeepBlocks > 0 && num > env.BlobKeepBlocks {
+		limit = max(limit, num-env.BlobKeepBlocks)
+	}
+	// Sidecars not frozen yet are stored in the key-value store too, only the
+	// ones pruned from the blob table are kept ones.
+	frozen, _ := f.Ancients()
+	if limit = min(limit, f.blobTableTail(frozen)); limit == 0 {
+		return
+	}
+	pruned, err := pruneKeptBlobs(db, limit)
+	if err != nil {
+		log.Error("Cannot prune kept blob sidecars", "block", num, "limit", limit, "err", err)
+		return
+	}
+	if pruned > 0 {
+		log.Info("Pruned kept blob sidecars", "blocks", pruned, "limit", limit)
+	}
+}
+
+// pruneKeptBlobs deletes the sidecars stored in the key-value store for the
+// blocks below the given limit, returning the number of blocks affected.
+func pruneKeptBlobs(db ethdb.KeyValueStore, limit uint64) (int, error) {
+	it := db.NewIterator(BlockBlobSidecarsPrefix, nil)
+	defer it.Release()
+
+	var (
+		batch  = db.NewBatch()
+		pruned int
+	)
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(BlockBlobSidecarsPrefix)+8+common.HashLength {
+			continue
+		}
+		if binary.BigEndian.Uint64(key[len(BlockBlobSidecarsPrefix):]) >= limit {
+			break
+		}
+		if err := batch.Delete(key); err != nil {
+			return pruned, err
+		}
+		pruned++
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return pruned, err
+			}
+			batch.Reset()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return pruned, err
+	}
+	return pruned, batch.Write()
+}
+
+// tryOffloadCold moves the sealed segments older than the configured age from
+// the local freezer to the cold tier.
+func (f *chainFreezer) tryOffloadCold(num uint64) {
+	cold := f.cold.Load()
+	if cold == nil || num < cold.age {
+		return
+	}
+	limit := num - cold.age
+	if frozen, _ := f.Ancients(); limit > frozen {
+		limit = frozen
+	}
+	start := time.Now()
+	offloaded, err := cold.offload(f.AncientStore, limit, f.quit)
+	if err != nil {
+		log.Error("Cannot offload ancient segments", "block", num, "limit", limit, "err", err)
+	}
+	if offloaded > 0 {
+		tail, head := cold.bounds()
+		log.Info("Offloaded ancient segments", "blocks", offloaded, "from", tail, "to", head-1, "elapsed", common.PrettyDuration(time.Since(start)))
+	}
+}
+
 func getBlobExtraReserveFromEnv(env *ethdb.FreezerEnv) uint64 {
 	if env == nil {
 		return params.DefaultExtraReserveForBlobRequests
@@ -549,10 +823,57 @@ func (f *chainFreezer) freezeRange(nfdb *nofreezedb, number, limit uint64) (hash
 }
 
 func (f *chainFreezer) SetupFreezerEnv(env *ethdb.FreezerEnv) error {
+	if env != nil && env.ColdStore != nil && f.cold.Load() == nil {
+		cold, err := newColdTier(env.ColdStore, env.ColdAge, env.ColdCache)
+		if err != nil {
+			return err
+		}
+		f.cold.Store(cold)
+	}
 	f.freezeEnv.Store(env)
 	return nil
 }
 
+// reader returns the reader for ancient items, consulting the cold tier for
+// the offloaded ones.
+func (f *chainFreezer) reader(op ethdb.AncientReaderOp) ethdb.AncientReaderOp {
+	if cold := f.cold.Load(); cold != nil {
+		return &coldReader{AncientReaderOp: op, cold: cold}
+	}
+	return op
+}
+
+// HasAncient returns an indicator whether the specified ancient data exists,
+// locally or in the cold tier.
+func (f *chainFreezer) HasAncient(kind string, number uint64) (bool, error) {
+	return f.reader(f.AncientStore).HasAncient(kind, number)
+}
+
+// Ancient retrieves an ancient binary blob, locally or from the cold tier.
+func (f *chainFreezer) Ancient(kind string, number uint64) ([]byte, error) {
+	return f.reader(f.AncientStore).Ancient(kind, number)
+}
+
+// AncientRange retrieves multiple items in sequence, locally or from the cold
+// tier.
+func (f *chainFreezer) AncientRange(kind string, start, count, maxBytes uint64) ([][]byte, error) {
+	return f.reader(f.AncientStore).AncientRange(kind, start, count, maxBytes)
+}
+
+// Tail returns the number of the first stored item, including the ones moved
+// to the cold tier.
+func (f *chainFreezer) Tail() (uint64, error) {
+	return f.reader(f.AncientStore).Tail()
+}
+
+// ReadAncients runs the given read operation while ensuring that no writes
+// take place on the underlying freezer.
+func (f *chainFreezer) ReadAncients(fn func(ethdb.AncientReaderOp) error) error {
+	return f.AncientStore.ReadAncients(func(op ethdb.AncientReaderOp) error {
+		return fn(f.reader(op))
+	})
+}
+
 func (f *chainFreezer) checkFreezerEnv() error {
 	_, exist := f.freezeEnv.Load().(*ethdb.FreezerEnv)
 	if exist {
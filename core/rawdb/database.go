@@ -764,16 +764,17 @@ func DataTypeByKey(key []byte) DataType {
 		bytes.HasPrefix(key, blockReceiptsPrefix) && len(key) == (len(blockReceiptsPrefix)+8+common.HashLength),
 		bytes.HasPrefix(key, headerPrefix) && bytes.HasSuffix(key, headerTDSuffix),
 		bytes.HasPrefix(key, headerPrefix) && bytes.HasSuffix(key, headerHashSuffix),
-		bytes.HasPrefix(key, headerNumberPrefix) && len(key) == (len(headerNumberPrefix)+common.HashLength):
+		bytes.HasPrefix(key, headerNumberPrefix) && len(key) == (len(headerNumberPrefix)+common.HashLength),
+		bytes.HasPrefix(key, BlockBlobSidecarsPrefix) && len(key) == (len(BlockBlobSidecarsPrefix)+8+common.HashLength):
 		return BlockDataType
 	default:
 		for _, meta := range [][]byte{
-			fastTrieProgressKey, persistentStateIDKey, trieJournalKey, snapSyncStatusFlagKey} {
+			fastTrieProgressKey, persistentStateIDKey, trieJournalKey, snapSyncStatusFlagKey, hbss2pbssProgressKey} {
 			if bytes.Equal(key, meta) {
 				return StateDataType
 			}
 		}
-		for _, meta := range [][]byte{headHeaderKey, headFinalizedBlockKey, headBlockKey, headFastBlockKey} {
+		for _, meta := range blockStoreMetaKeys {
 			if bytes.Equal(key, meta) {
 				return BlockDataType
 			}
@@ -838,6 +839,11 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 
 		// Totals
 		total common.StorageSize
+
+		// Per-store totals
+		chainStore stat
+		stateStore stat
+		blockStore stat
 	)
 	// Inspect key-value database first.
 	for it.Next() {
@@ -925,6 +931,7 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 				snapshotGeneratorKey, snapshotRecoveryKey, txIndexTailKey, fastTxLookupLimitKey,
 				uncleanShutdownKey, badBlockKey, transitionStatusKey, skeletonSyncStatusKey,
 				persistentStateIDKey, trieJournalKey, snapshotSyncStatusKey, snapSyncStatusFlagKey,
+				hbss2pbssProgressKey,
 			} {
 				if bytes.Equal(key, meta) {
 					metadata.Add(size)
@@ -936,6 +943,7 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 				unaccounted.Add(size)
 			}
 		}
+		chainStore.Add(size)
 		count++
 		if count%1000 == 0 && time.Since(logged) > 8*time.Second {
 			log.Info("Inspecting database", "count", count, "elapsed", common.PrettyDuration(time.Since(start)))
@@ -966,7 +974,7 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 			default:
 				var accounted bool
 				for _, meta := range [][]byte{
-					fastTrieProgressKey, persistentStateIDKey, trieJournalKey, snapSyncStatusFlagKey} {
+					fastTrieProgressKey, persistentStateIDKey, trieJournalKey, snapSyncStatusFlagKey, hbss2pbssProgressKey} {
 					if bytes.Equal(key, meta) {
 						metadata.Add(size)
 						accounted = true
@@ -977,6 +985,7 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 					unaccounted.Add(size)
 				}
 			}
+			stateStore.Add(size)
 			count++
 			if count%1000 == 0 && time.Since(logged) > 8*time.Second {
 				log.Info("Inspecting separate state database", "count", count, "elapsed", common.PrettyDuration(time.Since(start)))
@@ -1026,6 +1035,7 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 					unaccounted.Add(size)
 				}
 			}
+			blockStore.Add(size)
 			count++
 			if count%1000 == 0 && time.Since(logged) > 8*time.Second {
 				log.Info("Inspecting separate block database", "count", count, "elapsed", common.PrettyDuration(time.Since(start)))
@@ -1062,6 +1072,8 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 		{"Light client", "Bloom trie nodes", bloomTrieNodes.Size(), bloomTrieNodes.Count()},
 	}
 	// Inspect all registered append-only file store then.
+	var chainAncientSize, stateAncientSize, blockAncientSize common.StorageSize
+
 	ancients, err := inspectFreezers(db.BlockStore())
 	if err != nil {
 		return err
@@ -1076,6 +1088,34 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 			})
 		}
 		total += ancient.size()
+		if db.HasSeparateBlockStore() {
+			blockAncientSize += ancient.size()
+		} else {
+			chainAncientSize += ancient.size()
+		}
+	}
+	// The state freezer stays with the chain store if only the block data is
+	// kept separately.
+	if db.HasSeparateBlockStore() && trieIter == nil {
+		ancients, err := inspectFreezers(db)
+		if err != nil {
+			return err
+		}
+		for _, ancient := range ancients {
+			if ancient.name == ChainFreezerName {
+				continue
+			}
+			for _, table := range ancient.sizes {
+				stats = append(stats, []string{
+					fmt.Sprintf("Ancient store (%s)", strings.Title(ancient.name)),
+					strings.Title(table.name),
+					table.size.String(),
+					fmt.Sprintf("%d", ancient.count()),
+				})
+			}
+			total += ancient.size()
+			chainAncientSize += ancient.size()
+		}
 	}
 
 	// inspect ancient state in separate trie db if exist
@@ -1097,6 +1137,7 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 				})
 			}
 			total += ancient.size()
+			stateAncientSize += ancient.size()
 		}
 	}
 	table := tablewriter.NewWriter(os.Stdout)
@@ -1105,6 +1146,21 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 	table.AppendBulk(stats)
 	table.Render()
 
+	// Display the size of each store, which might live on different disks.
+	stores := [][]string{
+		{"Chain", chainStore.Size(), chainStore.Count(), chainAncientSize.String(), (chainStore.size + chainAncientSize).String()},
+	}
+	if trieIter != nil {
+		stores = append(stores, []string{"State", stateStore.Size(), stateStore.Count(), stateAncientSize.String(), (stateStore.size + stateAncientSize).String()})
+	}
+	if blockIter != nil {
+		stores = append(stores, []string{"Block", blockStore.Size(), blockStore.Count(), blockAncientSize.String(), (blockStore.size + blockAncientSize).String()})
+	}
+	table = tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Store", "Key-Value size", "Key-Value items", "Ancient size", "Total"})
+	table.AppendBulk(stores)
+	table.Render()
+
 	if unaccounted.size > 0 {
 		log.Error("Database contains unaccounted data", "size", unaccounted.size, "count", unaccounted.count)
 	}
@@ -377,7 +377,11 @@ func (f *Freezer) TruncateTail(tail uint64) (uint64, error) {
 	if old >= tail {
 		return old, nil
 	}
-	for _, table := range f.tables {
+	for kind, table := range f.tables {
+		// addition tables may not be populated yet, nothing to discard
+		if slices.Contains(additionTables, kind) && EmptyTable(table) {
+			continue
+		}
 		if err := table.truncateTail(tail - f.offset); err != nil {
 			return 0, err
 		}
@@ -0,0 +1,404 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/objstore"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/golang/snappy"
+)
+
+const (
+	// coldSegmentItems is the number of consecutive items of a single table
+	// bundled into one object of the cold store. Segments are aligned to
+	// multiples of this number, apart from the very first one.
+	coldSegmentItems = 128
+
+	// coldMetaKey is the object key of the cold store metadata.
+	coldMetaKey = "meta"
+
+	// defaultColdCacheSize is the default memory allowance for decoded cold
+	// segments.
+	defaultColdCacheSize = 64 * 1024 * 1024
+)
+
+var (
+	coldHitMeter   = metrics.NewRegisteredMeter("chain/ancient/cold/cache/hit", nil)
+	coldMissMeter  = metrics.NewRegisteredMeter("chain/ancient/cold/cache/miss", nil)
+	coldFetchMeter = metrics.NewRegisteredMeter("chain/ancient/cold/fetch", nil)
+	coldPutMeter   = metrics.NewRegisteredMeter("chain/ancient/cold/put", nil)
+)
+
+// coldMeta tracks the range of items offloaded to the cold store. Items in
+// [Tail, Head) are available remotely.
+type coldMeta struct {
+	Tail uint64
+	Head uint64
+}
+
+// coldSegment is a run of consecutive items of a single table starting at
+// item First.
+type coldSegment struct {
+	First uint64
+	Items [][]byte
+}
+
+// size returns the approximate memory consumed by the segment.
+func (s *coldSegment) size() int {
+	size := 0
+	for _, item := range s.Items {
+		size += len(item)
+	}
+	return size
+}
+
+// coldKey returns the object key of the segment containing the given item.
+func coldKey(kind string, number uint64) string {
+	return fmt.Sprintf("%s/%010d", kind, number/coldSegmentItems)
+}
+
+// coldTier is the remote extension of the chain freezer. Sealed segments older
+// than the configured age are uploaded to an object store and removed from the
+// local freezer; reads of them are served back lazily through a memory cache.
+type coldTier struct {
+	store  objstore.Store
+	age    uint64   // Number of blocks from the head after which segments are offloaded
+	tables []string // Freezer tables to offload
+
+	meta     coldMeta
+	metaLock sync.RWMutex
+
+	cache     lru.BasicLRU[string, *coldSegment]
+	cacheSize int // Current memory used by the cached segments
+	cacheMax  int // Memory allowance of the cached segments
+	cacheLock sync.Mutex
+}
+
+// newColdTier loads the metadata of a cold store, initializing an empty tier
+// if nothing was offloaded yet.
+func newColdTier(store objstore.Store, age uint64, cacheSize int) (*coldTier, error) {
+	if cacheSize <= 0 {
+		cacheSize = defaultColdCacheSize
+	}
+	t := &coldTier{
+		store:    store,
+		age:      age,
+		cache:    lru.NewBasicLRU[string, *coldSegment](1 << 16),
+		cacheMax: cacheSize,
+	}
+	for kind := range chainFreezerNoSnappy {
+		t.tables = append(t.tables, kind)
+	}
+	sort.Strings(t.tables)
+
+	blob, err := store.Get(coldMetaKey)
+	switch {
+	case errors.Is(err, objstore.ErrNotFound):
+	case err != nil:
+		return nil, err
+	default:
+		if err := rlp.DecodeBytes(blob, &t.meta); err != nil {
+			return nil, fmt.Errorf("invalid cold store metadata: %v", err)
+		}
+	}
+	return t, nil
+}
+
+// bounds returns the range of offloaded items.
+func (t *coldTier) bounds() (uint64, uint64) {
+	t.metaLock.RLock()
+	defer t.metaLock.RUnlock()
+
+	return t.meta.Tail, t.meta.Head
+}
+
+// contains reports whether the given item was offloaded.
+func (t *coldTier) contains(number uint64) bool {
+	tail, head := t.bounds()
+	return number >= tail && number < head
+}
+
+// segment retrieves the segment of the given table containing the item,
+// fetching it from the store if it isn't cached. Nil is returned if the
+// segment is missing from the store, as optional tables may have gaps.
+func (t *coldTier) segment(kind string, number uint64) (*coldSegment, error) {
+	key := coldKey(kind, number)
+
+	t.cacheLock.Lock()
+	if seg, ok := t.cache.Get(key); ok {
+		t.cacheLock.Unlock()
+		coldHitMeter.Mark(1)
+		return seg, nil
+	}
+	t.cacheLock.Unlock()
+	coldMissMeter.Mark(1)
+
+	blob, err := t.store.Get(key)
+	if errors.Is(err, objstore.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	coldFetchMeter.Mark(int64(len(blob)))
+
+	data, err := snappy.Decode(nil, blob)
+	if err != nil {
+		return nil, fmt.Errorf("corrupt cold segment %s: %v", key, err)
+	}
+	seg := new(coldSegment)
+	if err := rlp.DecodeBytes(data, seg); err != nil {
+		return nil, fmt.Errorf("corrupt cold segment %s: %v", key, err)
+	}
+	t.cacheLock.Lock()
+	defer t.cacheLock.Unlock()
+
+	if old, ok := t.cache.Peek(key); ok {
+		return old, nil
+	}
+	t.cache.Add(key, seg)
+	t.cacheSize += seg.size()
+	for t.cacheSize > t.cacheMax && t.cache.Len() > 1 {
+		_, old, _ := t.cache.RemoveOldest()
+		t.cacheSize -= old.size()
+	}
+	return seg, nil
+}
+
+// ancient retrieves a single offloaded item.
+func (t *coldTier) ancient(kind string, number uint64) ([]byte, error) {
+	if !slices.Contains(t.tables, kind) {
+		return nil, errUnknownTable
+	}
+	seg, err := t.segment(kind, number)
+	if err != nil {
+		return nil, err
+	}
+	if seg == nil || number < seg.First || number-seg.First >= uint64(len(seg.Items)) {
+		return nil, errOutOfBounds
+	}
+	return seg.Items[number-seg.First], nil
+}
+
+// ancientRange retrieves consecutive offloaded items, following the semantics
+// of ethdb.AncientReaderOp.AncientRange.
+func (t *coldTier) ancientRange(kind string, start, count, maxBytes uint64) ([][]byte, error) {
+	var (
+		items [][]byte
+		size  uint64
+	)
+	for number := start; number < start+count; number++ {
+		item, err := t.ancient(kind, number)
+		if err != nil {
+			if len(items) > 0 {
+				break
+			}
+			return nil, err
+		}
+		if maxBytes != 0 && len(items) > 0 && size+uint64(len(item)) > maxBytes {
+			break
+		}
+		items = append(items, item)
+		size += uint64(len(item))
+	}
+	return items, nil
+}
+
+// writeMeta persists the given metadata and makes it visible to readers.
+func (t *coldTier) writeMeta(meta coldMeta) error {
+	blob, err := rlp.EncodeToBytes(&meta)
+	if err != nil {
+		return err
+	}
+	if err := t.store.Put(coldMetaKey, blob); err != nil {
+		return err
+	}
+	t.metaLock.Lock()
+	t.meta = meta
+	t.metaLock.Unlock()
+	return nil
+}
+
+// upload stores the items [from, to) of the given table as a single segment.
+// Optional tables may start in the middle of the range or be missing entirely,
+// in which case only the available items are uploaded.
+func (t *coldTier) upload(local ethdb.AncientStore, kind string, from, to uint64) error {
+	items, err := local.AncientRange(kind, from, to-from, 0)
+	if err != nil || uint64(len(items)) != to-from {
+		if !slices.Contains(additionTables, kind) {
+			return fmt.Errorf("failed to read %s %d-%d: %v", kind, from, to-1, err)
+		}
+		for from < to {
+			if ok, _ := local.HasAncient(kind, from); ok {
+				break
+			}
+			from++
+		}
+		if from == to {
+			return nil
+		}
+		if items, err = local.AncientRange(kind, from, to-from, 0); err != nil {
+			return err
+		}
+	}
+	data, err := rlp.EncodeToBytes(&coldSegment{First: from, Items: items})
+	if err != nil {
+		return err
+	}
+	blob := snappy.Encode(nil, data)
+	if err := t.store.Put(coldKey(kind, from), blob); err != nil {
+		return err
+	}
+	coldPutMeter.Mark(int64(len(blob)))
+	return nil
+}
+
+// offload moves all full segments below the limit from the local freezer to
+// the cold store. Segments are uploaded before the metadata is advanced and
+// the local tail truncated, so an interrupted run is resumed on the next call.
+func (t *coldTier) offload(local ethdb.AncientStore, limit uint64, quit chan struct{}) (uint64, error) {
+	tail, err := local.Tail()
+	if err != nil {
+		return 0, err
+	}
+	meta := t.meta
+	switch {
+	case meta.Head == 0:
+		// Nothing offloaded yet, start from the local tail
+		meta = coldMeta{Tail: tail, Head: tail}
+
+	case tail < meta.Head:
+		// Offload was interrupted before the local data was dropped
+		if _, err := local.TruncateTail(meta.Head); err != nil {
+			return 0, err
+		}
+
+	case tail > meta.Head:
+		return 0, fmt.Errorf("gap between cold store and freezer: cold head %d, freezer tail %d", meta.Head, tail)
+	}
+	var offloaded uint64
+	for {
+		from := meta.Head
+		to := (from/coldSegmentItems + 1) * coldSegmentItems
+		if to > limit {
+			return offloaded, nil
+		}
+		select {
+		case <-quit:
+			return offloaded, nil
+		default:
+		}
+		for _, kind := range t.tables {
+			if err := t.upload(local, kind, from, to); err != nil {
+				return offloaded, err
+			}
+		}
+		meta.Head = to
+		if err := t.writeMeta(meta); err != nil {
+			return offloaded, err
+		}
+		if _, err := local.TruncateTail(to); err != nil {
+			return offloaded, err
+		}
+		offloaded += to - from
+	}
+}
+
+// coldReader serves reads of offloaded items from the cold tier and all others
+// from the local freezer.
+type coldReader struct {
+	ethdb.AncientReaderOp
+	cold *coldTier
+}
+
+// offloaded reports whether the item is only available from the cold tier.
+func (r *coldReader) offloaded(number uint64) bool {
+	if !r.cold.contains(number) {
+		return false
+	}
+	tail, err := r.AncientReaderOp.Tail()
+	return err == nil && number < tail
+}
+
+// HasAncient implements ethdb.AncientReaderOp.
+func (r *coldReader) HasAncient(kind string, number uint64) (bool, error) {
+	if r.offloaded(number) {
+		if _, err := r.cold.ancient(kind, number); err != nil {
+			if errors.Is(err, errOutOfBounds) || errors.Is(err, errUnknownTable) {
+				return false, nil
+			}
+			return false, err
+		}
+		return true, nil
+	}
+	return r.AncientReaderOp.HasAncient(kind, number)
+}
+
+// Ancient implements ethdb.AncientReaderOp.
+func (r *coldReader) Ancient(kind string, number uint64) ([]byte, error) {
+	if r.offloaded(number) {
+		return r.cold.ancient(kind, number)
+	}
+	return r.AncientReaderOp.Ancient(kind, number)
+}
+
+// AncientRange implements ethdb.AncientReaderOp, stitching together the
+// offloaded and local parts of the range.
+func (r *coldReader) AncientRange(kind string, start, count, maxBytes uint64) ([][]byte, error) {
+	if !r.offloaded(start) {
+		return r.AncientReaderOp.AncientRange(kind, start, count, maxBytes)
+	}
+	tail, _ := r.AncientReaderOp.Tail()
+	end := min(start+count, tail)
+	items, err := r.cold.ancientRange(kind, start, end-start, maxBytes)
+	if err != nil || uint64(len(items)) < end-start || end == start+count {
+		return items, err
+	}
+	var size uint64
+	for _, item := range items {
+		size += uint64(len(item))
+	}
+	if maxBytes != 0 {
+		if size >= maxBytes {
+			return items, nil
+		}
+		maxBytes -= size
+	}
+	rest, err := r.AncientReaderOp.AncientRange(kind, end, start+count-end, maxBytes)
+	if err != nil {
+		return items, nil
+	}
+	return append(items, rest...), nil
+}
+
+// Tail implements ethdb.AncientReaderOp, returning the first offloaded item if
+// anything was moved to the cold tier.
+func (r *coldReader) Tail() (uint64, error) {
+	if tail, head := r.cold.bounds(); head > tail {
+		return tail, nil
+	}
+	return r.AncientReaderOp.Tail()
+}
@@ -0,0 +1,134 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/objstore"
+)
+
+func TestColdTier(t *testing.T) {
+	var (
+		dir      = t.TempDir()
+		blocks   = makeTestBlocks(600, 1)
+		receipts = makeTestReceipts(600, 1)
+	)
+	store, err := objstore.NewDirStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := &ethdb.FreezerEnv{ColdStore: store, ColdAge: 200, ColdCache: 1024}
+
+	f, err := newChainFreezer(dir, "", false, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := WriteAncientBlocks(f, blocks, receipts, big.NewInt(100)); err != nil {
+		t.Fatal(err)
+	}
+	want := make(map[string][][]byte)
+	for _, kind := range []string{ChainFreezerHeaderTable, ChainFreezerBodiesTable, ChainFreezerReceiptTable, ChainFreezerHashTable} {
+		if want[kind], err = f.AncientRange(kind, 0, 600, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	check := func(f *chainFreezer) {
+		t.Helper()
+		for kind, items := range want {
+			for _, n := range []uint64{0, 127, 128, 383, 384, 599} {
+				if ok, _ := f.HasAncient(kind, n); !ok {
+					t.Fatalf("%s %d: missing", kind, n)
+				}
+				have, err := f.Ancient(kind, n)
+				if err != nil {
+					t.Fatalf("%s %d: %v", kind, n, err)
+				}
+				if !bytes.Equal(have, items[n]) {
+					t.Fatalf("%s %d: item mismatch", kind, n)
+				}
+			}
+			// Ranges spanning both tiers are stitched together.
+			have, err := f.AncientRange(kind, 300, 200, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(have) != 200 {
+				t.Fatalf("%s: wrong range length: have %d, want 200", kind, len(have))
+			}
+			for i, item := range have {
+				if !bytes.Equal(item, items[300+i]) {
+					t.Fatalf("%s %d: range item mismatch", kind, 300+i)
+				}
+			}
+		}
+		err := f.ReadAncients(func(op ethdb.AncientReaderOp) error {
+			_, err := op.Ancient(ChainFreezerHeaderTable, 10)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("read through ReadAncients failed: %v", err)
+		}
+		if tail, _ := f.Tail(); tail != 0 {
+			t.Fatalf("wrong tail: have %d, want 0", tail)
+		}
+		if ok, _ := f.HasAncient(ChainFreezerBlobSidecarTable, 10); ok {
+			t.Fatal("missing blob reported as available")
+		}
+	}
+	if err := f.SetupFreezerEnv(env); err != nil {
+		t.Fatal(err)
+	}
+	// All full segments older than the age are offloaded.
+	f.tryOffloadCold(600)
+	if tail, _ := f.AncientStore.Tail(); tail != 384 {
+		t.Fatalf("wrong local tail: have %d, want 384", tail)
+	}
+	check(f)
+
+	// Simulate a crash between advancing the cold metadata and truncating the
+	// local freezer, the next offload must finish the truncation.
+	cold := f.cold.Load()
+	for _, kind := range cold.tables {
+		if err := cold.upload(f.AncientStore, kind, 384, 512); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := cold.writeMeta(coldMeta{Tail: 0, Head: 512}); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	f, err = newChainFreezer(dir, "", false, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := f.SetupFreezerEnv(env); err != nil {
+		t.Fatal(err)
+	}
+	check(f)
+
+	f.tryOffloadCold(600)
+	if tail, _ := f.AncientStore.Tail(); tail != 512 {
+		t.Fatalf("wrong local tail after resume: have %d, want 512", tail)
+	}
+	check(f)
+}
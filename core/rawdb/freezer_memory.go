@@ -401,7 +401,11 @@ func (f *MemoryFreezer) TruncateTail(tail uint64) (uint64, error) {
 	if old >= tail {
 		return old, nil
 	}
-	for _, table := range f.tables {
+	for name, table := range f.tables {
+		// skip empty addition tables
+		if slices.Contains(additionTables, name) && table.items == 0 {
+			continue
+		}
 		if err := table.truncateTail(tail); err != nil {
 			return 0, err
 		}
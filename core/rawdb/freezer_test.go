@@ -425,6 +425,45 @@ func TestFreezer_AdditionTables(t *testing.T) {
 	require.NoError(t, f.Close())
 }
 
+// Tests that truncating the tail of the freezer skips addition tables that
+// are not populated yet.
+func TestFreezer_TruncateTailEmptyAdditionTable(t *testing.T) {
+	defer func(old []string) { additionTables = old }(additionTables)
+	additionTables = []string{"a1"}
+
+	dir := t.TempDir()
+	f, err := NewFreezer(dir, "", false, 0, 2049, map[string]bool{"o1": true, "o2": true, "a1": true})
+	require.NoError(t, err)
+
+	var item = make([]byte, 1024)
+	_, err = f.ModifyAncients(func(op ethdb.AncientWriteOp) error {
+		for i := uint64(0); i < 5; i++ {
+			if err := appendSameItem(op, []string{"o1", "o2"}, i, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	old, err := f.TruncateTail(3)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), old)
+	_, err = f.Ancient("o1", 2)
+	require.Error(t, err)
+	_, err = f.Ancient("o2", 3)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	// The tail survives a restart.
+	f, err = NewFreezer(dir, "", false, 0, 2049, map[string]bool{"o1": true, "o2": true, "a1": true})
+	require.NoError(t, err)
+	tail, err := f.Tail()
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), tail)
+	require.NoError(t, f.Close())
+}
+
 func appendSameItem(op ethdb.AncientWriteOp, tables []string, i uint64, item []byte) error {
 	for _, t := range tables {
 		if err := op.AppendRaw(t, i, item); err != nil {
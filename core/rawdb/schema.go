@@ -76,6 +76,10 @@ var (
 	// trieJournalKey tracks the in-memory trie node layers across restarts.
 	trieJournalKey = []byte("TrieJournal")
 
+	// hbss2pbssProgressKey tracks the progress of the hash to path scheme
+	// conversion of the state trie across restarts.
+	hbss2pbssProgressKey = []byte("Hbss2PbssProgress")
+
 	// txIndexTailKey tracks the oldest block whose transactions have been indexed.
 	txIndexTailKey = []byte("TransactionIndexTail")
 
@@ -99,6 +103,13 @@ var (
 	//PruneAncientFlag flag whether prune ancient
 	pruneAncientKey = []byte("PruneAncientFlag")
 
+	// blockStoreMigrationKey tracks the progress of a block store split or merge.
+	blockStoreMigrationKey = []byte("BlockStoreMigration")
+
+	// blockStoreSplitKey marks a block store split off on its own, without a
+	// separate state store, once all block data has been copied into it.
+	blockStoreSplitKey = []byte("BlockStoreSplit")
+
 	// badBlockKey tracks the list of bad blocks seen by local
 	badBlockKey = []byte("InvalidBlock")
 
@@ -0,0 +1,101 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/core/state/snapshot"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/trie/utils"
+	"github.com/ethereum/go-ethereum/triedb"
+)
+
+// HistoricDB is an implementation of Database interface, with the ability to
+// access the historical state which is resolved from the state histories of
+// the path database.
+//
+// The tries of historical state are not available, the state database opened
+// on top of it works in the no-trie mode and is only meant for state reads,
+// e.g. serving RPC requests and tracing.
+type HistoricDB struct {
+	ctx           context.Context // Context for aborting the aggregation of the histories
+	disk          ethdb.KeyValueStore
+	triedb        *triedb.Database
+	codeCache     *lru.SizeConstrainedCache[common.Hash, []byte]
+	codeSizeCache *lru.Cache[common.Hash, int]
+	pointCache    *utils.PointCache
+}
+
+// NewHistoricDatabase creates a historic state database. The given context
+// bounds the aggregation of the state histories when opening a state.
+func NewHistoricDatabase(ctx context.Context, disk ethdb.KeyValueStore, triedb *triedb.Database) *HistoricDB {
+	return &HistoricDB{
+		ctx:           ctx,
+		disk:          disk,
+		triedb:        triedb,
+		codeCache:     lru.NewSizeConstrainedCache[common.Hash, []byte](codeCacheSize),
+		codeSizeCache: lru.NewCache[common.Hash, int](codeSizeCacheSize),
+		pointCache:    utils.NewPointCache(pointCacheSize),
+	}
+}
+
+// Reader implements Database interface, returning a reader of the specific state.
+func (db *HistoricDB) Reader(stateRoot common.Hash) (Reader, error) {
+	hr, err := db.triedb.HistoricReader(db.ctx, stateRoot)
+	if err != nil {
+		return nil, err
+	}
+	return newReader(newCachingCodeReader(db.disk, db.codeCache, db.codeSizeCache), newHistoricReader(hr)), nil
+}
+
+// OpenTrie opens the main account trie. The tries of historical state are not
+// available, an empty trie is returned instead.
+func (db *HistoricDB) OpenTrie(root common.Hash) (Trie, error) {
+	return trie.NewEmptyTrie(), nil
+}
+
+// OpenStorageTrie opens the storage trie of an account. The tries of historical
+// state are not available, an empty trie is returned instead.
+func (db *HistoricDB) OpenStorageTrie(stateRoot common.Hash, address common.Address, root common.Hash, self Trie) (Trie, error) {
+	return trie.NewEmptyTrie(), nil
+}
+
+// PointCache returns the cache holding points used in verkle tree key computation.
+func (db *HistoricDB) PointCache() *utils.PointCache {
+	return db.pointCache
+}
+
+// TrieDB returns the underlying trie database for managing trie nodes.
+func (db *HistoricDB) TrieDB() *triedb.Database {
+	return db.triedb
+}
+
+// NoTries returns whether the database has tries storage, which is always
+// true for the historic database.
+func (db *HistoricDB) NoTries() bool {
+	return true
+}
+
+// Snapshot returns the underlying state snapshot, which is not available for
+// the historic database.
+func (db *HistoricDB) Snapshot() *snapshot.Tree {
+	return nil
+}
@@ -0,0 +1,211 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/internal/testrand"
+	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/ethereum/go-ethereum/triedb/pathdb"
+	"github.com/holiman/uint256"
+)
+
+// historicTester contains the same sequence of states maintained by a hash
+// scheme archive database and a path scheme database with state histories.
+type historicTester struct {
+	archive  *CachingDB    // Hash scheme database with all states persisted
+	historic *HistoricDB   // Path scheme database resolving states from histories
+	roots    []common.Hash // State roots of the blocks, the last one is the live state
+	addrs    []common.Address
+	slots    []common.Hash
+}
+
+func newHistoricTester(tb testing.TB, blocks int, accounts int, changes int) *historicTester {
+	pdisk, err := rawdb.NewDatabaseWithFreezer(rawdb.NewMemoryDatabase(), "", "", false, false, false, false, false)
+	if err != nil {
+		tb.Fatalf("Failed to create database: %v", err)
+	}
+	var (
+		htdb = triedb.NewDatabase(rawdb.NewMemoryDatabase(), triedb.HashDefaults)
+		ptdb = triedb.NewDatabase(pdisk, &triedb.Config{PathDB: pathdb.Defaults})
+		t    = &historicTester{
+			archive:  NewDatabase(htdb, nil),
+			historic: NewHistoricDatabase(context.Background(), pdisk, ptdb),
+		}
+		hdb = NewDatabase(htdb, nil)
+		pdb = NewDatabase(ptdb, nil)
+	)
+	for i := 0; i < accounts; i++ {
+		t.addrs = append(t.addrs, testrand.Address())
+	}
+	for i := 0; i < 16; i++ {
+		t.slots = append(t.slots, testrand.Hash())
+	}
+	root := types.EmptyRootHash
+	for i := 0; i < blocks; i++ {
+		hstate, _ := New(root, hdb)
+		pstate, _ := New(root, pdb)
+
+		// Apply the same random changes to both states
+		for j := 0; j < changes; j++ {
+			var (
+				addr  = t.addrs[rand.Intn(len(t.addrs))]
+				slot  = t.slots[rand.Intn(len(t.slots))]
+				value = testrand.Hash()
+			)
+			for _, state := range []*StateDB{hstate, pstate} {
+				state.AddBalance(addr, uint256.NewInt(uint64(j+1)), tracing.BalanceChangeUnspecified)
+				state.SetNonce(addr, uint64(i), tracing.NonceChangeUnspecified)
+				state.SetState(addr, slot, value)
+			}
+		}
+		hroot, _, err := hstate.Commit(uint64(i+1), false, false)
+		if err != nil {
+			tb.Fatalf("Failed to commit state: %v", err)
+		}
+		proot, _, err := pstate.Commit(uint64(i+1), false, false)
+		if err != nil {
+			tb.Fatalf("Failed to commit state: %v", err)
+		}
+		if hroot != proot {
+			tb.Fatalf("State root mismatch, hash: %x, path: %x", hroot, proot)
+		}
+		if err := htdb.Commit(hroot, false); err != nil {
+			tb.Fatalf("Failed to persist state: %v", err)
+		}
+		root = hroot
+		t.roots = append(t.roots, root)
+	}
+	// Flush all the layers into disk, all the states except the last one
+	// are only available via state histories.
+	if err := ptdb.Commit(root, false); err != nil {
+		tb.Fatalf("Failed to persist state: %v", err)
+	}
+	return t
+}
+
+func TestHistoricDatabase(t *testing.T) {
+	tester := newHistoricTester(t, 32, 64, 32)
+
+	for i, root := range tester.roots[:len(tester.roots)-1] {
+		want, err := New(root, tester.archive)
+		if err != nil {
+			t.Fatalf("Failed to open archive state %d: %v", i, err)
+		}
+		got, err := New(root, tester.historic)
+		if err != nil {
+			t.Fatalf("Failed to open historic state %d: %v", i, err)
+		}
+		for _, addr := range tester.addrs {
+			if want.GetBalance(addr).Cmp(got.GetBalance(addr)) != 0 {
+				t.Fatalf("Balance mismatch of %x in state %d, want %v, got %v", addr, i, want.GetBalance(addr), got.GetBalance(addr))
+			}
+			if want.GetNonce(addr) != got.GetNonce(addr) {
+				t.Fatalf("Nonce mismatch of %x in state %d, want %d, got %d", addr, i, want.GetNonce(addr), got.GetNonce(addr))
+			}
+			if want.GetStorageRoot(addr) != got.GetStorageRoot(addr) {
+				t.Fatalf("Storage root mismatch of %x in state %d", addr, i)
+			}
+			for _, slot := range tester.slots {
+				if want.GetState(addr, slot) != got.GetState(addr, slot) {
+					t.Fatalf("Slot mismatch of %x/%x in state %d, want %x, got %x", addr, slot, i, want.GetState(addr, slot), got.GetState(addr, slot))
+				}
+			}
+		}
+		if err := got.Error(); err != nil {
+			t.Fatalf("Failed to read historic state %d: %v", i, err)
+		}
+	}
+	// The unknown state is not served by the historic database
+	if _, err := New(common.Hash{0x1}, tester.historic); err == nil {
+		t.Fatal("Expected error for unknown state")
+	}
+}
+
+// benchmarkHistoricRead measures the state reads on the given database from
+// the states deep in the history.
+func benchmarkHistoricRead(b *testing.B, tester *historicTester, db Database, depth int) {
+	root := tester.roots[len(tester.roots)-1-depth]
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		state, err := New(root, db)
+		if err != nil {
+			b.Fatalf("Failed to open state: %v", err)
+		}
+		addr := tester.addrs[i%len(tester.addrs)]
+		state.GetBalance(addr)
+		state.GetState(addr, tester.slots[i%len(tester.slots)])
+		if err := state.Error(); err != nil {
+			b.Fatalf("Failed to read state: %v", err)
+		}
+	}
+}
+
+// BenchmarkHistoricRead compares the state reads from the states deep in the
+// history, served by a hash scheme archive database and by the path database
+// with state histories. The aggregated state diffs are reused across reads in
+// path scheme.
+func BenchmarkHistoricRead(b *testing.B) {
+	tester := newHistoricTester(b, 256, 1000, 200)
+	for _, depth := range []int{16, 128} {
+		b.Run(fmt.Sprintf("hash-archive/depth-%d", depth), func(b *testing.B) {
+			benchmarkHistoricRead(b, tester, tester.archive, depth)
+		})
+		b.Run(fmt.Sprintf("path-history/depth-%d", depth), func(b *testing.B) {
+			benchmarkHistoricRead(b, tester, tester.historic, depth)
+		})
+	}
+}
+
+// BenchmarkHistoricOpen compares opening the states deep in the history along
+// with a single read, served by a hash scheme archive database and by the path
+// database with state histories. The states are rotated to defeat the reader
+// cache in path scheme, measuring the aggregation of the state diffs.
+func BenchmarkHistoricOpen(b *testing.B) {
+	tester := newHistoricTester(b, 256, 1000, 200)
+	for _, c := range []struct {
+		name string
+		db   Database
+	}{
+		{"hash-archive", tester.archive},
+		{"path-history", tester.historic},
+	} {
+		b.Run(c.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				root := tester.roots[len(tester.roots)-128+i%64]
+				state, err := New(root, c.db)
+				if err != nil {
+					b.Fatalf("Failed to open state: %v", err)
+				}
+				state.GetBalance(tester.addrs[i%len(tester.addrs)])
+				if err := state.Error(); err != nil {
+					b.Fatalf("Failed to read state: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -1102,6 +1102,9 @@ func (p *BlobPool) validateTx(tx *types.Transaction) error {
 			return txpool.ErrInBlackList
 		}
 	}
+	if err := p.config.Filters.Check(tx, sender); err != nil {
+		return err
+	}
 	// Ensure the transaction adheres to basic pool filters (type, size, tip) and
 	// consensus rules
 	baseOpts := &txpool.ValidationOptions{
@@ -1722,6 +1725,12 @@ func (p *BlobPool) ContentFrom(addr common.Address) ([]*types.Transaction, []*ty
 	return []*types.Transaction{}, []*types.Transaction{}
 }
 
+// Queued retrieves the hashes of at most limit queued transactions. The blob
+// pool has no non-executable transactions, so this method returns nothing.
+func (p *BlobPool) Queued(limit int) []common.Hash {
+	return nil
+}
+
 // Status returns the known status (unknown/pending/queued) of a transaction
 // identified by their hashes.
 func (p *BlobPool) Status(hash common.Hash) txpool.TxStatus {
@@ -1731,6 +1740,18 @@ func (p *BlobPool) Status(hash common.Hash) txpool.TxStatus {
 	return txpool.TxStatusUnknown
 }
 
+// History is not supported by the blob pool, it is just here to implement the
+// txpool.SubPool interface.
+func (p *BlobPool) History(hash common.Hash) []*txpool.TxEvent {
+	return nil
+}
+
+// SubscribeDroppedTransactions is not supported by the blob pool, it is just
+// here to implement the txpool.SubPool interface.
+func (p *BlobPool) SubscribeDroppedTransactions(ch chan<- txpool.DroppedTxsEvent) event.Subscription {
+	return nil
+}
+
 func (p *BlobPool) SetMaxGas(maxGas uint64) {
 	p.maxGas.Store(maxGas)
 }
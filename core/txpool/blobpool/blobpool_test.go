@@ -25,10 +25,12 @@ import (
 	"math/big"
 	"os"
 	"path/filepath"
+	"reflect"
 	"sync"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
 	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
 	"github.com/ethereum/go-ethereum/core"
@@ -945,6 +947,89 @@ func TestOpenHeap(t *testing.T) {
 	verifyPoolInternals(t, pool)
 }
 
+// Tests that the status of the pool reports the eviction order of the accounts,
+// and that the same status is rebuilt offline from the persistent store.
+func TestInspect(t *testing.T) {
+	// Create a temporary folder for the persistent backend
+	storage := t.TempDir()
+
+	os.MkdirAll(filepath.Join(storage, pendingTransactionStore), 0700)
+	store, _ := billy.Open(billy.Options{Path: filepath.Join(storage, pendingTransactionStore)}, newSlotter(testMaxBlobsPerBlock), nil)
+
+	// Insert a few transactions from a few accounts, with the eviction order
+	// following the fee caps of the accounts' cheapest transactions
+	var (
+		key1, _ = crypto.GenerateKey()
+		key2, _ = crypto.GenerateKey()
+		key3, _ = crypto.GenerateKey()
+
+		addr1 = crypto.PubkeyToAddress(key1.PublicKey)
+		addr2 = crypto.PubkeyToAddress(key2.PublicKey)
+		addr3 = crypto.PubkeyToAddress(key3.PublicKey)
+
+		txs = []*types.Transaction{
+			makeTx(0, 1, 1000, 90, key1),
+			makeTx(1, 1, 900, 80, key1),
+			makeTx(0, 1, 800, 70, key2),
+			makeTx(0, 1, 1500, 110, key3),
+		}
+		ranks = map[common.Address]int{addr2: 0, addr1: 1, addr3: 2}
+	)
+	for _, tx := range txs {
+		blob, _ := rlp.EncodeToBytes(tx)
+		store.Put(blob)
+	}
+	store.Close()
+
+	// Create a blob pool out of the pre-seeded data
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	statedb.AddBalance(addr1, uint256.NewInt(1_000_000_000), tracing.BalanceChangeUnspecified)
+	statedb.AddBalance(addr2, uint256.NewInt(1_000_000_000), tracing.BalanceChangeUnspecified)
+	statedb.AddBalance(addr3, uint256.NewInt(1_000_000_000), tracing.BalanceChangeUnspecified)
+	statedb.Commit(0, true, false)
+
+	chain := &testBlockChain{
+		config:  params.MainnetChainConfig,
+		basefee: uint256.NewInt(1050),
+		blobfee: uint256.NewInt(105),
+		statedb: statedb,
+	}
+	pool := New(Config{Datadir: storage}, chain)
+	if err := pool.Init(1, chain.CurrentBlock(), makeAddressReserver()); err != nil {
+		t.Fatalf("failed to create blob pool: %v", err)
+	}
+	verifyPoolInternals(t, pool)
+
+	status := pool.Inspect()
+	if len(status.Accounts) != len(ranks) {
+		t.Fatalf("account count mismatch: have %d, want %d", len(status.Accounts), len(ranks))
+	}
+	var stored hexutil.Uint64
+	for addr, rank := range ranks {
+		account := status.Accounts[addr]
+		if account.EvictionRank != rank {
+			t.Errorf("eviction rank mismatch for %v: have %d, want %d", addr, account.EvictionRank, rank)
+		}
+		stored += account.Size
+	}
+	if len(status.Accounts[addr1].Txs) != 2 || status.Accounts[addr1].Txs[1].Hash != txs[1].Hash() {
+		t.Errorf("account transactions mismatch: %v", status.Accounts[addr1].Txs)
+	}
+	if status.Stored != stored || uint64(status.Stored) != pool.stored {
+		t.Errorf("stored size mismatch: have %d, accounts %d, pool %d", status.Stored, stored, pool.stored)
+	}
+	pool.Close()
+
+	// Rebuild the status from the persistent store, it should be the same
+	offline, err := InspectStore(pool.config, chain.config, chain.CurrentBlock())
+	if err != nil {
+		t.Fatalf("failed to inspect blob pool store: %v", err)
+	}
+	if !reflect.DeepEqual(status, offline) {
+		t.Errorf("offline status mismatch: have %+v, want %+v", offline, status)
+	}
+}
+
 // Tests that after the pool's previous state is loaded back, any transactions
 // over the new storage cap will get dropped.
 func TestOpenCap(t *testing.T) {
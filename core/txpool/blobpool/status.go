@@ -0,0 +1,227 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package blobpool
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/holiman/billy"
+	"github.com/holiman/uint256"
+)
+
+// TxStatus is the storage and eviction details of a pooled blob transaction.
+type TxStatus struct {
+	Hash     common.Hash    `json:"hash"`
+	Nonce    hexutil.Uint64 `json:"nonce"`
+	Blobs    int            `json:"blobs"`
+	Size     hexutil.Uint64 `json:"size"`     // Bytes used in the persistent store
+	Priority int            `json:"priority"` // Eviction priority, the lower the sooner evicted
+}
+
+// AccountStatus is the blob transactions of an account and their storage usage.
+type AccountStatus struct {
+	Txs          []*TxStatus    `json:"txs"`          // Transactions sorted by nonce
+	Size         hexutil.Uint64 `json:"size"`         // Bytes used by all the transactions
+	EvictionRank int            `json:"evictionRank"` // Position in the eviction order, 0 is evicted from first
+}
+
+// LimboStatus is an included but not yet finalized blob transaction, kept to
+// resurrect it in case of a reorg.
+type LimboStatus struct {
+	Hash  common.Hash    `json:"hash"`
+	Block hexutil.Uint64 `json:"block"`
+}
+
+// Status is the storage usage and the eviction order of the blob pool. When the
+// pool exceeds its data cap, the last transaction of the account with the lowest
+// eviction rank is dropped first.
+type Status struct {
+	Datacap  hexutil.Uint64                    `json:"datacap"`
+	Stored   hexutil.Uint64                    `json:"stored"`
+	Accounts map[common.Address]*AccountStatus `json:"accounts"`
+	Limbo    []*LimboStatus                    `json:"limbo"`
+}
+
+// order returns the accounts of the heap sorted by eviction order, the first one
+// being the next to evict from.
+func (h *evictHeap) order() []common.Address {
+	sorted := &evictHeap{
+		metas:        h.metas,
+		basefeeJumps: h.basefeeJumps,
+		blobfeeJumps: h.blobfeeJumps,
+		addrs:        slices.Clone(h.addrs),
+		index:        maps.Clone(h.index),
+	}
+	sort.Sort(sorted)
+	return sorted.addrs
+}
+
+// newStatus assembles the status of a blob pool from its indices.
+func newStatus(datacap uint64, stored uint64, index map[common.Address][]*blobTxMeta, evict *evictHeap, limbo *limbo) *Status {
+	status := &Status{
+		Datacap:  hexutil.Uint64(datacap),
+		Stored:   hexutil.Uint64(stored),
+		Accounts: make(map[common.Address]*AccountStatus, len(index)),
+		Limbo:    make([]*LimboStatus, 0, len(limbo.index)),
+	}
+	for rank, addr := range evict.order() {
+		account := &AccountStatus{
+			Txs:          make([]*TxStatus, len(index[addr])),
+			EvictionRank: rank,
+		}
+		for i, meta := range index[addr] {
+			priority := evictionPriority(evict.basefeeJumps, meta.evictionExecFeeJumps, evict.blobfeeJumps, meta.evictionBlobFeeJumps)
+			if priority > 0 {
+				priority = 0
+			}
+			account.Txs[i] = &TxStatus{
+				Hash:     meta.hash,
+				Nonce:    hexutil.Uint64(meta.nonce),
+				Blobs:    len(meta.vhashes),
+				Size:     hexutil.Uint64(meta.size),
+				Priority: priority,
+			}
+			account.Size += hexutil.Uint64(meta.size)
+		}
+		status.Accounts[addr] = account
+	}
+	for block, txs := range limbo.groups {
+		for _, hash := range txs {
+			status.Limbo = append(status.Limbo, &LimboStatus{Hash: hash, Block: hexutil.Uint64(block)})
+		}
+	}
+	slices.SortFunc(status.Limbo, func(a, b *LimboStatus) int {
+		if a.Block != b.Block {
+			return cmp.Compare(a.Block, b.Block)
+		}
+		return a.Hash.Cmp(b.Hash)
+	})
+	return status
+}
+
+// Inspect returns the storage usage of the pool, the eviction order of its
+// transactions and the contents of the limbo.
+func (p *BlobPool) Inspect() *Status {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	return newStatus(p.config.Datacap, p.stored, p.index, p.evict, p.limbo)
+}
+
+// InspectStore returns the status of the blob pool persisted in the configured
+// data directory, with the eviction order as of the given chain head. It opens
+// the stores read only, so it must not be used while the node is running.
+func InspectStore(config Config, chain *params.ChainConfig, head *types.Header) (*Status, error) {
+	var (
+		queuedir = filepath.Join(config.Datadir, pendingTransactionStore)
+		limbodir = filepath.Join(config.Datadir, limboedTransactionStore)
+		signer   = types.LatestSigner(chain)
+		maxBlobs = eip4844.LatestMaxBlobsPerBlock(chain)
+
+		index  = make(map[common.Address][]*blobTxMeta)
+		stored uint64
+	)
+	for _, dir := range []string{queuedir, limbodir} {
+		if _, err := os.Stat(dir); err != nil {
+			return nil, fmt.Errorf("blob pool store not found: %v", err)
+		}
+	}
+	// Index the pooled transactions, skipping anything unprocessable the same
+	// way the pool would on startup
+	parse := func(id uint64, size uint32, blob []byte) {
+		tx := new(types.Transaction)
+		if err := rlp.DecodeBytes(blob, tx); err != nil {
+			log.Warn("Failed to decode blob pool entry", "id", id, "err", err)
+			return
+		}
+		sender, err := types.Sender(signer, tx)
+		if err != nil {
+			log.Warn("Failed to recover blob tx sender", "id", id, "hash", tx.Hash(), "err", err)
+			return
+		}
+		index[sender] = append(index[sender], newBlobTxMeta(id, size, tx))
+		stored += uint64(size)
+	}
+	store, err := billy.Open(billy.Options{Path: queuedir, Readonly: true}, newSlotter(maxBlobs), parse)
+	if err != nil {
+		return nil, err
+	}
+	defer store.Close()
+
+	for _, txs := range index {
+		sort.Slice(txs, func(i, j int) bool {
+			return txs[i].nonce < txs[j].nonce
+		})
+		setEvictionThresholds(txs)
+	}
+	// Index the limboed transactions too
+	limboed := &limbo{
+		index:  make(map[common.Hash]uint64),
+		groups: make(map[uint64]map[uint64]common.Hash),
+	}
+	limboStore, err := billy.Open(billy.Options{Path: limbodir, Readonly: true}, newSlotter(maxBlobs), func(id uint64, size uint32, data []byte) {
+		limboed.parseBlob(id, data)
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer limboStore.Close()
+
+	// Order the accounts by the fees as of the given head
+	var (
+		basefee = uint256.MustFromBig(eip1559.CalcBaseFee(chain, head))
+		blobfee = uint256.NewInt(params.BlobTxMinBlobGasprice)
+	)
+	if head.ExcessBlobGas != nil {
+		blobfee = uint256.MustFromBig(eip4844.CalcBlobFee(chain, head))
+	}
+	return newStatus(config.Datacap, stored, index, newPriceHeap(basefee, blobfee, index), limboed), nil
+}
+
+// setEvictionThresholds initializes the eviction thresholds of the nonce sorted
+// transactions of an account, as the minimum fees across all previous nonces.
+func setEvictionThresholds(txs []*blobTxMeta) {
+	for i, tx := range txs {
+		tx.evictionExecTip = tx.execTipCap
+		tx.evictionExecFeeJumps = tx.basefeeJumps
+		tx.evictionBlobFeeJumps = tx.blobfeeJumps
+
+		if i == 0 || tx.nonce != txs[i-1].nonce+1 {
+			continue
+		}
+		prev := txs[i-1]
+		if tx.evictionExecTip.Cmp(prev.evictionExecTip) > 0 {
+			tx.evictionExecTip = prev.evictionExecTip
+		}
+		tx.evictionExecFeeJumps = min(tx.evictionExecFeeJumps, prev.evictionExecFeeJumps)
+		tx.evictionBlobFeeJumps = min(tx.evictionBlobFeeJumps, prev.evictionBlobFeeJumps)
+	}
+}
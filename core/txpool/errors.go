@@ -59,4 +59,21 @@ var (
 
 	// ErrInBlackList is returned if the transaction send by banned address
 	ErrInBlackList = errors.New("sender or to in black list")
+
+	// ErrSenderRateLimited is returned if the sender of a transaction submitted
+	// more transactions recently than the pool accepts per account.
+	ErrSenderRateLimited = errors.New("sender rate limit exceeded")
+
+	// ErrContractRateLimited is returned if the destination contract of a
+	// transaction was targeted by more transactions recently than the pool
+	// accepts per contract.
+	ErrContractRateLimited = errors.New("contract rate limit exceeded")
+
+	// ErrPeerRateLimited is returned if the peer a transaction originated from
+	// delivered more transactions recently than accepted per peer.
+	ErrPeerRateLimited = errors.New("peer rate limit exceeded")
+
+	// ErrTxFiltered is returned if a transaction is rejected by one of the
+	// admission filters configured for the pool.
+	ErrTxFiltered = errors.New("transaction filtered")
 )
@@ -0,0 +1,257 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// filterReloadInterval is how often the file backed filters check whether their
+// file changed.
+var filterReloadInterval = 10 * time.Second
+
+// TxFilter is an admission check of the pools on top of the consensus and pool
+// rules, e.g. to enforce compliance policies. Filters are called concurrently
+// and must not block, as they are run for every transaction added to the pools.
+type TxFilter interface {
+	// Name identifies the filter in the rejection errors and metrics.
+	Name() string
+
+	// Filter returns an error if the transaction of the given sender must not
+	// be admitted into the pool.
+	Filter(tx *types.Transaction, from common.Address) error
+}
+
+// TxFilters is a set of admission filters, all of which a transaction must pass.
+type TxFilters []TxFilter
+
+// Check runs a transaction through all the filters, returning the rejection of
+// the first one failing it.
+func (filters TxFilters) Check(tx *types.Transaction, from common.Address) error {
+	for _, filter := range filters {
+		if err := filter.Filter(tx, from); err != nil {
+			metrics.GetOrRegisterMeter("txpool/filter/"+filter.Name(), nil).Mark(1)
+			return fmt.Errorf("%w: %s: %v", ErrTxFiltered, filter.Name(), err)
+		}
+	}
+	return nil
+}
+
+// fileSet is a set of entries listed in a file, one per line, which is reloaded
+// whenever the file changes. Empty lines and lines starting with '#' are ignored.
+type fileSet[T comparable] struct {
+	name  string                  // Name of the filter owning the set
+	path  string                  // File the entries are listed in
+	parse func(string) (T, error) // Parser of a line of the file
+
+	entries map[T]struct{} // Entries of the last successful load
+	modtime time.Time      // Modification time of the file at the last successful load
+	lock    sync.RWMutex
+
+	checked atomic.Int64   // Time of the last file change check, in unix nanoseconds
+	gauge   *metrics.Gauge // Number of entries loaded
+}
+
+// newFileSet loads the entries listed in a file.
+func newFileSet[T comparable](name string, path string, parse func(string) (T, error)) (*fileSet[T], error) {
+	set := &fileSet[T]{
+		name:  name,
+		path:  path,
+		parse: parse,
+		gauge: metrics.GetOrRegisterGauge("txpool/filter/"+name+"/entries", nil),
+	}
+	if err := set.reload(); err != nil {
+		return nil, err
+	}
+	set.checked.Store(time.Now().UnixNano())
+	return set, nil
+}
+
+// reload reads and parses the file, replacing the entries only if all of them
+// are valid.
+func (set *fileSet[T]) reload() error {
+	info, err := os.Stat(set.path)
+	if err != nil {
+		return err
+	}
+	blob, err := os.ReadFile(set.path)
+	if err != nil {
+		return err
+	}
+	var (
+		entries = make(map[T]struct{})
+		scanner = bufio.NewScanner(bytes.NewReader(blob))
+	)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		entry, err := set.parse(line)
+		if err != nil {
+			return fmt.Errorf("%s line %d: %v", set.path, number, err)
+		}
+		entries[entry] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	set.lock.Lock()
+	set.entries, set.modtime = entries, info.ModTime()
+	set.lock.Unlock()
+
+	set.gauge.Update(int64(len(entries)))
+	log.Info("Loaded transaction filter", "filter", set.name, "path", set.path, "entries", len(entries))
+	return nil
+}
+
+// maybeReload reloads the file if it changed since the last load. The file is
+// checked at most once per reload interval, by a single caller.
+func (set *fileSet[T]) maybeReload() {
+	var (
+		now  = time.Now().UnixNano()
+		last = set.checked.Load()
+	)
+	if now-last < int64(filterReloadInterval) || !set.checked.CompareAndSwap(last, now) {
+		return
+	}
+	info, err := os.Stat(set.path)
+	if err != nil {
+		log.Warn("Failed to check transaction filter", "filter", set.name, "path", set.path, "err", err)
+		return
+	}
+	set.lock.RLock()
+	modtime := set.modtime
+	set.lock.RUnlock()
+
+	if info.ModTime().Equal(modtime) {
+		return
+	}
+	if err := set.reload(); err != nil {
+		log.Warn("Failed to reload transaction filter, keeping previous entries", "filter", set.name, "path", set.path, "err", err)
+	}
+}
+
+// contains reports whether an entry is listed in the file.
+func (set *fileSet[T]) contains(entry T) bool {
+	set.maybeReload()
+
+	set.lock.RLock()
+	defer set.lock.RUnlock()
+
+	_, ok := set.entries[entry]
+	return ok
+}
+
+// AddressFilter rejects the transactions sent from, sent to or delegating the
+// code of the addresses listed in a file. The file is reloaded when changed.
+type AddressFilter struct {
+	blocked *fileSet[common.Address]
+}
+
+// NewAddressFilter creates a filter blocking the addresses listed in a file, one
+// hex address per line.
+func NewAddressFilter(path string) (*AddressFilter, error) {
+	blocked, err := newFileSet("blocklist", path, func(line string) (common.Address, error) {
+		if !common.IsHexAddress(line) {
+			return common.Address{}, fmt.Errorf("invalid address %q", line)
+		}
+		return common.HexToAddress(line), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &AddressFilter{blocked: blocked}, nil
+}
+
+// Name implements TxFilter, returning the name of the filter.
+func (f *AddressFilter) Name() string {
+	return f.blocked.name
+}
+
+// Filter implements TxFilter, rejecting the transactions involving any of the
+// blocked addresses.
+func (f *AddressFilter) Filter(tx *types.Transaction, from common.Address) error {
+	if f.blocked.contains(from) {
+		return fmt.Errorf("sender %v blocked", from)
+	}
+	if to := tx.To(); to != nil && f.blocked.contains(*to) {
+		return fmt.Errorf("recipient %v blocked", *to)
+	}
+	for _, authority := range tx.SetCodeAuthorities() {
+		if f.blocked.contains(authority) {
+			return fmt.Errorf("authority %v blocked", authority)
+		}
+	}
+	return nil
+}
+
+// SelectorFilter rejects the contract calls to the function selectors listed in
+// a file, whichever the called contract. The file is reloaded when changed.
+type SelectorFilter struct {
+	blocked *fileSet[[4]byte]
+}
+
+// NewSelectorFilter creates a filter blocking the function selectors listed in a
+// file, one 4 byte hex selector per line.
+func NewSelectorFilter(path string) (*SelectorFilter, error) {
+	blocked, err := newFileSet("selectors", path, func(line string) ([4]byte, error) {
+		var selector [4]byte
+
+		blob, err := hexutil.Decode(line)
+		if err != nil || len(blob) != len(selector) {
+			return selector, fmt.Errorf("invalid selector %q", line)
+		}
+		copy(selector[:], blob)
+		return selector, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &SelectorFilter{blocked: blocked}, nil
+}
+
+// Name implements TxFilter, returning the name of the filter.
+func (f *SelectorFilter) Name() string {
+	return f.blocked.name
+}
+
+// Filter implements TxFilter, rejecting the contract calls to any of the blocked
+// function selectors. Contract creations are never rejected.
+func (f *SelectorFilter) Filter(tx *types.Transaction, from common.Address) error {
+	data := tx.Data()
+	if tx.To() == nil || len(data) < 4 {
+		return nil
+	}
+	if selector := [4]byte(data[:4]); f.blocked.contains(selector) {
+		return fmt.Errorf("selector %#x blocked", selector)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Tests that the file backed filters pick up the changes of their file, and keep
+// their entries if the file becomes invalid.
+func TestFilterReload(t *testing.T) {
+	defer func(interval time.Duration) { filterReloadInterval = interval }(filterReloadInterval)
+	filterReloadInterval = 0
+
+	var (
+		path    = filepath.Join(t.TempDir(), "blocklist")
+		blocked = common.HexToAddress("0xdead")
+		tx      = types.NewTransaction(0, blocked, big.NewInt(0), 21000, big.NewInt(1), nil)
+		modtime = time.Now()
+	)
+	// write replaces the file, making sure its modification time changes
+	write := func(content string) {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write filter file: %v", err)
+		}
+		modtime = modtime.Add(time.Second)
+		if err := os.Chtimes(path, modtime, modtime); err != nil {
+			t.Fatalf("Failed to touch filter file: %v", err)
+		}
+	}
+	write("")
+
+	filter, err := NewAddressFilter(path)
+	if err != nil {
+		t.Fatalf("Failed to create filter: %v", err)
+	}
+	filters := TxFilters{filter}
+	if err := filters.Check(tx, common.Address{}); err != nil {
+		t.Fatalf("Transaction filtered by empty blocklist: %v", err)
+	}
+	write(blocked.Hex())
+	if err := filters.Check(tx, common.Address{}); !errors.Is(err, ErrTxFiltered) {
+		t.Fatalf("Filter error mismatch after reload: have %v, want %v", err, ErrTxFiltered)
+	}
+	write("not an address")
+	if err := filters.Check(tx, common.Address{}); !errors.Is(err, ErrTxFiltered) {
+		t.Fatalf("Filter error mismatch after invalid reload: have %v, want %v", err, ErrTxFiltered)
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// txHistoryEvents is the maximum number of lifecycle events retained for
+// a single transaction. Beyond it, the oldest events are discarded.
+const txHistoryEvents = 16
+
+// TxEventType is the kind of change in the lifecycle of a pooled transaction.
+type TxEventType string
+
+const (
+	TxEventQueued  TxEventType = "queued"  // Entered the non-executable queue
+	TxEventPending TxEventType = "pending" // Entered the executable set
+	TxEventDemoted TxEventType = "demoted" // Moved back from the executable set to the queue
+	TxEventDropped TxEventType = "dropped" // Removed from the pool
+)
+
+// DropReason is the reason why a transaction was removed from the pool.
+type DropReason string
+
+const (
+	DropUnderpriced  DropReason = "underpriced"        // Evicted by better paying transactions from a full pool
+	DropReplaced     DropReason = "replaced"           // Replaced by a transaction with the same nonce
+	DropOverflowPool DropReason = "overflowpool"       // Moved from a full pool to the overflow pool
+	DropOverflowFull DropReason = "overflowpool-full"  // Evicted by newer transactions from the full overflow pool
+	DropLifetime     DropReason = "lifetime"           // Queued longer than the configured lifetime
+	DropNonceTooLow  DropReason = "nonce-too-low"      // Account nonce moved past it, included or superseded on chain
+	DropUnpayable    DropReason = "insufficient-funds" // Sender can't pay for it anymore, or it exceeds the block gas limit
+	DropAccountLimit DropReason = "account-limit"      // Exceeds the number of queued transactions per account
+	DropPoolLimit    DropReason = "pool-limit"         // Exceeds the number of pending or queued transactions of the pool
+	DropGasTip       DropReason = "gas-tip"            // Below the raised minimum gas tip
+	DropRevalidation DropReason = "revalidation"       // Rejected when re-added after a reorg or from the overflow pool
+	DropCleared      DropReason = "cleared"            // Pool explicitly cleared
+	DropExpired      DropReason = "expired"            // Inclusion conditions can't be met anymore
+)
+
+// TxEvent is an entry in the lifecycle log of a transaction.
+type TxEvent struct {
+	Time   time.Time   // Time of the event
+	Type   TxEventType // Kind of the event
+	Reason DropReason  // Reason of the removal, only for dropped events
+	Error  string      // Validation error of the rejection, if any
+}
+
+// DroppedTx is a transaction removed from the pool, with the reason.
+type DroppedTx struct {
+	Tx     *types.Transaction
+	Reason DropReason
+	Error  string
+}
+
+// DroppedTxsEvent is posted when a batch of transactions is removed from the
+// pool for any reason other than their nonce being used up on chain.
+type DroppedTxsEvent struct{ Txs []*DroppedTx }
+
+// TxHistory is a bounded in-memory log of the lifecycle events of transactions,
+// keyed by transaction hash. The least recently updated transactions are
+// forgotten first once the limit is reached.
+//
+// Dropped transactions are also buffered, to be published on the event feed by
+// Flush once the pool lock is released.
+type TxHistory struct {
+	logs  lru.BasicLRU[common.Hash, []*TxEvent]
+	drops []*DroppedTx
+	feed  event.Feed
+	lock  sync.Mutex
+}
+
+// NewTxHistory creates a lifecycle log retaining the events of the given number
+// of transactions.
+func NewTxHistory(limit int) *TxHistory {
+	return &TxHistory{logs: lru.NewBasicLRU[common.Hash, []*TxEvent](limit)}
+}
+
+// record appends an event to the log of the given transaction.
+func (h *TxHistory) record(hash common.Hash, ev *TxEvent) {
+	events, _ := h.logs.Get(hash)
+	if len(events) >= txHistoryEvents {
+		events = append(events[:0:0], events[len(events)-txHistoryEvents+1:]...)
+	}
+	h.logs.Add(hash, append(events, ev))
+}
+
+// Record logs a non-terminal lifecycle event of the given transaction.
+func (h *TxHistory) Record(tx *types.Transaction, typ TxEventType) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.record(tx.Hash(), &TxEvent{Time: time.Now(), Type: typ})
+}
+
+// Drop logs the removal of the given transaction from the pool and schedules it
+// to be published on the next Flush.
+func (h *TxHistory) Drop(tx *types.Transaction, reason DropReason, err error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	ev := &TxEvent{Time: time.Now(), Type: TxEventDropped, Reason: reason}
+	if err != nil {
+		ev.Error = err.Error()
+	}
+	h.record(tx.Hash(), ev)
+
+	// Transactions with a used up nonce are mostly included in a block, don't
+	// flood the subscribers with them.
+	if reason != DropNonceTooLow {
+		h.drops = append(h.drops, &DroppedTx{Tx: tx, Reason: reason, Error: ev.Error})
+	}
+}
+
+// History returns the lifecycle log of the given transaction, or nil if it's
+// not tracked.
+func (h *TxHistory) History(hash common.Hash) []*TxEvent {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	events, ok := h.logs.Peek(hash)
+	if !ok {
+		return nil
+	}
+	return append([]*TxEvent(nil), events...)
+}
+
+// Flush publishes the buffered dropped transactions to the subscribers. It must
+// not be called with the pool lock held, as the subscribers may block.
+func (h *TxHistory) Flush() {
+	h.lock.Lock()
+	drops := h.drops
+	h.drops = nil
+	h.lock.Unlock()
+
+	if len(drops) > 0 {
+		h.feed.Send(DroppedTxsEvent{Txs: drops})
+	}
+}
+
+// Subscribe registers a subscription for the dropped transaction events.
+func (h *TxHistory) Subscribe(ch chan<- DroppedTxsEvent) event.Subscription {
+	return h.feed.Subscribe(ch)
+}
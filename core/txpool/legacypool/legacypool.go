@@ -59,6 +59,14 @@ const (
 
 	// txReannoMaxNum is the maximum number of transactions a reannounce action can include.
 	txReannoMaxNum = 1024
+
+	// txHistoryLimit is the number of transactions whose lifecycle events are
+	// retained after they left the pool.
+	txHistoryLimit = 65536
+
+	// rateLimitBuckets is the maximum number of senders and contracts whose rate
+	// limiting buckets are tracked at once.
+	rateLimitBuckets = 65536
 )
 
 var (
@@ -111,6 +119,10 @@ var (
 	underpricedTxMeter = metrics.NewRegisteredMeter("txpool/underpriced", nil)
 	overflowedTxMeter  = metrics.NewRegisteredMeter("txpool/overflowed", nil)
 
+	// expiredTxMeter counts the conditional transactions dropped once their
+	// inclusion conditions can't be met anymore.
+	expiredTxMeter = metrics.NewRegisteredMeter("txpool/expired", nil)
+
 	// throttleTxMeter counts how many transactions are rejected due to too-many-changes between
 	// txpool reorgs.
 	throttleTxMeter = metrics.NewRegisteredMeter("txpool/throttle", nil)
@@ -125,6 +137,16 @@ var (
 	slotsGauge        = metrics.NewRegisteredGauge("txpool/slots", nil)
 	OverflowPoolGauge = metrics.NewRegisteredGauge("txpool/overflowpool", nil)
 
+	// Metrics for the rate limited transactions
+	senderRateLimitMeter   = metrics.NewRegisteredMeter("txpool/ratelimit/sender", nil)
+	contractRateLimitMeter = metrics.NewRegisteredMeter("txpool/ratelimit/contract", nil)
+
+	// Metrics for the overflow pool
+	overflowPoolSlotsGauge = metrics.NewRegisteredGauge("txpool/overflowpool/slots", nil)
+	overflowPoolDiskGauge  = metrics.NewRegisteredGauge("txpool/overflowpool/disk", nil)  // Bytes spilled to disk
+	overflowPoolEvictMeter = metrics.NewRegisteredMeter("txpool/overflowpool/evict", nil) // Dropped to make room
+	overflowPoolFlushMeter = metrics.NewRegisteredMeter("txpool/overflowpool/flush", nil) // Moved back to the main pool
+
 	reheapTimer = metrics.NewRegisteredTimer("txpool/reheap", nil)
 )
 
@@ -159,9 +181,21 @@ type Config struct {
 	AccountQueue      uint64 // Maximum number of non-executable transaction slots permitted per account
 	GlobalQueue       uint64 // Maximum number of non-executable transaction slots for all accounts
 	OverflowPoolSlots uint64 // Maximum number of transaction slots in overflow pool
+	OverflowPoolDir   string // Directory to persist the overflow pool in, kept in memory only if empty
 
 	Lifetime       time.Duration // Maximum amount of time non-executable transaction are queued
 	ReannounceTime time.Duration // Duration for announcing local pending transactions again
+
+	SenderRateLimit   float64 // Transactions per second accepted from a single sender (0 = unlimited)
+	SenderRateBurst   uint64  // Maximum burst of transactions accepted from a single sender
+	ContractRateLimit float64 // Transactions per second accepted to a single contract (0 = unlimited)
+	ContractRateBurst uint64  // Maximum burst of transactions accepted to a single contract
+	PeerRateLimit     float64 // Transactions per second accepted from a single peer (0 = unlimited)
+	PeerRateBurst     uint64  // Maximum burst of transactions accepted from a single peer
+
+	Blocklist      string           // File of addresses barred from the pools, reloaded when changed
+	SelectorFilter string           // File of function selectors barred from the pools, reloaded when changed
+	Filters        txpool.TxFilters `toml:"-"` // Admission filters transactions must pass on top of the pool rules
 }
 
 // DefaultConfig contains the default configurations for the transaction pool.
@@ -180,6 +214,10 @@ var DefaultConfig = Config{
 
 	Lifetime:       3 * time.Hour,
 	ReannounceTime: 10 * 365 * 24 * time.Hour,
+
+	SenderRateBurst:   64,
+	ContractRateBurst: 1024,
+	PeerRateBurst:     4096,
 }
 
 // sanitize checks the provided user configurations and changes anything that's
@@ -265,7 +303,11 @@ type LegacyPool struct {
 	all     *lookup                      // All transactions to allow lookups
 	priced  *pricedList                  // All transactions sorted by price
 
-	localBufferPool *TxOverflowPool // Local buffer transactions
+	localBufferPool *TxOverflowPool   // Local buffer transactions
+	history         *txpool.TxHistory // Lifecycle events of the recently seen transactions
+
+	senderLimiter   *txpool.RateLimiter[common.Address] // Rate limiter of the accepted transactions per sender
+	contractLimiter *txpool.RateLimiter[common.Address] // Rate limiter of the accepted transactions per contract
 
 	reqResetCh      chan *txpoolResetRequest
 	reqPromoteCh    chan *accountSet
@@ -305,8 +347,14 @@ func New(config Config, chain BlockChain) *LegacyPool {
 		reorgShutdownCh: make(chan struct{}),
 		initDoneCh:      make(chan struct{}),
 		localBufferPool: NewTxOverflowPoolHeap(config.OverflowPoolSlots),
+		history:         txpool.NewTxHistory(txHistoryLimit),
+		senderLimiter:   txpool.NewRateLimiter[common.Address](config.SenderRateLimit, int(config.SenderRateBurst), rateLimitBuckets),
+		contractLimiter: txpool.NewRateLimiter[common.Address](config.ContractRateLimit, int(config.ContractRateBurst), rateLimitBuckets),
 	}
 	pool.priced = newPricedList(pool.all)
+	pool.localBufferPool.evicted = func(tx *types.Transaction) {
+		pool.history.Drop(tx, txpool.DropOverflowFull, nil)
+	}
 
 	return pool
 }
@@ -346,6 +394,12 @@ func (pool *LegacyPool) Init(gasTip uint64, head *types.Header, reserve txpool.A
 	pool.currentState = statedb
 	pool.pendingNonces = newNoncer(statedb)
 
+	// Reload the overflow pool spilled to disk before the restart, if persistent
+	if pool.config.OverflowPoolDir != "" {
+		if err := pool.localBufferPool.Open(pool.config.OverflowPoolDir); err != nil {
+			return err
+		}
+	}
 	pool.wg.Add(1)
 	go pool.scheduleReorgLoop()
 
@@ -401,11 +455,13 @@ func (pool *LegacyPool) loop() {
 					list := pool.queue[addr].Flatten()
 					for _, tx := range list {
 						pool.removeTx(tx.Hash(), true, true)
+						pool.history.Drop(tx, txpool.DropLifetime, nil)
 					}
 					queuedEvictionMeter.Mark(int64(len(list)))
 				}
 			}
 			pool.mu.Unlock()
+			pool.history.Flush()
 
 		case <-reannounce.C:
 			pool.mu.RLock()
@@ -439,6 +495,9 @@ func (pool *LegacyPool) Close() error {
 	close(pool.reorgShutdownCh)
 	pool.wg.Wait()
 
+	if err := pool.localBufferPool.Close(); err != nil {
+		log.Error("Failed to close overflow pool", "err", err)
+	}
 	log.Info("Transaction pool stopped")
 	return nil
 }
@@ -466,9 +525,23 @@ func (pool *LegacyPool) SubscribeReannoTxsEvent(ch chan<- core.ReannoTxsEvent) e
 	return pool.scope.Track(pool.reannoTxFeed.Subscribe(ch))
 }
 
+// SubscribeDroppedTransactions registers a subscription for the events of
+// transactions removed from the pool.
+func (pool *LegacyPool) SubscribeDroppedTransactions(ch chan<- txpool.DroppedTxsEvent) event.Subscription {
+	return pool.scope.Track(pool.history.Subscribe(ch))
+}
+
+// History returns the lifecycle log of a transaction identified by its hash,
+// or nil if it was never seen or already forgotten.
+func (pool *LegacyPool) History(hash common.Hash) []*txpool.TxEvent {
+	return pool.history.History(hash)
+}
+
 // SetGasTip updates the minimum gas tip required by the transaction pool for a
 // new transaction, and drops all transactions below this threshold.
 func (pool *LegacyPool) SetGasTip(tip *big.Int) {
+	defer pool.history.Flush() // Runs after the unlock
+
 	pool.mu.Lock()
 	defer pool.mu.Unlock()
 
@@ -483,6 +556,7 @@ func (pool *LegacyPool) SetGasTip(tip *big.Int) {
 		drop := pool.all.TxsBelowTip(tip)
 		for _, tx := range drop {
 			pool.removeTx(tx.Hash(), false, true)
+			pool.history.Drop(tx, txpool.DropGasTip, nil)
 		}
 		pool.priced.Removed(len(drop))
 	}
@@ -566,6 +640,24 @@ func (pool *LegacyPool) ContentFrom(addr common.Address) ([]*types.Transaction,
 	return pending, queued
 }
 
+// Queued retrieves the hashes of at most limit queued (non-executable)
+// transactions, in no particular order.
+func (pool *LegacyPool) Queued(limit int) []common.Hash {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	var hashes []common.Hash
+	for _, list := range pool.queue {
+		for _, tx := range list.txs.items {
+			if len(hashes) >= limit {
+				return hashes
+			}
+			hashes = append(hashes, tx.Hash())
+		}
+	}
+	return hashes
+}
+
 // Pending retrieves all currently processable transactions, grouped by origin
 // account and sorted by nonce.
 //
@@ -639,6 +731,9 @@ func (pool *LegacyPool) validateTxBasics(tx *types.Transaction) error {
 			return txpool.ErrInBlackList
 		}
 	}
+	if err := pool.config.Filters.Check(tx, sender); err != nil {
+		return err
+	}
 
 	opts := &txpool.ValidationOptions{
 		Config: pool.chainconfig,
@@ -742,10 +837,43 @@ func (pool *LegacyPool) validateAuth(tx *types.Transaction) error {
 	return nil
 }
 
+// checkRateLimits ensures neither the sender nor the destination contract of the
+// transaction exceeded the configured rate of accepted transactions, returning
+// the contract to charge, if any. No token is consumed, that's done by
+// consumeRateLimits once the transaction is accepted. The pool lock must be
+// held for the state access.
+func (pool *LegacyPool) checkRateLimits(tx *types.Transaction, from common.Address) (*common.Address, error) {
+	var contract *common.Address
+	if to := tx.To(); to != nil && pool.contractLimiter != nil && pool.currentState.GetCodeSize(*to) > 0 {
+		contract = to
+	}
+	if !pool.senderLimiter.Available(from) {
+		senderRateLimitMeter.Mark(1)
+		return nil, fmt.Errorf("%w: sender %v", txpool.ErrSenderRateLimited, from)
+	}
+	if contract != nil && !pool.contractLimiter.Available(*contract) {
+		contractRateLimitMeter.Mark(1)
+		return nil, fmt.Errorf("%w: contract %v", txpool.ErrContractRateLimited, *contract)
+	}
+	return contract, nil
+}
+
+// consumeRateLimits charges an accepted transaction against the rate limits of
+// its sender and destination contract.
+func (pool *LegacyPool) consumeRateLimits(from common.Address, contract *common.Address) {
+	pool.senderLimiter.Allow(from)
+	if contract != nil {
+		pool.contractLimiter.Allow(*contract)
+	}
+}
+
 // add validates a transaction and inserts it into the non-executable queue for later
 // pending promotion and execution. If the transaction is a replacement for an already
 // pending or queued one, it overwrites the previous transaction if its price is higher.
-func (pool *LegacyPool) add(tx *types.Transaction) (replaced bool, err error) {
+//
+// If limit is set, the transaction is subject to the sender and contract rate limits.
+// Transactions already accepted once, e.g. reinjected after a reorg, are not.
+func (pool *LegacyPool) add(tx *types.Transaction, limit bool) (replaced bool, err error) {
 	// If the transaction is already known, discard it
 	hash := tx.Hash()
 	if pool.all.Get(hash) != nil {
@@ -763,6 +891,24 @@ func (pool *LegacyPool) add(tx *types.Transaction) (replaced bool, err error) {
 	// already validated by this point
 	from, _ := types.Sender(pool.signer, tx)
 
+	// If the sender or the destination contract is flooding the pool, discard it
+	if limit {
+		var contract *common.Address
+		if contract, err = pool.checkRateLimits(tx, from); err != nil {
+			log.Trace("Discarding rate limited transaction", "hash", hash, "err", err)
+			return false, err
+		}
+		defer func() {
+			// Only the transactions actually inserted count against the limits,
+			// the ones rejected below by the pool checks don't.
+			//
+			// Note, `err` here is the named error return, see the reservation
+			// release below.
+			if err == nil {
+				pool.consumeRateLimits(from, contract)
+			}
+		}()
+	}
 	// If the address is not yet known, request exclusivity to track the account
 	// only by this subpool until all transactions are evicted
 	var (
@@ -861,9 +1007,11 @@ func (pool *LegacyPool) add(tx *types.Transaction) (replaced bool, err error) {
 			pool.all.Remove(old.Hash())
 			pool.priced.Removed(1)
 			pendingReplaceMeter.Mark(1)
+			pool.history.Drop(old, txpool.DropReplaced, nil)
 		}
 		pool.all.Add(tx)
 		pool.priced.Put(tx)
+		pool.history.Record(tx, txpool.TxEventPending)
 		pool.queueTxEvent(tx)
 		log.Trace("Pooled new executable transaction", "hash", hash, "from", from, "to", tx.To())
 
@@ -887,8 +1035,10 @@ func (pool *LegacyPool) addToOverflowPool(drop types.Transactions) {
 		if added {
 			from, _ := types.Sender(pool.signer, tx)
 			log.Debug("Added to OverflowPool", "transaction", tx.Hash().String(), "from", from.String())
+			pool.history.Drop(tx, txpool.DropOverflowPool, nil)
 		} else {
 			log.Debug("Failed to add transaction to OverflowPool", "transaction", tx.Hash().String())
+			pool.history.Drop(tx, txpool.DropUnderpriced, nil)
 		}
 	}
 }
@@ -937,10 +1087,16 @@ func (pool *LegacyPool) enqueueTx(hash common.Hash, tx *types.Transaction, addAl
 		pool.all.Remove(old.Hash())
 		pool.priced.Removed(1)
 		queuedReplaceMeter.Mark(1)
+		pool.history.Drop(old, txpool.DropReplaced, nil)
 	} else {
 		// Nothing was replaced, bump the queued counter
 		queuedGauge.Inc(1)
 	}
+	if addAll {
+		pool.history.Record(tx, txpool.TxEventQueued)
+	} else {
+		pool.history.Record(tx, txpool.TxEventDemoted)
+	}
 	// If the transaction isn't in lookup set but it's expected to be there,
 	// show the error log.
 	if pool.all.Get(hash) == nil && !addAll {
@@ -974,6 +1130,7 @@ func (pool *LegacyPool) promoteTx(addr common.Address, hash common.Hash, tx *typ
 		pool.all.Remove(hash)
 		pool.priced.Removed(1)
 		pendingDiscardMeter.Mark(1)
+		pool.history.Drop(tx, txpool.DropReplaced, nil)
 		return false
 	}
 	// Otherwise discard any previous transaction and mark this
@@ -981,10 +1138,12 @@ func (pool *LegacyPool) promoteTx(addr common.Address, hash common.Hash, tx *typ
 		pool.all.Remove(old.Hash())
 		pool.priced.Removed(1)
 		pendingReplaceMeter.Mark(1)
+		pool.history.Drop(old, txpool.DropReplaced, nil)
 	} else {
 		// Nothing was replaced, bump the pending counter
 		pendingGauge.Inc(1)
 	}
+	pool.history.Record(tx, txpool.TxEventPending)
 	// Set the potentially new pending nonce and notify any subsystems of the new tx
 	pool.pendingNonces.set(addr, tx.Nonce()+1)
 
@@ -1023,6 +1182,12 @@ func (pool *LegacyPool) addRemoteSync(tx *types.Transaction) error {
 // If sync is set, the method will block until all internal maintenance related
 // to the add is finished. Only use this during tests for determinism!
 func (pool *LegacyPool) Add(txs []*types.Transaction, sync bool) []error {
+	return pool.addTxs(txs, sync, true)
+}
+
+// addTxs enqueues a batch of transactions into the pool if they are valid, only
+// enforcing the rate limits if requested.
+func (pool *LegacyPool) addTxs(txs []*types.Transaction, sync bool, limit bool) []error {
 	// Filter out known ones without obtaining the pool lock or recovering signatures
 	var (
 		errs = make([]error, len(txs))
@@ -1053,7 +1218,7 @@ func (pool *LegacyPool) Add(txs []*types.Transaction, sync bool) []error {
 
 	// Process all the new transaction and merge any errors into the original slice
 	pool.mu.Lock()
-	newErrs, dirtyAddrs := pool.addTxsLocked(news)
+	newErrs, dirtyAddrs := pool.addTxsLocked(news, limit)
 	pool.mu.Unlock()
 
 	var nilSlot = 0
@@ -1074,11 +1239,11 @@ func (pool *LegacyPool) Add(txs []*types.Transaction, sync bool) []error {
 
 // addTxsLocked attempts to queue a batch of transactions if they are valid.
 // The transaction pool lock must be held.
-func (pool *LegacyPool) addTxsLocked(txs []*types.Transaction) ([]error, *accountSet) {
+func (pool *LegacyPool) addTxsLocked(txs []*types.Transaction, limit bool) ([]error, *accountSet) {
 	dirty := newAccountSet(pool.signer)
 	errs := make([]error, len(txs))
 	for i, tx := range txs {
-		replaced, err := pool.add(tx)
+		replaced, err := pool.add(tx, limit)
 		errs[i] = err
 		if err == nil && !replaced {
 			dirty.addTx(tx)
@@ -1322,6 +1487,9 @@ func (pool *LegacyPool) runReorg(done chan struct{}, reset *txpoolResetRequest,
 	if reset != nil {
 		// Reset from the old head to the new, rescheduling any reorged transactions
 		pool.reset(reset.oldHead, reset.newHead)
+		if reset.newHead != nil {
+			pool.dropExpired(reset.newHead)
+		}
 
 		// Nonces were reset, discard any events that became stale
 		for addr := range events {
@@ -1371,6 +1539,9 @@ func (pool *LegacyPool) runReorg(done chan struct{}, reset *txpoolResetRequest,
 	// Transfer transactions from OverflowPool to MainPool for new block import
 	pool.transferTransactions()
 
+	// Notify subsystems for the dropped transactions
+	pool.history.Flush()
+
 	// Notify subsystems for newly added transactions
 	for _, tx := range promoted {
 		addr, _ := types.Sender(pool.signer, tx)
@@ -1485,7 +1656,30 @@ func (pool *LegacyPool) reset(oldHead, newHead *types.Header) {
 	// Inject any transactions discarded due to reorgs
 	log.Debug("Reinjecting stale transactions", "count", len(reinject))
 	core.SenderCacher().Recover(pool.signer, reinject)
-	pool.addTxsLocked(reinject)
+	errs, _ := pool.addTxsLocked(reinject, false)
+	for i, err := range errs {
+		if err != nil && !errors.Is(err, txpool.ErrAlreadyKnown) {
+			pool.history.Drop(reinject[i], txpool.DropRevalidation, err)
+		}
+	}
+}
+
+// dropExpired removes the conditional transactions whose inclusion conditions
+// can't be met anymore by any block following the given head.
+func (pool *LegacyPool) dropExpired(head *types.Header) {
+	var dropped int
+	for _, tx := range pool.all.Conditionals() {
+		if !tx.Options().Expired(head.Number.Uint64(), head.Time) {
+			continue
+		}
+		pool.removeTx(tx.Hash(), true, true)
+		pool.history.Drop(tx, txpool.DropExpired, nil)
+		dropped++
+	}
+	if dropped > 0 {
+		expiredTxMeter.Mark(int64(dropped))
+		log.Debug("Dropped expired conditional transactions", "count", dropped, "number", head.Number)
+	}
 }
 
 // promoteExecutables moves transactions that have become processable from the
@@ -1506,12 +1700,14 @@ func (pool *LegacyPool) promoteExecutables(accounts []common.Address) []*types.T
 		forwards := list.Forward(pool.currentState.GetNonce(addr))
 		for _, tx := range forwards {
 			pool.all.Remove(tx.Hash())
+			pool.history.Drop(tx, txpool.DropNonceTooLow, nil)
 		}
 		log.Trace("Removed old queued transactions", "count", len(forwards))
 		// Drop all transactions that are too costly (low balance or out of gas)
 		drops, _ := list.Filter(pool.currentState.GetBalance(addr), gasLimit)
 		for _, tx := range drops {
 			pool.all.Remove(tx.Hash())
+			pool.history.Drop(tx, txpool.DropUnpayable, nil)
 		}
 		log.Trace("Removed unpayable queued transactions", "count", len(drops))
 		queuedNofundsMeter.Mark(int64(len(drops)))
@@ -1532,6 +1728,7 @@ func (pool *LegacyPool) promoteExecutables(accounts []common.Address) []*types.T
 		for _, tx := range caps {
 			hash := tx.Hash()
 			pool.all.Remove(hash)
+			pool.history.Drop(tx, txpool.DropAccountLimit, nil)
 			log.Trace("Removed cap-exceeding queued transaction", "hash", hash)
 		}
 		queuedRateLimitMeter.Mark(int64(len(caps)))
@@ -1594,6 +1791,7 @@ func (pool *LegacyPool) truncatePending() {
 						// Drop the transaction from the global pools too
 						hash := tx.Hash()
 						pool.all.Remove(hash)
+						pool.history.Drop(tx, txpool.DropPoolLimit, nil)
 
 						// Update the account nonce to the dropped transaction
 						pool.pendingNonces.setIfLower(offenders[i], tx.Nonce())
@@ -1619,6 +1817,7 @@ func (pool *LegacyPool) truncatePending() {
 					// Drop the transaction from the global pools too
 					hash := tx.Hash()
 					pool.all.Remove(hash)
+					pool.history.Drop(tx, txpool.DropPoolLimit, nil)
 
 					// Update the account nonce to the dropped transaction
 					pool.pendingNonces.setIfLower(addr, tx.Nonce())
@@ -1661,6 +1860,7 @@ func (pool *LegacyPool) truncateQueue() {
 		if size := uint64(list.Len()); size <= drop {
 			for _, tx := range list.Flatten() {
 				pool.removeTx(tx.Hash(), true, true)
+				pool.history.Drop(tx, txpool.DropPoolLimit, nil)
 			}
 			drop -= size
 			queuedRateLimitMeter.Mark(int64(size))
@@ -1670,6 +1870,7 @@ func (pool *LegacyPool) truncateQueue() {
 		txs := list.Flatten()
 		for i := len(txs) - 1; i >= 0 && drop > 0; i-- {
 			pool.removeTx(txs[i].Hash(), true, true)
+			pool.history.Drop(txs[i], txpool.DropPoolLimit, nil)
 			drop--
 			queuedRateLimitMeter.Mark(1)
 		}
@@ -1694,6 +1895,7 @@ func (pool *LegacyPool) demoteUnexecutables() {
 		for _, tx := range olds {
 			hash := tx.Hash()
 			pool.all.Remove(hash)
+			pool.history.Drop(tx, txpool.DropNonceTooLow, nil)
 			log.Trace("Removed old pending transaction", "hash", hash)
 		}
 		// Drop all transactions that are too costly (low balance or out of gas), and queue any invalids back for later
@@ -1701,6 +1903,7 @@ func (pool *LegacyPool) demoteUnexecutables() {
 		for _, tx := range drops {
 			hash := tx.Hash()
 			pool.all.Remove(hash)
+			pool.history.Drop(tx, txpool.DropUnpayable, nil)
 			log.Trace("Removed unpayable pending transaction", "hash", hash)
 		}
 		pendingNofundsMeter.Mark(int64(len(drops)))
@@ -1819,14 +2022,16 @@ type lookup struct {
 	lock  sync.RWMutex
 	txs   map[common.Hash]*types.Transaction
 
-	auths map[common.Address][]common.Hash // All accounts with a pooled authorization
+	auths        map[common.Address][]common.Hash   // All accounts with a pooled authorization
+	conditionals map[common.Hash]*types.Transaction // Transactions carrying inclusion conditions
 }
 
 // newLookup returns a new lookup structure.
 func newLookup() *lookup {
 	return &lookup{
-		txs:   make(map[common.Hash]*types.Transaction),
-		auths: make(map[common.Address][]common.Hash),
+		txs:          make(map[common.Hash]*types.Transaction),
+		auths:        make(map[common.Address][]common.Hash),
+		conditionals: make(map[common.Hash]*types.Transaction),
 	}
 }
 
@@ -1878,6 +2083,9 @@ func (t *lookup) Add(tx *types.Transaction) {
 
 	t.txs[tx.Hash()] = tx
 	t.addAuthorities(tx)
+	if tx.Options() != nil {
+		t.conditionals[tx.Hash()] = tx
+	}
 }
 
 // Remove removes a transaction from the lookup.
@@ -1895,6 +2103,19 @@ func (t *lookup) Remove(hash common.Hash) {
 	slotsGauge.Update(int64(t.slots))
 
 	delete(t.txs, hash)
+	delete(t.conditionals, hash)
+}
+
+// Conditionals returns all the transactions carrying inclusion conditions.
+func (t *lookup) Conditionals() []*types.Transaction {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	txs := make([]*types.Transaction, 0, len(t.conditionals))
+	for _, tx := range t.conditionals {
+		txs = append(txs, tx)
+	}
+	return txs
 }
 
 // TxsBelowTip finds all remote transactions below the given tip threshold.
@@ -1976,12 +2197,18 @@ func (pool *LegacyPool) transferTransactions() {
 	extraSlots := maxMainPoolSize - currentMainPoolSize
 	extraTransactions := (extraSlots + 3) / 4 // Since a transaction can take up to 4 slots
 	log.Debug("Will attempt to transfer from OverflowPool to MainPool", "transactions", extraTransactions)
-	txs := pool.localBufferPool.Flush(extraTransactions)
+	txs := pool.localBufferPool.FlushBest(extraTransactions)
 	if len(txs) == 0 {
 		return
 	}
 
-	pool.Add(txs, false)
+	// The transactions were already rate limited when first added, don't punish
+	// their senders again for the detour through the overflow pool
+	for i, err := range pool.addTxs(txs, false, false) {
+		if err != nil && !errors.Is(err, txpool.ErrAlreadyKnown) {
+			pool.history.Drop(txs[i], txpool.DropRevalidation, err)
+		}
+	}
 }
 
 func (pool *LegacyPool) PrintTxStats() {
@@ -2000,6 +2227,8 @@ func (pool *LegacyPool) PrintTxStats() {
 // Clear implements txpool.SubPool, removing all tracked txs from the pool
 // and rotating the journal.
 func (pool *LegacyPool) Clear() {
+	defer pool.history.Flush() // Runs after the unlock
+
 	pool.mu.Lock()
 	defer pool.mu.Unlock()
 
@@ -2020,6 +2249,7 @@ func (pool *LegacyPool) Clear() {
 	for _, tx := range pool.all.txs {
 		senderAddr, _ := types.Sender(pool.signer, tx)
 		pool.reserve(senderAddr, false)
+		pool.history.Drop(tx, txpool.DropCleared, nil)
 	}
 	pool.all = newLookup()
 	pool.priced = newPricedList(pool.all)
@@ -23,6 +23,8 @@ import (
 	"fmt"
 	"math/big"
 	"math/rand"
+	"os"
+	"path/filepath"
 	"slices"
 	"sync"
 	"sync/atomic"
@@ -30,6 +32,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/tracing"
@@ -466,6 +469,42 @@ func TestQueue2(t *testing.T) {
 	}
 }
 
+// Tests that the queued transaction hashes can be retrieved up to a limit.
+func TestQueuedHashes(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupPool()
+	defer pool.Close()
+
+	tx1 := transaction(0, 100, key)
+	tx2 := transaction(10, 100, key)
+	tx3 := transaction(11, 100, key)
+	from, _ := deriveSender(tx1)
+	testAddBalance(pool, from, big.NewInt(1000))
+	pool.reset(nil, nil)
+
+	pool.enqueueTx(tx1.Hash(), tx1, true)
+	pool.enqueueTx(tx2.Hash(), tx2, true)
+	pool.enqueueTx(tx3.Hash(), tx3, true)
+	pool.promoteExecutables([]common.Address{from})
+
+	hashes := pool.Queued(10)
+	if len(hashes) != 2 {
+		t.Fatalf("queued hash count mismatch: have %d, want 2", len(hashes))
+	}
+	for _, hash := range hashes {
+		if hash != tx2.Hash() && hash != tx3.Hash() {
+			t.Errorf("unexpected queued hash %x", hash)
+		}
+	}
+	if hashes = pool.Queued(1); len(hashes) != 1 {
+		t.Fatalf("limited queued hash count mismatch: have %d, want 1", len(hashes))
+	}
+	if hashes = pool.Queued(0); len(hashes) != 0 {
+		t.Fatalf("empty queued hash count mismatch: have %d, want 0", len(hashes))
+	}
+}
+
 func TestNegativeValue(t *testing.T) {
 	t.Parallel()
 
@@ -530,14 +569,14 @@ func TestChainFork(t *testing.T) {
 	resetState()
 
 	tx := transaction(0, 100000, key)
-	if _, err := pool.add(tx); err != nil {
+	if _, err := pool.add(tx, true); err != nil {
 		t.Error("didn't expect error", err)
 	}
 	pool.removeTx(tx.Hash(), true, true)
 
 	// reset the pool's internal state
 	resetState()
-	if _, err := pool.add(tx); err != nil {
+	if _, err := pool.add(tx, true); err != nil {
 		t.Error("didn't expect error", err)
 	}
 }
@@ -564,10 +603,10 @@ func TestDoubleNonce(t *testing.T) {
 	tx3, _ := types.SignTx(types.NewTransaction(0, common.Address{}, big.NewInt(100), 1000000, big.NewInt(1), nil), signer, key)
 
 	// Add the first two transaction, ensure higher priced stays only
-	if replace, err := pool.add(tx1); err != nil || replace {
+	if replace, err := pool.add(tx1, true); err != nil || replace {
 		t.Errorf("first transaction insert failed (%v) or reported replacement (%v)", err, replace)
 	}
-	if replace, err := pool.add(tx2); err != nil || !replace {
+	if replace, err := pool.add(tx2, true); err != nil || !replace {
 		t.Errorf("second transaction insert failed (%v) or not reported replacement (%v)", err, replace)
 	}
 	<-pool.requestPromoteExecutables(newAccountSet(signer, addr))
@@ -579,7 +618,7 @@ func TestDoubleNonce(t *testing.T) {
 	}
 
 	// Add the third transaction and ensure it's not saved (smaller price)
-	pool.add(tx3)
+	pool.add(tx3, true)
 	<-pool.requestPromoteExecutables(newAccountSet(signer, addr))
 	if pool.pending[addr].Len() != 1 {
 		t.Error("expected 1 pending transactions, got", pool.pending[addr].Len())
@@ -602,7 +641,7 @@ func TestMissingNonce(t *testing.T) {
 	addr := crypto.PubkeyToAddress(key.PublicKey)
 	testAddBalance(pool, addr, big.NewInt(100000000000000))
 	tx := transaction(1, 100000, key)
-	if _, err := pool.add(tx); err != nil {
+	if _, err := pool.add(tx, true); err != nil {
 		t.Error("didn't expect error", err)
 	}
 	if len(pool.pending) != 0 {
@@ -2096,6 +2135,60 @@ func TestTransferTransactions(t *testing.T) {
 	assert.Equal(t, uint64(1), pool.statsOverflowPool(), "OverflowPool size unexpected")
 }
 
+// Tests that the transactions of a persistent overflow pool survive a restart
+// and are moved into the main pool once there's room.
+func TestOverflowPoolRestart(t *testing.T) {
+	t.Parallel()
+
+	config := testTxPoolConfig
+	config.OverflowPoolSlots = 4
+	config.OverflowPoolDir = t.TempDir()
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	blockchain := newTestBlockChain(eip1559Config, 10000000, statedb, new(event.Feed))
+
+	pool := New(config, blockchain)
+	if err := pool.Init(config.PriceLimit, blockchain.CurrentBlock(), makeAddressReserver()); err != nil {
+		t.Fatalf("Failed to init pool: %v", err)
+	}
+	<-pool.initDoneCh
+
+	keys := make([]*ecdsa.PrivateKey, 3)
+	txs := make([]*types.Transaction, len(keys))
+	for i := 0; i < len(keys); i++ {
+		keys[i], _ = crypto.GenerateKey()
+		testAddBalance(pool, crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(1000000))
+		txs[i] = dynamicFeeTx(0, 100000, big.NewInt(int64(i+2)), big.NewInt(1), keys[i])
+	}
+	pool.addToOverflowPool(txs)
+	assert.Equal(t, uint64(3), pool.statsOverflowPool(), "OverflowPool size unexpected")
+	pool.Close()
+
+	// Restart the pool with room for a single transaction in the main pool
+	pool = New(config, blockchain)
+	if err := pool.Init(config.PriceLimit, blockchain.CurrentBlock(), makeAddressReserver()); err != nil {
+		t.Fatalf("Failed to reinit pool: %v", err)
+	}
+	<-pool.initDoneCh
+	defer pool.Close()
+
+	pool.config.GlobalSlots = 1
+	pool.config.GlobalQueue = 0
+
+	assert.Equal(t, uint64(3), pool.statsOverflowPool(), "OverflowPool size unexpected after restart")
+
+	// The best paying transaction should be moved back first
+	<-pool.requestReset(nil, nil)
+	<-pool.requestPromoteExecutables(newAccountSet(pool.signer, crypto.PubkeyToAddress(keys[2].PublicKey)))
+
+	pending, _ := pool.Stats()
+	assert.Equal(t, 1, pending, "pending transactions mismatched")
+	assert.Equal(t, uint64(2), pool.statsOverflowPool(), "OverflowPool size unexpected")
+	if pool.Get(txs[2].Hash()) == nil {
+		t.Fatalf("Best paying transaction not transferred first")
+	}
+}
+
 // Tests that the pool rejects replacement dynamic fee transactions that don't
 // meet the minimum price bump required.
 func TestReplacementDynamicFee(t *testing.T) {
@@ -2261,6 +2354,306 @@ func TestStatusCheck(t *testing.T) {
 	}
 }
 
+// Tests that the lifecycle events of the transactions are logged, and that the
+// removed ones are published with the reason of the removal.
+func TestTransactionHistory(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupPool()
+	defer pool.Close()
+
+	drops := make(chan txpool.DroppedTxsEvent, 16)
+	sub := pool.SubscribeDroppedTransactions(drops)
+	defer sub.Unsubscribe()
+
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	testAddBalance(pool, from, big.NewInt(1000000000))
+
+	// Add a few pending and queued transactions, and replace a pending one
+	var (
+		tx0  = pricedTransaction(0, 100000, big.NewInt(1), key)
+		tx0b = pricedTransaction(0, 100000, big.NewInt(2), key)
+		tx1  = pricedTransaction(1, 100000, big.NewInt(1), key)
+		tx3  = pricedTransaction(3, 100000, big.NewInt(1), key)
+	)
+	for _, err := range pool.addRemotesSync([]*types.Transaction{tx0, tx1, tx3}) {
+		if err != nil {
+			t.Fatalf("failed to add transaction: %v", err)
+		}
+	}
+	if err := pool.addRemoteSync(tx0b); err != nil {
+		t.Fatalf("failed to replace transaction: %v", err)
+	}
+	checkHistory := func(tx *types.Transaction, reason txpool.DropReason, want ...txpool.TxEventType) {
+		t.Helper()
+
+		events := pool.History(tx.Hash())
+		if len(events) != len(want) {
+			t.Fatalf("event count mismatch: have %d, want %d", len(events), len(want))
+		}
+		for i, ev := range events {
+			if ev.Type != want[i] {
+				t.Fatalf("event %d type mismatch: have %s, want %s", i, ev.Type, want[i])
+			}
+		}
+		if last := events[len(events)-1]; last.Reason != reason {
+			t.Fatalf("drop reason mismatch: have %q, want %q", last.Reason, reason)
+		}
+	}
+	checkHistory(tx0, txpool.DropReplaced, txpool.TxEventQueued, txpool.TxEventPending, txpool.TxEventDropped)
+	checkHistory(tx0b, "", txpool.TxEventPending)
+	checkHistory(tx1, "", txpool.TxEventQueued, txpool.TxEventPending)
+	checkHistory(tx3, "", txpool.TxEventQueued)
+
+	if events := pool.History(common.Hash{}); events != nil {
+		t.Fatalf("unexpected history of unknown transaction: %v", events)
+	}
+	checkDrops := func(want map[common.Hash]txpool.DropReason) {
+		t.Helper()
+
+		for len(want) > 0 {
+			select {
+			case ev := <-drops:
+				for _, drop := range ev.Txs {
+					reason, ok := want[drop.Tx.Hash()]
+					if !ok {
+						t.Fatalf("unexpected dropped transaction %x", drop.Tx.Hash())
+					}
+					if drop.Reason != reason {
+						t.Fatalf("drop reason mismatch: have %q, want %q", drop.Reason, reason)
+					}
+					delete(want, drop.Tx.Hash())
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("missing dropped transactions: %v", want)
+			}
+		}
+		select {
+		case ev := <-drops:
+			t.Fatalf("unexpected dropped transactions: %v", ev.Txs)
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+	checkDrops(map[common.Hash]txpool.DropReason{tx0.Hash(): txpool.DropReplaced})
+
+	// Include the first transaction and drain the balance of the account, all
+	// the transactions should be dropped, only the unpayable ones published.
+	testSetNonce(pool, from, 1)
+	testAddBalance(pool, from, big.NewInt(-999999000))
+	<-pool.requestReset(nil, nil)
+
+	checkHistory(tx0b, txpool.DropNonceTooLow, txpool.TxEventPending, txpool.TxEventDropped)
+	checkHistory(tx1, txpool.DropUnpayable, txpool.TxEventQueued, txpool.TxEventPending, txpool.TxEventDropped)
+	checkHistory(tx3, txpool.DropUnpayable, txpool.TxEventQueued, txpool.TxEventDropped)
+	checkDrops(map[common.Hash]txpool.DropReason{tx1.Hash(): txpool.DropUnpayable, tx3.Hash(): txpool.DropUnpayable})
+
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that the transactions exceeding the per-sender and per-contract rate
+// limits are rejected, while the internal moves are exempt.
+func TestRateLimits(t *testing.T) {
+	t.Parallel()
+
+	config := testTxPoolConfig
+	config.SenderRateLimit, config.SenderRateBurst = 0.001, 2
+	config.ContractRateLimit, config.ContractRateBurst = 0.001, 3
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	blockchain := newTestBlockChain(params.TestChainConfig, 10000000, statedb, new(event.Feed))
+
+	pool := New(config, blockchain)
+	if err := pool.Init(config.PriceLimit, blockchain.CurrentBlock(), makeAddressReserver()); err != nil {
+		t.Fatalf("Failed to init pool: %v", err)
+	}
+	defer pool.Close()
+
+	contract := common.HexToAddress("0xc0ffee")
+	pool.mu.Lock()
+	pool.currentState.SetCode(contract, []byte{0x00})
+	pool.mu.Unlock()
+
+	call := func(nonce uint64, to common.Address, key *ecdsa.PrivateKey) *types.Transaction {
+		tx, _ := types.SignTx(types.NewTransaction(nonce, to, big.NewInt(0), 100000, big.NewInt(1), nil), types.HomesteadSigner{}, key)
+		return tx
+	}
+	// Flood the pool from a single sender, only the burst should be accepted
+	key, _ := crypto.GenerateKey()
+	testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+
+	errs := pool.addRemotesSync([]*types.Transaction{transaction(0, 100000, key), transaction(1, 100000, key), transaction(2, 100000, key)})
+	if errs[0] != nil || errs[1] != nil {
+		t.Fatalf("failed to add transactions within the sender burst: %v", errs)
+	}
+	if !errors.Is(errs[2], txpool.ErrSenderRateLimited) {
+		t.Fatalf("sender rate limit error mismatch: have %v, want %v", errs[2], txpool.ErrSenderRateLimited)
+	}
+	// Flood a contract from many senders, only the burst should be accepted
+	var txs []*types.Transaction
+	for i := 0; i < 4; i++ {
+		key, _ := crypto.GenerateKey()
+		testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+		txs = append(txs, call(0, contract, key))
+	}
+	errs = pool.addRemotesSync(txs)
+	for i := 0; i < 3; i++ {
+		if errs[i] != nil {
+			t.Fatalf("failed to add transaction %d within the contract burst: %v", i, errs[i])
+		}
+	}
+	if !errors.Is(errs[3], txpool.ErrContractRateLimited) {
+		t.Fatalf("contract rate limit error mismatch: have %v, want %v", errs[3], txpool.ErrContractRateLimited)
+	}
+	// Transactions rejected by the contract limit don't use up the sender burst
+	key, _ = crypto.GenerateKey()
+	testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+	for i := 0; i < 2; i++ {
+		if err := pool.addRemoteSync(call(0, contract, key)); !errors.Is(err, txpool.ErrContractRateLimited) {
+			t.Fatalf("contract rate limit error mismatch: have %v, want %v", err, txpool.ErrContractRateLimited)
+		}
+	}
+	errs = pool.addRemotesSync([]*types.Transaction{transaction(0, 100000, key), transaction(1, 100000, key)})
+	if errs[0] != nil || errs[1] != nil {
+		t.Fatalf("failed to add transactions within the sender burst: %v", errs)
+	}
+	// Transactions rejected by the pool itself don't use up the sender burst
+	key, _ = crypto.GenerateKey()
+	testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+	if err := pool.addRemoteSync(transaction(0, 100000, key)); err != nil {
+		t.Fatalf("failed to add transaction within the sender burst: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := pool.addRemoteSync(transaction(0, 90000, key)); !errors.Is(err, txpool.ErrReplaceUnderpriced) {
+			t.Fatalf("replacement error mismatch: have %v, want %v", err, txpool.ErrReplaceUnderpriced)
+		}
+	}
+	if err := pool.addRemoteSync(transaction(1, 100000, key)); err != nil {
+		t.Fatalf("failed to add transaction within the sender burst: %v", err)
+	}
+	// Transactions to plain accounts aren't subject to the contract limit
+	for i := 0; i < 4; i++ {
+		key, _ := crypto.GenerateKey()
+		testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+		if err := pool.addRemoteSync(call(0, common.HexToAddress("0xbeef"), key)); err != nil {
+			t.Fatalf("failed to add transaction to plain account: %v", err)
+		}
+	}
+	// Internal moves, e.g. from the overflow pool, are exempt from the limits
+	if errs := pool.addTxs([]*types.Transaction{txs[3]}, true, false); errs[0] != nil {
+		t.Fatalf("failed to add transaction bypassing the limits: %v", errs[0])
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that the transactions rejected by the admission filters are kept out of
+// the pool.
+func TestTxFilters(t *testing.T) {
+	t.Parallel()
+
+	var (
+		dir      = t.TempDir()
+		blocked  = common.HexToAddress("0xdead")
+		selector = []byte{0xa9, 0x05, 0x9c, 0xbb}
+	)
+	os.WriteFile(filepath.Join(dir, "blocklist"), []byte("# sanctioned\n"+blocked.Hex()+"\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "selectors"), []byte(hexutil.Encode(selector)+"\n"), 0644)
+
+	blocklist, err := txpool.NewAddressFilter(filepath.Join(dir, "blocklist"))
+	if err != nil {
+		t.Fatalf("Failed to create blocklist: %v", err)
+	}
+	selectors, err := txpool.NewSelectorFilter(filepath.Join(dir, "selectors"))
+	if err != nil {
+		t.Fatalf("Failed to create selector filter: %v", err)
+	}
+	config := testTxPoolConfig
+	config.Filters = txpool.TxFilters{blocklist, selectors}
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	blockchain := newTestBlockChain(params.TestChainConfig, 10000000, statedb, new(event.Feed))
+
+	pool := New(config, blockchain)
+	if err := pool.Init(config.PriceLimit, blockchain.CurrentBlock(), makeAddressReserver()); err != nil {
+		t.Fatalf("Failed to init pool: %v", err)
+	}
+	defer pool.Close()
+
+	key, _ := crypto.GenerateKey()
+	testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+
+	call := func(nonce uint64, to common.Address, data []byte) *types.Transaction {
+		tx, _ := types.SignTx(types.NewTransaction(nonce, to, big.NewInt(0), 100000, big.NewInt(1), data), types.HomesteadSigner{}, key)
+		return tx
+	}
+	for i, tx := range []*types.Transaction{
+		call(0, blocked, nil),
+		call(0, common.HexToAddress("0xbeef"), append(selector, make([]byte, 64)...)),
+	} {
+		if err := pool.addRemoteSync(tx); !errors.Is(err, txpool.ErrTxFiltered) {
+			t.Fatalf("transaction %d: filter error mismatch: have %v, want %v", i, err, txpool.ErrTxFiltered)
+		}
+	}
+	if err := pool.addRemoteSync(call(0, common.HexToAddress("0xbeef"), []byte{0x01, 0x02, 0x03, 0x04})); err != nil {
+		t.Fatalf("failed to add unfiltered transaction: %v", err)
+	}
+	if pending, queued := pool.Stats(); pending != 1 || queued != 0 {
+		t.Fatalf("pool size mismatch: have %d pending %d queued, want 1 pending", pending, queued)
+	}
+}
+
+// Tests that conditional transactions are dropped from the pool once the chain
+// moves past their maximum block number.
+func TestConditionalExpiry(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupPool()
+	defer pool.Close()
+
+	var (
+		expiring = hexutil.Uint64(5)
+		lasting  = hexutil.Uint64(10)
+		keys     = []*ecdsa.PrivateKey{key, nil, nil}
+	)
+	for i := 1; i < len(keys); i++ {
+		keys[i], _ = crypto.GenerateKey()
+	}
+	for _, key := range keys {
+		testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+	}
+	txs := []*types.Transaction{
+		transaction(0, 100000, keys[0]).WithOptions(&types.TransactionOpts{BlockNumberMax: &expiring}),
+		transaction(0, 100000, keys[1]).WithOptions(&types.TransactionOpts{BlockNumberMax: &lasting}),
+		transaction(0, 100000, keys[2]),
+	}
+	for i, err := range pool.addRemotesSync(txs) {
+		if err != nil {
+			t.Fatalf("failed to add transaction %d: %v", i, err)
+		}
+	}
+	// Move the head to the last block the first transaction could be included
+	// after, it should be gone
+	<-pool.requestReset(nil, &types.Header{Number: big.NewInt(5), Difficulty: common.Big0, GasLimit: 10000000, BaseFee: big.NewInt(0)})
+
+	if pool.Has(txs[0].Hash()) {
+		t.Fatalf("expired conditional transaction still in the pool")
+	}
+	for i, tx := range txs[1:] {
+		if !pool.Has(tx.Hash()) {
+			t.Fatalf("transaction %d missing from the pool", i+1)
+		}
+	}
+	if count := len(pool.all.Conditionals()); count != 1 {
+		t.Fatalf("conditional transaction count mismatch: have %d, want %d", count, 1)
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
 // Test the transaction slots consumption is computed correctly
 func TestSlotCount(t *testing.T) {
 	t.Parallel()
@@ -2,82 +2,227 @@ package legacypool
 
 import (
 	"container/heap"
+	"errors"
 	"fmt"
+	"os"
 	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/holiman/billy"
+	"github.com/holiman/uint256"
 )
 
-// txHeapItem implements the Interface interface (https://pkg.go.dev/container/heap#Interface) of heap so that it can be heapified
+// txHeapItem is a transaction tracked by the overflow pool, positioned in both
+// the age and the price heaps.
 type txHeapItem struct {
-	tx        *types.Transaction
-	timestamp int64 // Unix timestamp (nanoseconds) of when the transaction was added
-	index     int
-}
-
-type txHeap []*txHeapItem
+	tx        *types.Transaction // Transaction, nil if spilled to disk
+	hash      common.Hash        // Hash of the transaction
+	id        uint64             // Datastore id of the spilled transaction
+	size      uint32             // Datastore bytes used by the spilled transaction
+	slots     uint64             // Number of slots the transaction takes up
+	feeCap    *uint256.Int       // Fee cap of the transaction
+	tipCap    *uint256.Int       // Tip cap of the transaction
+	timestamp int64              // Unix timestamp (nanoseconds) of when the transaction was added
 
-func (h txHeap) Len() int { return len(h) }
-func (h txHeap) Less(i, j int) bool {
-	return h[i].timestamp < h[j].timestamp
+	ageIndex   int // Position in the age heap
+	priceIndex int // Position in the price heap
 }
-func (h txHeap) Swap(i, j int) {
-	if i < 0 || j < 0 || i >= len(h) || j >= len(h) {
-		return // Silently fail if indices are out of bounds
-	}
-	h[i], h[j] = h[j], h[i]
-	if h[i] != nil {
-		h[i].index = i
+
+// newTxHeapItem creates the overflow pool item of a transaction.
+func newTxHeapItem(tx *types.Transaction, timestamp int64) *txHeapItem {
+	return &txHeapItem{
+		hash:      tx.Hash(),
+		slots:     uint64(numSlots(tx)),
+		feeCap:    uint256.MustFromBig(tx.GasFeeCap()),
+		tipCap:    uint256.MustFromBig(tx.GasTipCap()),
+		timestamp: timestamp,
 	}
-	if h[j] != nil {
-		h[j].index = j
+}
+
+// cmpPrice compares the prices of two items, fee caps first and tips second.
+func (item *txHeapItem) cmpPrice(other *txHeapItem) int {
+	if c := item.feeCap.Cmp(other.feeCap); c != 0 {
+		return c
 	}
+	return item.tipCap.Cmp(other.tipCap)
+}
+
+// txHeap is a heap of the overflow pool items, ordered by the given comparator
+// and tracking the positions of the items to allow removing them.
+type txHeap struct {
+	items []*txHeapItem
+	less  func(a, b *txHeapItem) bool
+	index func(item *txHeapItem) *int
+}
+
+func (h *txHeap) Len() int           { return len(h.items) }
+func (h *txHeap) Less(i, j int) bool { return h.less(h.items[i], h.items[j]) }
+
+func (h *txHeap) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	*h.index(h.items[i]) = i
+	*h.index(h.items[j]) = j
 }
 
 func (h *txHeap) Push(x interface{}) {
-	item, ok := x.(*txHeapItem)
-	if !ok {
-		return
-	}
-	n := len(*h)
-	item.index = n
-	*h = append(*h, item)
+	item := x.(*txHeapItem)
+	*h.index(item) = len(h.items)
+	h.items = append(h.items, item)
 }
 
 func (h *txHeap) Pop() interface{} {
-	old := *h
-	n := len(old)
-	if n == 0 {
-		return nil // Return nil if the heap is empty
-	}
-	item := old[n-1]
-	old[n-1] = nil // avoid memory leak
-	*h = old[0 : n-1]
-	if item != nil {
-		item.index = -1 // for safety
-	}
+	n := len(h.items)
+	item := h.items[n-1]
+	h.items[n-1] = nil // avoid memory leak
+	h.items = h.items[:n-1]
+	*h.index(item) = -1 // for safety
 	return item
 }
 
+// overflowTx is the on-disk representation of a spilled transaction.
+type overflowTx struct {
+	Tx   *types.Transaction
+	Time uint64 // Unix timestamp (nanoseconds) of when the transaction was added
+}
+
+// newOverflowSlotter creates the shelf sizes of the persistent overflow pool,
+// doubling from 512 bytes until the largest permitted transaction fits.
+func newOverflowSlotter() func() (uint32, bool) {
+	slotsize := uint32(256)
+	return func() (size uint32, done bool) {
+		slotsize *= 2
+		return slotsize, slotsize > txMaxSize
+	}
+}
+
+// TxOverflowPool buffers the transactions evicted from the full main pool, to
+// be moved back once there's room again. When full, the oldest transactions are
+// evicted first.
+//
+// The pool is kept in memory by default. If opened with a data directory, the
+// transactions are spilled to disk with only an index kept in memory, and are
+// reloaded after a restart.
 type TxOverflowPool struct {
-	txHeap    txHeap
+	ageHeap   txHeap // Oldest transactions first
+	priceHeap txHeap // Best paying (and oldest among equals) transactions first
 	index     map[common.Hash]*txHeapItem
+	store     billy.Database // Persistent store of the spilled transactions, nil if in memory
 	mu        sync.RWMutex
 	maxSize   uint64 // Maximum slots
 	totalSize uint64 // Total number of slots currently
+	diskSize  uint64 // Total number of bytes spilled to disk
+
+	evicted func(tx *types.Transaction) // Optional callback for the transactions evicted to make room
 }
 
 func NewTxOverflowPoolHeap(estimatedMaxSize uint64) *TxOverflowPool {
 	return &TxOverflowPool{
-		txHeap:  make(txHeap, 0, estimatedMaxSize),
+		ageHeap: txHeap{
+			items: make([]*txHeapItem, 0, estimatedMaxSize),
+			less: func(a, b *txHeapItem) bool {
+				return a.timestamp < b.timestamp
+			},
+			index: func(item *txHeapItem) *int { return &item.ageIndex },
+		},
+		priceHeap: txHeap{
+			items: make([]*txHeapItem, 0, estimatedMaxSize),
+			less: func(a, b *txHeapItem) bool {
+				if c := a.cmpPrice(b); c != 0 {
+					return c > 0
+				}
+				return a.timestamp < b.timestamp
+			},
+			index: func(item *txHeapItem) *int { return &item.priceIndex },
+		},
 		index:   make(map[common.Hash]*txHeapItem, estimatedMaxSize),
 		maxSize: estimatedMaxSize,
 	}
 }
 
+// Open attaches a persistent store in the given directory to the overflow pool,
+// loading the transactions spilled there before a restart. Any transaction added
+// afterwards is kept on disk instead of in memory.
+func (tp *TxOverflowPool) Open(path string) error {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+
+	if tp.store != nil {
+		return errors.New("overflow pool already opened")
+	}
+	if err := os.MkdirAll(path, 0700); err != nil {
+		return err
+	}
+	// Index all transactions on disk and delete anything unprocessable
+	var fails []uint64
+	index := func(id uint64, size uint32, blob []byte) {
+		if tp.parseTransaction(id, blob) != nil {
+			fails = append(fails, id)
+		}
+	}
+	store, err := billy.Open(billy.Options{Path: path, Repair: true}, newOverflowSlotter(), index)
+	if err != nil {
+		return err
+	}
+	tp.store = store
+
+	if len(fails) > 0 {
+		log.Warn("Dropping invalidated OverflowPool transactions", "ids", fails)
+		for _, id := range fails {
+			if err := tp.store.Delete(id); err != nil {
+				return err
+			}
+		}
+	}
+	// The capacity might have been reduced since the last run, trim the excess
+	for tp.totalSize > tp.maxSize {
+		tp.evictOldest()
+	}
+	tp.updateMetrics()
+
+	if len(tp.index) > 0 {
+		log.Info("Loaded OverflowPool transactions from disk", "transactions", len(tp.index), "slots", tp.totalSize)
+	}
+	return nil
+}
+
+// parseTransaction is a callback method on store opening that gets called for
+// each spilled transaction on disk to create the in-memory index.
+func (tp *TxOverflowPool) parseTransaction(id uint64, blob []byte) error {
+	entry := new(overflowTx)
+	if err := rlp.DecodeBytes(blob, entry); err != nil {
+		// This path is impossible unless the disk data representation changes
+		// across restarts. For that ever improbable case, recover gracefully
+		// by ignoring this data entry.
+		log.Error("Failed to decode OverflowPool transaction", "id", id, "err", err)
+		return err
+	}
+	if _, ok := tp.index[entry.Tx.Hash()]; ok {
+		log.Error("Dropping duplicate OverflowPool transaction", "hash", entry.Tx.Hash(), "id", id)
+		return errors.New("duplicate transaction")
+	}
+	item := newTxHeapItem(entry.Tx, int64(entry.Time))
+	item.id, item.size = id, uint32(len(blob))
+	tp.push(item)
+	return nil
+}
+
+// Close releases the persistent store of the overflow pool, if any.
+func (tp *TxOverflowPool) Close() error {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+
+	if tp.store == nil {
+		return nil
+	}
+	err := tp.store.Close()
+	tp.store = nil
+	return err
+}
+
 func (tp *TxOverflowPool) Add(tx *types.Transaction) bool {
 	tp.mu.Lock()
 	defer tp.mu.Unlock()
@@ -86,91 +231,177 @@ func (tp *TxOverflowPool) Add(tx *types.Transaction) bool {
 		// Transaction already in pool, ignore
 		return false
 	}
-
-	txSlots := uint64(numSlots(tx))
+	item := newTxHeapItem(tx, time.Now().UnixNano())
 
 	// If the transaction is too big to ever fit (and the pool isn't empty right now), reject it
-	if (txSlots > tp.maxSize) || (txSlots == tp.maxSize && tp.totalSize != 0) {
-		log.Debug("Transaction too large to fit in OverflowPool", "transaction", tx.Hash().String(), "requiredSlots", txSlots, "maxSlots", tp.maxSize)
+	if (item.slots > tp.maxSize) || (item.slots == tp.maxSize && tp.totalSize != 0) {
+		log.Debug("Transaction too large to fit in OverflowPool", "transaction", tx.Hash().String(), "requiredSlots", item.slots, "maxSlots", tp.maxSize)
 		return false
 	}
-
 	// Remove transactions until there is room for the new transaction
-	for tp.totalSize+txSlots > tp.maxSize {
-		if tp.txHeap.Len() == 0 {
+	for tp.totalSize+item.slots > tp.maxSize {
+		if tp.ageHeap.Len() == 0 {
 			// No transactions left to remove, cannot make room
 			log.Warn("Not enough space in OverflowPool even after clearing", "transaction", tx.Hash().String())
 			return false
 		}
-		// Remove the oldest transaction
-		oldestItem, ok := heap.Pop(&tp.txHeap).(*txHeapItem)
-		if !ok || oldestItem == nil {
-			log.Error("Failed to pop from txHeap during Add")
+		tp.evictOldest()
+	}
+	// Add the new transaction, spilling it to disk if persistent. Conditional
+	// transactions are kept in memory, as their conditions are not part of the
+	// encoding and they'd be reloaded unconditionally after a restart.
+	if tp.store != nil && tx.Options() == nil {
+		blob, err := rlp.EncodeToBytes(&overflowTx{Tx: tx, Time: uint64(item.timestamp)})
+		if err != nil {
+			log.Error("Failed to encode OverflowPool transaction", "transaction", tx.Hash().String(), "err", err)
 			return false
 		}
-		delete(tp.index, oldestItem.tx.Hash())
-		tp.totalSize -= uint64(numSlots(oldestItem.tx))
-		OverflowPoolGauge.Dec(1)
+		id, err := tp.store.Put(blob)
+		if err != nil {
+			log.Error("Failed to spill OverflowPool transaction to disk", "transaction", tx.Hash().String(), "err", err)
+			return false
+		}
+		item.id, item.size = id, uint32(len(blob))
+	} else {
+		item.tx = tx
 	}
+	tp.push(item)
+	tp.updateMetrics()
 
-	// Add the new transaction
-	item := &txHeapItem{
-		tx:        tx,
-		timestamp: time.Now().UnixNano(),
+	return true
+}
+
+// push inserts an item into the index and the heaps.
+func (tp *TxOverflowPool) push(item *txHeapItem) {
+	heap.Push(&tp.ageHeap, item)
+	heap.Push(&tp.priceHeap, item)
+	tp.index[item.hash] = item
+	tp.totalSize += item.slots
+	if item.tx == nil {
+		tp.diskSize += uint64(item.size)
 	}
-	heap.Push(&tp.txHeap, item)
-	tp.index[tx.Hash()] = item
-	tp.totalSize += txSlots
-	OverflowPoolGauge.Inc(1)
+}
 
-	return true
+// release drops an item already removed from the heaps from the index and the
+// persistent store, returning the transaction if requested.
+func (tp *TxOverflowPool) release(item *txHeapItem, load bool) *types.Transaction {
+	delete(tp.index, item.hash)
+	tp.totalSize -= item.slots
+
+	tx := item.tx
+	if tx == nil && tp.store != nil {
+		tp.diskSize -= uint64(item.size)
+		if load {
+			tx = tp.load(item)
+		}
+		if err := tp.store.Delete(item.id); err != nil {
+			log.Error("Failed to delete OverflowPool transaction from disk", "transaction", item.hash, "err", err)
+		}
+	}
+	return tx
+}
+
+// load retrieves a spilled transaction from disk.
+func (tp *TxOverflowPool) load(item *txHeapItem) *types.Transaction {
+	blob, err := tp.store.Get(item.id)
+	if err != nil {
+		log.Error("Failed to load OverflowPool transaction from disk", "transaction", item.hash, "err", err)
+		return nil
+	}
+	entry := new(overflowTx)
+	if err := rlp.DecodeBytes(blob, entry); err != nil {
+		log.Error("Failed to decode OverflowPool transaction", "transaction", item.hash, "err", err)
+		return nil
+	}
+	return entry.Tx
+}
+
+// evictOldest drops the oldest transaction from the pool to make room.
+func (tp *TxOverflowPool) evictOldest() {
+	item := heap.Pop(&tp.ageHeap).(*txHeapItem)
+	heap.Remove(&tp.priceHeap, item.priceIndex)
+
+	tx := tp.release(item, tp.evicted != nil)
+	overflowPoolEvictMeter.Mark(1)
+
+	if tp.evicted != nil && tx != nil {
+		tp.evicted(tx)
+	}
+}
+
+// updateMetrics reports the current size of the pool.
+func (tp *TxOverflowPool) updateMetrics() {
+	OverflowPoolGauge.Update(int64(len(tp.index)))
+	overflowPoolSlotsGauge.Update(int64(tp.totalSize))
+	overflowPoolDiskGauge.Update(int64(tp.diskSize))
 }
 
 func (tp *TxOverflowPool) Get(hash common.Hash) (*types.Transaction, bool) {
 	tp.mu.RLock()
 	defer tp.mu.RUnlock()
-	if item, ok := tp.index[hash]; ok {
+
+	item, ok := tp.index[hash]
+	if !ok {
+		return nil, false
+	}
+	if item.tx != nil {
 		return item.tx, true
 	}
-	return nil, false
+	tx := tp.load(item)
+	return tx, tx != nil
 }
 
 func (tp *TxOverflowPool) Remove(hash common.Hash) {
 	tp.mu.Lock()
 	defer tp.mu.Unlock()
+
 	if item, ok := tp.index[hash]; ok {
-		heap.Remove(&tp.txHeap, item.index)
-		delete(tp.index, hash)
-		tp.totalSize -= uint64(numSlots(item.tx))
-		OverflowPoolGauge.Dec(1)
+		heap.Remove(&tp.ageHeap, item.ageIndex)
+		heap.Remove(&tp.priceHeap, item.priceIndex)
+		tp.release(item, false)
+		tp.updateMetrics()
 	}
 }
 
+// Flush removes up to n transactions from the pool, the oldest ones first.
 func (tp *TxOverflowPool) Flush(n int) []*types.Transaction {
+	return tp.flush(n, &tp.ageHeap, &tp.priceHeap)
+}
+
+// FlushBest removes up to n transactions from the pool, the best paying ones
+// first.
+func (tp *TxOverflowPool) FlushBest(n int) []*types.Transaction {
+	return tp.flush(n, &tp.priceHeap, &tp.ageHeap)
+}
+
+// flush removes up to n transactions from the pool in the order of the given
+// heap, dropping them from the other heap as well.
+func (tp *TxOverflowPool) flush(n int, order *txHeap, other *txHeap) []*types.Transaction {
 	tp.mu.Lock()
 	defer tp.mu.Unlock()
-	if n > tp.txHeap.Len() {
-		n = tp.txHeap.Len()
+
+	if n > order.Len() {
+		n = order.Len()
 	}
-	txs := make([]*types.Transaction, n)
+	txs := make([]*types.Transaction, 0, n)
 	for i := 0; i < n; i++ {
-		item, ok := heap.Pop(&tp.txHeap).(*txHeapItem)
-		if !ok || item == nil {
-			continue
+		item := heap.Pop(order).(*txHeapItem)
+		heap.Remove(other, *other.index(item))
+
+		if tx := tp.release(item, true); tx != nil {
+			txs = append(txs, tx)
 		}
-		txs[i] = item.tx
-		delete(tp.index, item.tx.Hash())
-		tp.totalSize -= uint64(numSlots(item.tx))
 	}
+	overflowPoolFlushMeter.Mark(int64(len(txs)))
+	tp.updateMetrics()
 
-	OverflowPoolGauge.Dec(int64(n))
 	return txs
 }
 
 func (tp *TxOverflowPool) Len() int {
 	tp.mu.RLock()
 	defer tp.mu.RUnlock()
-	return tp.txHeap.Len()
+	return len(tp.index)
 }
 
 func (tp *TxOverflowPool) Size() uint64 {
@@ -182,9 +413,8 @@ func (tp *TxOverflowPool) Size() uint64 {
 func (tp *TxOverflowPool) PrintTxStats() {
 	tp.mu.RLock()
 	defer tp.mu.RUnlock()
-	for _, item := range tp.txHeap {
-		tx := item.tx
+	for _, item := range tp.ageHeap.items {
 		fmt.Printf("Hash: %s, Timestamp: %d, GasFeeCap: %s, GasTipCap: %s\n",
-			tx.Hash().String(), item.timestamp, tx.GasFeeCap().String(), tx.GasTipCap().String())
+			item.hash.String(), item.timestamp, item.feeCap.String(), item.tipCap.String())
 	}
 }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/cometbft/cometbft/libs/rand"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/stretchr/testify/assert"
 )
@@ -200,6 +201,156 @@ func TestTxOverflowPoolSlotCalculation(t *testing.T) {
 	assert.False(t, exists, "Expected tx1 to be evicted from the pool")
 }
 
+func TestTxOverflowPoolEviction(t *testing.T) {
+	pool := NewTxOverflowPoolHeap(2)
+
+	var evicted []common.Hash
+	pool.evicted = func(tx *types.Transaction) { evicted = append(evicted, tx.Hash()) }
+
+	tx1 := createTestTx(1, big.NewInt(2000))
+	tx2 := createTestTx(2, big.NewInt(1000))
+	assert.True(t, pool.Add(tx1))
+	time.Sleep(time.Millisecond)
+	assert.True(t, pool.Add(tx2))
+	time.Sleep(time.Millisecond)
+
+	// A new transaction evicts the oldest one, even if it's cheaper
+	tx3 := createTestTx(3, big.NewInt(500))
+	assert.True(t, pool.Add(tx3))
+	assert.Equal(t, []common.Hash{tx1.Hash()}, evicted)
+
+	_, exists := pool.Get(tx1.Hash())
+	assert.False(t, exists, "Expected tx1 to be evicted from the pool")
+	_, exists = pool.Get(tx2.Hash())
+	assert.True(t, exists, "Expected tx2 to remain in the pool")
+}
+
+func TestTxOverflowPoolFlushBest(t *testing.T) {
+	pool := NewTxOverflowPoolHeap(4)
+	tx1 := createTestTx(1, big.NewInt(1000))
+	tx2 := createTestTx(2, big.NewInt(2000))
+	tx3 := createTestTx(3, big.NewInt(3000))
+	tx4 := createTestTx(4, big.NewInt(2000))
+
+	pool.Add(tx2)
+	time.Sleep(time.Millisecond)
+	pool.Add(tx1)
+	pool.Add(tx3)
+	time.Sleep(time.Millisecond)
+	pool.Add(tx4) // Same price as tx2, but added later
+
+	popped := pool.FlushBest(4)
+	if len(popped) != 4 {
+		t.Fatalf("FlushBest(4) should return 4 transactions, got %d", len(popped))
+	}
+	if popped[0].Hash() != tx3.Hash() || popped[1].Hash() != tx2.Hash() || popped[2].Hash() != tx4.Hash() || popped[3].Hash() != tx1.Hash() {
+		t.Error("Transactions not flushed in correct order (highest price first, then earliest timestamp)")
+	}
+	if pool.Len() != 0 || pool.Size() != 0 {
+		t.Errorf("Pool should be empty after flushing, got length %d, size %d", pool.Len(), pool.Size())
+	}
+}
+
+func TestTxOverflowPoolPersistence(t *testing.T) {
+	dir := t.TempDir()
+
+	pool := NewTxOverflowPoolHeap(4)
+	if err := pool.Open(dir); err != nil {
+		t.Fatalf("Failed to open overflow pool: %v", err)
+	}
+	txs := []*types.Transaction{
+		createTestTx(1, big.NewInt(2000)),
+		createTestTx(2, big.NewInt(1000)),
+		createTestTx(3, big.NewInt(3000)),
+		createLargeTestTx(4, big.NewInt(1500), 40000), // takes 2 slots
+	}
+	for _, tx := range txs[:3] {
+		assert.True(t, pool.Add(tx))
+		time.Sleep(time.Millisecond)
+	}
+	// Spilled transactions are retrievable and removable while open
+	if tx, ok := pool.Get(txs[0].Hash()); !ok || tx.Hash() != txs[0].Hash() {
+		t.Fatalf("Failed to retrieve spilled transaction")
+	}
+	pool.Remove(txs[0].Hash())
+	assert.True(t, pool.Add(txs[0]))
+	time.Sleep(time.Millisecond)
+
+	// Evict the oldest transaction to make room for the large one
+	assert.True(t, pool.Add(txs[3]))
+	assert.Equal(t, 3, pool.Len())
+	assert.Equal(t, uint64(4), pool.Size())
+	if err := pool.Close(); err != nil {
+		t.Fatalf("Failed to close overflow pool: %v", err)
+	}
+	// Reopen the pool and ensure the transactions survived the restart
+	pool = NewTxOverflowPoolHeap(4)
+	if err := pool.Open(dir); err != nil {
+		t.Fatalf("Failed to reopen overflow pool: %v", err)
+	}
+	assert.Equal(t, 3, pool.Len())
+	assert.Equal(t, uint64(4), pool.Size())
+	if _, ok := pool.Get(txs[1].Hash()); ok {
+		t.Fatalf("Evicted transaction resurrected after restart")
+	}
+	popped := pool.FlushBest(2)
+	if len(popped) != 2 || popped[0].Hash() != txs[2].Hash() || popped[1].Hash() != txs[0].Hash() {
+		t.Fatalf("Transactions not flushed in price order after restart")
+	}
+	if err := pool.Close(); err != nil {
+		t.Fatalf("Failed to close overflow pool: %v", err)
+	}
+	// Reopen the pool with a reduced capacity, the remaining large tx is trimmed
+	pool = NewTxOverflowPoolHeap(1)
+	if err := pool.Open(dir); err != nil {
+		t.Fatalf("Failed to reopen overflow pool: %v", err)
+	}
+	defer pool.Close()
+
+	assert.Equal(t, 0, pool.Len())
+	assert.Equal(t, uint64(0), pool.Size())
+}
+
+func TestTxOverflowPoolConditional(t *testing.T) {
+	dir := t.TempDir()
+
+	pool := NewTxOverflowPoolHeap(4)
+	if err := pool.Open(dir); err != nil {
+		t.Fatalf("Failed to open overflow pool: %v", err)
+	}
+	max := hexutil.Uint64(1000)
+	opts := &types.TransactionOpts{BlockNumberMax: &max}
+
+	plain := createTestTx(1, big.NewInt(1000))
+	cond := createTestTx(2, big.NewInt(1000)).WithOptions(opts)
+	assert.True(t, pool.Add(plain))
+	assert.True(t, pool.Add(cond))
+
+	// The conditional transaction is kept in memory along with its conditions
+	tx, ok := pool.Get(cond.Hash())
+	if !ok || tx.Options() != opts {
+		t.Fatalf("Conditional transaction lost its conditions")
+	}
+	if err := pool.Close(); err != nil {
+		t.Fatalf("Failed to close overflow pool: %v", err)
+	}
+	// Reopen the pool and ensure the conditional transaction isn't reloaded
+	// without its conditions
+	pool = NewTxOverflowPoolHeap(4)
+	if err := pool.Open(dir); err != nil {
+		t.Fatalf("Failed to reopen overflow pool: %v", err)
+	}
+	defer pool.Close()
+
+	assert.Equal(t, 1, pool.Len())
+	if _, ok := pool.Get(cond.Hash()); ok {
+		t.Fatalf("Conditional transaction reloaded after restart")
+	}
+	if _, ok := pool.Get(plain.Hash()); !ok {
+		t.Fatalf("Transaction missing after restart")
+	}
+}
+
 func TestBiggerTx(t *testing.T) {
 	// Create a transaction with 40KB of data (which should take 2 slots)
 	dataSize := 40000
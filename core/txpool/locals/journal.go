@@ -41,6 +41,31 @@ type devNull struct{}
 func (*devNull) Write(p []byte) (n int, err error) { return len(p), nil }
 func (*devNull) Close() error                      { return nil }
 
+// journalEntry is a tracked transaction as stored in the journal, along with
+// its tracking progress.
+type journalEntry struct {
+	Tx       *types.Transaction
+	Time     uint64 // Unix time of the first submission
+	Block    uint64 // Head block number at the first submission
+	Attempts uint64 // Number of times the transaction was resubmitted to the pool
+	Bumps    uint64 `rlp:"optional"` // Number of times the fees of the transaction were bumped
+}
+
+// decodeJournalEntry parses an entry of the journal. Journals written before the
+// tracking progress was recorded contain bare transactions, which are returned
+// with zero progress.
+func decodeJournalEntry(blob []byte) (*journalEntry, error) {
+	entry := new(journalEntry)
+	if err := rlp.DecodeBytes(blob, entry); err == nil {
+		return entry, nil
+	}
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(blob, tx); err != nil {
+		return nil, err
+	}
+	return &journalEntry{Tx: tx}, nil
+}
+
 // journal is a rotating log of transactions with the aim of storing locally
 // created transactions to allow non-executed ones to survive node restarts.
 type journal struct {
@@ -57,7 +82,7 @@ func newTxJournal(path string) *journal {
 
 // load parses a transaction journal dump from disk, loading its contents into
 // the specified pool.
-func (journal *journal) load(add func([]*types.Transaction) []error) error {
+func (journal *journal) load(add func([]*journalEntry) []error) error {
 	// Open the journal for loading any past transactions
 	input, err := os.Open(journal.path)
 	if errors.Is(err, fs.ErrNotExist) {
@@ -80,8 +105,8 @@ func (journal *journal) load(add func([]*types.Transaction) []error) error {
 	// Create a method to load a limited batch of transactions and bump the
 	// appropriate progress counters. Then use this method to load all the
 	// journaled transactions in small-ish batches.
-	loadBatch := func(txs types.Transactions) {
-		for _, err := range add(txs) {
+	loadBatch := func(entries []*journalEntry) {
+		for _, err := range add(entries) {
 			if err != nil {
 				log.Debug("Failed to add journaled transaction", "err", err)
 				dropped++
@@ -90,16 +115,20 @@ func (journal *journal) load(add func([]*types.Transaction) []error) error {
 	}
 	var (
 		failure error
-		batch   types.Transactions
+		batch   []*journalEntry
 	)
 	for {
 		// Parse the next transaction and terminate on error
-		tx := new(types.Transaction)
-		if err = stream.Decode(tx); err != nil {
+		var entry *journalEntry
+		blob, err := stream.Raw()
+		if err == nil {
+			entry, err = decodeJournalEntry(blob)
+		}
+		if err != nil {
 			if err != io.EOF {
 				failure = err
 			}
-			if batch.Len() > 0 {
+			if len(batch) > 0 {
 				loadBatch(batch)
 			}
 			break
@@ -107,7 +136,7 @@ func (journal *journal) load(add func([]*types.Transaction) []error) error {
 		// New transaction parsed, queue up for later, import if threshold is reached
 		total++
 
-		if batch = append(batch, tx); batch.Len() > 1024 {
+		if batch = append(batch, entry); len(batch) > 1024 {
 			loadBatch(batch)
 			batch = batch[:0]
 		}
@@ -118,11 +147,11 @@ func (journal *journal) load(add func([]*types.Transaction) []error) error {
 }
 
 // insert adds the specified transaction to the local disk journal.
-func (journal *journal) insert(tx *types.Transaction) error {
+func (journal *journal) insert(entry *journalEntry) error {
 	if journal.writer == nil {
 		return errNoActiveJournal
 	}
-	if err := rlp.Encode(journal.writer, tx); err != nil {
+	if err := rlp.Encode(journal.writer, entry); err != nil {
 		return err
 	}
 	return nil
@@ -130,7 +159,7 @@ func (journal *journal) insert(tx *types.Transaction) error {
 
 // rotate regenerates the transaction journal based on the current contents of
 // the transaction pool.
-func (journal *journal) rotate(all map[common.Address]types.Transactions) error {
+func (journal *journal) rotate(all map[common.Address][]*journalEntry) error {
 	// Close the current journal (if any is open)
 	if journal.writer != nil {
 		if err := journal.writer.Close(); err != nil {
@@ -144,14 +173,14 @@ func (journal *journal) rotate(all map[common.Address]types.Transactions) error
 		return err
 	}
 	journaled := 0
-	for _, txs := range all {
-		for _, tx := range txs {
-			if err = rlp.Encode(replacement, tx); err != nil {
+	for _, entries := range all {
+		for _, entry := range entries {
+			if err = rlp.Encode(replacement, entry); err != nil {
 				replacement.Close()
 				return err
 			}
 		}
-		journaled += len(txs)
+		journaled += len(entries)
 	}
 	replacement.Close()
 
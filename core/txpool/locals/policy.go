@@ -0,0 +1,153 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package locals
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// maxPolicyPriceBump is the highest fee bump percentage a policy may set.
+const maxPolicyPriceBump = 100
+
+// Policy is the resubmission policy of the transactions of an account. The zero
+// policy tracks the transactions until included, resubmitting them unchanged.
+// Fee bumps are applied to at most maxPriceBumps resubmissions of a transaction,
+// including the ones still pending in the pool, and are raised to the minimum
+// bump the pool accepts replacements at.
+type Policy struct {
+	MaxAge    uint64 `json:"maxAge"`    // Seconds after the first submission to give up tracking at, 0 for never
+	MaxBlocks uint64 `json:"maxBlocks"` // Blocks after the first submission to give up tracking at, 0 for never
+	PriceBump uint64 `json:"priceBump"` // Percentage to bump the fees of the resubmitted transactions by, 0 for none
+}
+
+// expired reports whether the tracking of a transaction should be given up.
+func (p Policy) expired(tx *trackedTx, now time.Time, head uint64) bool {
+	if p.MaxAge > 0 && now.Sub(tx.time) > time.Duration(p.MaxAge)*time.Second {
+		return true
+	}
+	return p.MaxBlocks > 0 && head > tx.block+p.MaxBlocks
+}
+
+// policyPath returns the path of the policy file kept along a journal.
+func policyPath(journalPath string) string {
+	return strings.TrimSuffix(journalPath, filepath.Ext(journalPath)) + ".policies.json"
+}
+
+// loadPolicies reads the account policies from disk. A missing file is no error.
+func loadPolicies(path string) (map[common.Address]Policy, error) {
+	policies := make(map[common.Address]Policy)
+
+	blob, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return policies, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(blob, &policies); err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+// savePolicies writes the account policies to disk, replacing the previous file
+// atomically.
+func savePolicies(path string, policies map[common.Address]Policy) error {
+	blob, err := json.MarshalIndent(policies, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path+".new", blob, 0644); err != nil {
+		return err
+	}
+	return os.Rename(path+".new", path)
+}
+
+// bumpFee raises a fee by the given percentage.
+func bumpFee(fee *big.Int, bump uint64) *big.Int {
+	bumped := new(big.Int).Mul(fee, new(big.Int).SetUint64(100+bump))
+	bumped.Div(bumped, big.NewInt(100))
+
+	// Make sure tiny fees are bumped too, despite the rounding
+	if bump > 0 && bumped.Cmp(fee) <= 0 {
+		bumped.Add(fee, common.Big1)
+	}
+	return bumped
+}
+
+// replacement creates the unsigned replacement of a transaction with the fees
+// bumped by the given percentage. A cancellation replaces the transaction with
+// an empty transfer to the sender itself.
+func replacement(tx *types.Transaction, from common.Address, bump uint64, cancel bool) (*types.Transaction, error) {
+	var (
+		to         = tx.To()
+		value      = tx.Value()
+		data       = tx.Data()
+		gas        = tx.Gas()
+		accessList = tx.AccessList()
+	)
+	if cancel {
+		to, value, data, gas, accessList = &from, new(big.Int), nil, params.TxGas, nil
+	}
+	switch tx.Type() {
+	case types.LegacyTxType:
+		return types.NewTx(&types.LegacyTx{
+			Nonce:    tx.Nonce(),
+			GasPrice: bumpFee(tx.GasPrice(), bump),
+			Gas:      gas,
+			To:       to,
+			Value:    value,
+			Data:     data,
+		}), nil
+	case types.AccessListTxType:
+		return types.NewTx(&types.AccessListTx{
+			ChainID:    tx.ChainId(),
+			Nonce:      tx.Nonce(),
+			GasPrice:   bumpFee(tx.GasPrice(), bump),
+			Gas:        gas,
+			To:         to,
+			Value:      value,
+			Data:       data,
+			AccessList: accessList,
+		}), nil
+	case types.DynamicFeeTxType:
+		return types.NewTx(&types.DynamicFeeTx{
+			ChainID:    tx.ChainId(),
+			Nonce:      tx.Nonce(),
+			GasTipCap:  bumpFee(tx.GasTipCap(), bump),
+			GasFeeCap:  bumpFee(tx.GasFeeCap(), bump),
+			Gas:        gas,
+			To:         to,
+			Value:      value,
+			Data:       data,
+			AccessList: accessList,
+		}), nil
+	default:
+		return nil, errors.New("transaction type not replaceable")
+	}
+}
@@ -18,11 +18,14 @@
 package locals
 
 import (
+	"errors"
+	"fmt"
 	"slices"
 	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -31,43 +34,131 @@ import (
 	"github.com/ethereum/go-ethereum/params"
 )
 
+// maxPriceBumps is the number of times the fees of a transaction are bumped
+// on resubmission at most, after which it is resubmitted unchanged.
+const maxPriceBumps = 5
+
 var (
 	recheckInterval = time.Minute
 	localGauge      = metrics.GetOrRegisterGauge("txpool/local", nil)
+	expiredMeter    = metrics.GetOrRegisterMeter("txpool/local/expired", nil)
+	bumpedMeter     = metrics.GetOrRegisterMeter("txpool/local/bumped", nil)
+)
+
+var (
+	// ErrNotTracked is returned if a transaction to act on is not tracked.
+	ErrNotTracked = errors.New("transaction not tracked")
+
+	// ErrNoSigner is returned if a transaction needs to be replaced, but there
+	// is no way to sign the replacement.
+	ErrNoSigner = errors.New("no signer for replacement transactions")
+
+	// ErrPriceBumpTooHigh is returned if a policy bumps the fees by more than
+	// the allowed percentage.
+	ErrPriceBumpTooHigh = errors.New("price bump too high")
 )
 
+// BlockChain defines the minimal set of methods needed to age the tracked
+// transactions.
+type BlockChain interface {
+	// Config retrieves the chain's fork configuration.
+	Config() *params.ChainConfig
+
+	// CurrentBlock returns the current head of the chain.
+	CurrentBlock() *types.Header
+
+	// StateAt returns a state database for a given root hash (generally the head).
+	StateAt(root common.Hash) (*state.StateDB, error)
+}
+
+// SignFn signs a replacement transaction on behalf of its sender.
+type SignFn func(from common.Address, tx *types.Transaction) (*types.Transaction, error)
+
+// trackedTx is a tracked transaction along with its tracking progress.
+type trackedTx struct {
+	tx       *types.Transaction
+	from     common.Address
+	time     time.Time // Time of the first submission
+	block    uint64    // Head block number at the first submission
+	attempts uint64    // Number of times the transaction was resubmitted
+	bumps    uint64    // Number of times the fees of the transaction were bumped
+	last     time.Time // Time of the last (re)submission
+}
+
+// entry converts the tracked transaction into its journal representation.
+func (tx *trackedTx) entry() *journalEntry {
+	return &journalEntry{
+		Tx:       tx.tx,
+		Time:     uint64(tx.time.Unix()),
+		Block:    tx.block,
+		Attempts: tx.attempts,
+		Bumps:    tx.bumps,
+	}
+}
+
+// TrackedTx is the tracking status of a local transaction.
+type TrackedTx struct {
+	Hash     common.Hash    `json:"hash"`
+	From     common.Address `json:"from"`
+	Nonce    uint64         `json:"nonce"`
+	Time     time.Time      `json:"time"`     // Time of the first submission
+	Block    uint64         `json:"block"`    // Head block number at the first submission
+	Attempts uint64         `json:"attempts"` // Number of times the transaction was resubmitted
+	Bumps    uint64         `json:"bumps"`    // Number of times the fees of the transaction were bumped
+	Pooled   bool           `json:"pooled"`   // Whether the transaction is currently in the pool
+}
+
 // TxTracker is a struct used to track priority transactions; it will check from
 // time to time if the main pool has forgotten about any of the transaction
 // it is tracking, and if so, submit it again.
 // This is used to track 'locals'.
-// This struct does not care about transaction validity, price-bumps or account limits,
-// but optimistically accepts transactions.
+// This struct does not care about transaction validity or account limits, but
+// optimistically accepts transactions. The resubmissions of each account follow
+// its policy, which may give up on old transactions or bump their fees.
 type TxTracker struct {
-	all    map[common.Hash]*types.Transaction       // All tracked transactions
-	byAddr map[common.Address]*legacypool.SortedMap // Transactions by address
+	all      map[common.Hash]*trackedTx               // All tracked transactions
+	byAddr   map[common.Address]*legacypool.SortedMap // Transactions by address
+	policies map[common.Address]Policy                // Resubmission policies by address
+	bump     uint64                                   // Minimum fee bump percentage the pool accepts replacements at
 
-	journal   *journal       // Journal of local transaction to back up to disk
-	rejournal time.Duration  // How often to rotate journal
-	pool      *txpool.TxPool // The tx pool to interact with
-	signer    types.Signer
+	journal    *journal       // Journal of local transaction to back up to disk
+	rejournal  time.Duration  // How often to rotate journal
+	policyPath string         // File to persist the account policies into
+	chain      BlockChain     // The chain to age the transactions by
+	pool       *txpool.TxPool // The tx pool to interact with
+	signer     types.Signer
+	sign       SignFn // Signer of fee bumped replacements, if any
 
 	shutdownCh chan struct{}
 	mu         sync.Mutex
 	wg         sync.WaitGroup
 }
 
-// New creates a new TxTracker
-func New(journalPath string, journalTime time.Duration, chainConfig *params.ChainConfig, next *txpool.TxPool) *TxTracker {
+// New creates a new TxTracker. The price bump is the minimum fee bump percentage
+// the pool accepts replacements at.
+func New(journalPath string, journalTime time.Duration, chain BlockChain, next *txpool.TxPool, priceBump uint64, sign SignFn) *TxTracker {
 	pool := &TxTracker{
-		all:        make(map[common.Hash]*types.Transaction),
+		all:        make(map[common.Hash]*trackedTx),
 		byAddr:     make(map[common.Address]*legacypool.SortedMap),
-		signer:     types.LatestSigner(chainConfig),
+		policies:   make(map[common.Address]Policy),
+		bump:       priceBump,
+		signer:     types.LatestSigner(chain.Config()),
+		sign:       sign,
 		shutdownCh: make(chan struct{}),
+		chain:      chain,
 		pool:       next,
 	}
 	if journalPath != "" {
 		pool.journal = newTxJournal(journalPath)
 		pool.rejournal = journalTime
+		pool.policyPath = policyPath(journalPath)
+
+		policies, err := loadPolicies(pool.policyPath)
+		if err != nil {
+			log.Warn("Failed to load local transaction policies", "path", pool.policyPath, "err", err)
+		} else {
+			pool.policies = policies
+		}
 	}
 	return pool
 }
@@ -81,10 +172,25 @@ func (tracker *TxTracker) Track(tx *types.Transaction) {
 // TrackAll adds a list of transactions to the tracked set.
 // Note: blob-type transactions are ignored.
 func (tracker *TxTracker) TrackAll(txs []*types.Transaction) {
+	entries := make([]*journalEntry, len(txs))
+	for i, tx := range txs {
+		entries[i] = &journalEntry{Tx: tx}
+	}
+	tracker.trackEntries(entries)
+}
+
+// trackEntries adds a list of transactions to the tracked set, resuming their
+// tracking progress if known.
+func (tracker *TxTracker) trackEntries(entries []*journalEntry) []error {
 	tracker.mu.Lock()
 	defer tracker.mu.Unlock()
 
-	for _, tx := range txs {
+	var (
+		now  = time.Now()
+		head = tracker.chain.CurrentBlock().Number.Uint64()
+	)
+	for _, entry := range entries {
+		tx := entry.Tx
 		if tx.Type() == types.BlobTxType {
 			continue
 		}
@@ -96,65 +202,263 @@ func (tracker *TxTracker) TrackAll(txs []*types.Transaction) {
 		if err != nil { // Ignore this tx
 			continue
 		}
-		tracker.all[tx.Hash()] = tx
-		if tracker.byAddr[addr] == nil {
-			tracker.byAddr[addr] = legacypool.NewSortedMap()
+		tracked := &trackedTx{tx: tx, from: addr, time: now, block: head, attempts: entry.Attempts, bumps: entry.Bumps, last: now}
+		if entry.Time != 0 {
+			tracked.time = time.Unix(int64(entry.Time), 0)
+		}
+		if entry.Block != 0 {
+			tracked.block = entry.Block
 		}
-		tracker.byAddr[addr].Put(tx)
+		tracker.put(tracked)
 
 		if tracker.journal != nil {
-			_ = tracker.journal.insert(tx)
+			_ = tracker.journal.insert(tracked.entry())
 		}
 	}
 	localGauge.Update(int64(len(tracker.all)))
+	return nil
+}
+
+// put adds a transaction to the tracked set, replacing any previous one of the
+// same sender and nonce.
+func (tracker *TxTracker) put(tx *trackedTx) {
+	txs := tracker.byAddr[tx.from]
+	if txs == nil {
+		txs = legacypool.NewSortedMap()
+		tracker.byAddr[tx.from] = txs
+	}
+	if old := txs.Get(tx.tx.Nonce()); old != nil {
+		delete(tracker.all, old.Hash())
+	}
+	txs.Put(tx.tx)
+	tracker.all[tx.tx.Hash()] = tx
+}
+
+// remove drops a transaction from the tracked set.
+func (tracker *TxTracker) remove(tx *trackedTx) {
+	delete(tracker.all, tx.tx.Hash())
+	if txs := tracker.byAddr[tx.from]; txs != nil {
+		txs.Remove(tx.tx.Nonce())
+		if txs.Len() == 0 {
+			delete(tracker.byAddr, tx.from)
+		}
+	}
+}
+
+// replace signs a replacement of a tracked transaction with bumped fees, which
+// carries over the tracking progress of the original.
+func (tracker *TxTracker) replace(tx *trackedTx, bump uint64, cancel bool) (*trackedTx, error) {
+	if tracker.sign == nil {
+		return nil, ErrNoSigner
+	}
+	unsigned, err := replacement(tx.tx, tx.from, bump, cancel)
+	if err != nil {
+		return nil, err
+	}
+	signed, err := tracker.sign(tx.from, unsigned)
+	if err != nil {
+		return nil, err
+	}
+	return &trackedTx{tx: signed, from: tx.from, time: tx.time, block: tx.block, attempts: tx.attempts, bumps: tx.bumps + 1, last: time.Now()}, nil
+}
+
+// putReplacement tracks a replacement instead of the original transaction.
+func (tracker *TxTracker) putReplacement(tx *trackedTx) {
+	tracker.put(tx)
+	if tracker.journal != nil {
+		_ = tracker.journal.insert(tx.entry())
+	}
 }
 
 // recheck checks and returns any transactions that needs to be resubmitted.
-func (tracker *TxTracker) recheck(journalCheck bool) (resubmits []*types.Transaction, rejournal map[common.Address]types.Transactions) {
+func (tracker *TxTracker) recheck(journalCheck bool) (resubmits []*types.Transaction, rejournal map[common.Address][]*journalEntry) {
 	tracker.mu.Lock()
 	defer tracker.mu.Unlock()
 
 	var (
-		numStales = 0
-		numOk     = 0
+		numStales  = 0
+		numExpired = 0
+		numBumped  = 0
+		numOk      = 0
+
+		now    = time.Now()
+		header = tracker.chain.CurrentBlock()
+		head   = header.Number.Uint64()
 	)
+	// Transactions are stale once included, the pool nonce would also count the
+	// pending ones which may still need a bump
+	statedb, err := tracker.chain.StateAt(header.Root)
+	if err != nil {
+		log.Warn("Failed to retrieve head state for tx tracker", "err", err)
+	}
 	for sender, txs := range tracker.byAddr {
 		// Wipe the stales
-		stales := txs.Forward(tracker.pool.Nonce(sender))
+		var nonce uint64
+		if statedb != nil {
+			nonce = statedb.GetNonce(sender)
+		} else {
+			nonce = tracker.pool.Nonce(sender)
+		}
+		stales := txs.Forward(nonce)
 		for _, tx := range stales {
 			delete(tracker.all, tx.Hash())
 		}
 		numStales += len(stales)
 
-		// Check the non-stale
+		// Check the non-stale, giving up on the ones expired by the policy
+		policy := tracker.policies[sender]
 		for _, tx := range txs.Flatten() {
-			if tracker.pool.Has(tx.Hash()) {
+			tracked := tracker.all[tx.Hash()]
+			if policy.expired(tracked, now, head) {
+				tracker.remove(tracked)
+				numExpired++
+				continue
+			}
+			// Pooled transactions are left alone, unless they have been pending
+			// for a whole resubmission interval and the policy bumps their fees
+			bump := policy.PriceBump > 0 && tracked.bumps < maxPriceBumps
+			if tracker.pool.Has(tx.Hash()) && (!bump || now.Sub(tracked.last) < recheckInterval) {
 				numOk++
 				continue
 			}
-			resubmits = append(resubmits, tx)
+			tracked.attempts++
+			tracked.last = now
+			if bump {
+				replaced, err := tracker.replace(tracked, max(policy.PriceBump, tracker.bump), false)
+				if err != nil {
+					log.Debug("Failed to bump local transaction", "hash", tx.Hash(), "err", err)
+				} else {
+					tracker.putReplacement(replaced)
+					tracked = replaced
+					numBumped++
+				}
+			}
+			resubmits = append(resubmits, tracked.tx)
+		}
+		if txs.Len() == 0 {
+			delete(tracker.byAddr, sender)
 		}
 	}
 
 	if journalCheck { // rejournal
-		rejournal = make(map[common.Address]types.Transactions)
+		rejournal = make(map[common.Address][]*journalEntry)
 		for _, tx := range tracker.all {
-			addr, _ := types.Sender(tracker.signer, tx)
-			rejournal[addr] = append(rejournal[addr], tx)
+			rejournal[tx.from] = append(rejournal[tx.from], tx.entry())
 		}
 		// Sort them
 		for _, list := range rejournal {
 			// cmp(a, b) should return a negative number when a < b,
-			slices.SortFunc(list, func(a, b *types.Transaction) int {
-				return int(a.Nonce() - b.Nonce())
+			slices.SortFunc(list, func(a, b *journalEntry) int {
+				return int(a.Tx.Nonce() - b.Tx.Nonce())
 			})
 		}
 	}
 	localGauge.Update(int64(len(tracker.all)))
-	log.Debug("Tx tracker status", "need-resubmit", len(resubmits), "stale", numStales, "ok", numOk)
+	expiredMeter.Mark(int64(numExpired))
+	bumpedMeter.Mark(int64(numBumped))
+	log.Debug("Tx tracker status", "need-resubmit", len(resubmits), "stale", numStales, "expired", numExpired, "bumped", numBumped, "ok", numOk)
 	return resubmits, rejournal
 }
 
+// Tracked returns the tracking status of all the tracked transactions, sorted by
+// sender and nonce.
+func (tracker *TxTracker) Tracked() []*TrackedTx {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	tracked := make([]*TrackedTx, 0, len(tracker.all))
+	for _, tx := range tracker.all {
+		tracked = append(tracked, &TrackedTx{
+			Hash:     tx.tx.Hash(),
+			From:     tx.from,
+			Nonce:    tx.tx.Nonce(),
+			Time:     tx.time,
+			Block:    tx.block,
+			Attempts: tx.attempts,
+			Bumps:    tx.bumps,
+			Pooled:   tracker.pool.Has(tx.tx.Hash()),
+		})
+	}
+	slices.SortFunc(tracked, func(a, b *TrackedTx) int {
+		if c := a.From.Cmp(b.From); c != 0 {
+			return c
+		}
+		return int(a.Nonce - b.Nonce)
+	})
+	return tracked
+}
+
+// Untrack stops tracking a transaction. The transaction is left in the pool if
+// still there, but won't be resubmitted anymore.
+func (tracker *TxTracker) Untrack(hash common.Hash) error {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	tx, ok := tracker.all[hash]
+	if !ok {
+		return ErrNotTracked
+	}
+	tracker.remove(tx)
+	localGauge.Update(int64(len(tracker.all)))
+	return nil
+}
+
+// Cancel replaces a tracked transaction with an empty transfer of its sender to
+// itself, paying enough to replace the original in the pool. The cancellation
+// is tracked instead of the original and its hash returned.
+func (tracker *TxTracker) Cancel(hash common.Hash) (common.Hash, error) {
+	tracker.mu.Lock()
+	tx, ok := tracker.all[hash]
+	if !ok {
+		tracker.mu.Unlock()
+		return common.Hash{}, ErrNotTracked
+	}
+	bump := max(tracker.policies[tx.from].PriceBump, tracker.bump)
+	cancel, err := tracker.replace(tx, bump, true)
+	tracker.mu.Unlock()
+
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if err := tracker.pool.Add([]*types.Transaction{cancel.tx}, false)[0]; err != nil {
+		return common.Hash{}, err
+	}
+	tracker.mu.Lock()
+	tracker.putReplacement(cancel)
+	localGauge.Update(int64(len(tracker.all)))
+	tracker.mu.Unlock()
+
+	return cancel.tx.Hash(), nil
+}
+
+// Policy returns the resubmission policy of an account.
+func (tracker *TxTracker) Policy(addr common.Address) Policy {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	return tracker.policies[addr]
+}
+
+// SetPolicy sets the resubmission policy of an account, persisting it along the
+// journal if there's one. Setting the zero policy restores the default one.
+func (tracker *TxTracker) SetPolicy(addr common.Address, policy Policy) error {
+	if policy.PriceBump > maxPolicyPriceBump {
+		return fmt.Errorf("%w: %d%% > %d%%", ErrPriceBumpTooHigh, policy.PriceBump, maxPolicyPriceBump)
+	}
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	if policy == (Policy{}) {
+		delete(tracker.policies, addr)
+	} else {
+		tracker.policies[addr] = policy
+	}
+	if tracker.policyPath == "" {
+		return nil
+	}
+	return savePolicies(tracker.policyPath, tracker.policies)
+}
+
 // Start implements node.Lifecycle interface
 // Start is called after all services have been constructed and the networking
 // layer was also initialized to spawn any goroutines required by the service.
@@ -177,10 +481,7 @@ func (tracker *TxTracker) loop() {
 	defer tracker.wg.Done()
 
 	if tracker.journal != nil {
-		tracker.journal.load(func(transactions []*types.Transaction) []error {
-			tracker.TrackAll(transactions)
-			return nil
-		})
+		tracker.journal.load(tracker.trackEntries)
 		defer tracker.journal.close()
 	}
 	var (
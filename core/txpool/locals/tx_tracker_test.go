@@ -0,0 +1,253 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package locals
+
+import (
+	"errors"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+var (
+	testKey, _ = crypto.GenerateKey()
+	testAddr   = crypto.PubkeyToAddress(testKey.PublicKey)
+	testSigner = types.LatestSigner(params.TestChainConfig)
+)
+
+// newTestTracker creates a tracker on top of a fresh chain and pool with the
+// test account funded, signing replacements with the test key.
+func newTestTracker(t *testing.T, journal string) *TxTracker {
+	gspec := &core.Genesis{
+		Config: params.TestChainConfig,
+		Alloc:  types.GenesisAlloc{testAddr: {Balance: big.NewInt(params.Ether)}},
+	}
+	chain, _ := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	t.Cleanup(chain.Stop)
+
+	config := legacypool.DefaultConfig
+	config.Journal = ""
+
+	pool, err := txpool.New(config.PriceLimit, chain, []txpool.SubPool{legacypool.New(config, chain)})
+	if err != nil {
+		t.Fatalf("Failed to create transaction pool: %v", err)
+	}
+	t.Cleanup(func() { pool.Close() })
+
+	sign := func(from common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		return types.SignTx(tx, testSigner, testKey)
+	}
+	return New(journal, time.Hour, chain, pool, config.PriceBump, sign)
+}
+
+func testTx(nonce uint64, price int64) *types.Transaction {
+	return types.MustSignNewTx(testKey, testSigner, &types.LegacyTx{
+		Nonce:    nonce,
+		To:       &common.Address{},
+		Gas:      params.TxGas,
+		GasPrice: big.NewInt(price),
+	})
+}
+
+// Tests that the journal entries keep the tracking progress, and that journals
+// of bare transactions are still loaded.
+func TestJournalEntries(t *testing.T) {
+	tx := testTx(0, params.GWei)
+
+	legacy, _ := rlp.EncodeToBytes(tx)
+	entry, err := decodeJournalEntry(legacy)
+	if err != nil {
+		t.Fatalf("Failed to decode bare transaction: %v", err)
+	}
+	if entry.Tx.Hash() != tx.Hash() || entry.Time != 0 || entry.Attempts != 0 {
+		t.Fatalf("Unexpected entry of bare transaction: %+v", entry)
+	}
+	blob, _ := rlp.EncodeToBytes(&journalEntry{Tx: tx, Time: 1234, Block: 5, Attempts: 2})
+	if entry, err = decodeJournalEntry(blob); err != nil {
+		t.Fatalf("Failed to decode journal entry: %v", err)
+	}
+	if entry.Tx.Hash() != tx.Hash() || entry.Time != 1234 || entry.Block != 5 || entry.Attempts != 2 {
+		t.Fatalf("Unexpected journal entry: %+v", entry)
+	}
+}
+
+// Tests that the resubmissions follow the policy of the sender.
+func TestResubmissionPolicy(t *testing.T) {
+	tracker := newTestTracker(t, "")
+
+	// Without a policy, lost transactions are resubmitted unchanged
+	tx := testTx(0, params.GWei)
+	tracker.Track(tx)
+
+	resubmits, _ := tracker.recheck(false)
+	if len(resubmits) != 1 || resubmits[0].Hash() != tx.Hash() {
+		t.Fatalf("Unexpected resubmissions: %v", resubmits)
+	}
+	// With a price bump, the resubmitted transaction replaces the original
+	if err := tracker.SetPolicy(testAddr, Policy{PriceBump: 20}); err != nil {
+		t.Fatalf("Failed to set policy: %v", err)
+	}
+	resubmits, _ = tracker.recheck(false)
+	if len(resubmits) != 1 || resubmits[0].GasPrice().Cmp(big.NewInt(params.GWei*12/10)) != 0 {
+		t.Fatalf("Unexpected resubmissions: %v", resubmits)
+	}
+	tracked := tracker.Tracked()
+	if len(tracked) != 1 || tracked[0].Hash != resubmits[0].Hash() || tracked[0].Attempts != 2 {
+		t.Fatalf("Unexpected tracked transactions: %+v", tracked)
+	}
+	// Past the maximum age, the transaction is given up on
+	tracker.all[resubmits[0].Hash()].time = time.Now().Add(-time.Hour)
+	if err := tracker.SetPolicy(testAddr, Policy{MaxAge: 60}); err != nil {
+		t.Fatalf("Failed to set policy: %v", err)
+	}
+	if resubmits, _ = tracker.recheck(false); len(resubmits) != 0 {
+		t.Fatalf("Unexpected resubmissions: %v", resubmits)
+	}
+	if tracked := tracker.Tracked(); len(tracked) != 0 {
+		t.Fatalf("Unexpected tracked transactions: %+v", tracked)
+	}
+}
+
+// Tests that the fees of transactions stuck in the pool are bumped once they are
+// pending for a whole resubmission interval.
+func TestPooledBump(t *testing.T) {
+	tracker := newTestTracker(t, "")
+	if err := tracker.SetPolicy(testAddr, Policy{PriceBump: 20}); err != nil {
+		t.Fatalf("Failed to set policy: %v", err)
+	}
+	tx := testTx(0, params.GWei)
+	if err := tracker.pool.Add([]*types.Transaction{tx}, true)[0]; err != nil {
+		t.Fatalf("Failed to add transaction: %v", err)
+	}
+	tracker.Track(tx)
+
+	// Freshly submitted transactions are left alone
+	if resubmits, _ := tracker.recheck(false); len(resubmits) != 0 {
+		t.Fatalf("Unexpected resubmissions: %v", resubmits)
+	}
+	// Pending past the resubmission interval, the transaction is bumped
+	tracker.all[tx.Hash()].last = time.Now().Add(-2 * recheckInterval)
+	resubmits, _ := tracker.recheck(false)
+	if len(resubmits) != 1 || resubmits[0].GasPrice().Cmp(big.NewInt(params.GWei*12/10)) != 0 {
+		t.Fatalf("Unexpected resubmissions: %v", resubmits)
+	}
+	if err := tracker.pool.Add(resubmits, false)[0]; err != nil {
+		t.Fatalf("Failed to replace pooled transaction: %v", err)
+	}
+	tracker.pool.Sync()
+	if tracker.pool.Has(tx.Hash()) || !tracker.pool.Has(resubmits[0].Hash()) {
+		t.Fatal("Bumped transaction didn't replace the original in the pool")
+	}
+	// The replacement was just submitted, so it's left alone again
+	if resubmits, _ := tracker.recheck(false); len(resubmits) != 0 {
+		t.Fatalf("Unexpected resubmissions: %v", resubmits)
+	}
+}
+
+// Tests that the account policies are persisted along the journal.
+func TestPolicyPersistence(t *testing.T) {
+	journal := filepath.Join(t.TempDir(), "transactions.rlp")
+
+	policy := Policy{MaxAge: 600, MaxBlocks: 100, PriceBump: 15}
+	if err := newTestTracker(t, journal).SetPolicy(testAddr, policy); err != nil {
+		t.Fatalf("Failed to set policy: %v", err)
+	}
+	if have := newTestTracker(t, journal).Policy(testAddr); have != policy {
+		t.Fatalf("Policy mismatch after restart: have %+v, want %+v", have, policy)
+	}
+}
+
+// Tests that a cancellation replaces the original transaction in the pool and
+// in the tracked set.
+func TestCancel(t *testing.T) {
+	tracker := newTestTracker(t, "")
+
+	tx := testTx(0, params.GWei)
+	if err := tracker.pool.Add([]*types.Transaction{tx}, true)[0]; err != nil {
+		t.Fatalf("Failed to add transaction: %v", err)
+	}
+	tracker.Track(tx)
+
+	tracker.bump = 25
+	hash, err := tracker.Cancel(tx.Hash())
+	if err != nil {
+		t.Fatalf("Failed to cancel transaction: %v", err)
+	}
+	tracker.pool.Sync()
+
+	if tracker.pool.Has(tx.Hash()) || !tracker.pool.Has(hash) {
+		t.Fatal("Cancellation didn't replace the transaction in the pool")
+	}
+	cancel := tracker.pool.Get(hash)
+	if *cancel.To() != testAddr || cancel.Value().Sign() != 0 || cancel.Nonce() != tx.Nonce() {
+		t.Fatalf("Unexpected cancellation: to %v, value %v, nonce %d", cancel.To(), cancel.Value(), cancel.Nonce())
+	}
+	if cancel.GasPrice().Cmp(big.NewInt(params.GWei*125/100)) != 0 {
+		t.Fatalf("Cancellation price mismatch: have %v, want configured bump", cancel.GasPrice())
+	}
+	if tracked := tracker.Tracked(); len(tracked) != 1 || tracked[0].Hash != hash {
+		t.Fatalf("Unexpected tracked transactions: %+v", tracked)
+	}
+	if _, err := tracker.Cancel(tx.Hash()); err != ErrNotTracked {
+		t.Fatalf("Unexpected error cancelling untracked transaction: %v", err)
+	}
+}
+
+// Tests that the fee bumps are capped, both per bump and in number.
+func TestPriceBumpCap(t *testing.T) {
+	tracker := newTestTracker(t, "")
+
+	if err := tracker.SetPolicy(testAddr, Policy{PriceBump: maxPolicyPriceBump + 1}); !errors.Is(err, ErrPriceBumpTooHigh) {
+		t.Fatalf("Policy error mismatch: have %v, want %v", err, ErrPriceBumpTooHigh)
+	}
+	if err := tracker.SetPolicy(testAddr, Policy{PriceBump: 10}); err != nil {
+		t.Fatalf("Failed to set policy: %v", err)
+	}
+	tracker.Track(testTx(0, params.GWei))
+
+	var price *big.Int
+	for i := 0; i < maxPriceBumps+3; i++ {
+		resubmits, _ := tracker.recheck(false)
+		if len(resubmits) != 1 {
+			t.Fatalf("Unexpected resubmissions: %v", resubmits)
+		}
+		price = resubmits[0].GasPrice()
+	}
+	want := big.NewInt(params.GWei)
+	for i := 0; i < maxPriceBumps; i++ {
+		want = bumpFee(want, 10)
+	}
+	if price.Cmp(want) != 0 {
+		t.Fatalf("Gas price mismatch after capped bumps: have %v, want %v", price, want)
+	}
+	if tracked := tracker.Tracked(); tracked[0].Bumps != maxPriceBumps || tracked[0].Attempts != maxPriceBumps+3 {
+		t.Fatalf("Unexpected tracking progress: %+v", tracked[0])
+	}
+}
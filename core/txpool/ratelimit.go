@@ -0,0 +1,88 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common/lru"
+	"golang.org/x/time/rate"
+)
+
+// RateLimiter is a set of token buckets limiting the rate of transactions
+// accepted per key, e.g. per sender, per destination or per peer.
+//
+// Only the buckets of the most recently active keys are tracked, an evicted
+// bucket starts out full again if the key returns.
+type RateLimiter[K comparable] struct {
+	limit   rate.Limit
+	burst   int
+	buckets lru.BasicLRU[K, *rate.Limiter]
+	lock    sync.Mutex
+}
+
+// NewRateLimiter creates a limiter accepting the given number of transactions
+// per second and key on average, with bursts up to the given size. The buckets
+// of at most the given number of keys are tracked at once.
+//
+// If the limit is not positive, nil is returned which permits everything.
+func NewRateLimiter[K comparable](limit float64, burst int, keys int) *RateLimiter[K] {
+	if limit <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter[K]{
+		limit:   rate.Limit(limit),
+		burst:   burst,
+		buckets: lru.NewBasicLRU[K, *rate.Limiter](keys),
+	}
+}
+
+// Allow reports whether a transaction of the given key may be accepted now,
+// consuming a token from its bucket if so.
+func (l *RateLimiter[K]) Allow(key K) bool {
+	if l == nil {
+		return true
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	bucket, ok := l.buckets.Get(key)
+	if !ok {
+		bucket = rate.NewLimiter(l.limit, l.burst)
+		l.buckets.Add(key, bucket)
+	}
+	return bucket.Allow()
+}
+
+// Available reports whether a transaction of the given key may be accepted now,
+// without consuming a token from its bucket.
+func (l *RateLimiter[K]) Available(key K) bool {
+	if l == nil {
+		return true
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	bucket, ok := l.buckets.Peek(key)
+	if !ok {
+		return true // A new bucket starts out full
+	}
+	return bucket.Tokens() >= 1
+}
@@ -166,10 +166,22 @@ type SubPool interface {
 	// pending as well as queued transactions of this address, grouped by nonce.
 	ContentFrom(addr common.Address) ([]*types.Transaction, []*types.Transaction)
 
+	// Queued retrieves the hashes of at most limit queued (non-executable)
+	// transactions, in no particular order.
+	Queued(limit int) []common.Hash
+
 	// Status returns the known status (unknown/pending/queued) of a transaction
 	// identified by their hashes.
 	Status(hash common.Hash) TxStatus
 
+	// History returns the lifecycle log of a transaction identified by its hash,
+	// or nil if the subpool doesn't track it.
+	History(hash common.Hash) []*TxEvent
+
+	// SubscribeDroppedTransactions subscribes to events of transactions removed
+	// from the pool, carrying the reason of the removal.
+	SubscribeDroppedTransactions(ch chan<- DroppedTxsEvent) event.Subscription
+
 	// SetMaxGas limit max acceptable tx gas when mine is enabled
 	SetMaxGas(maxGas uint64)
 
@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"sort"
 	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -478,6 +479,51 @@ func (p *TxPool) ContentFrom(addr common.Address) ([]*types.Transaction, []*type
 	return []*types.Transaction{}, []*types.Transaction{}
 }
 
+// Queued retrieves the hashes of at most limit queued (non-executable)
+// transactions, in no particular order.
+func (p *TxPool) Queued(limit int) []common.Hash {
+	var hashes []common.Hash
+	for _, subpool := range p.subpools {
+		if len(hashes) >= limit {
+			break
+		}
+		hashes = append(hashes, subpool.Queued(limit-len(hashes))...)
+	}
+	return hashes
+}
+
+// Dump retrieves all the transactions of the pool, pending and queued ones
+// alike, grouped by account and sorted by nonce. Blob transactions are returned
+// with their sidecars, so the result can be re-added to another pool as is.
+// Conditional transactions are left out, as their conditions would be lost.
+func (p *TxPool) Dump() map[common.Address][]*types.Transaction {
+	txs := make(map[common.Address][]*types.Transaction)
+
+	run, block := p.Content()
+	for _, set := range []map[common.Address][]*types.Transaction{run, block} {
+		for addr, list := range set {
+			for _, tx := range list {
+				if tx.Options() == nil {
+					txs[addr] = append(txs[addr], tx)
+				}
+			}
+		}
+	}
+	// The subpools of blob transactions don't report their content, but all
+	// their transactions are executable
+	for addr, lazies := range p.Pending(PendingFilter{OnlyBlobTxs: true}) {
+		for _, lazy := range lazies {
+			if tx := lazy.Resolve(); tx != nil {
+				txs[addr] = append(txs[addr], tx)
+			}
+		}
+	}
+	for _, list := range txs {
+		sort.Sort(types.TxByNonce(list))
+	}
+	return txs
+}
+
 // Status returns the known status (unknown/pending/queued) of a transaction
 // identified by its hash.
 func (p *TxPool) Status(hash common.Hash) TxStatus {
@@ -489,6 +535,30 @@ func (p *TxPool) Status(hash common.Hash) TxStatus {
 	return TxStatusUnknown
 }
 
+// History returns the lifecycle log of a transaction identified by its hash,
+// or nil if no subpool tracks it.
+func (p *TxPool) History(hash common.Hash) []*TxEvent {
+	for _, subpool := range p.subpools {
+		if events := subpool.History(hash); len(events) > 0 {
+			return events
+		}
+	}
+	return nil
+}
+
+// SubscribeDroppedTransactions registers a subscription for the events of
+// transactions removed from the pool.
+func (p *TxPool) SubscribeDroppedTransactions(ch chan<- DroppedTxsEvent) event.Subscription {
+	subs := make([]event.Subscription, 0, len(p.subpools))
+	for _, subpool := range p.subpools {
+		sub := subpool.SubscribeDroppedTransactions(ch)
+		if sub != nil { // sub will be nil when subpool doesn't track drops
+			subs = append(subs, sub)
+		}
+	}
+	return p.subs.Track(event.JoinSubscriptions(subs...))
+}
+
 // Sync is a helper method for unit tests or simulator runs where the chain events
 // are arriving in quick succession, without any time in between them to run the
 // internal background reset operations. This method will run an explicit reset
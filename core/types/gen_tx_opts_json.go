@@ -11,11 +11,12 @@ import (
 // MarshalJSON marshals as JSON.
 func (t TransactionOpts) MarshalJSON() ([]byte, error) {
 	type TransactionOpts struct {
-		KnownAccounts  KnownAccounts   `json:"knownAccounts"`
-		BlockNumberMin *hexutil.Uint64 `json:"blockNumberMin,omitempty"`
-		BlockNumberMax *hexutil.Uint64 `json:"blockNumberMax,omitempty"`
-		TimestampMin   *hexutil.Uint64 `json:"timestampMin,omitempty"`
-		TimestampMax   *hexutil.Uint64 `json:"timestampMax,omitempty"`
+		KnownAccounts    KnownAccounts   `json:"knownAccounts"`
+		BlockNumberMin   *hexutil.Uint64 `json:"blockNumberMin,omitempty"`
+		BlockNumberMax   *hexutil.Uint64 `json:"blockNumberMax,omitempty"`
+		TimestampMin     *hexutil.Uint64 `json:"timestampMin,omitempty"`
+		TimestampMax     *hexutil.Uint64 `json:"timestampMax,omitempty"`
+		RevertProtection bool            `json:"revertProtection,omitempty"`
 	}
 	var enc TransactionOpts
 	enc.KnownAccounts = t.KnownAccounts
@@ -23,17 +24,19 @@ func (t TransactionOpts) MarshalJSON() ([]byte, error) {
 	enc.BlockNumberMax = t.BlockNumberMax
 	enc.TimestampMin = t.TimestampMin
 	enc.TimestampMax = t.TimestampMax
+	enc.RevertProtection = t.RevertProtection
 	return json.Marshal(&enc)
 }
 
 // UnmarshalJSON unmarshals from JSON.
 func (t *TransactionOpts) UnmarshalJSON(input []byte) error {
 	type TransactionOpts struct {
-		KnownAccounts  *KnownAccounts  `json:"knownAccounts"`
-		BlockNumberMin *hexutil.Uint64 `json:"blockNumberMin,omitempty"`
-		BlockNumberMax *hexutil.Uint64 `json:"blockNumberMax,omitempty"`
-		TimestampMin   *hexutil.Uint64 `json:"timestampMin,omitempty"`
-		TimestampMax   *hexutil.Uint64 `json:"timestampMax,omitempty"`
+		KnownAccounts    *KnownAccounts  `json:"knownAccounts"`
+		BlockNumberMin   *hexutil.Uint64 `json:"blockNumberMin,omitempty"`
+		BlockNumberMax   *hexutil.Uint64 `json:"blockNumberMax,omitempty"`
+		TimestampMin     *hexutil.Uint64 `json:"timestampMin,omitempty"`
+		TimestampMax     *hexutil.Uint64 `json:"timestampMax,omitempty"`
+		RevertProtection *bool           `json:"revertProtection,omitempty"`
 	}
 	var dec TransactionOpts
 	if err := json.Unmarshal(input, &dec); err != nil {
@@ -54,5 +57,8 @@ func (t *TransactionOpts) UnmarshalJSON(input []byte) error {
 	if dec.TimestampMax != nil {
 		t.TimestampMax = dec.TimestampMax
 	}
+	if dec.RevertProtection != nil {
+		t.RevertProtection = *dec.RevertProtection
+	}
 	return nil
 }
@@ -56,6 +56,10 @@ type Transaction struct {
 	inner TxData    // Consensus contents of a transaction
 	time  time.Time // Time first seen locally (spam avoidance)
 
+	// options are the local inclusion conditions of a transaction submitted via
+	// eth_sendRawTransactionConditional. They are not part of the encoding.
+	options *TransactionOpts
+
 	// caches
 	hash atomic.Pointer[common.Hash]
 	size atomic.Uint64
@@ -485,8 +489,9 @@ func (tx *Transaction) WithoutBlobTxSidecar() *Transaction {
 		return tx
 	}
 	cpy := &Transaction{
-		inner: blobtx.withoutSidecar(),
-		time:  tx.time,
+		inner:   blobtx.withoutSidecar(),
+		time:    tx.time,
+		options: tx.options,
 	}
 	// Note: tx.size cache not carried over because the sidecar is included in size!
 	if h := tx.hash.Load(); h != nil {
@@ -505,8 +510,9 @@ func (tx *Transaction) WithBlobTxSidecar(sideCar *BlobTxSidecar) *Transaction {
 		return tx
 	}
 	cpy := &Transaction{
-		inner: blobtx.withSidecar(sideCar),
-		time:  tx.time,
+		inner:   blobtx.withSidecar(sideCar),
+		time:    tx.time,
+		options: tx.options,
 	}
 	// Note: tx.size cache not carried over because the sidecar is included in size!
 	if h := tx.hash.Load(); h != nil {
@@ -557,6 +563,32 @@ func (tx *Transaction) SetTime(t time.Time) {
 	tx.time = t
 }
 
+// WithOptions returns a copy of the transaction carrying the given inclusion
+// conditions, to be checked by the pool and the miner.
+func (tx *Transaction) WithOptions(opts *TransactionOpts) *Transaction {
+	cpy := &Transaction{
+		inner:   tx.inner,
+		time:    tx.time,
+		options: opts,
+	}
+	if h := tx.hash.Load(); h != nil {
+		cpy.hash.Store(h)
+	}
+	if size := tx.size.Load(); size > 0 {
+		cpy.size.Store(size)
+	}
+	if f := tx.from.Load(); f != nil {
+		cpy.from.Store(f)
+	}
+	return cpy
+}
+
+// Options returns the inclusion conditions of the transaction, or nil if it's
+// unconditional.
+func (tx *Transaction) Options() *TransactionOpts {
+	return tx.options
+}
+
 // Time returns the time when the transaction was first seen on the network. It
 // is a heuristic to prefer mining older txs vs new all other things equal.
 func (tx *Transaction) Time() time.Time {
@@ -1,7 +1,9 @@
 package types
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -40,4 +42,71 @@ type TransactionOpts struct {
 	BlockNumberMax *hexutil.Uint64 `json:"blockNumberMax,omitempty"`
 	TimestampMin   *hexutil.Uint64 `json:"timestampMin,omitempty"`
 	TimestampMax   *hexutil.Uint64 `json:"timestampMax,omitempty"`
+
+	// RevertProtection requests the transaction to be left out of the block if
+	// its execution reverts, instead of being included as failed.
+	RevertProtection bool `json:"revertProtection,omitempty"`
+}
+
+var (
+	ErrBlockNumberMin = errors.New("BlockNumberMin condition not met")
+	ErrBlockNumberMax = errors.New("BlockNumberMax condition not met")
+	ErrTimestampMin   = errors.New("TimestampMin condition not met")
+	ErrTimestampMax   = errors.New("TimestampMax condition not met")
+	ErrStorageRoot    = errors.New("storage root hash condition not met")
+	ErrStorageSlot    = errors.New("storage slot value condition not met")
+)
+
+// KnownAccountsState is the state access needed to check the known accounts
+// condition of a transaction.
+type KnownAccountsState interface {
+	GetRoot(addr common.Address) common.Hash
+	GetState(addr common.Address, slot common.Hash) common.Hash
+}
+
+// CheckBlock checks whether a block with the given number and timestamp meets
+// the block and timestamp bounds.
+func (o *TransactionOpts) CheckBlock(number uint64, time uint64) error {
+	if o.BlockNumberMin != nil && number < uint64(*o.BlockNumberMin) {
+		return ErrBlockNumberMin
+	}
+	if o.BlockNumberMax != nil && number > uint64(*o.BlockNumberMax) {
+		return ErrBlockNumberMax
+	}
+	if o.TimestampMin != nil && time < uint64(*o.TimestampMin) {
+		return ErrTimestampMin
+	}
+	if o.TimestampMax != nil && time > uint64(*o.TimestampMax) {
+		return ErrTimestampMax
+	}
+	return nil
+}
+
+// CheckStorage checks whether the given state meets the known accounts condition.
+func (o *TransactionOpts) CheckStorage(state KnownAccountsState) error {
+	for address, accountStorage := range o.KnownAccounts {
+		if accountStorage.StorageRoot != nil {
+			if state.GetRoot(address) != *accountStorage.StorageRoot {
+				return ErrStorageRoot
+			}
+		} else if len(accountStorage.StorageSlots) > 0 {
+			for slot, value := range accountStorage.StorageSlots {
+				stored := state.GetState(address, slot)
+				if !bytes.Equal(stored.Bytes(), value.Bytes()) {
+					return ErrStorageSlot
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Expired reports whether no block following the one with the given number and
+// timestamp can meet the block and timestamp upper bounds anymore. Note, blocks
+// may share a timestamp in seconds, so only a passed maximum timestamp counts.
+func (o *TransactionOpts) Expired(number uint64, time uint64) bool {
+	if o.BlockNumberMax != nil && number >= uint64(*o.BlockNumberMax) {
+		return true
+	}
+	return o.TimestampMax != nil && time > uint64(*o.TimestampMax)
 }
@@ -100,6 +100,14 @@ func TestTransactionOptsJSONUnMarshalTrip(t *testing.T) {
 				TimestampMax: u64Ptr(0xffffff),
 			},
 		},
+		{
+			"RevertProtection",
+			`{"revertProtection":true}`,
+			false,
+			TransactionOpts{
+				RevertProtection: true,
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -119,3 +127,34 @@ func TestTransactionOptsJSONUnMarshalTrip(t *testing.T) {
 		})
 	}
 }
+
+func TestTransactionOptsBounds(t *testing.T) {
+	opts := TransactionOpts{
+		BlockNumberMin: u64Ptr(10),
+		BlockNumberMax: u64Ptr(20),
+		TimestampMin:   u64Ptr(100),
+		TimestampMax:   u64Ptr(200),
+	}
+	tests := []struct {
+		number, time uint64
+		err          error
+		expired      bool
+	}{
+		{9, 150, ErrBlockNumberMin, false},
+		{10, 150, nil, false},
+		{19, 150, nil, false},
+		{20, 150, nil, true},
+		{21, 150, ErrBlockNumberMax, true},
+		{15, 99, ErrTimestampMin, false},
+		{15, 200, nil, false},
+		{15, 201, ErrTimestampMax, true},
+	}
+	for i, test := range tests {
+		if err := opts.CheckBlock(test.number, test.time); err != test.err {
+			t.Errorf("test %d: check error mismatch: have %v, want %v", i, err, test.err)
+		}
+		if expired := opts.Expired(test.number, test.time); expired != test.expired {
+			t.Errorf("test %d: expiry mismatch: have %v, want %v", i, expired, test.expired)
+		}
+	}
+}
@@ -63,8 +63,9 @@ type VotePool struct {
 	chain *core.BlockChain
 	mu    sync.RWMutex
 
-	votesFeed event.Feed
-	scope     event.SubscriptionScope
+	votesFeed        event.Feed
+	invalidVotesFeed event.Feed
+	scope            event.SubscriptionScope
 
 	receivedVotes mapset.Set[common.Hash]
 
@@ -170,6 +171,7 @@ func (pool *VotePool) putIntoVotePool(vote *types.VoteEnvelope) bool {
 	if !isFutureVote {
 		// Verify if the vote comes from valid validators based on voteAddress (BLSPublicKey), only verify curVotes here, will verify futureVotes in transfer process.
 		if pool.engine.VerifyVote(pool.chain, vote) != nil {
+			pool.invalidVotesFeed.Send(core.InvalidVoteEvent{Vote: vote})
 			return false
 		}
 
@@ -187,6 +189,12 @@ func (pool *VotePool) SubscribeNewVoteEvent(ch chan<- core.NewVoteEvent) event.S
 	return pool.scope.Track(pool.votesFeed.Subscribe(ch))
 }
 
+// SubscribeInvalidVoteEvent registers a subscription for the votes rejected by
+// the pool, so that the network layer can penalize their senders.
+func (pool *VotePool) SubscribeInvalidVoteEvent(ch chan<- core.InvalidVoteEvent) event.Subscription {
+	return pool.scope.Track(pool.invalidVotesFeed.Subscribe(ch))
+}
+
 func (pool *VotePool) putVote(m map[common.Hash]*VoteBox, votesPq *votesPriorityQueue, vote *types.VoteEnvelope, voteData *types.VoteData, voteHash common.Hash, isFutureVote bool) {
 	targetHash := vote.Data.TargetHash
 	targetNumber := vote.Data.TargetNumber
@@ -276,6 +284,7 @@ func (pool *VotePool) transfer(blockHash common.Hash) {
 		// Verify if the vote comes from valid validators based on voteAddress (BLSPublicKey).
 		if pool.engine.VerifyVote(pool.chain, vote) != nil {
 			pool.receivedVotes.Remove(vote.Hash())
+			pool.invalidVotesFeed.Send(core.InvalidVoteEvent{Vote: vote})
 			continue
 		}
 
@@ -380,6 +389,7 @@ func (pool *VotePool) basicVerify(vote *types.VoteEnvelope, headNumber uint64, m
 	// Verify bls signature.
 	if err := vote.Verify(); err != nil {
 		log.Error("Failed to verify voteMessage", "err", err)
+		pool.invalidVotesFeed.Send(core.InvalidVoteEvent{Vote: vote, BadSignature: true})
 		return false
 	}
 
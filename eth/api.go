@@ -17,7 +17,12 @@
 package eth
 
 import (
+	"context"
+	"math/big"
+
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/miner"
 )
 
 // EthereumAPI provides an API to access Ethereum full node-related information.
@@ -44,3 +49,56 @@ func (api *EthereumAPI) Coinbase() (common.Address, error) {
 func (api *EthereumAPI) Mining() bool {
 	return api.e.IsMining()
 }
+
+// GetPendingBlockPreview returns the transaction ordering the local miner would
+// currently produce for the next block, with the simulated gas used of each
+// transaction and the pending transactions left out along with the reason.
+func (api *EthereumAPI) GetPendingBlockPreview() (*miner.PendingBlockPreview, error) {
+	return api.e.Miner().PendingBlockPreview()
+}
+
+// GasPriceEstimate is a suggested tip to get a transaction included within a
+// number of blocks.
+type GasPriceEstimate struct {
+	Blocks               hexutil.Uint64 `json:"blocks"`
+	MaxPriorityFeePerGas *hexutil.Big   `json:"maxPriorityFeePerGas"`
+	GasPrice             *hexutil.Big   `json:"gasPrice"`    // Tip plus the next base fee, for legacy transactions
+	Probability          float64        `json:"probability"` // Modelled probability of inclusion
+}
+
+// GasPriceEstimates are the suggested tips to get a transaction included in the
+// next block, within 3 blocks and within 10 blocks.
+type GasPriceEstimates struct {
+	BaseFee   *hexutil.Big        `json:"baseFeePerGas,omitempty"`
+	Estimates []*GasPriceEstimate `json:"estimates"`
+}
+
+// GasPriceEstimates returns the tips to pay to get a transaction included in the
+// next block, within 3 blocks and within 10 blocks, modelled after the lowest
+// tips included in the recent blocks and the depth of the transaction pool at
+// each price level. Unlike GasPrice, the estimates aren't stuck at the price
+// floor of the validators as long as the blocks fill up.
+func (api *EthereumAPI) GasPriceEstimates(ctx context.Context) (*GasPriceEstimates, error) {
+	pending, _ := api.e.APIBackend.TxPoolContent()
+	tips, err := api.e.APIBackend.gpo.EstimateTips(ctx, pending)
+	if err != nil {
+		return nil, err
+	}
+	result := &GasPriceEstimates{
+		BaseFee:   (*hexutil.Big)(tips.BaseFee),
+		Estimates: make([]*GasPriceEstimate, len(tips.Estimates)),
+	}
+	for i, tip := range tips.Estimates {
+		price := new(big.Int).Set(tip.TipCap)
+		if tips.BaseFee != nil {
+			price.Add(price, tips.BaseFee)
+		}
+		result.Estimates[i] = &GasPriceEstimate{
+			Blocks:               hexutil.Uint64(tip.Blocks),
+			MaxPriorityFeePerGas: (*hexutil.Big)(tip.TipCap),
+			GasPrice:             (*hexutil.Big)(price),
+			Probability:          tip.Probability,
+		}
+	}
+	return result, nil
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/parlia"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/bloombits"
+	"github.com/ethereum/go-ethereum/core/history"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/txpool"
@@ -94,7 +95,10 @@ func (b *EthAPIBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumb
 		}
 		return block, nil
 	}
-	return b.eth.blockchain.GetHeaderByNumber(uint64(number)), nil
+	if header := b.eth.blockchain.GetHeaderByNumber(uint64(number)); header != nil {
+		return header, nil
+	}
+	return nil, b.prunedError(uint64(number))
 }
 
 func (b *EthAPIBackend) HeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*types.Header, error) {
@@ -104,6 +108,9 @@ func (b *EthAPIBackend) HeaderByNumberOrHash(ctx context.Context, blockNrOrHash
 	if hash, ok := blockNrOrHash.Hash(); ok {
 		header := b.eth.blockchain.GetHeaderByHash(hash)
 		if header == nil {
+			if err := b.prunedHashError(hash); err != nil {
+				return nil, err
+			}
 			return nil, errors.New("header for hash not found")
 		}
 		if blockNrOrHash.RequireCanonical && b.eth.blockchain.GetCanonicalHash(header.Number.Uint64()) != hash {
@@ -115,7 +122,10 @@ func (b *EthAPIBackend) HeaderByNumberOrHash(ctx context.Context, blockNrOrHash
 }
 
 func (b *EthAPIBackend) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
-	return b.eth.blockchain.GetHeaderByHash(hash), nil
+	if header := b.eth.blockchain.GetHeaderByHash(hash); header != nil {
+		return header, nil
+	}
+	return nil, b.prunedHashError(hash)
 }
 
 func (b *EthAPIBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Block, error) {
@@ -146,11 +156,34 @@ func (b *EthAPIBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumbe
 		}
 		return b.eth.blockchain.GetBlock(header.Hash(), header.Number.Uint64()), nil
 	}
-	return b.eth.blockchain.GetBlockByNumber(uint64(number)), nil
+	if block := b.eth.blockchain.GetBlockByNumber(uint64(number)); block != nil {
+		return block, nil
+	}
+	return nil, b.prunedError(uint64(number))
 }
 
 func (b *EthAPIBackend) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
-	return b.eth.blockchain.GetBlockByHash(hash), nil
+	if block := b.eth.blockchain.GetBlockByHash(hash); block != nil {
+		return block, nil
+	}
+	return nil, b.prunedHashError(hash)
+}
+
+// prunedError returns the pruned history error if the block with the given
+// number is missing because the block history was pruned, nil otherwise.
+func (b *EthAPIBackend) prunedError(number uint64) error {
+	if number > 0 && number < b.eth.blockchain.HistoryPruningCutoff() {
+		return &history.PrunedHistoryError{}
+	}
+	return nil
+}
+
+// prunedHashError is like prunedError, for blocks looked up by hash.
+func (b *EthAPIBackend) prunedHashError(hash common.Hash) error {
+	if number := b.eth.blockchain.GetBlockNumber(hash); number != nil {
+		return b.prunedError(*number)
+	}
+	return nil
 }
 
 // GetBody returns body of a block. It does not resolve special block numbers.
@@ -161,6 +194,9 @@ func (b *EthAPIBackend) GetBody(ctx context.Context, hash common.Hash, number rp
 	if body := b.eth.blockchain.GetBody(hash); body != nil {
 		return body, nil
 	}
+	if err := b.prunedError(uint64(number)); err != nil {
+		return nil, err
+	}
 	return nil, errors.New("block body not found")
 }
 
@@ -171,6 +207,9 @@ func (b *EthAPIBackend) BlockByNumberOrHash(ctx context.Context, blockNrOrHash r
 	if hash, ok := blockNrOrHash.Hash(); ok {
 		header := b.eth.blockchain.GetHeaderByHash(hash)
 		if header == nil {
+			if err := b.prunedHashError(hash); err != nil {
+				return nil, err
+			}
 			return nil, errors.New("header for hash not found")
 		}
 		if blockNrOrHash.RequireCanonical && b.eth.blockchain.GetCanonicalHash(header.Number.Uint64()) != hash {
@@ -208,7 +247,10 @@ func (b *EthAPIBackend) StateAndHeaderByNumber(ctx context.Context, number rpc.B
 	}
 	stateDb, err := b.eth.BlockChain().StateAt(header.Root)
 	if err != nil {
-		return nil, nil, err
+		stateDb, err = b.eth.BlockChain().HistoricState(ctx, header.Root)
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 	return stateDb, header, nil
 }
@@ -230,7 +272,10 @@ func (b *EthAPIBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockN
 		}
 		stateDb, err := b.eth.BlockChain().StateAt(header.Root)
 		if err != nil {
-			return nil, nil, err
+			stateDb, err = b.eth.BlockChain().HistoricState(ctx, header.Root)
+			if err != nil {
+				return nil, nil, err
+			}
 		}
 		return stateDb, header, nil
 	}
@@ -238,7 +283,10 @@ func (b *EthAPIBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockN
 }
 
 func (b *EthAPIBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
-	return b.eth.blockchain.GetReceiptsByHash(hash), nil
+	if receipts := b.eth.blockchain.GetReceiptsByHash(hash); receipts != nil {
+		return receipts, nil
+	}
+	return nil, b.prunedHashError(hash)
 }
 
 func (b *EthAPIBackend) GetBlobSidecars(ctx context.Context, hash common.Hash) (types.BlobSidecars, error) {
@@ -288,8 +336,16 @@ func (b *EthAPIBackend) SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscri
 	return b.eth.BlockChain().SubscribeLogsEvent(ch)
 }
 
+// SendTx adds a locally submitted transaction to the pool and tracks it for
+// resubmission. Conditional transactions are never propagated to peers, as
+// their conditions are not part of the wire encoding, so they are only accepted
+// by mining nodes. They are not tracked either, since the journal would lose
+// their conditions and resubmit them unconditionally.
 func (b *EthAPIBackend) SendTx(ctx context.Context, signedTx *types.Transaction) error {
-	if locals := b.eth.localTxTracker; locals != nil {
+	if signedTx.Options() != nil && !b.eth.IsMining() {
+		return errors.New("conditional transactions are only accepted by mining nodes")
+	}
+	if locals := b.eth.localTxTracker; locals != nil && signedTx.Options() == nil {
 		locals.Track(signedTx)
 	}
 	return b.eth.txPool.Add([]*types.Transaction{signedTx}, false)[0]
@@ -357,6 +413,14 @@ func (b *EthAPIBackend) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.S
 	return b.eth.txPool.SubscribeTransactions(ch, true)
 }
 
+func (b *EthAPIBackend) TxPoolHistory(hash common.Hash) []*txpool.TxEvent {
+	return b.eth.txPool.History(hash)
+}
+
+func (b *EthAPIBackend) SubscribeDroppedTxsEvent(ch chan<- txpool.DroppedTxsEvent) event.Subscription {
+	return b.eth.txPool.SubscribeDroppedTransactions(ch)
+}
+
 func (b *EthAPIBackend) SubscribeNewVoteEvent(ch chan<- core.NewVoteEvent) event.Subscription {
 	if b.eth.VotePool() == nil {
 		return nil
@@ -24,6 +24,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/history"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -446,3 +447,250 @@ func (api *DebugAPI) GetTrieFlushInterval() (string, error) {
 	}
 	return api.eth.blockchain.GetTrieFlushInterval().String(), nil
 }
+
+// PrunedBlockRanges returns the ranges of blocks whose headers, bodies and
+// receipts were pruned from the local database.
+func (api *DebugAPI) PrunedBlockRanges() []history.Range {
+	return history.PrunedRanges(api.eth.blockchain.HistoryPruningCutoff())
+}
+
+// maxStateHistoryRange is the maximum number of blocks that can be queried for
+// the account or storage history at once.
+const maxStateHistoryRange = 8192
+
+// HistoryAccount represents an account value in the state history.
+type HistoryAccount struct {
+	Nonce       hexutil.Uint64 `json:"nonce"`
+	Balance     *hexutil.Big   `json:"balance"`
+	CodeHash    common.Hash    `json:"codeHash"`
+	StorageRoot common.Hash    `json:"storageRoot"`
+}
+
+// AccountChange represents the mutation of an account within a block. The
+// account value is null if the account is not existent.
+type AccountChange struct {
+	BlockNumber hexutil.Uint64  `json:"blockNumber"`
+	From        *HistoryAccount `json:"from"`
+	To          *HistoryAccount `json:"to"`
+}
+
+// AccountHistoryResult is the result of a debug_getAccountHistory API call.
+//
+// If the value after the last change can't be resolved within the query
+// limits, its "to" field is null and the result is marked as incomplete.
+type AccountHistoryResult struct {
+	Address   common.Address  `json:"address"`
+	FromBlock hexutil.Uint64  `json:"fromBlock"`
+	ToBlock   hexutil.Uint64  `json:"toBlock"`
+	Changes   []AccountChange `json:"changes"`
+	Complete  bool            `json:"complete"`
+}
+
+// StorageChange represents the mutation of a storage slot within a block.
+type StorageChange struct {
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	From        common.Hash    `json:"from"`
+	To          *common.Hash   `json:"to"`
+}
+
+// StorageHistoryResult is the result of a debug_getStorageHistory API call.
+//
+// If the value after the last change can't be resolved within the query
+// limits, its "to" field is null and the result is marked as incomplete.
+type StorageHistoryResult struct {
+	Address   common.Address  `json:"address"`
+	Slot      common.Hash     `json:"slot"`
+	FromBlock hexutil.Uint64  `json:"fromBlock"`
+	ToBlock   hexutil.Uint64  `json:"toBlock"`
+	Changes   []StorageChange `json:"changes"`
+	Complete  bool            `json:"complete"`
+}
+
+// GetAccountHistory returns the changes of the given account within the block
+// range [from, to], along with the account value before and after each change.
+//
+// The changes are resolved from the state histories maintained by the path-based
+// state scheme, the range must be covered by the local histories. Note the most
+// recent blocks whose states are still kept in memory are not covered yet, the
+// returned toBlock reports the end of the range actually inspected.
+func (api *DebugAPI) GetAccountHistory(ctx context.Context, address common.Address, from, to rpc.BlockNumber) (*AccountHistoryResult, error) {
+	start, end, first, last, err := api.stateHistoryRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+	result := &AccountHistoryResult{
+		Address:   address,
+		FromBlock: hexutil.Uint64(start),
+		ToBlock:   hexutil.Uint64(end),
+		Changes:   []AccountChange{},
+		Complete:  true,
+	}
+	if first > last {
+		return result, nil
+	}
+	changes, err := api.eth.blockchain.TrieDB().AccountChanges(address, first, last, maxStateHistoryRange)
+	if err != nil {
+		return nil, err
+	}
+	for i, number := range changes.Blocks {
+		prev, err := decodeHistoryAccount(changes.Origins[i])
+		if err != nil {
+			return nil, err
+		}
+		change := AccountChange{
+			BlockNumber: hexutil.Uint64(number),
+			From:        prev,
+		}
+		if i < len(changes.Blocks)-1 || changes.Resolved {
+			post, err := decodeHistoryAccount(changes.Values[i])
+			if err != nil {
+				return nil, err
+			}
+			change.To = post
+		}
+		result.Changes = append(result.Changes, change)
+	}
+	result.Complete = changes.Resolved
+	return result, nil
+}
+
+// GetStorageHistory returns the changes of the given storage slot within the
+// block range [from, to], along with the slot value before and after each change.
+// The slot refers to the raw storage key, not its hash.
+//
+// The range is resolved in the same way as debug_getAccountHistory.
+func (api *DebugAPI) GetStorageHistory(ctx context.Context, address common.Address, slot common.Hash, from, to rpc.BlockNumber) (*StorageHistoryResult, error) {
+	start, end, first, last, err := api.stateHistoryRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+	result := &StorageHistoryResult{
+		Address:   address,
+		Slot:      slot,
+		FromBlock: hexutil.Uint64(start),
+		ToBlock:   hexutil.Uint64(end),
+		Changes:   []StorageChange{},
+		Complete:  true,
+	}
+	if first > last {
+		return result, nil
+	}
+	changes, err := api.eth.blockchain.TrieDB().StorageChanges(address, slot, first, last, maxStateHistoryRange)
+	if err != nil {
+		return nil, err
+	}
+	for i, number := range changes.Blocks {
+		prev, err := decodeHistorySlot(changes.Origins[i])
+		if err != nil {
+			return nil, err
+		}
+		change := StorageChange{
+			BlockNumber: hexutil.Uint64(number),
+			From:        prev,
+		}
+		if i < len(changes.Blocks)-1 || changes.Resolved {
+			post, err := decodeHistorySlot(changes.Values[i])
+			if err != nil {
+				return nil, err
+			}
+			change.To = &post
+		}
+		result.Changes = append(result.Changes, change)
+	}
+	result.Complete = changes.Resolved
+	return result, nil
+}
+
+// stateHistoryRange resolves the given block range to the range of state
+// histories covering it. The block range is returned as well, with the end
+// capped to the latest state history.
+func (api *DebugAPI) stateHistoryRange(from, to rpc.BlockNumber) (uint64, uint64, uint64, uint64, error) {
+	triedb := api.eth.blockchain.TrieDB()
+	if triedb.Scheme() != rawdb.PathScheme {
+		return 0, 0, 0, 0, errors.New("state history is only available in path-based scheme")
+	}
+	start, err := api.resolveBlockNumber(from)
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+	end, err := api.resolveBlockNumber(to)
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+	if start > end {
+		return 0, 0, 0, 0, fmt.Errorf("invalid block range, from: %d, to: %d", start, end)
+	}
+	if end-start >= maxStateHistoryRange {
+		return 0, 0, 0, 0, fmt.Errorf("block range too large, max: %d, got: %d", maxStateHistoryRange, end-start+1)
+	}
+	oldest, latest, err := triedb.ChangesRange()
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+	if start < oldest || start > latest {
+		return 0, 0, 0, 0, fmt.Errorf("state history of block %d is not available, range: [%d-%d]", start, oldest, latest)
+	}
+	end = min(end, latest)
+
+	first, err := triedb.HistoryIndex(start)
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+	next, err := triedb.HistoryIndex(end + 1)
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+	return start, end, first, next - 1, nil
+}
+
+// resolveBlockNumber resolves the given block number tag to the number of a
+// local block.
+func (api *DebugAPI) resolveBlockNumber(number rpc.BlockNumber) (uint64, error) {
+	if number >= 0 {
+		return uint64(number), nil
+	}
+	var header *types.Header
+	switch number {
+	case rpc.SafeBlockNumber:
+		header = api.eth.blockchain.CurrentSafeBlock()
+	case rpc.FinalizedBlockNumber:
+		header = api.eth.blockchain.CurrentFinalBlock()
+	default:
+		header = api.eth.blockchain.CurrentBlock()
+	}
+	if header == nil {
+		return 0, fmt.Errorf("block %s not found", number)
+	}
+	return header.Number.Uint64(), nil
+}
+
+// decodeHistoryAccount decodes the account value in slim format from the state
+// history, nil is returned if the account is not existent.
+func decodeHistoryAccount(blob []byte) (*HistoryAccount, error) {
+	if len(blob) == 0 {
+		return nil, nil
+	}
+	account, err := types.FullAccount(blob)
+	if err != nil {
+		return nil, err
+	}
+	return &HistoryAccount{
+		Nonce:       hexutil.Uint64(account.Nonce),
+		Balance:     (*hexutil.Big)(account.Balance.ToBig()),
+		CodeHash:    common.BytesToHash(account.CodeHash),
+		StorageRoot: account.Root,
+	}, nil
+}
+
+// decodeHistorySlot decodes the RLP-encoded storage slot value from the state
+// history, the empty value is returned if the slot is not existent.
+func decodeHistorySlot(blob []byte) (common.Hash, error) {
+	if len(blob) == 0 {
+		return common.Hash{}, nil
+	}
+	_, content, _, err := rlp.Split(blob)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(content), nil
+}
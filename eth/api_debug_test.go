@@ -18,7 +18,9 @@ package eth
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"math/big"
 	"reflect"
 	"slices"
 	"strings"
@@ -26,11 +28,16 @@ import (
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/tracing"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/ethereum/go-ethereum/triedb"
 	"github.com/holiman/uint256"
 )
@@ -224,3 +231,114 @@ func TestStorageRangeAt(t *testing.T) {
 		}
 	}
 }
+
+func TestStateHistory(t *testing.T) {
+	t.Parallel()
+
+	var (
+		key, _   = crypto.GenerateKey()
+		sender   = crypto.PubkeyToAddress(key.PublicKey)
+		receiver = common.Address{0x01}
+		contract = common.Address{0x02}
+		gspec    = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc: types.GenesisAlloc{
+				sender: {Balance: big.NewInt(params.Ether)},
+				// Stores the first word of the call data into the slot 0
+				contract: {Balance: common.Big0, Code: []byte{0x60, 0x00, 0x35, 0x60, 0x00, 0x55, 0x00}},
+			},
+		}
+		signer = types.LatestSigner(gspec.Config)
+		engine = ethash.NewFaker()
+	)
+	// Transfer to the receiver every 10 blocks and update the contract storage
+	// every 25 blocks.
+	_, blocks, _ := core.GenerateChainWithGenesis(gspec, engine, 200, func(i int, b *core.BlockGen) {
+		number := b.Number().Uint64()
+		if number%10 == 0 {
+			b.AddTx(types.MustSignNewTx(key, signer, &types.LegacyTx{
+				Nonce:    b.TxNonce(sender),
+				To:       &receiver,
+				Value:    big.NewInt(1),
+				Gas:      params.TxGas,
+				GasPrice: b.BaseFee(),
+			}))
+		}
+		if number%25 == 0 {
+			b.AddTx(types.MustSignNewTx(key, signer, &types.LegacyTx{
+				Nonce:    b.TxNonce(sender),
+				To:       &contract,
+				Gas:      100000,
+				GasPrice: b.BaseFee(),
+				Data:     common.BigToHash(b.Number()).Bytes(),
+			}))
+		}
+	})
+	db, err := rawdb.NewDatabaseWithFreezer(rawdb.NewMemoryDatabase(), "", "", false, false, false, false, false)
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	chain, err := core.NewBlockChain(db, core.DefaultCacheConfigWithScheme(rawdb.PathScheme), gspec, nil, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("Failed to insert block %d: %v", n, err)
+	}
+	api := NewDebugAPI(&Ethereum{blockchain: chain})
+
+	// The states of the most recent blocks are kept in memory, the range is
+	// capped to the latest state history.
+	_, latest, err := chain.TrieDB().ChangesRange()
+	if err != nil {
+		t.Fatalf("Failed to retrieve history range: %v", err)
+	}
+	accounts, err := api.GetAccountHistory(context.Background(), receiver, 1, rpc.LatestBlockNumber)
+	if err != nil {
+		t.Fatalf("Failed to retrieve account history: %v", err)
+	}
+	if uint64(accounts.ToBlock) != latest || !accounts.Complete {
+		t.Fatalf("Unexpected account history range, to: %d, complete: %v", accounts.ToBlock, accounts.Complete)
+	}
+	if len(accounts.Changes) != int(latest/10) {
+		t.Fatalf("Unexpected account changes, want %d, got %d", latest/10, len(accounts.Changes))
+	}
+	for i, change := range accounts.Changes {
+		if uint64(change.BlockNumber) != uint64(i+1)*10 {
+			t.Fatalf("Unexpected block of change %d: %d", i, change.BlockNumber)
+		}
+		if (i == 0) != (change.From == nil) {
+			t.Fatalf("Unexpected origin of change %d: %v", i, change.From)
+		}
+		if change.From != nil && change.From.Balance.ToInt().Int64() != int64(i) {
+			t.Fatalf("Unexpected origin balance of change %d: %v", i, change.From.Balance)
+		}
+		if change.To == nil || change.To.Balance.ToInt().Int64() != int64(i+1) {
+			t.Fatalf("Unexpected balance of change %d: %v", i, change.To)
+		}
+	}
+	// The storage changes within the range are resolved from the next change
+	// out of the range.
+	storages, err := api.GetStorageHistory(context.Background(), contract, common.Hash{}, 30, 60)
+	if err != nil {
+		t.Fatalf("Failed to retrieve storage history: %v", err)
+	}
+	post := common.BigToHash(big.NewInt(50))
+	want := []StorageChange{{
+		BlockNumber: 50,
+		From:        common.BigToHash(big.NewInt(25)),
+		To:          &post,
+	}}
+	if !reflect.DeepEqual(storages.Changes, want) || !storages.Complete {
+		t.Fatalf("Unexpected storage changes: %s", dumper.Sdump(storages))
+	}
+	// Ranges out of the local state histories are rejected.
+	if _, err := api.GetAccountHistory(context.Background(), receiver, rpc.BlockNumber(latest+1), rpc.LatestBlockNumber); err == nil {
+		t.Fatal("Expected error for unavailable range")
+	}
+	if _, err := api.GetAccountHistory(context.Background(), receiver, 0, maxStateHistoryRange); err == nil {
+		t.Fatal("Expected error for oversized range")
+	}
+}
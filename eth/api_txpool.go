@@ -0,0 +1,241 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/txpool/blobpool"
+	"github.com/ethereum/go-ethereum/core/txpool/locals"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// TxPoolDumpAPI offers the export and import of the whole transaction pool, to
+// move its contents between nodes, e.g. during a planned failover.
+type TxPoolDumpAPI struct {
+	eth *Ethereum
+}
+
+// NewTxPoolDumpAPI creates a new instance of TxPoolDumpAPI.
+func NewTxPoolDumpAPI(eth *Ethereum) *TxPoolDumpAPI {
+	return &TxPoolDumpAPI{eth: eth}
+}
+
+// TxPoolImportResult is the outcome of importing a transaction pool dump.
+type TxPoolImportResult struct {
+	Imported int                    `json:"imported"`         // Transactions added to the pool
+	Known    int                    `json:"known"`            // Transactions already in the pool
+	Errors   map[common.Hash]string `json:"errors,omitempty"` // Transactions rejected by the pool
+}
+
+// maxTxPoolPageSize is the soft limit of the size of the transactions exported
+// in a single page. The transactions are hex encoded in the response, so a page
+// can be imported again without exceeding the size limit of HTTP requests.
+var maxTxPoolPageSize = 1024 * 1024
+
+// TxPoolCursor is the position of a transaction pool export, the transactions
+// of the account with a lower nonce, and the ones of lower accounts, have been
+// exported already.
+type TxPoolCursor struct {
+	Account common.Address `json:"account"`
+	Nonce   hexutil.Uint64 `json:"nonce"`
+}
+
+// TxPoolExportPage is a page of a transaction pool export.
+type TxPoolExportPage struct {
+	Transactions hexutil.Bytes `json:"transactions"`   // Stream of RLP encoded transactions
+	Count        int           `json:"count"`          // Number of transactions in the page
+	Next         *TxPoolCursor `json:"next,omitempty"` // Position of the next page, omitted if the export is finished
+}
+
+// ExportAll returns a page of the pending and queued transactions of the pool
+// as a stream of RLP encoded transactions, with the blob transactions including
+// their sidecars. The transactions are ordered by account and nonce, the export
+// starts at the given position, or at the beginning if omitted, and is resumed
+// by passing the returned position of the next page.
+func (api *TxPoolDumpAPI) ExportAll(cursor *TxPoolCursor) (*TxPoolExportPage, error) {
+	var (
+		dump  = api.eth.txPool.Dump()
+		addrs = make([]common.Address, 0, len(dump))
+	)
+	for addr := range dump {
+		if cursor == nil || addr.Cmp(cursor.Account) >= 0 {
+			addrs = append(addrs, addr)
+		}
+	}
+	slices.SortFunc(addrs, common.Address.Cmp)
+
+	var (
+		buf  bytes.Buffer
+		page = new(TxPoolExportPage)
+	)
+	for _, addr := range addrs {
+		for _, tx := range dump[addr] {
+			if cursor != nil && addr == cursor.Account && tx.Nonce() < uint64(cursor.Nonce) {
+				continue
+			}
+			blob, err := rlp.EncodeToBytes(tx)
+			if err != nil {
+				return nil, err
+			}
+			// Always export at least one transaction, so that the export
+			// progresses even if a single one exceeds the limit.
+			if page.Count > 0 && buf.Len()+len(blob) > maxTxPoolPageSize {
+				page.Next = &TxPoolCursor{Account: addr, Nonce: hexutil.Uint64(tx.Nonce())}
+				page.Transactions = buf.Bytes()
+				return page, nil
+			}
+			buf.Write(blob)
+			page.Count++
+		}
+	}
+	page.Transactions = buf.Bytes()
+	return page, nil
+}
+
+// ImportAll adds the transactions of an RLP stream, e.g. a page produced by
+// ExportAll, to the pool. The transactions are validated as any other submitted
+// ones, the ones rejected are reported along with the reason.
+func (api *TxPoolDumpAPI) ImportAll(dump hexutil.Bytes) (*TxPoolImportResult, error) {
+	var (
+		stream = rlp.NewStream(bytes.NewReader(dump), 0)
+		txs    []*types.Transaction
+	)
+	for {
+		tx := new(types.Transaction)
+		if err := stream.Decode(tx); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("transaction %d: failed to parse: %v", len(txs), err)
+		}
+		txs = append(txs, tx)
+	}
+	result := &TxPoolImportResult{Errors: make(map[common.Hash]string)}
+	for i, err := range api.eth.txPool.Add(txs, false) {
+		switch {
+		case err == nil:
+			result.Imported++
+		case errors.Is(err, txpool.ErrAlreadyKnown):
+			result.Known++
+		default:
+			result.Errors[txs[i].Hash()] = err.Error()
+		}
+	}
+	log.Debug("Imported transaction pool page", "imported", result.Imported, "known", result.Known, "rejected", len(result.Errors))
+	return result, nil
+}
+
+// BlobPoolAPI offers an insight into the storage usage and the eviction order
+// of the blob pool.
+type BlobPoolAPI struct {
+	eth *Ethereum
+}
+
+// NewBlobPoolAPI creates a new instance of BlobPoolAPI.
+func NewBlobPoolAPI(eth *Ethereum) *BlobPoolAPI {
+	return &BlobPoolAPI{eth: eth}
+}
+
+// BlobStatus returns the blob transactions of each account with their storage
+// usage and eviction priority, the data cap usage of the pool and the contents
+// of the limbo, the included but not yet finalized transactions.
+func (api *BlobPoolAPI) BlobStatus() *blobpool.Status {
+	return api.eth.blobPool.Inspect()
+}
+
+// errNoLocals is returned if the local transactions are accessed, but their
+// tracking is disabled.
+var errNoLocals = errors.New("local transaction tracking disabled")
+
+// LocalTxsAPI offers an insight into the tracked local transactions and the
+// resubmission policies of their senders.
+type LocalTxsAPI struct {
+	eth *Ethereum
+}
+
+// NewLocalTxsAPI creates a new instance of LocalTxsAPI.
+func NewLocalTxsAPI(eth *Ethereum) *LocalTxsAPI {
+	return &LocalTxsAPI{eth: eth}
+}
+
+// LocalTransactions returns the tracked local transactions along with their
+// submission time and number of resubmissions.
+func (api *LocalTxsAPI) LocalTransactions() ([]*locals.TrackedTx, error) {
+	if api.eth.localTxTracker == nil {
+		return nil, errNoLocals
+	}
+	return api.eth.localTxTracker.Tracked(), nil
+}
+
+// LocalPolicy returns the resubmission policy of the local transactions of an
+// account.
+func (api *LocalTxsAPI) LocalPolicy(addr common.Address) (*locals.Policy, error) {
+	if api.eth.localTxTracker == nil {
+		return nil, errNoLocals
+	}
+	policy := api.eth.localTxTracker.Policy(addr)
+	return &policy, nil
+}
+
+// LocalTxsAdminAPI offers the management of the tracked local transactions and
+// the resubmission policies of their senders. As replacements are signed with
+// the node's unlocked accounts, it is only exposed in the admin namespace.
+type LocalTxsAdminAPI struct {
+	eth *Ethereum
+}
+
+// NewLocalTxsAdminAPI creates a new instance of LocalTxsAdminAPI.
+func NewLocalTxsAdminAPI(eth *Ethereum) *LocalTxsAdminAPI {
+	return &LocalTxsAdminAPI{eth: eth}
+}
+
+// UntrackLocal stops tracking a local transaction, which won't be resubmitted
+// anymore if dropped from the pool.
+func (api *LocalTxsAdminAPI) UntrackLocal(hash common.Hash) error {
+	if api.eth.localTxTracker == nil {
+		return errNoLocals
+	}
+	return api.eth.localTxTracker.Untrack(hash)
+}
+
+// CancelLocal replaces a local transaction with an empty transfer to its sender,
+// signed by the sender's unlocked account, and returns the replacement's hash.
+func (api *LocalTxsAdminAPI) CancelLocal(hash common.Hash) (common.Hash, error) {
+	if api.eth.localTxTracker == nil {
+		return common.Hash{}, errNoLocals
+	}
+	return api.eth.localTxTracker.Cancel(hash)
+}
+
+// SetLocalPolicy sets the resubmission policy of the local transactions of an
+// account. Fee bumps need the account to be unlocked, and are applied to a few
+// resubmissions of each transaction at most.
+func (api *LocalTxsAdminAPI) SetLocalPolicy(addr common.Address, policy locals.Policy) error {
+	if api.eth.localTxTracker == nil {
+		return errNoLocals
+	}
+	return api.eth.localTxTracker.SetPolicy(addr, policy)
+}
@@ -0,0 +1,192 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// newTestTxPoolDumpAPI creates a dump API backed by a transaction pool on top
+// of a fresh chain with the test account funded.
+func newTestTxPoolDumpAPI(t *testing.T) *TxPoolDumpAPI {
+	gspec := &core.Genesis{
+		Config: params.TestChainConfig,
+		Alloc:  types.GenesisAlloc{testAddr: {Balance: big.NewInt(1000000000000)}},
+	}
+	chain, _ := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	t.Cleanup(chain.Stop)
+
+	config := legacypool.DefaultConfig
+	config.Journal = ""
+
+	pool, err := txpool.New(config.PriceLimit, chain, []txpool.SubPool{legacypool.New(config, chain)})
+	if err != nil {
+		t.Fatalf("Failed to create transaction pool: %v", err)
+	}
+	t.Cleanup(func() { pool.Close() })
+
+	return NewTxPoolDumpAPI(&Ethereum{txPool: pool})
+}
+
+// exportTestTxPool exports all the transactions of the pool page by page,
+// returning the concatenated stream and the number of pages.
+func exportTestTxPool(t *testing.T, api *TxPoolDumpAPI) ([]byte, int) {
+	var (
+		dump   []byte
+		pages  int
+		cursor *TxPoolCursor
+	)
+	for {
+		page, err := api.ExportAll(cursor)
+		if err != nil {
+			t.Fatalf("Failed to export pool: %v", err)
+		}
+		dump = append(dump, page.Transactions...)
+		pages++
+
+		if page.Next == nil {
+			return dump, pages
+		}
+		cursor = page.Next
+	}
+}
+
+// Tests that the pending and queued transactions of a pool can be moved to
+// another one with an export and import round.
+func TestTxPoolExportImport(t *testing.T) {
+	var (
+		source = newTestTxPoolDumpAPI(t)
+		target = newTestTxPoolDumpAPI(t)
+		signer = types.HomesteadSigner{}
+		txs    []*types.Transaction
+	)
+	for _, nonce := range []uint64{0, 1, 3} {
+		tx, _ := types.SignTx(types.NewTransaction(nonce, common.Address{}, big.NewInt(1), 21000, big.NewInt(1), nil), signer, testKey)
+		txs = append(txs, tx)
+	}
+	for i, err := range source.eth.txPool.Add(txs, true) {
+		if err != nil {
+			t.Fatalf("Failed to add transaction %d: %v", i, err)
+		}
+	}
+	dump, _ := exportTestTxPool(t, source)
+	result, err := target.ImportAll(dump)
+	if err != nil {
+		t.Fatalf("Failed to import pool: %v", err)
+	}
+	if result.Imported != len(txs) || result.Known != 0 || len(result.Errors) != 0 {
+		t.Fatalf("Unexpected import result: %+v", result)
+	}
+	for _, tx := range txs {
+		if !target.eth.txPool.Has(tx.Hash()) {
+			t.Fatalf("Transaction %x missing after import", tx.Hash())
+		}
+	}
+	// Importing again reports the known transactions, the invalid ones rejected
+	key, _ := crypto.GenerateKey()
+	unfunded, _ := types.SignTx(types.NewTransaction(0, common.Address{}, big.NewInt(1), 21000, big.NewInt(1), nil), signer, key)
+	blob, _ := rlp.EncodeToBytes(unfunded)
+
+	more, _ := exportTestTxPool(t, target)
+	more = append(more, blob...)
+
+	if result, err = target.ImportAll(more); err != nil {
+		t.Fatalf("Failed to import pool: %v", err)
+	}
+	if result.Imported != 0 || result.Known != len(txs) || len(result.Errors) != 1 || result.Errors[unfunded.Hash()] == "" {
+		t.Fatalf("Unexpected import result: %+v", result)
+	}
+	// Garbage is rejected as a whole
+	if _, err := target.ImportAll([]byte{0xff, 0x00}); err == nil {
+		t.Fatal("Expected import failure of malformed dump")
+	}
+}
+
+// Tests that conditional transactions are left out of the export, as they'd be
+// imported without their conditions.
+func TestTxPoolExportConditional(t *testing.T) {
+	var (
+		api    = newTestTxPoolDumpAPI(t)
+		signer = types.HomesteadSigner{}
+		max    = hexutil.Uint64(1000)
+	)
+	plain, _ := types.SignTx(types.NewTransaction(0, common.Address{}, big.NewInt(1), 21000, big.NewInt(1), nil), signer, testKey)
+	cond, _ := types.SignTx(types.NewTransaction(1, common.Address{}, big.NewInt(1), 21000, big.NewInt(1), nil), signer, testKey)
+	cond = cond.WithOptions(&types.TransactionOpts{BlockNumberMax: &max})
+
+	for i, err := range api.eth.txPool.Add([]*types.Transaction{plain, cond}, true) {
+		if err != nil {
+			t.Fatalf("Failed to add transaction %d: %v", i, err)
+		}
+	}
+	dump, _ := exportTestTxPool(t, api)
+	want, _ := rlp.EncodeToBytes(plain)
+	if !bytes.Equal(dump, want) {
+		t.Fatal("Conditional transaction exported")
+	}
+}
+
+// Tests that the transaction pool is exported in pages of limited size, which
+// together hold all the transactions in account and nonce order.
+func TestTxPoolExportPaging(t *testing.T) {
+	defer func(size int) { maxTxPoolPageSize = size }(maxTxPoolPageSize)
+
+	var (
+		api    = newTestTxPoolDumpAPI(t)
+		signer = types.HomesteadSigner{}
+		txs    []*types.Transaction
+	)
+	for nonce := uint64(0); nonce < 5; nonce++ {
+		tx, _ := types.SignTx(types.NewTransaction(nonce, common.Address{}, big.NewInt(1), 21000, big.NewInt(1), nil), signer, testKey)
+		txs = append(txs, tx)
+	}
+	for i, err := range api.eth.txPool.Add(txs, true) {
+		if err != nil {
+			t.Fatalf("Failed to add transaction %d: %v", i, err)
+		}
+	}
+	// Fit two transactions into each page
+	maxTxPoolPageSize = 2 * int(txs[0].Size())
+
+	dump, pages := exportTestTxPool(t, api)
+	if pages != 3 {
+		t.Fatalf("Unexpected number of pages: have %d, want %d", pages, 3)
+	}
+	var want []byte
+	for _, tx := range txs {
+		blob, _ := rlp.EncodeToBytes(tx)
+		want = append(want, blob...)
+	}
+	if !bytes.Equal(dump, want) {
+		t.Fatal("Exported transactions mismatch")
+	}
+}
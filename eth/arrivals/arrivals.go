@@ -0,0 +1,283 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package arrivals records when blocks and votes are first seen on the network,
+// from which peer, and when blocks are imported. The events can be written to
+// a JSON lines file and streamed over an RPC subscription.
+package arrivals
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// Kind is the type of an arrival event.
+type Kind string
+
+const (
+	Announce Kind = "announce" // Block hash announced by a peer
+	Header   Kind = "header"   // Block header received from a peer
+	Body     Kind = "body"     // Block body received from a peer
+	Vote     Kind = "vote"     // Vote received from a peer
+	Import   Kind = "import"   // Block imported into the local chain
+)
+
+const (
+	// seenCacheSize is the number of recent events remembered to filter out all
+	// but the first arrival of the same item.
+	seenCacheSize = 16384
+
+	// eventChanSize is the size of the channel buffering the recorded events
+	// until they are written out. Events beyond are dropped.
+	eventChanSize = 4096
+
+	// chainEventChanSize is the size of channel listening to ChainEvent.
+	chainEventChanSize = 64
+)
+
+var droppedMeter = metrics.NewRegisteredMeter("eth/arrivals/dropped", nil)
+
+// Event is a single arrival. Votes use Number and Target for the target block
+// of the vote, Hash is the hash of the vote itself.
+type Event struct {
+	Kind   Kind                `json:"kind"`
+	Time   time.Time           `json:"time"`
+	Hash   common.Hash         `json:"hash"`
+	Number uint64              `json:"number"`
+	Peer   string              `json:"peer,omitempty"`   // ID of the delivering peer
+	Addr   string              `json:"addr,omitempty"`   // Remote address of the delivering peer
+	Voter  *types.BLSPublicKey `json:"voter,omitempty"`  // Validator that cast the vote
+	Target *common.Hash        `json:"target,omitempty"` // Target block of the vote
+}
+
+// Config are the settings of the arrival recorder.
+type Config struct {
+	Enabled bool   // Whether to record arrival events
+	File    string `toml:",omitempty"` // JSON lines file to append the events to
+}
+
+// Chain is the part of the blockchain the recorder watches for imports.
+type Chain interface {
+	SubscribeChainEvent(ch chan<- core.ChainEvent) event.Subscription
+}
+
+type seenKey struct {
+	kind Kind
+	hash common.Hash
+}
+
+// Recorder records the first arrival of blocks and votes. The nil recorder is
+// valid and discards everything.
+type Recorder struct {
+	chain  Chain
+	path   string
+	seen   lru.BasicLRU[seenKey, struct{}]
+	seenMu sync.Mutex
+
+	events chan Event
+	feed   event.Feed
+	scope  event.SubscriptionScope
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates an arrival recorder. It returns nil if recording is disabled.
+func New(config Config, chain Chain) *Recorder {
+	if !config.Enabled {
+		return nil
+	}
+	return &Recorder{
+		chain:  chain,
+		path:   config.File,
+		seen:   lru.NewBasicLRU[seenKey, struct{}](seenCacheSize),
+		events: make(chan Event, eventChanSize),
+		quit:   make(chan struct{}),
+	}
+}
+
+// Start opens the output file and starts writing out the events, implementing
+// node.Lifecycle.
+func (r *Recorder) Start() error {
+	var out *os.File
+	if r.path != "" {
+		f, err := os.OpenFile(r.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to open arrivals file: %v", err)
+		}
+		out = f
+		log.Info("Recording block and vote arrivals", "file", r.path)
+	}
+	r.wg.Add(1)
+	go r.loop(out)
+	return nil
+}
+
+// Stop flushes the pending events and closes the output file, implementing
+// node.Lifecycle.
+func (r *Recorder) Stop() error {
+	r.scope.Close()
+	close(r.quit)
+	r.wg.Wait()
+	return nil
+}
+
+// SubscribeEvents subscribes to the arrival events.
+func (r *Recorder) SubscribeEvents(ch chan<- Event) event.Subscription {
+	return r.scope.Track(r.feed.Subscribe(ch))
+}
+
+// Record records an event unless an event of the same kind was already
+// recorded for the hash. The arrival time is set if missing.
+func (r *Recorder) Record(ev Event) {
+	if r == nil {
+		return
+	}
+	key := seenKey{ev.Kind, ev.Hash}
+	r.seenMu.Lock()
+	if r.seen.Contains(key) {
+		r.seenMu.Unlock()
+		return
+	}
+	r.seen.Add(key, struct{}{})
+	r.seenMu.Unlock()
+
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	select {
+	case r.events <- ev:
+	default:
+		droppedMeter.Mark(1)
+	}
+}
+
+// RecordHeader records the arrival of a block header.
+func (r *Recorder) RecordHeader(peer, addr string, header *types.Header, time time.Time) {
+	if r == nil {
+		return
+	}
+	r.Record(Event{Kind: Header, Time: time, Hash: header.Hash(), Number: header.Number.Uint64(), Peer: peer, Addr: addr})
+}
+
+// RecordBlock records the arrival of the header and body of a complete block.
+// The block's receive time is used if set.
+func (r *Recorder) RecordBlock(peer, addr string, block *types.Block) {
+	if r == nil {
+		return
+	}
+	at := block.ReceivedAt
+	if at.IsZero() {
+		at = time.Now()
+	}
+	for _, kind := range []Kind{Header, Body} {
+		r.Record(Event{Kind: kind, Time: at, Hash: block.Hash(), Number: block.NumberU64(), Peer: peer, Addr: addr})
+	}
+}
+
+// RecordVote records the arrival of a vote.
+func (r *Recorder) RecordVote(peer, addr string, vote *types.VoteEnvelope) {
+	if r == nil {
+		return
+	}
+	target := vote.Data.TargetHash
+	r.Record(Event{
+		Kind:   Vote,
+		Hash:   vote.Hash(),
+		Number: vote.Data.TargetNumber,
+		Peer:   peer,
+		Addr:   addr,
+		Voter:  &vote.VoteAddress,
+		Target: &target,
+	})
+}
+
+func (r *Recorder) loop(out *os.File) {
+	defer r.wg.Done()
+
+	var (
+		w   *bufio.Writer
+		enc *json.Encoder
+	)
+	if out != nil {
+		defer out.Close()
+		w = bufio.NewWriter(out)
+		enc = json.NewEncoder(w)
+	}
+	var (
+		chainCh  = make(chan core.ChainEvent, chainEventChanSize)
+		chainSub event.Subscription
+		chainErr <-chan error
+	)
+	if r.chain != nil {
+		chainSub = r.chain.SubscribeChainEvent(chainCh)
+		chainErr = chainSub.Err()
+		defer chainSub.Unsubscribe()
+	}
+	write := func(ev Event) {
+		r.feed.Send(ev)
+		if enc == nil {
+			return
+		}
+		if err := enc.Encode(ev); err != nil {
+			log.Warn("Failed to write arrival event", "err", err)
+		}
+		// Flush once caught up, to keep the file current without a
+		// syscall for every event of a burst.
+		if len(r.events) == 0 {
+			if err := w.Flush(); err != nil {
+				log.Warn("Failed to flush arrival events", "err", err)
+			}
+		}
+	}
+	for {
+		select {
+		case ev := <-r.events:
+			write(ev)
+
+		case head := <-chainCh:
+			r.Record(Event{Kind: Import, Hash: head.Header.Hash(), Number: head.Header.Number.Uint64()})
+
+		case <-chainErr:
+			chainErr = nil
+
+		case <-r.quit:
+			for {
+				select {
+				case ev := <-r.events:
+					write(ev)
+				default:
+					if w != nil {
+						w.Flush()
+					}
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package arrivals
+
+import (
+	"bufio"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+type testChain struct {
+	feed event.Feed
+}
+
+func (c *testChain) SubscribeChainEvent(ch chan<- core.ChainEvent) event.Subscription {
+	return c.feed.Subscribe(ch)
+}
+
+func readEvent(t *testing.T, ch <-chan Event) Event {
+	t.Helper()
+	select {
+	case ev := <-ch:
+		return ev
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for event")
+	}
+	return Event{}
+}
+
+func expectNoEvent(t *testing.T, ch <-chan Event) {
+	t.Helper()
+	select {
+	case ev := <-ch:
+		t.Fatalf("unexpected event: %+v", ev)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	r := New(Config{}, nil)
+	if r != nil {
+		t.Fatal("recorder created while disabled")
+	}
+	// The nil recorder must be usable.
+	r.Record(Event{Kind: Announce})
+	r.RecordHeader("peer", "", &types.Header{Number: big.NewInt(1)}, time.Now())
+	r.RecordBlock("peer", "", types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1)}))
+	r.RecordVote("peer", "", &types.VoteEnvelope{Data: new(types.VoteData)})
+}
+
+func TestFirstArrival(t *testing.T) {
+	chain := new(testChain)
+	r := New(Config{Enabled: true}, chain)
+	if err := r.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer r.Stop()
+
+	events := make(chan Event, 16)
+	sub := r.SubscribeEvents(events)
+	defer sub.Unsubscribe()
+
+	var (
+		header = &types.Header{Number: big.NewInt(10)}
+		block  = types.NewBlockWithHeader(header)
+		hash   = block.Hash()
+	)
+	r.Record(Event{Kind: Announce, Hash: hash, Number: 10, Peer: "a", Addr: "1.2.3.4:30303"})
+	r.Record(Event{Kind: Announce, Hash: hash, Number: 10, Peer: "b"})
+	if ev := readEvent(t, events); ev.Kind != Announce || ev.Peer != "a" || ev.Addr != "1.2.3.4:30303" || ev.Time.IsZero() {
+		t.Fatalf("wrong announce event: %+v", ev)
+	}
+	expectNoEvent(t, events)
+
+	// The header is seen first, the full block later only adds the body.
+	r.RecordHeader("b", "", header, time.Now())
+	r.RecordBlock("c", "", block)
+	if ev := readEvent(t, events); ev.Kind != Header || ev.Peer != "b" || ev.Hash != hash || ev.Number != 10 {
+		t.Fatalf("wrong header event: %+v", ev)
+	}
+	if ev := readEvent(t, events); ev.Kind != Body || ev.Peer != "c" || ev.Hash != hash {
+		t.Fatalf("wrong body event: %+v", ev)
+	}
+	expectNoEvent(t, events)
+
+	// Votes are recorded once per vote.
+	vote := &types.VoteEnvelope{
+		VoteAddress: types.BLSPublicKey{0x01},
+		Data:        &types.VoteData{TargetNumber: 10, TargetHash: hash},
+	}
+	r.RecordVote("d", "", vote)
+	r.RecordVote("e", "", vote)
+	ev := readEvent(t, events)
+	if ev.Kind != Vote || ev.Peer != "d" || ev.Hash != vote.Hash() || ev.Number != 10 {
+		t.Fatalf("wrong vote event: %+v", ev)
+	}
+	if ev.Target == nil || *ev.Target != hash || ev.Voter == nil || *ev.Voter != vote.VoteAddress {
+		t.Fatalf("wrong vote target or voter: %+v", ev)
+	}
+	expectNoEvent(t, events)
+
+	// Imports are picked up from the chain.
+	chain.feed.Send(core.ChainEvent{Header: header})
+	if ev := readEvent(t, events); ev.Kind != Import || ev.Hash != hash || ev.Peer != "" {
+		t.Fatalf("wrong import event: %+v", ev)
+	}
+}
+
+func TestFileOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "arrivals.jsonl")
+	if err := os.WriteFile(path, []byte(`{"kind":"announce"}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	r := New(Config{Enabled: true, File: path}, nil)
+	if err := r.Start(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		r.Record(Event{Kind: Header, Hash: common.Hash{byte(i)}, Number: uint64(i), Peer: "a"})
+	}
+	// Stopping must flush all recorded events.
+	r.Stop()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var lines []Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var ev Event
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			t.Fatalf("invalid line %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, ev)
+	}
+	// The file is appended to.
+	if len(lines) != 4 {
+		t.Fatalf("wrong number of lines: have %d, want 4", len(lines))
+	}
+	for i, ev := range lines[1:] {
+		if ev.Kind != Header || ev.Hash != (common.Hash{byte(i)}) || ev.Number != uint64(i) || ev.Time.IsZero() {
+			t.Errorf("line %d: wrong event %+v", i+1, ev)
+		}
+	}
+}
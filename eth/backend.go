@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"math/big"
 	"runtime"
+	"slices"
 	"sync"
 	"time"
 
@@ -46,6 +47,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/core/vote"
+	"github.com/ethereum/go-ethereum/eth/arrivals"
 	"github.com/ethereum/go-ethereum/eth/downloader"
 	"github.com/ethereum/go-ethereum/eth/ethconfig"
 	"github.com/ethereum/go-ethereum/eth/filters"
@@ -55,7 +57,9 @@ import (
 	"github.com/ethereum/go-ethereum/eth/protocols/snap"
 	"github.com/ethereum/go-ethereum/eth/protocols/trust"
 	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/eth/txbroadcast"
 	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/objstore"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/internal/shutdowncheck"
@@ -103,11 +107,13 @@ type Ethereum struct {
 	// core protocol objects
 	config         *ethconfig.Config
 	txPool         *txpool.TxPool
+	blobPool       *blobpool.BlobPool
 	localTxTracker *locals.TxTracker
 	blockchain     *core.BlockChain
 
-	handler *handler
-	discmix *enode.FairMix
+	handler  *handler
+	arrivals *arrivals.Recorder
+	discmix  *enode.FairMix
 
 	// DB interfaces
 	chainDb ethdb.Database // Block chain database
@@ -227,14 +233,61 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		overrides.OverrideVerkle = config.OverrideVerkle
 	}
 
+	// Block history is pruned online by the chain freezer, which only ever
+	// holds blocks older than the immutability threshold. Transactions can't
+	// stay indexed beyond the retained blocks.
+	if config.BlockHistory != 0 {
+		if config.PruneAncientData {
+			return nil, errors.New("block history window is not supported with pruned ancient data")
+		}
+		if config.BlockHistory < params.FullImmutabilityThreshold {
+			log.Warn("Sanitizing block history window", "provided", config.BlockHistory, "updated", params.FullImmutabilityThreshold)
+			config.BlockHistory = params.FullImmutabilityThreshold
+		}
+		if config.TransactionHistory == 0 || config.TransactionHistory > config.BlockHistory {
+			log.Warn("Limiting transaction history to the block history window", "provided", config.TransactionHistory, "updated", config.BlockHistory)
+			config.TransactionHistory = config.BlockHistory
+		}
+		log.Info("Pruning block history online", "window", config.BlockHistory)
+	}
+	// Ancient segments older than the cold age are moved to the object store.
+	// Offloaded blocks must stay readable, so it can't be combined with pruning.
+	var coldStore objstore.Store
+	if config.ColdHistoryDir != "" {
+		if config.PruneAncientData || config.BlockHistory != 0 {
+			return nil, errors.New("cold history tier is not supported with pruned block history")
+		}
+		if config.ColdHistoryAge < params.FullImmutabilityThreshold {
+			log.Warn("Sanitizing cold history age", "provided", config.ColdHistoryAge, "updated", params.FullImmutabilityThreshold)
+			config.ColdHistoryAge = params.FullImmutabilityThreshold
+		}
+		dir := stack.ResolvePath(config.ColdHistoryDir)
+		if coldStore, err = objstore.NewDirStore(dir); err != nil {
+			return nil, err
+		}
+		log.Info("Offloading ancient segments to cold store", "dir", dir, "age", config.ColdHistoryAge)
+	}
+	// Sidecars are exported before pruning if an archive directory is given
+	var blobArchiveDir string
+	if config.BlobArchiveDir != "" {
+		blobArchiveDir = stack.ResolvePath(config.BlobArchiveDir)
+		log.Info("Exporting blob sidecars before pruning", "dir", blobArchiveDir)
+	}
 	// startup ancient freeze
 	freezeDb := chainDb
-	if stack.CheckIfMultiDataBase() {
+	if stack.CheckIfSeparateBlockStore() {
 		freezeDb = chainDb.BlockStore()
 	}
 	if err = freezeDb.SetupFreezerEnv(&ethdb.FreezerEnv{
-		ChainCfg:         chainConfig,
-		BlobExtraReserve: config.BlobExtraReserve,
+		ChainCfg:          chainConfig,
+		BlobExtraReserve:  config.BlobExtraReserve,
+		BlockHistory:      config.BlockHistory,
+		ColdStore:         coldStore,
+		ColdAge:           config.ColdHistoryAge,
+		ColdCache:         config.ColdHistoryCache * 1024 * 1024,
+		BlobKeepAddresses: config.BlobKeepAddresses,
+		BlobKeepBlocks:    config.BlobKeepBlocks,
+		BlobArchiveDir:    blobArchiveDir,
 	}); err != nil {
 		return nil, err
 	}
@@ -312,10 +365,12 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 			TriesInMemory:       config.TriesInMemory,
 			Preimages:           config.Preimages,
 			StateHistory:        config.StateHistory,
+			HistoricStateDepth:  config.HistoricStateDepth,
 			StateScheme:         config.StateScheme,
 			PathSyncFlush:       config.PathSyncFlush,
 			JournalFilePath:     journalFilePath,
 			JournalFile:         config.JournalFileEnabled,
+			JournalCheckpoint:   config.JournalCheckpoint,
 		}
 	)
 	if config.VMTrace != "" {
@@ -350,17 +405,38 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	}
 	eth.bloomIndexer.Start(eth.blockchain)
 
+	// Assemble the admission filters shared by the transaction pools
+	filters := slices.Clone(config.TxPool.Filters)
+	if config.TxPool.Blocklist != "" {
+		filter, err := txpool.NewAddressFilter(stack.ResolvePath(config.TxPool.Blocklist))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load txpool blocklist: %v", err)
+		}
+		filters = append(filters, filter)
+	}
+	if config.TxPool.SelectorFilter != "" {
+		filter, err := txpool.NewSelectorFilter(stack.ResolvePath(config.TxPool.SelectorFilter))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load txpool selector filter: %v", err)
+		}
+		filters = append(filters, filter)
+	}
+	config.TxPool.Filters, config.BlobPool.Filters = filters, filters
+
 	if config.BlobPool.Datadir != "" {
 		config.BlobPool.Datadir = stack.ResolvePath(config.BlobPool.Datadir)
 	}
-	blobPool := blobpool.New(config.BlobPool, eth.blockchain)
+	eth.blobPool = blobpool.New(config.BlobPool, eth.blockchain)
 
 	if config.TxPool.Journal != "" {
 		config.TxPool.Journal = stack.ResolvePath(config.TxPool.Journal)
 	}
+	if config.TxPool.OverflowPoolDir != "" {
+		config.TxPool.OverflowPoolDir = stack.ResolvePath(config.TxPool.OverflowPoolDir)
+	}
 	legacyPool := legacypool.New(config.TxPool, eth.blockchain)
 
-	eth.txPool, err = txpool.New(config.TxPool.PriceLimit, eth.blockchain, []txpool.SubPool{legacyPool, blobPool})
+	eth.txPool, err = txpool.New(config.TxPool.PriceLimit, eth.blockchain, []txpool.SubPool{legacyPool, eth.blobPool})
 	if err != nil {
 		return nil, err
 	}
@@ -371,9 +447,20 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 			log.Warn("Sanitizing invalid txpool journal time", "provided", rejournal, "updated", time.Second)
 			rejournal = time.Second
 		}
-		eth.localTxTracker = locals.New(config.TxPool.Journal, rejournal, eth.blockchain.Config(), eth.txPool)
+		eth.localTxTracker = locals.New(config.TxPool.Journal, rejournal, eth.blockchain, eth.txPool, config.TxPool.PriceBump, eth.signLocalTx)
 		stack.RegisterLifecycle(eth.localTxTracker)
 	}
+	if config.Arrivals.File != "" {
+		config.Arrivals.File = stack.ResolvePath(config.Arrivals.File)
+	}
+	eth.arrivals = arrivals.New(config.Arrivals, eth.blockchain)
+	if eth.arrivals != nil {
+		stack.RegisterLifecycle(eth.arrivals)
+	}
+	txBroadcastPolicy, err := txbroadcast.New(config.TxBroadcastPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transaction broadcast policy: %v", err)
+	}
 	// Permit the downloader to use the trie cache allowance during fast sync
 	cacheLimit := cacheConfig.TrieCleanLimit + cacheConfig.TrieDirtyLimit + cacheConfig.SnapshotLimit
 	if eth.handler, err = newHandler(&handlerConfig{
@@ -390,7 +477,11 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		EnableEVNFeatures:         stack.Config().EnableEVNFeatures,
 		EVNNodeIdsWhitelist:       stack.Config().P2P.EVNNodeIdsWhitelist,
 		ProxyedValidatorAddresses: stack.Config().P2P.ProxyedValidatorAddresses,
+		ProxyedValidatorNodeIDs:   stack.Config().P2P.ProxyedValidatorNodeIDs,
 		DisablePeerTxBroadcast:    config.DisablePeerTxBroadcast,
+		TxBroadcastPolicy:         txBroadcastPolicy,
+		TxRateLimiter:             txpool.NewRateLimiter[string](config.TxPool.PeerRateLimit, int(config.TxPool.PeerRateBurst), stack.Config().P2P.MaxPeers),
+		Arrivals:                  eth.arrivals,
 		PeerSet:                   peers,
 		EnableQuickBlockFetching:  stack.Config().EnableQuickBlockFetching,
 	}); err != nil {
@@ -474,6 +565,14 @@ func (s *Ethereum) APIs() []rpc.API {
 	// Append any APIs exposed explicitly by the consensus engine
 	apis = append(apis, s.engine.APIs(s.BlockChain())...)
 
+	// Stream the block and vote arrivals if they are recorded
+	if s.arrivals != nil {
+		apis = append(apis, rpc.API{Namespace: "eth", Service: arrivals.NewAPI(s.arrivals)})
+	}
+	// Serve the database writes on the authenticated endpoint if enabled
+	if s.config.DBWriteAPI {
+		apis = append(apis, rpc.API{Namespace: "dbwrite", Service: ethapi.NewDbWriteAPI(s.APIBackend), Authenticated: true})
+	}
 	// Append all the local APIs and return
 	return append(apis, []rpc.API{
 		{
@@ -488,6 +587,18 @@ func (s *Ethereum) APIs() []rpc.API {
 		}, {
 			Namespace: "eth",
 			Service:   filters.NewFilterAPI(filters.NewFilterSystem(s.APIBackend, filters.Config{}), s.config.RangeLimit),
+		}, {
+			Namespace: "txpool",
+			Service:   NewTxPoolDumpAPI(s),
+		}, {
+			Namespace: "txpool",
+			Service:   NewBlobPoolAPI(s),
+		}, {
+			Namespace: "txpool",
+			Service:   NewLocalTxsAPI(s),
+		}, {
+			Namespace: "admin",
+			Service:   NewLocalTxsAdminAPI(s),
 		}, {
 			Namespace: "admin",
 			Service:   NewAdminAPI(s),
@@ -714,6 +825,17 @@ func (s *Ethereum) StartMining() error {
 	return nil
 }
 
+// signLocalTx signs a replacement of a local transaction with the sender's
+// wallet, which must be available and unlocked.
+func (s *Ethereum) signLocalTx(from common.Address, tx *types.Transaction) (*types.Transaction, error) {
+	account := accounts.Account{Address: from}
+	wallet, err := s.accountManager.Find(account)
+	if err != nil {
+		return nil, err
+	}
+	return wallet.SignTx(account, tx, s.blockchain.Config().ChainID)
+}
+
 // StopMining terminates the miner, both at the consensus engine level as well as
 // at the block creation level.
 func (s *Ethereum) StopMining() {
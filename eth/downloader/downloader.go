@@ -96,6 +96,10 @@ const (
 // peerDropFn is a callback type for dropping a peer detected as malicious.
 type peerDropFn func(id string)
 
+// peerPenalizeFn is a callback type for dropping and penalizing a peer proven
+// misbehaving, e.g. by serving an invalid chain.
+type peerPenalizeFn func(id string, reason string)
+
 // headerTask is a set of downloaded headers to queue along with their precomputed
 // hashes to avoid constant rehashing.
 type headerTask struct {
@@ -120,7 +124,8 @@ type Downloader struct {
 	blockchain BlockChain
 
 	// Callbacks
-	dropPeer peerDropFn // Drops a peer for misbehaving
+	dropPeer     peerDropFn     // Drops a peer for misbehaving
+	penalizePeer peerPenalizeFn // Drops and penalizes a peer for proven misbehaviour
 
 	// Status
 	synchroniseMock func(id string, hash common.Hash) error // Replacement for synchronise during testing
@@ -221,7 +226,7 @@ type BlockChain interface {
 type DownloadOption func(downloader *Downloader) *Downloader
 
 // New creates a new downloader to fetch hashes and blocks from remote peers.
-func New(stateDb ethdb.Database, mux *event.TypeMux, chain BlockChain, dropPeer peerDropFn, _ func()) *Downloader {
+func New(stateDb ethdb.Database, mux *event.TypeMux, chain BlockChain, dropPeer peerDropFn, penalizePeer peerPenalizeFn, _ func()) *Downloader {
 	dl := &Downloader{
 		stateDB:        stateDb,
 		mux:            mux,
@@ -229,6 +234,7 @@ func New(stateDb ethdb.Database, mux *event.TypeMux, chain BlockChain, dropPeer
 		peers:          newPeerSet(),
 		blockchain:     chain,
 		dropPeer:       dropPeer,
+		penalizePeer:   penalizePeer,
 		headerProcCh:   make(chan *headerTask, 1),
 		quitCh:         make(chan struct{}),
 		SnapSyncer:     snap.NewSyncer(stateDb, chain.TrieDB().Scheme()),
@@ -340,6 +346,8 @@ func (d *Downloader) LegacySync(id string, head common.Hash, name string, td *bi
 			// The dropPeer method is nil when `--copydb` is used for a local copy.
 			// Timeouts can occur if e.g. compaction hits at the wrong time, and can be ignored
 			log.Warn("Downloader wants to drop peer, but peerdrop-function is not set", "peer", id)
+		} else if d.penalizePeer != nil && isMisbehaviour(err) {
+			d.penalizePeer(id, err.Error())
 		} else {
 			d.dropPeer(id)
 		}
@@ -349,6 +357,12 @@ func (d *Downloader) LegacySync(id string, head common.Hash, name string, td *bi
 	return err
 }
 
+// isMisbehaviour reports whether a sync failure proves the remote peer served
+// invalid data, as opposed to merely being slow or out of sync.
+func isMisbehaviour(err error) bool {
+	return errors.Is(err, errInvalidChain) || errors.Is(err, errBadPeer) || errors.Is(err, errInvalidAncestor)
+}
+
 // synchronise will select the peer and use it for synchronising. If an empty string is given
 // it will use the best peer possible and synchronize if its TD is higher than our own. If any of the
 // checks fail an error will be returned. This method is synchronous
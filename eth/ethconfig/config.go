@@ -30,7 +30,9 @@ import (
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/txpool/blobpool"
 	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
+	"github.com/ethereum/go-ethereum/eth/arrivals"
 	"github.com/ethereum/go-ethereum/eth/gasprice"
+	"github.com/ethereum/go-ethereum/eth/txbroadcast"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/log"
@@ -55,6 +57,9 @@ var Defaults = Config{
 	TxLookupLimit:      2350000,
 	TransactionHistory: 2350000,
 	StateHistory:       params.FullImmutabilityThreshold,
+	HistoricStateDepth: 16384,
+	ColdHistoryAge:     1000000,
+	ColdHistoryCache:   64,
 	DatabaseCache:      512,
 	TrieCleanCache:     154,
 	TrieDirtyCache:     256,
@@ -112,17 +117,37 @@ type Config struct {
 	EnableTrustProtocol bool // Whether enable trust protocol
 	RangeLimit          bool
 
+	// DBWriteAPI enables the database write API in the dbwrite namespace,
+	// served on the authenticated RPC endpoint only.
+	DBWriteAPI bool `toml:",omitempty"`
+
+	// TxBroadcastPolicy decides per transaction and peer whether transactions
+	// are sent in full, announced or withheld.
+	TxBroadcastPolicy txbroadcast.Config `toml:",omitempty"`
+
+	// Arrivals records when blocks and votes are first seen on the network.
+	Arrivals arrivals.Config `toml:",omitempty"`
+
 	// Deprecated: use 'TransactionHistory' instead.
 	TxLookupLimit uint64 `toml:",omitempty"` // The maximum number of blocks from head whose tx indices are reserved.
 
 	TransactionHistory uint64 `toml:",omitempty"` // The maximum number of blocks from head whose tx indices are reserved.
 	StateHistory       uint64 `toml:",omitempty"` // The maximum number of blocks from head whose state histories are reserved.
+	HistoricStateDepth uint64 `toml:",omitempty"` // The maximum number of state histories aggregated to serve a historical state (0 = no limit).
+	BlockHistory       uint64 `toml:",omitempty"` // The number of blocks from head whose block data is retained, older ones are pruned online (0 = entire chain)
+
+	// Cold history tier, offloading old ancient segments to an object store.
+	ColdHistoryDir   string `toml:",omitempty"` // Directory of the object store old ancient segments are offloaded to (empty = disabled)
+	ColdHistoryAge   uint64 `toml:",omitempty"` // The number of blocks from head whose ancient data is always kept locally
+	ColdHistoryCache int    `toml:",omitempty"` // Memory allowance (MB) for caching offloaded segments
+
 	// State scheme represents the scheme used to store ethereum states and trie
 	// nodes on top. It can be 'hash', 'path', or none which means use the scheme
 	// consistent with persistent state.
 	StateScheme        string `toml:",omitempty"` // State scheme used to store ethereum state and merkle trie nodes on top
 	PathSyncFlush      bool   `toml:",omitempty"` // State scheme used to store ethereum state and merkle trie nodes on top
 	JournalFileEnabled bool   // Whether the TrieJournal is stored using journal file
+	JournalCheckpoint  uint64 `toml:",omitempty"` // Number of blocks between incremental checkpoints of the in-memory pathdb layers (0 = disabled)
 
 	// RequiredBlocks is a set of block number -> hash mappings which must be in the
 	// canonical chain of all remote peers. Setting the option makes geth verify the
@@ -197,7 +222,10 @@ type Config struct {
 	OverrideVerkle *uint64 `toml:",omitempty"`
 
 	// blob setting
-	BlobExtraReserve uint64
+	BlobExtraReserve  uint64
+	BlobKeepAddresses []common.Address `toml:",omitempty"` // Sidecars of blob transactions from or to these addresses are kept after pruning
+	BlobKeepBlocks    uint64           `toml:",omitempty"` // Number of recent blocks whose kept sidecars are retained (0 = as long as the block)
+	BlobArchiveDir    string           `toml:",omitempty"` // Directory to export blob sidecars to before pruning them (empty = disabled)
 }
 
 // CreateConsensusEngine creates a consensus engine for the given chain config.
@@ -9,7 +9,9 @@ import (
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/txpool/blobpool"
 	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
+	"github.com/ethereum/go-ethereum/eth/arrivals"
 	"github.com/ethereum/go-ethereum/eth/gasprice"
+	"github.com/ethereum/go-ethereum/eth/txbroadcast"
 	"github.com/ethereum/go-ethereum/miner/minerconfig"
 	"github.com/ethereum/go-ethereum/p2p/enode"
 )
@@ -33,12 +35,21 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		DisableSnapProtocol     bool
 		EnableTrustProtocol     bool
 		RangeLimit              bool
-		TxLookupLimit           uint64 `toml:",omitempty"`
-		TransactionHistory      uint64 `toml:",omitempty"`
-		StateHistory            uint64 `toml:",omitempty"`
-		StateScheme             string `toml:",omitempty"`
-		PathSyncFlush           bool   `toml:",omitempty"`
+		DBWriteAPI              bool               `toml:",omitempty"`
+		TxBroadcastPolicy       txbroadcast.Config `toml:",omitempty"`
+		Arrivals                arrivals.Config    `toml:",omitempty"`
+		TxLookupLimit           uint64             `toml:",omitempty"`
+		TransactionHistory      uint64             `toml:",omitempty"`
+		StateHistory            uint64             `toml:",omitempty"`
+		HistoricStateDepth      uint64             `toml:",omitempty"`
+		BlockHistory            uint64             `toml:",omitempty"`
+		ColdHistoryDir          string             `toml:",omitempty"`
+		ColdHistoryAge          uint64             `toml:",omitempty"`
+		ColdHistoryCache        int                `toml:",omitempty"`
+		StateScheme             string             `toml:",omitempty"`
+		PathSyncFlush           bool               `toml:",omitempty"`
 		JournalFileEnabled      bool
+		JournalCheckpoint       uint64                 `toml:",omitempty"`
 		RequiredBlocks          map[uint64]common.Hash `toml:"-"`
 		SkipBcVersionCheck      bool                   `toml:"-"`
 		DatabaseHandles         int                    `toml:"-"`
@@ -71,6 +82,9 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		OverrideMaxwell         *uint64 `toml:",omitempty"`
 		OverrideVerkle          *uint64 `toml:",omitempty"`
 		BlobExtraReserve        uint64
+		BlobKeepAddresses       []common.Address `toml:",omitempty"`
+		BlobKeepBlocks          uint64           `toml:",omitempty"`
+		BlobArchiveDir          string           `toml:",omitempty"`
 	}
 	var enc Config
 	enc.Genesis = c.Genesis
@@ -89,12 +103,21 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.DisableSnapProtocol = c.DisableSnapProtocol
 	enc.EnableTrustProtocol = c.EnableTrustProtocol
 	enc.RangeLimit = c.RangeLimit
+	enc.DBWriteAPI = c.DBWriteAPI
+	enc.TxBroadcastPolicy = c.TxBroadcastPolicy
+	enc.Arrivals = c.Arrivals
 	enc.TxLookupLimit = c.TxLookupLimit
 	enc.TransactionHistory = c.TransactionHistory
 	enc.StateHistory = c.StateHistory
+	enc.HistoricStateDepth = c.HistoricStateDepth
+	enc.BlockHistory = c.BlockHistory
+	enc.ColdHistoryDir = c.ColdHistoryDir
+	enc.ColdHistoryAge = c.ColdHistoryAge
+	enc.ColdHistoryCache = c.ColdHistoryCache
 	enc.StateScheme = c.StateScheme
 	enc.PathSyncFlush = c.PathSyncFlush
 	enc.JournalFileEnabled = c.JournalFileEnabled
+	enc.JournalCheckpoint = c.JournalCheckpoint
 	enc.RequiredBlocks = c.RequiredBlocks
 	enc.SkipBcVersionCheck = c.SkipBcVersionCheck
 	enc.DatabaseHandles = c.DatabaseHandles
@@ -127,6 +150,9 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.OverrideMaxwell = c.OverrideMaxwell
 	enc.OverrideVerkle = c.OverrideVerkle
 	enc.BlobExtraReserve = c.BlobExtraReserve
+	enc.BlobKeepAddresses = c.BlobKeepAddresses
+	enc.BlobKeepBlocks = c.BlobKeepBlocks
+	enc.BlobArchiveDir = c.BlobArchiveDir
 	return &enc, nil
 }
 
@@ -149,12 +175,21 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		DisableSnapProtocol     *bool
 		EnableTrustProtocol     *bool
 		RangeLimit              *bool
-		TxLookupLimit           *uint64 `toml:",omitempty"`
-		TransactionHistory      *uint64 `toml:",omitempty"`
-		StateHistory            *uint64 `toml:",omitempty"`
-		StateScheme             *string `toml:",omitempty"`
-		PathSyncFlush           *bool   `toml:",omitempty"`
+		DBWriteAPI              *bool               `toml:",omitempty"`
+		TxBroadcastPolicy       *txbroadcast.Config `toml:",omitempty"`
+		Arrivals                *arrivals.Config    `toml:",omitempty"`
+		TxLookupLimit           *uint64             `toml:",omitempty"`
+		TransactionHistory      *uint64             `toml:",omitempty"`
+		StateHistory            *uint64             `toml:",omitempty"`
+		HistoricStateDepth      *uint64             `toml:",omitempty"`
+		BlockHistory            *uint64             `toml:",omitempty"`
+		ColdHistoryDir          *string             `toml:",omitempty"`
+		ColdHistoryAge          *uint64             `toml:",omitempty"`
+		ColdHistoryCache        *int                `toml:",omitempty"`
+		StateScheme             *string             `toml:",omitempty"`
+		PathSyncFlush           *bool               `toml:",omitempty"`
 		JournalFileEnabled      *bool
+		JournalCheckpoint       *uint64                `toml:",omitempty"`
 		RequiredBlocks          map[uint64]common.Hash `toml:"-"`
 		SkipBcVersionCheck      *bool                  `toml:"-"`
 		DatabaseHandles         *int                   `toml:"-"`
@@ -187,6 +222,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		OverrideMaxwell         *uint64 `toml:",omitempty"`
 		OverrideVerkle          *uint64 `toml:",omitempty"`
 		BlobExtraReserve        *uint64
+		BlobKeepAddresses       []common.Address `toml:",omitempty"`
+		BlobKeepBlocks          *uint64          `toml:",omitempty"`
+		BlobArchiveDir          *string          `toml:",omitempty"`
 	}
 	var dec Config
 	if err := unmarshal(&dec); err != nil {
@@ -240,6 +278,15 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.RangeLimit != nil {
 		c.RangeLimit = *dec.RangeLimit
 	}
+	if dec.DBWriteAPI != nil {
+		c.DBWriteAPI = *dec.DBWriteAPI
+	}
+	if dec.TxBroadcastPolicy != nil {
+		c.TxBroadcastPolicy = *dec.TxBroadcastPolicy
+	}
+	if dec.Arrivals != nil {
+		c.Arrivals = *dec.Arrivals
+	}
 	if dec.TxLookupLimit != nil {
 		c.TxLookupLimit = *dec.TxLookupLimit
 	}
@@ -249,6 +296,21 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.StateHistory != nil {
 		c.StateHistory = *dec.StateHistory
 	}
+	if dec.HistoricStateDepth != nil {
+		c.HistoricStateDepth = *dec.HistoricStateDepth
+	}
+	if dec.BlockHistory != nil {
+		c.BlockHistory = *dec.BlockHistory
+	}
+	if dec.ColdHistoryDir != nil {
+		c.ColdHistoryDir = *dec.ColdHistoryDir
+	}
+	if dec.ColdHistoryAge != nil {
+		c.ColdHistoryAge = *dec.ColdHistoryAge
+	}
+	if dec.ColdHistoryCache != nil {
+		c.ColdHistoryCache = *dec.ColdHistoryCache
+	}
 	if dec.StateScheme != nil {
 		c.StateScheme = *dec.StateScheme
 	}
@@ -258,6 +320,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.JournalFileEnabled != nil {
 		c.JournalFileEnabled = *dec.JournalFileEnabled
 	}
+	if dec.JournalCheckpoint != nil {
+		c.JournalCheckpoint = *dec.JournalCheckpoint
+	}
 	if dec.RequiredBlocks != nil {
 		c.RequiredBlocks = dec.RequiredBlocks
 	}
@@ -354,5 +419,14 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.BlobExtraReserve != nil {
 		c.BlobExtraReserve = *dec.BlobExtraReserve
 	}
+	if dec.BlobKeepAddresses != nil {
+		c.BlobKeepAddresses = dec.BlobKeepAddresses
+	}
+	if dec.BlobKeepBlocks != nil {
+		c.BlobKeepBlocks = *dec.BlobKeepBlocks
+	}
+	if dec.BlobArchiveDir != nil {
+		c.BlobArchiveDir = *dec.BlobArchiveDir
+	}
 	return nil
 }
@@ -105,9 +105,21 @@ type chainInsertFn func(types.Blocks) (int, error)
 // peerDropFn is a callback type for dropping a peer detected as malicious.
 type peerDropFn func(id string)
 
+// peerPenalizeFn is a callback type for dropping and penalizing a peer proven
+// misbehaving, e.g. by delivering invalid blocks.
+type peerPenalizeFn func(id string, reason string)
+
 // fetchRangeBlocksFn is a callback type for fetching a range of blocks from a peer.
 type fetchRangeBlocksFn func(peer string, startHeight uint64, startHash common.Hash, count uint64) ([]*types.Block, error)
 
+// headerArrivalFn is a callback type for reporting a requested header delivered
+// by a peer.
+type headerArrivalFn func(peer string, header *types.Header, time time.Time)
+
+// blockArrivalFn is a callback type for reporting a requested block completed
+// with the body delivered by a peer.
+type blockArrivalFn func(peer string, block *types.Block)
+
 // blockAnnounce is the hash notification of the availability of a new block in the
 // network.
 type blockAnnounce struct {
@@ -207,7 +219,10 @@ type BlockFetcher struct {
 	chainFinalizedHeight chainFinalizedHeightFn // Retrieves the current chain's finalized height
 	insertChain          chainInsertFn          // Injects a batch of blocks into the chain
 	dropPeer             peerDropFn             // Drops a peer for misbehaving
+	penalizePeer         peerPenalizeFn         // Drops and penalizes a peer for proven misbehaviour
 	fetchRangeBlocks     fetchRangeBlocksFn     // Fetches a range of blocks from a peer
+	headerArrived        headerArrivalFn        // Reports requested headers delivered by a peer (optional)
+	blockArrived         blockArrivalFn         // Reports requested blocks completed by a peer (optional)
 
 	// Testing hooks
 	announceChangeHook func(common.Hash, bool)           // Method to call upon adding or deleting a hash from the blockAnnounce list
@@ -220,7 +235,7 @@ type BlockFetcher struct {
 // NewBlockFetcher creates a block fetcher to retrieve blocks based on hash announcements.
 func NewBlockFetcher(getBlock blockRetrievalFn, verifyHeader headerVerifierFn, broadcastBlock blockBroadcasterFn,
 	chainHeight chainHeightFn, chainFinalizedHeight chainFinalizedHeightFn, insertChain chainInsertFn, dropPeer peerDropFn,
-	fetchRangeBlocks fetchRangeBlocksFn) *BlockFetcher {
+	penalizePeer peerPenalizeFn, fetchRangeBlocks fetchRangeBlocksFn, headerArrived headerArrivalFn, blockArrived blockArrivalFn) *BlockFetcher {
 	return &BlockFetcher{
 		notify:               make(chan *blockAnnounce),
 		inject:               make(chan *blockOrHeaderInject),
@@ -245,7 +260,10 @@ func NewBlockFetcher(getBlock blockRetrievalFn, verifyHeader headerVerifierFn, b
 		chainFinalizedHeight: chainFinalizedHeight,
 		insertChain:          insertChain,
 		dropPeer:             dropPeer,
+		penalizePeer:         penalizePeer,
 		fetchRangeBlocks:     fetchRangeBlocks,
+		headerArrived:        headerArrived,
+		blockArrived:         blockArrived,
 	}
 }
 
@@ -636,7 +654,7 @@ func (f *BlockFetcher) loop() {
 					// If the delivered header does not match the promised number, drop the announcer
 					if header.Number.Uint64() != announce.number {
 						log.Trace("Invalid block number fetched", "peer", announce.origin, "hash", header.Hash(), "announced", announce.number, "provided", header.Number)
-						f.dropPeer(announce.origin)
+						f.penalize(announce.origin, "invalid announced block number")
 						f.forgetHash(hash)
 						continue
 					}
@@ -644,6 +662,9 @@ func (f *BlockFetcher) loop() {
 					if f.getBlock(hash) == nil {
 						announce.header = header
 						announce.time = task.time
+						if f.headerArrived != nil {
+							f.headerArrived(task.peer, header, task.time)
+						}
 
 						// If the block is empty (header only), short circuit into the final import queue
 						if header.TxHash == types.EmptyTxsHash && header.UncleHash == types.EmptyUncleHash {
@@ -654,7 +675,9 @@ func (f *BlockFetcher) loop() {
 								block = block.WithWithdrawals(make([]*types.Withdrawal, 0))
 							}
 							block.ReceivedAt = task.time
-
+							if f.blockArrived != nil {
+								f.blockArrived(task.peer, block)
+							}
 							complete = append(complete, block)
 							f.completing[hash] = announce
 							continue
@@ -739,6 +762,9 @@ func (f *BlockFetcher) loop() {
 							block := types.NewBlockWithHeader(announce.header).WithBody(types.Body{Transactions: task.transactions[i], Uncles: task.uncles[i]})
 							block = block.WithSidecars(task.sidecars[i])
 							block.ReceivedAt = task.time
+							if f.blockArrived != nil {
+								f.blockArrived(task.peer, block)
+							}
 							blocks = append(blocks, block)
 						} else {
 							f.forgetHash(hash)
@@ -788,6 +814,9 @@ func (f *BlockFetcher) loop() {
 				if f.getBlock(hash) != nil {
 					continue
 				}
+				if f.blockArrived != nil {
+					f.blockArrived(entry.announce.origin, block)
+				}
 				f.enqueue(entry.announce.origin, nil, block)
 				quickBlockFetchingTimer.UpdateSince(entry.announce.time)
 			}
@@ -921,7 +950,7 @@ func (f *BlockFetcher) importBlocks(op *blockOrHeaderInject) {
 		default:
 			// Something went very wrong, drop the peer
 			log.Error("Propagated block verification failed", "peer", peer, "number", block.Number(), "hash", hash, "err", err)
-			f.dropPeer(peer)
+			f.penalize(peer, "invalid propagated block")
 			return
 		}
 		// Run the actual import and log any issues
@@ -999,3 +1028,12 @@ func (f *BlockFetcher) forgetBlock(hash common.Hash) {
 		delete(f.queued, hash)
 	}
 }
+
+// penalize drops a peer proven misbehaving, penalizing it if supported.
+func (f *BlockFetcher) penalize(peer string, reason string) {
+	if f.penalizePeer == nil {
+		f.dropPeer(peer)
+		return
+	}
+	f.penalizePeer(peer, reason)
+}
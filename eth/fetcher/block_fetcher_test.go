@@ -105,10 +105,10 @@ func newTester() *fetcherTester {
 		drops:   make(map[string]bool),
 	}
 	tester.fetcher = NewBlockFetcher(tester.getBlock, tester.verifyHeader, tester.broadcastBlock,
-		tester.chainHeight, tester.chainFinalizedHeight, tester.insertChain, tester.dropPeer,
+		tester.chainHeight, tester.chainFinalizedHeight, tester.insertChain, tester.dropPeer, nil,
 		func(peer string, startHeight uint64, startHash common.Hash, count uint64) ([]*types.Block, error) {
 			return nil, errors.New("not implemented")
-		})
+		}, nil, nil)
 	tester.fetcher.Start()
 
 	return tester
@@ -1070,10 +1070,13 @@ func TestBlockFetcherMultiplePeers(t *testing.T) {
 		},
 		// dropPeer
 		func(id string) {},
+		// penalizePeer
+		nil,
 		// fetchRangeBlocks
 		func(peer string, startHeight uint64, startHash common.Hash, count uint64) ([]*types.Block, error) {
 			return nil, errors.New("not implemented")
 		},
+		nil, nil,
 	)
 
 	// Start fetcher
@@ -1279,6 +1282,7 @@ func TestQuickBlockFetching(t *testing.T) {
 			return len(blocks), nil
 		},
 		func(id string) {},
+		nil,
 		// fetchRangeBlocks function simulates quick block fetching
 		func(peer string, startHeight uint64, startHash common.Hash, count uint64) ([]*types.Block, error) {
 			fetchRangeBlocksCalled.Store(true)
@@ -1288,6 +1292,7 @@ func TestQuickBlockFetching(t *testing.T) {
 			// Return requested block
 			return []*types.Block{block}, nil
 		},
+		nil, nil,
 	)
 
 	// Start fetcher
@@ -341,6 +341,11 @@ func (f *TxFetcher) Enqueue(peer string, txs []*types.Transaction, direct bool)
 		batch := txs[i:end]
 
 		for j, err := range f.addTxs(peer, batch) {
+			// Transactions refused due to the rate limit of the delivering peer
+			// are not considered delivered, they can be retrieved from others.
+			if errors.Is(err, txpool.ErrPeerRateLimited) {
+				continue
+			}
 			// Track the transaction hash if the price is too low for us.
 			// Avoid re-request this transaction when we receive another
 			// announcement.
@@ -1281,6 +1281,71 @@ func TestTransactionFetcherUnderpricedDedup(t *testing.T) {
 	})
 }
 
+// Tests that transactions refused due to the rate limit of the delivering peer
+// are not considered delivered, but rescheduled from other peers.
+func TestTransactionFetcherPeerRateLimited(t *testing.T) {
+	testTransactionFetcherParallel(t, txFetcherTest{
+		init: func() *TxFetcher {
+			return NewTxFetcher(
+				func(common.Hash) bool { return false },
+				func(peer string, txs []*types.Transaction) []error {
+					errs := make([]error, len(txs))
+					for i, tx := range txs {
+						if peer == "A" && tx.Hash() == testTxsHashes[0] {
+							errs[i] = txpool.ErrPeerRateLimited
+						}
+					}
+					return errs
+				},
+				func(string, []common.Hash) error { return nil },
+				nil,
+			)
+		},
+		steps: []interface{}{
+			// Push an initial announcement through to the scheduled stage
+			doTxNotify{peer: "A",
+				hashes: []common.Hash{testTxsHashes[0], testTxsHashes[1]},
+				types:  []byte{testTxs[0].Type(), testTxs[1].Type()},
+				sizes:  []uint32{uint32(testTxs[0].Size()), uint32(testTxs[1].Size())},
+			},
+			doWait{time: txArriveTimeout, step: true},
+			doTxNotify{peer: "B",
+				hashes: []common.Hash{testTxsHashes[0]},
+				types:  []byte{testTxs[0].Type()},
+				sizes:  []uint32{uint32(testTxs[0].Size())},
+			},
+			isScheduled{
+				tracking: map[string][]announce{
+					"A": {
+						{testTxsHashes[0], testTxs[0].Type(), uint32(testTxs[0].Size())},
+						{testTxsHashes[1], testTxs[1].Type(), uint32(testTxs[1].Size())},
+					},
+					"B": {
+						{testTxsHashes[0], testTxs[0].Type(), uint32(testTxs[0].Size())},
+					},
+				},
+				fetching: map[string][]common.Hash{
+					"A": {testTxsHashes[0], testTxsHashes[1]},
+				},
+			},
+			// Deliver both transactions, the rate limited one should be
+			// requested from the other peer.
+			doTxEnqueue{peer: "A", txs: []*types.Transaction{testTxs[0], testTxs[1]}, direct: true},
+			isScheduled{
+				tracking: map[string][]announce{
+					"B": {
+						{testTxsHashes[0], testTxs[0].Type(), uint32(testTxs[0].Size())},
+					},
+				},
+				fetching: map[string][]common.Hash{
+					"B": {testTxsHashes[0]},
+				},
+			},
+			isUnderpriced(0),
+		},
+	})
+}
+
 // Tests that underpriced transactions don't get rescheduled after being rejected,
 // but at the same time there's a hard cap on the number of transactions that are
 // tracked.
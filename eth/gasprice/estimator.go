@@ -0,0 +1,187 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package gasprice
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/big"
+	"slices"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// estimateConfidence is the inclusion probability the estimated tips aim for.
+const estimateConfidence = 0.9
+
+// EstimateTargets are the inclusion targets of the tip estimates, in blocks.
+var EstimateTargets = []uint64{1, 3, 10}
+
+// TipEstimate is a suggested tip to get a transaction included within a target
+// number of blocks.
+type TipEstimate struct {
+	Blocks      uint64   // Number of blocks the transaction should be included within
+	TipCap      *big.Int // Suggested tip, capped at the oracle's maximum price
+	Probability float64  // Modelled probability of inclusion paying the suggested tip
+}
+
+// TipEstimates are the suggested tips for each inclusion target on top of the
+// current chain head.
+type TipEstimates struct {
+	BaseFee   *big.Int // Base fee of the next block, nil before London
+	Estimates []TipEstimate
+}
+
+// poolLevel is the gas of the pending transactions paying a given tip.
+type poolLevel struct {
+	tip *big.Int
+	gas uint64
+}
+
+// EstimateTips suggests tips for a transaction to be included within each of the
+// EstimateTargets blocks.
+//
+// Unlike SuggestTipCap, which samples a percentile of the cheapest transactions
+// and on Parlia chains mostly returns the validators' price floor, the estimates
+// model the probability of inclusion of a tip: a block accepts the tip if it is
+// at least the lowest one included in the recent blocks, but only once all the
+// given pending transactions paying more have been included, a block gas limit
+// at a time.
+func (oracle *Oracle) EstimateTips(ctx context.Context, pending map[common.Address][]*types.Transaction) (*TipEstimates, error) {
+	head, err := oracle.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if head == nil {
+		if err == nil {
+			err = errors.New("chain head not found")
+		}
+		return nil, err
+	}
+	mins, err := oracle.recentMinTips(ctx, head)
+	if err != nil {
+		return nil, err
+	}
+	var baseFee *big.Int
+	if config := oracle.backend.ChainConfig(); config.IsLondon(new(big.Int).Add(head.Number, common.Big1)) {
+		baseFee = eip1559.CalcBaseFee(config, head)
+	}
+	// Aggregate the pool by tip, dropping the transactions not executable in the
+	// next block
+	var levels []poolLevel
+	for _, txs := range pending {
+		for _, tx := range txs {
+			tip, err := tx.EffectiveGasTip(baseFee)
+			if err != nil {
+				continue
+			}
+			levels = append(levels, poolLevel{tip: tip, gas: tx.Gas()})
+		}
+	}
+	slices.SortFunc(levels, func(a, b poolLevel) int { return a.tip.Cmp(b.tip) })
+
+	ahead := make([]uint64, len(levels)+1) // Gas of the pending transactions from the i-th level up
+	for i := len(levels) - 1; i >= 0; i-- {
+		ahead[i] = ahead[i+1] + levels[i].gas
+	}
+	// Every recently included tip and every pooled tip is a candidate, the lowest
+	// one reaching the confidence being suggested for a target
+	candidates := append([]*big.Int{oracle.defaultPrice, oracle.maxPrice}, mins...)
+	for _, level := range levels {
+		candidates = append(candidates, level.tip)
+	}
+	slices.SortFunc(candidates, func(a, b *big.Int) int { return a.Cmp(b) })
+	candidates = slices.CompactFunc(candidates, func(a, b *big.Int) bool { return a.Cmp(b) == 0 })
+
+	probability := func(tip *big.Int, blocks uint64) float64 {
+		// Probability of a single block accepting the tip
+		accept := 1.0
+		if len(mins) > 0 {
+			accepted := sort.Search(len(mins), func(i int) bool { return mins[i].Cmp(tip) > 0 })
+			accept = float64(accepted) / float64(len(mins))
+		}
+		// Number of blocks filled up by the transactions paying more
+		var queued uint64
+		if head.GasLimit > 0 {
+			queued = ahead[sort.Search(len(levels), func(i int) bool { return levels[i].tip.Cmp(tip) > 0 })] / head.GasLimit
+		}
+		if queued >= blocks {
+			return 0
+		}
+		return 1 - math.Pow(1-accept, float64(blocks-queued))
+	}
+	estimates := &TipEstimates{BaseFee: baseFee}
+	for _, blocks := range EstimateTargets {
+		estimate := TipEstimate{Blocks: blocks, TipCap: oracle.maxPrice}
+		for _, tip := range candidates {
+			if tip.Cmp(oracle.defaultPrice) < 0 || tip.Cmp(oracle.maxPrice) > 0 {
+				continue
+			}
+			if estimate.Probability = probability(tip, blocks); estimate.Probability >= estimateConfidence {
+				estimate.TipCap = tip
+				break
+			}
+		}
+		estimate.TipCap = new(big.Int).Set(estimate.TipCap)
+		estimates.Estimates = append(estimates.Estimates, estimate)
+	}
+	return estimates, nil
+}
+
+// recentMinTips returns the lowest tips included in the recent blocks up to the
+// given head, sorted in ascending order. Empty blocks and blocks only including
+// transactions of the miner itself are skipped.
+func (oracle *Oracle) recentMinTips(ctx context.Context, head *types.Header) ([]*big.Int, error) {
+	headHash := head.Hash()
+
+	oracle.cacheLock.RLock()
+	lastHead, mins := oracle.estimateHead, oracle.estimateMins
+	oracle.cacheLock.RUnlock()
+	if headHash == lastHead {
+		return mins, nil
+	}
+	var (
+		exp    int
+		number = head.Number.Uint64()
+		result = make(chan results, oracle.checkBlocks)
+		quit   = make(chan struct{})
+	)
+	for exp < oracle.checkBlocks && number > 0 {
+		go oracle.getBlockValues(ctx, number, 1, oracle.ignorePrice, result, quit)
+		exp++
+		number--
+	}
+	mins = make([]*big.Int, 0, exp)
+	for ; exp > 0; exp-- {
+		res := <-result
+		if res.err != nil {
+			close(quit)
+			return nil, res.err
+		}
+		mins = append(mins, res.values...)
+	}
+	slices.SortFunc(mins, func(a, b *big.Int) int { return a.Cmp(b) })
+
+	oracle.cacheLock.Lock()
+	oracle.estimateHead = headHash
+	oracle.estimateMins = mins
+	oracle.cacheLock.Unlock()
+
+	return mins, nil
+}
@@ -77,6 +77,9 @@ type Oracle struct {
 	maxHeaderHistory, maxBlockHistory uint64
 
 	historyCache *lru.Cache[cacheKey, processedFees]
+
+	estimateHead common.Hash // Head the lowest included tips were sampled at
+	estimateMins []*big.Int  // Lowest included tips of the recent blocks, ascending
 }
 
 // NewOracle returns a new gasprice oracle which can recommend suitable
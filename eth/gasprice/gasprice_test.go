@@ -277,3 +277,42 @@ func TestSuggestTipCap(t *testing.T) {
 		}
 	}
 }
+
+func TestEstimateTips(t *testing.T) {
+	backend := newTestBackend(t, nil, nil, false)
+	defer backend.teardown()
+
+	oracle := NewOracle(backend, Config{Blocks: 5, Percentile: 60}, big.NewInt(params.GWei))
+
+	// The lowest tips included are: 32G, 31G, 30G, 29G, 28G
+	check := func(pending map[common.Address][]*types.Transaction, want []int64) {
+		t.Helper()
+
+		estimates, err := oracle.EstimateTips(context.Background(), pending)
+		if err != nil {
+			t.Fatalf("Failed to estimate tips: %v", err)
+		}
+		if len(estimates.Estimates) != len(want) {
+			t.Fatalf("Estimate count mismatch, want %d, got %d", len(want), len(estimates.Estimates))
+		}
+		for i, estimate := range estimates.Estimates {
+			if estimate.TipCap.Cmp(big.NewInt(want[i]*params.GWei)) != 0 {
+				t.Errorf("Tip mismatch within %d blocks, want %dG, got %d", estimate.Blocks, want[i], estimate.TipCap)
+			}
+			if estimate.Probability < estimateConfidence {
+				t.Errorf("Inclusion probability within %d blocks too low: %f", estimate.Blocks, estimate.Probability)
+			}
+		}
+	}
+	check(nil, []int64{32, 30, 29})
+
+	// Two blocks worth of pending transactions paying more push the next block out
+	gaslimit := backend.chain.CurrentHeader().GasLimit
+	pending := map[common.Address][]*types.Transaction{
+		{0xaa}: {
+			types.NewTx(&types.LegacyTx{Nonce: 0, Gas: gaslimit, GasPrice: big.NewInt(40 * params.GWei)}),
+			types.NewTx(&types.LegacyTx{Nonce: 1, Gas: gaslimit, GasPrice: big.NewInt(40 * params.GWei)}),
+		},
+	}
+	check(pending, []int64{40, 32, 29})
+}
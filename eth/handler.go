@@ -27,6 +27,8 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/consensus/beacon"
 	"github.com/ethereum/go-ethereum/consensus/parlia"
 	"github.com/ethereum/go-ethereum/core"
@@ -36,6 +38,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/arrivals"
 	"github.com/ethereum/go-ethereum/eth/downloader"
 	"github.com/ethereum/go-ethereum/eth/ethconfig"
 	"github.com/ethereum/go-ethereum/eth/fetcher"
@@ -43,6 +46,7 @@ import (
 	"github.com/ethereum/go-ethereum/eth/protocols/eth"
 	"github.com/ethereum/go-ethereum/eth/protocols/snap"
 	"github.com/ethereum/go-ethereum/eth/protocols/trust"
+	"github.com/ethereum/go-ethereum/eth/txbroadcast"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
@@ -59,6 +63,10 @@ const (
 	// voteChanSize is the size of channel listening to NewVotesEvent.
 	voteChanSize = 256
 
+	// voteSourceCacheSize is the number of recent votes whose sender is remembered,
+	// so that the sender can be penalized if the vote turns out to be invalid.
+	voteSourceCacheSize = 4096
+
 	// deltaTdThreshold is the threshold of TD difference for peers to broadcast votes.
 	deltaTdThreshold = 20
 
@@ -71,6 +79,11 @@ const (
 var (
 	syncChallengeTimeout        = 15 * time.Second // Time allowance for a node to reply to the sync progress challenge
 	accountBlacklistPeerCounter = metrics.NewRegisteredCounter("eth/count/blacklist", nil)
+	peerTxRateLimitMeter        = metrics.NewRegisteredMeter("eth/ratelimit/txs", nil) // Transactions rejected due to the per-peer rate limit
+
+	proxyedValidatorPeerGauge       = metrics.NewRegisteredGauge("sentry/validator/peers", nil)
+	proxyedValidatorDisconnectMeter = metrics.NewRegisteredMeter("sentry/validator/disconnect", nil)
+	proxyedVoteRelayMeter           = metrics.NewRegisteredMeter("sentry/validator/votes", nil)
 )
 
 // txPool defines the methods needed from a transaction pool implementation to
@@ -110,6 +123,10 @@ type votePool interface {
 	// SubscribeNewVoteEvent should return an event subscription of
 	// NewVotesEvent and send events to the given channel.
 	SubscribeNewVoteEvent(ch chan<- core.NewVoteEvent) event.Subscription
+
+	// SubscribeInvalidVoteEvent should return an event subscription of
+	// InvalidVoteEvent and send events to the given channel.
+	SubscribeInvalidVoteEvent(ch chan<- core.InvalidVoteEvent) event.Subscription
 }
 
 // handlerConfig is the collection of initialization parameters to create a full
@@ -127,11 +144,15 @@ type handlerConfig struct {
 	RequiredBlocks            map[uint64]common.Hash // Hard coded map of required block hashes for sync challenges
 	DirectBroadcast           bool
 	DisablePeerTxBroadcast    bool
+	TxBroadcastPolicy         *txbroadcast.Policy // Policy deciding how transactions are propagated
+	Arrivals                  *arrivals.Recorder  // Recorder of block and vote arrivals (optional)
 	PeerSet                   *peerSet
+	TxRateLimiter             *txpool.RateLimiter[string] // Rate limiter of the transactions accepted per peer (optional)
 	EnableQuickBlockFetching  bool
 	EnableEVNFeatures         bool
 	EVNNodeIdsWhitelist       []enode.ID
 	ProxyedValidatorAddresses []common.Address
+	ProxyedValidatorNodeIDs   []enode.ID
 }
 
 type handler struct {
@@ -139,9 +160,14 @@ type handler struct {
 	networkID                  uint64
 	forkFilter                 forkid.Filter // Fork ID filter, constant across the lifetime of the node
 	disablePeerTxBroadcast     bool
+	txBroadcastPolicy          *txbroadcast.Policy
+	txRateLimiter              *txpool.RateLimiter[string]
+	arrivals                   *arrivals.Recorder
 	enableEVNFeatures          bool
 	evnNodeIdsWhitelistMap     map[enode.ID]struct{}
 	proxyedValidatorAddressMap map[common.Address]struct{}
+	proxyedValidatorNodeIDMap  map[enode.ID]struct{}
+	verifyProxyedVote          func(vote *types.VoteEnvelope) error // Checks proxyed validator votes before relaying them
 
 	snapSync        atomic.Bool // Flag whether snap sync is enabled (gets disabled if we already have blocks)
 	synced          atomic.Bool // Flag whether we're considered synchronised (enables transaction processing)
@@ -172,6 +198,9 @@ type handler struct {
 	voteCh         chan core.NewVoteEvent
 	votesSub       event.Subscription
 	voteMonitorSub event.Subscription
+	invalidVoteCh  chan core.InvalidVoteEvent
+	invalidVoteSub event.Subscription
+	voteSources    *lru.Cache[common.Hash, string] // Peer that first sent each recent vote
 
 	requiredBlocks map[uint64]common.Hash
 
@@ -200,6 +229,9 @@ func newHandler(config *handlerConfig) (*handler, error) {
 		networkID:                  config.Network,
 		forkFilter:                 forkid.NewFilter(config.Chain),
 		disablePeerTxBroadcast:     config.DisablePeerTxBroadcast,
+		txBroadcastPolicy:          config.TxBroadcastPolicy,
+		txRateLimiter:              config.TxRateLimiter,
+		arrivals:                   config.Arrivals,
 		eventMux:                   config.EventMux,
 		database:                   config.Database,
 		txpool:                     config.TxPool,
@@ -212,6 +244,8 @@ func newHandler(config *handlerConfig) (*handler, error) {
 		enableEVNFeatures:          config.EnableEVNFeatures,
 		evnNodeIdsWhitelistMap:     make(map[enode.ID]struct{}),
 		proxyedValidatorAddressMap: make(map[common.Address]struct{}),
+		proxyedValidatorNodeIDMap:  make(map[enode.ID]struct{}),
+		voteSources:                lru.NewCache[common.Hash, string](voteSourceCacheSize),
 		quitSync:                   make(chan struct{}),
 		handlerDoneCh:              make(chan struct{}),
 		handlerStartCh:             make(chan struct{}),
@@ -223,6 +257,10 @@ func newHandler(config *handlerConfig) (*handler, error) {
 	for _, address := range config.ProxyedValidatorAddresses {
 		h.proxyedValidatorAddressMap[address] = struct{}{}
 	}
+	for _, nodeID := range config.ProxyedValidatorNodeIDs {
+		h.proxyedValidatorNodeIDMap[nodeID] = struct{}{}
+	}
+	h.verifyProxyedVote = h.verifyVote
 	if config.Sync == ethconfig.FullSync {
 		// The database seems empty as the current block is the genesis. Yet the snap
 		// block is ahead, so snap sync was enabled for this node at a certain point.
@@ -259,7 +297,7 @@ func newHandler(config *handlerConfig) (*handler, error) {
 		return nil, errors.New("snap sync not supported with snapshots disabled")
 	}
 	// Construct the downloader (long sync)
-	h.downloader = downloader.New(config.Database, h.eventMux, h.chain, h.removePeer, nil)
+	h.downloader = downloader.New(config.Database, h.eventMux, h.chain, h.removePeer, h.penalizePeer, nil)
 
 	// Construct the fetcher (short sync)
 	validator := func(header *types.Header) error {
@@ -327,6 +365,10 @@ func newHandler(config *handlerConfig) (*handler, error) {
 		if err != nil {
 			return nil, err
 		}
+		if err := verifyBlocksByRange(res, startHeight, startHash, count); err != nil {
+			p.Peer.Penalize(p2p.PenaltyMajor, "bad blocks by range response")
+			return nil, err
+		}
 
 		blocks := make([]*types.Block, len(res))
 		for i, item := range res {
@@ -335,11 +377,13 @@ func newHandler(config *handlerConfig) (*handler, error) {
 			block.ReceivedAt = time.Now()
 			block.ReceivedFrom = p.ID()
 			if err := block.SanityCheck(); err != nil {
+				p.Peer.Penalize(p2p.PenaltyMajor, "invalid range block")
 				return nil, err
 			}
 			if len(block.Sidecars()) > 0 {
 				for _, sidecar := range block.Sidecars() {
 					if err := sidecar.SanityCheck(block.Number(), block.Hash()); err != nil {
+						p.Peer.Penalize(p2p.PenaltyMajor, "invalid range block sidecar")
 						return nil, err
 					}
 				}
@@ -353,8 +397,20 @@ func newHandler(config *handlerConfig) (*handler, error) {
 		fetchRangeBlocks = nil
 	}
 
+	var (
+		headerArrived func(peer string, header *types.Header, time time.Time)
+		blockArrived  func(peer string, block *types.Block)
+	)
+	if h.arrivals != nil {
+		headerArrived = func(peer string, header *types.Header, time time.Time) {
+			h.arrivals.RecordHeader(peer, h.peerAddr(peer), header, time)
+		}
+		blockArrived = func(peer string, block *types.Block) {
+			h.arrivals.RecordBlock(peer, h.peerAddr(peer), block)
+		}
+	}
 	h.blockFetcher = fetcher.NewBlockFetcher(h.chain.GetBlockByHash, validator, broadcastBlockWithCheck,
-		heighter, finalizeHeighter, inserter, h.removePeer, fetchRangeBlocks)
+		heighter, finalizeHeighter, inserter, h.removePeer, h.penalizePeer, fetchRangeBlocks, headerArrived, blockArrived)
 
 	fetchTx := func(peer string, hashes []common.Hash) error {
 		p := h.peers.peer(peer)
@@ -364,7 +420,7 @@ func newHandler(config *handlerConfig) (*handler, error) {
 		return p.RequestTxs(hashes)
 	}
 	addTxs := func(peer string, txs []*types.Transaction) []error {
-		errors := h.txpool.Add(txs, false)
+		errors := h.addPeerTxs(peer, txs)
 		for _, err := range errors {
 			if err == txpool.ErrInBlackList {
 				accountBlacklistPeerCounter.Inc(1)
@@ -384,6 +440,30 @@ func newHandler(config *handlerConfig) (*handler, error) {
 	return h, nil
 }
 
+// verifyBlocksByRange checks that a BlocksByRange response is the requested,
+// contiguous segment of the chain, ordered from the start block backwards.
+func verifyBlocksByRange(blocks []*bsc.BlockData, startHeight uint64, startHash common.Hash, count uint64) error {
+	if len(blocks) == 0 {
+		return errors.New("empty blocks by range response")
+	}
+	if uint64(len(blocks)) > count {
+		return fmt.Errorf("too many blocks in range response: have %d, want at most %d", len(blocks), count)
+	}
+	first := blocks[0].Header
+	if startHash != (common.Hash{}) && first.Hash() != startHash {
+		return fmt.Errorf("stale range response: start hash mismatch, have %v, want %v", first.Hash(), startHash)
+	}
+	if first.Number.Uint64() != startHeight {
+		return fmt.Errorf("stale range response: start height mismatch, have %d, want %d", first.Number, startHeight)
+	}
+	for i := 1; i < len(blocks); i++ {
+		if blocks[i-1].Header.ParentHash != blocks[i].Header.Hash() {
+			return fmt.Errorf("range response not contiguous at %d", blocks[i].Header.Number)
+		}
+	}
+	return nil
+}
+
 // protoTracker tracks the number of active protocol handlers.
 func (h *handler) protoTracker() {
 	defer h.wg.Done()
@@ -512,6 +592,11 @@ func (h *handler) runEthPeer(peer *eth.Peer, handler eth.Handler) error {
 	peer.Log().Debug("Ethereum peer connected", "name", peer.Name(), "peers.len", h.peers.len())
 	defer h.unregisterPeer(peer.ID())
 
+	if h.isProxyedValidatorPeer(peer.NodeID()) {
+		proxyedValidatorPeerGauge.Inc(1)
+		peer.Log().Info("Proxyed validator connected", "name", peer.Name(), "addr", peer.RemoteAddr())
+	}
+
 	p := h.peers.peer(peer.ID())
 	if p == nil {
 		return errors.New("peer dropped during handling")
@@ -658,6 +743,46 @@ func (h *handler) runBscExtension(peer *bsc.Peer, handler bsc.Handler) error {
 	return handler(peer)
 }
 
+// peerAddr returns the remote address of a peer, or an empty string if the
+// peer is no longer connected.
+func (h *handler) peerAddr(id string) string {
+	if p := h.peers.peer(id); p != nil && p.RemoteAddr() != nil {
+		return p.RemoteAddr().String()
+	}
+	return ""
+}
+
+// addPeerTxs adds the transactions delivered by a peer to the pool, rejecting
+// the ones exceeding the rate of transactions accepted per peer.
+func (h *handler) addPeerTxs(peer string, txs []*types.Transaction) []error {
+	if h.txRateLimiter == nil {
+		return h.txpool.Add(txs, false)
+	}
+	var (
+		errs    = make([]error, len(txs))
+		allowed = make([]*types.Transaction, 0, len(txs))
+		indices = make([]int, 0, len(txs))
+	)
+	for i, tx := range txs {
+		if !h.txRateLimiter.Allow(peer) {
+			errs[i] = txpool.ErrPeerRateLimited
+			continue
+		}
+		allowed = append(allowed, tx)
+		indices = append(indices, i)
+	}
+	if rejected := len(txs) - len(allowed); rejected > 0 {
+		peerTxRateLimitMeter.Mark(int64(rejected))
+		log.Debug("Rate limited peer transactions", "peer", peer, "rejected", rejected)
+	}
+	if len(allowed) > 0 {
+		for i, err := range h.txpool.Add(allowed, false) {
+			errs[indices[i]] = err
+		}
+	}
+	return errs
+}
+
 // removePeer requests disconnection of a peer.
 func (h *handler) removePeer(id string) {
 	peer := h.peers.peer(id)
@@ -667,6 +792,17 @@ func (h *handler) removePeer(id string) {
 	}
 }
 
+// penalizePeer requests disconnection of a peer proven misbehaving by the sync
+// or fetcher subsystems, e.g. by delivering invalid blocks, penalizing it too.
+// Peers merely timing out or stalling are only removed.
+func (h *handler) penalizePeer(id string, reason string) {
+	peer := h.peers.peer(id)
+	if peer != nil {
+		peer.Peer.Penalize(p2p.PenaltyMajor, reason)
+		peer.Peer.Disconnect(p2p.DiscUselessPeer)
+	}
+}
+
 // unregisterPeer removes a peer from the downloader, fetchers and main peer set.
 func (h *handler) unregisterPeer(id string) {
 	// Create a custom logger to avoid printing the entire id
@@ -696,6 +832,11 @@ func (h *handler) unregisterPeer(id string) {
 	if err := h.peers.unregisterPeer(id); err != nil {
 		logger.Error("Ethereum peer removal failed", "err", err)
 	}
+	if h.isProxyedValidatorPeer(peer.NodeID()) {
+		proxyedValidatorPeerGauge.Dec(1)
+		proxyedValidatorDisconnectMeter.Mark(1)
+		logger.Error("Proxyed validator disconnected, check the upstream validator", "name", peer.Name(), "addr", peer.RemoteAddr())
+	}
 
 	peerInfo := peer.Peer.Info()
 	remoteAddr := peerInfo.Network.RemoteAddress
@@ -739,6 +880,12 @@ func (h *handler) Start(maxPeers int, maxPeersPerIP int) {
 			h.wg.Add(1)
 			go h.startMaliciousVoteMonitor()
 		}
+
+		// penalize the senders of invalid votes
+		h.wg.Add(1)
+		h.invalidVoteCh = make(chan core.InvalidVoteEvent, voteChanSize)
+		h.invalidVoteSub = h.votepool.SubscribeInvalidVoteEvent(h.invalidVoteCh)
+		go h.invalidVoteLoop()
 	}
 
 	// announce local pending transactions again
@@ -783,7 +930,8 @@ func (h *handler) Stop() {
 	h.reannoTxsSub.Unsubscribe()  // quits txReannounceLoop
 	h.minedBlockSub.Unsubscribe() // quits blockBroadcastLoop
 	if h.votepool != nil {
-		h.votesSub.Unsubscribe() // quits voteBroadcastLoop
+		h.votesSub.Unsubscribe()       // quits voteBroadcastLoop
+		h.invalidVoteSub.Unsubscribe() // quits invalidVoteLoop
 		if h.maliciousVoteMonitor != nil {
 			h.voteMonitorSub.Unsubscribe()
 		}
@@ -887,6 +1035,26 @@ func (h *handler) needFullBroadcastInEVN(block *types.Block) bool {
 	return h.peers.isProxyedValidator(coinbase, h.proxyedValidatorAddressMap)
 }
 
+// verifyVote checks the signature of a vote and that it was cast by a validator
+// of the voted block. Votes for blocks not verified locally yet fail the check.
+func (h *handler) verifyVote(vote *types.VoteEnvelope) error {
+	if err := vote.Verify(); err != nil {
+		return err
+	}
+	posa, ok := h.chain.Engine().(consensus.PoSA)
+	if !ok {
+		return errors.New("consensus engine doesn't support votes")
+	}
+	return posa.VerifyVote(h.chain, vote)
+}
+
+// isProxyedValidatorPeer checks if the given peer is a validator proxyed by the
+// local sentry node.
+func (h *handler) isProxyedValidatorPeer(id enode.ID) bool {
+	_, ok := h.proxyedValidatorNodeIDMap[id]
+	return ok
+}
+
 func (h *handler) queryValidatorNodeIDsMap() map[common.Address][]enode.ID {
 	latest := h.chain.CurrentHeader()
 	if !h.chain.Config().IsMaxwell(latest.Number, latest.Time) {
@@ -914,9 +1082,11 @@ func (h *handler) BroadcastTransactions(txs types.Transactions) {
 	var (
 		blobTxs  int // Number of blob transactions to announce only
 		largeTxs int // Number of large transactions to announce only
+		condTxs  int // Number of conditional transactions kept local
 
-		directCount int // Number of transactions sent directly to peers (duplicates included)
-		annCount    int // Number of transactions announced across all peers (duplicates included)
+		directCount   int // Number of transactions sent directly to peers (duplicates included)
+		annCount      int // Number of transactions announced across all peers (duplicates included)
+		withheldCount int // Number of transactions withheld from peers by the policy (duplicates included)
 
 		txset = make(map[*ethPeer][]common.Hash) // Set peer->hash to transfer directly
 		annos = make(map[*ethPeer][]common.Hash) // Set peer->hash to announce
@@ -934,6 +1104,13 @@ func (h *handler) BroadcastTransactions(txs types.Transactions) {
 		hash   = make([]byte, 32)
 	)
 	for _, tx := range txs {
+		// Conditional transactions are only checked by the local pool and miner,
+		// peers would include them unconditionally. SendTx only accepts them on
+		// mining nodes, so they never need to leave this node.
+		if tx.Options() != nil {
+			condTxs++
+			continue
+		}
 		var maybeDirect bool
 		switch {
 		case tx.Type() == types.BlobTxType:
@@ -943,26 +1120,37 @@ func (h *handler) BroadcastTransactions(txs types.Transactions) {
 		default:
 			maybeDirect = true
 		}
-		// Send the transaction (if it's small enough) directly to a subset of
-		// the peers that have not received it yet, ensuring that the flow of
-		// transactions is grouped by account to (try and) avoid nonce gaps.
+		from, _ := types.Sender(signer, tx) // Ignore error, we only use the addr for the policy and as a propagation target splitter
+		decision := h.txBroadcastPolicy.Decide(tx, from)
+
+		// Unless the policy says otherwise, send the transaction (if it's small
+		// enough) directly to a subset of the peers that have not received it
+		// yet, ensuring that the flow of transactions is grouped by account to
+		// (try and) avoid nonce gaps.
 		//
 		// To do this, we hash the local enode IW with together with a peer's
 		// enode ID together with the transaction sender and broadcast if
 		// `sha(self, peer, sender) mod peers < sqrt(peers)`.
 		for _, peer := range h.peers.peersWithoutTransaction(tx.Hash()) {
 			var broadcast bool
-			if maybeDirect {
-				hasher.Reset()
-				hasher.Write(h.nodeID.Bytes())
-				hasher.Write(peer.Node().ID().Bytes())
-
-				from, _ := types.Sender(signer, tx) // Ignore error, we only use the addr as a propagation target splitter
-				hasher.Write(from.Bytes())
-
-				hasher.Read(hash)
-				if new(big.Int).Mod(new(big.Int).SetBytes(hash), total).Cmp(direct) < 0 {
-					broadcast = true
+			switch decision.For(h.txPeerClass(peer)) {
+			case txbroadcast.Withhold:
+				withheldCount++
+				continue
+			case txbroadcast.Announce:
+			case txbroadcast.Send:
+				broadcast = maybeDirect
+			default:
+				if maybeDirect {
+					hasher.Reset()
+					hasher.Write(h.nodeID.Bytes())
+					hasher.Write(peer.Node().ID().Bytes())
+					hasher.Write(from.Bytes())
+
+					hasher.Read(hash)
+					if new(big.Int).Mod(new(big.Int).SetBytes(hash), total).Cmp(direct) < 0 {
+						broadcast = true
+					}
 				}
 			}
 			if broadcast {
@@ -980,8 +1168,22 @@ func (h *handler) BroadcastTransactions(txs types.Transactions) {
 		annCount += len(hashes)
 		peer.AsyncSendPooledTransactionHashes(hashes)
 	}
-	log.Debug("Distributed transactions", "plaintxs", len(txs)-blobTxs-largeTxs, "blobtxs", blobTxs, "largetxs", largeTxs,
-		"bcastpeers", len(txset), "bcastcount", directCount, "annpeers", len(annos), "anncount", annCount)
+	log.Debug("Distributed transactions", "plaintxs", len(txs)-blobTxs-largeTxs-condTxs, "blobtxs", blobTxs, "largetxs", largeTxs, "condtxs", condTxs,
+		"bcastpeers", len(txset), "bcastcount", directCount, "annpeers", len(annos), "anncount", annCount, "withheld", withheldCount)
+}
+
+// txPeerClass returns the class of the given peer for the transaction
+// broadcast policy. EVN flagged peers are classed as EVN even if they are
+// proxied validators, so they keep receiving no transactions by default.
+func (h *handler) txPeerClass(peer *ethPeer) txbroadcast.PeerClass {
+	switch {
+	case peer.EVNPeerFlag.Load():
+		return txbroadcast.EVN
+	case h.isProxyedValidatorPeer(peer.Node().ID()):
+		return txbroadcast.Validator
+	default:
+		return txbroadcast.Regular
+	}
 }
 
 // ReannounceTransactions will announce a batch of local pending transactions
@@ -1033,6 +1235,24 @@ func (h *handler) BroadcastVote(vote *types.VoteEnvelope) {
 	log.Debug("Vote broadcast", "vote packs", directPeers, "broadcast vote", directCount)
 }
 
+// RelayProxyedVote propagates a vote received from a proxyed validator to all
+// peers that don't know it yet. Unlike BroadcastVote, it doesn't wait for the
+// vote pool and skips the TD filter, since the votes of the validator behind the
+// sentry must reach the network as soon as possible. The vote must have been
+// verified by the caller.
+func (h *handler) RelayProxyedVote(vote *types.VoteEnvelope) {
+	var relayed int
+	for _, peer := range h.peers.peersWithoutVote(vote.Hash()) {
+		if h.isProxyedValidatorPeer(peer.NodeID()) {
+			continue
+		}
+		peer.bscExt.AsyncSendVotes([]*types.VoteEnvelope{vote})
+		relayed++
+	}
+	proxyedVoteRelayMeter.Mark(1)
+	log.Debug("Relayed proxyed validator vote", "hash", vote.Hash(), "target", vote.Data.TargetNumber, "recipients", relayed)
+}
+
 // minedBroadcastLoop sends mined blocks to connected peers.
 func (h *handler) minedBroadcastLoop() {
 	defer h.wg.Done()
@@ -1098,6 +1318,31 @@ func (h *handler) voteBroadcastLoop() {
 	}
 }
 
+// invalidVoteLoop penalizes the peers that relayed votes rejected by the vote pool.
+func (h *handler) invalidVoteLoop() {
+	defer h.wg.Done()
+	for {
+		select {
+		case event := <-h.invalidVoteCh:
+			id, ok := h.voteSources.Get(event.Vote.Hash())
+			if !ok {
+				continue
+			}
+			peer := h.peers.peer(id)
+			if peer == nil {
+				continue
+			}
+			if event.BadSignature {
+				peer.Peer.Penalize(p2p.PenaltyFatal, "forged vote signature")
+			} else {
+				peer.Peer.Penalize(p2p.PenaltyMinor, "unverifiable vote")
+			}
+		case <-h.invalidVoteSub.Err():
+			return
+		}
+	}
+}
+
 // enableSyncedFeatures enables the post-sync functionalities when the initial
 // sync is finished.
 func (h *handler) enableSyncedFeatures() {
@@ -61,6 +61,36 @@ func (h *bscHandler) Handle(peer *bsc.Peer, packet bsc.Packet) error {
 // handleVotesBroadcast is invoked from a peer's message handler when it transmits a
 // votes broadcast for the local node to process.
 func (h *bscHandler) handleVotesBroadcast(peer *bsc.Peer, votes []*types.VoteEnvelope) error {
+	// Nodes without fast finality have no vote pool, drop the votes.
+	if h.votepool == nil {
+		return nil
+	}
+	var addr string
+	if peer.RemoteAddr() != nil {
+		addr = peer.RemoteAddr().String()
+	}
+	// Remember the sender, so it can be penalized if a vote turns out to be invalid.
+	for _, vote := range votes {
+		h.arrivals.RecordVote(peer.ID(), addr, vote)
+		if !h.voteSources.Contains(vote.Hash()) {
+			h.voteSources.Add(vote.Hash(), peer.ID())
+		}
+	}
+	// Votes of the validator behind this sentry skip the rate limit and get relayed
+	// with priority. They are verified first, so the sentry can't be used to spread
+	// invalid votes. The ones failing, e.g. for blocks not known yet, are left to
+	// the vote pool, which broadcasts them once verified.
+	if (*handler)(h).isProxyedValidatorPeer(peer.Peer.ID()) {
+		for _, vote := range votes {
+			h.votepool.PutVote(vote)
+			if err := h.verifyProxyedVote(vote); err != nil {
+				peer.Log().Debug("Proxyed validator vote not relayed", "hash", vote.Hash(), "err", err)
+				continue
+			}
+			(*handler)(h).RelayProxyedVote(vote)
+		}
+		return nil
+	}
 	if peer.IsOverLimitAfterReceiving() {
 		return nil
 	}
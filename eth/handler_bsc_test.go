@@ -1,6 +1,7 @@
 package eth
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 	"time"
@@ -257,3 +258,204 @@ func testRecvVotes(t *testing.T, protocol uint) {
 		t.Errorf("no NewVotesEvent received within 2 seconds")
 	}
 }
+
+// testSentryPeer is a simulated remote peer connected to the handler over both
+// the `eth` and the `bsc` protocols.
+type testSentryPeer struct {
+	eth     *eth.Peer
+	bsc     *bsc.Peer
+	backend *testBscHandler
+	closers []func()
+}
+
+func (p *testSentryPeer) close() {
+	for i := len(p.closers) - 1; i >= 0; i-- {
+		p.closers[i]()
+	}
+}
+
+// connectSentryPeer connects a simulated peer with the given node ID to the
+// handler and starts collecting the votes it receives.
+func connectSentryPeer(t *testing.T, handler *testHandler, id enode.ID) *testSentryPeer {
+	protos := []p2p.Protocol{{Name: "eth", Version: eth.ETH68}, {Name: "bsc", Version: bsc.Bsc1}}
+	caps := []p2p.Cap{{Name: "eth", Version: eth.ETH68}, {Name: "bsc", Version: bsc.Bsc1}}
+
+	p2pEthSrc, p2pEthSink := p2p.MsgPipe()
+	p2pBscSrc, p2pBscSink := p2p.MsgPipe()
+
+	localEth := eth.NewPeer(eth.ETH68, p2p.NewPeerWithProtocols(id, protos, "", caps), p2pEthSrc, nil)
+	remoteEth := eth.NewPeer(eth.ETH68, p2p.NewPeerWithProtocols(handler.handler.nodeID, protos, "", caps), p2pEthSink, nil)
+	localBsc := bsc.NewPeer(bsc.Bsc1, p2p.NewPeerWithProtocols(id, protos, "", caps), p2pBscSrc)
+	remoteBsc := bsc.NewPeer(bsc.Bsc1, p2p.NewPeerWithProtocols(handler.handler.nodeID, protos, "", caps), p2pBscSink)
+
+	peer := &testSentryPeer{
+		eth:     remoteEth,
+		bsc:     remoteBsc,
+		backend: new(testBscHandler),
+		closers: []func(){
+			func() { p2pEthSrc.Close(); p2pEthSink.Close() },
+			func() { p2pBscSrc.Close(); p2pBscSink.Close() },
+			localEth.Close, remoteEth.Close, localBsc.Close, remoteBsc.Close,
+		},
+	}
+	go (*bscHandler)(handler.handler).RunPeer(localBsc, func(peer *bsc.Peer) error {
+		return bsc.Handle((*bscHandler)(handler.handler), peer)
+	})
+	if err := remoteBsc.Handshake(); err != nil {
+		t.Fatalf("failed to run bsc handshake: %v", err)
+	}
+	go handler.handler.runEthPeer(localEth, func(peer *eth.Peer) error {
+		return eth.Handle((*ethHandler)(handler.handler), peer)
+	})
+	var (
+		genesis = handler.chain.Genesis()
+		head    = handler.chain.CurrentBlock()
+		td      = handler.chain.GetTd(head.Hash(), head.Number.Uint64())
+	)
+	if err := remoteEth.Handshake(1, td, head.Hash(), genesis.Hash(), forkid.NewIDWithChain(handler.chain), forkid.NewFilter(handler.chain), nil); err != nil {
+		t.Fatalf("failed to run eth handshake: %v", err)
+	}
+	go bsc.Handle(peer.backend, remoteBsc)
+
+	// Wait until the peer is fully registered
+	for i := 0; handler.handler.peers.peer(id.String()) == nil; i++ {
+		if i == 100 {
+			t.Fatalf("peer %v not registered", id)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	return peer
+}
+
+func newTestVote(target uint64) *types.VoteEnvelope {
+	return &types.VoteEnvelope{
+		Data: &types.VoteData{
+			TargetNumber: target,
+			TargetHash:   common.Hash{byte(target)},
+		},
+	}
+}
+
+// Tests that a sentry relays the votes of its proxyed validator to the other
+// peers right away, while votes from ordinary peers wait for the vote pool.
+func TestSentryRelayProxyedVotes(t *testing.T) {
+	t.Parallel()
+
+	handler := newTestHandler()
+	defer handler.close()
+
+	validatorID, otherID := enode.ID{0x11}, enode.ID{0x22}
+	handler.handler.proxyedValidatorNodeIDMap[validatorID] = struct{}{}
+
+	invalid := newTestVote(3)
+	verify := func(vote *types.VoteEnvelope) error {
+		if vote.Hash() == invalid.Hash() {
+			return errors.New("invalid vote")
+		}
+		return nil
+	}
+	handler.votepool.verify = verify
+	handler.handler.verifyProxyedVote = verify
+
+	validator := connectSentryPeer(t, handler, validatorID)
+	defer validator.close()
+	other := connectSentryPeer(t, handler, otherID)
+	defer other.close()
+
+	bcasts := make(chan []*types.VoteEnvelope, 16)
+	sub := other.backend.voteBroadcasts.Subscribe(bcasts)
+	defer sub.Unsubscribe()
+
+	// The handler is not started, so there is no vote broadcast loop and a vote
+	// from an ordinary peer must not be forwarded.
+	plain := newTestVote(1)
+	other.bsc.AsyncSendVotes([]*types.VoteEnvelope{plain})
+	select {
+	case votes := <-bcasts:
+		t.Fatalf("unexpected vote relayed from ordinary peer: %x", votes[0].Hash())
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	// An invalid vote from the proxyed validator must not be relayed.
+	validator.bsc.AsyncSendVotes([]*types.VoteEnvelope{invalid})
+	select {
+	case votes := <-bcasts:
+		t.Fatalf("unexpected invalid vote relayed from proxyed validator: %x", votes[0].Hash())
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	// A valid vote from the proxyed validator is relayed immediately.
+	vote := newTestVote(2)
+	validator.bsc.AsyncSendVotes([]*types.VoteEnvelope{vote})
+	select {
+	case votes := <-bcasts:
+		if len(votes) != 1 || votes[0].Hash() != vote.Hash() {
+			t.Fatalf("relayed wrong votes: %v", votes)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("proxyed validator vote not relayed")
+	}
+	if votes := handler.votepool.GetVotes(); len(votes) != 2 {
+		t.Errorf("vote pool size mismatch: have %d, want 2", len(votes))
+	}
+}
+
+// Tests that the sentry notices the proxyed validator going away.
+func TestSentryProxyedValidatorDisconnect(t *testing.T) {
+	t.Parallel()
+
+	handler := newTestHandler()
+	defer handler.close()
+
+	validatorID := enode.ID{0x33}
+	handler.handler.proxyedValidatorNodeIDMap[validatorID] = struct{}{}
+
+	validator := connectSentryPeer(t, handler, validatorID)
+	if !handler.handler.isProxyedValidatorPeer(validatorID) {
+		t.Fatal("validator not recognised as proxyed")
+	}
+	validator.close()
+	for i := 0; handler.handler.peers.peer(validatorID.String()) != nil; i++ {
+		if i == 100 {
+			t.Fatal("proxyed validator not unregistered after disconnect")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestVerifyBlocksByRange(t *testing.T) {
+	var (
+		parent = &types.Header{Number: common.Big1}
+		child  = &types.Header{Number: common.Big2, ParentHash: parent.Hash()}
+		other  = &types.Header{Number: common.Big1, Extra: []byte{1}}
+		blocks = func(headers ...*types.Header) []*bsc.BlockData {
+			res := make([]*bsc.BlockData, len(headers))
+			for i, header := range headers {
+				res[i] = &bsc.BlockData{Header: header}
+			}
+			return res
+		}
+	)
+	tests := []struct {
+		blocks []*bsc.BlockData
+		height uint64
+		hash   common.Hash
+		count  uint64
+		ok     bool
+	}{
+		{blocks(child, parent), 2, child.Hash(), 2, true},
+		{blocks(child, parent), 2, common.Hash{}, 2, true},
+		{blocks(child), 2, child.Hash(), 2, true},
+		{nil, 2, child.Hash(), 2, false},
+		{blocks(child, parent), 2, child.Hash(), 1, false},
+		{blocks(child, parent), 2, parent.Hash(), 2, false},
+		{blocks(child, parent), 3, common.Hash{}, 2, false},
+		{blocks(child, other), 2, child.Hash(), 2, false},
+	}
+	for i, tt := range tests {
+		err := verifyBlocksByRange(tt.blocks, tt.height, tt.hash, tt.count)
+		if (err == nil) != tt.ok {
+			t.Errorf("test %d: unexpected result: %v", i, err)
+		}
+	}
+}
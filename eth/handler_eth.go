@@ -25,6 +25,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/arrivals"
 	"github.com/ethereum/go-ethereum/eth/protocols/eth"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/p2p/enode"
@@ -106,13 +107,18 @@ func (h *ethHandler) handleBlockAnnounces(peer *eth.Peer, hashes []common.Hash,
 	for i := 0; i < len(unknownHashes); i++ {
 		h.blockFetcher.Notify(peer.ID(), unknownHashes[i], unknownNumbers[i], time.Now(), peer.RequestOneHeader, peer.RequestBodies)
 	}
-	for _, hash := range hashes {
+	var addr string
+	if peer.RemoteAddr() != nil {
+		addr = peer.RemoteAddr().String()
+	}
+	for i, hash := range hashes {
+		h.arrivals.Record(arrivals.Event{Kind: arrivals.Announce, Hash: hash, Number: numbers[i], Peer: peer.ID(), Addr: addr})
+
 		stats := h.chain.GetBlockStats(hash)
 		if stats.RecvNewBlockHashTime.Load() == 0 {
 			stats.RecvNewBlockHashTime.Store(time.Now().UnixMilli())
-			addr := peer.RemoteAddr()
-			if addr != nil {
-				stats.RecvNewBlockHashFrom.Store(addr.String())
+			if addr != "" {
+				stats.RecvNewBlockHashFrom.Store(addr)
 			}
 		}
 	}
@@ -132,12 +138,18 @@ func (h *ethHandler) handleBlockBroadcast(peer *eth.Peer, packet *eth.NewBlockPa
 	// Schedule the block for import
 	log.Debug("handleBlockBroadcast", "peer", peer.ID(), "block", block.Number(), "hash", block.Hash())
 	h.blockFetcher.Enqueue(peer.ID(), block)
+
+	var addr string
+	if peer.RemoteAddr() != nil {
+		addr = peer.RemoteAddr().String()
+	}
+	h.arrivals.RecordBlock(peer.ID(), addr, block)
+
 	stats := h.chain.GetBlockStats(block.Hash())
 	if stats.RecvNewBlockTime.Load() == 0 {
 		stats.RecvNewBlockTime.Store(time.Now().UnixMilli())
-		addr := peer.RemoteAddr()
-		if addr != nil {
-			stats.RecvNewBlockFrom.Store(addr.String())
+		if addr != "" {
+			stats.RecvNewBlockFrom.Store(addr)
 		}
 	}
 
@@ -17,6 +17,7 @@
 package eth
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
 	"strconv"
@@ -25,15 +26,19 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/forkid"
 	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/arrivals"
 	"github.com/ethereum/go-ethereum/eth/ethconfig"
 	"github.com/ethereum/go-ethereum/eth/protocols/bsc"
 	"github.com/ethereum/go-ethereum/eth/protocols/eth"
+	"github.com/ethereum/go-ethereum/eth/txbroadcast"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/p2p/enode"
@@ -531,6 +536,226 @@ func testTransactionPropagation(t *testing.T, protocol uint) {
 	}
 }
 
+// Tests that the transaction broadcast policy decides per transaction and peer
+// class whether transactions are sent, announced or withheld.
+func TestTransactionBroadcastPolicy(t *testing.T) {
+	t.Parallel()
+
+	var (
+		withheld  = common.Address{0x01}
+		announced = common.Address{0x02}
+		sent      = common.Address{0x03}
+
+		validator = enode.ID{0x01}
+		regular   = enode.ID{0x02}
+	)
+	policy, err := txbroadcast.New(txbroadcast.Config{Rules: []txbroadcast.Rule{
+		{To: []common.Address{withheld}, Action: txbroadcast.Withhold},
+		{To: []common.Address{announced}, Action: txbroadcast.Announce},
+		{To: []common.Address{sent}, Peers: []txbroadcast.PeerClass{txbroadcast.Validator}, Action: txbroadcast.Send},
+		{To: []common.Address{sent}, Action: txbroadcast.Announce},
+	}})
+	if err != nil {
+		t.Fatalf("failed to create policy: %v", err)
+	}
+	source := newTestHandler()
+	defer source.close()
+	source.handler.txBroadcastPolicy = policy
+	source.handler.proxyedValidatorNodeIDMap[validator] = struct{}{}
+
+	deliveries := connectTxPolicyPeers(t, source, validator, regular)
+
+	// Add a transaction to each recipient to the pool and check the deliveries
+	txs := make(map[common.Address]*types.Transaction)
+	for i, to := range []common.Address{withheld, announced, sent} {
+		tx := types.NewTransaction(uint64(i), to, big.NewInt(0), 100000, big.NewInt(0), nil)
+		txs[to], _ = types.SignTx(tx, types.HomesteadSigner{}, testKey)
+	}
+	source.txpool.Add([]*types.Transaction{txs[withheld], txs[announced], txs[sent]}, false)
+
+	var (
+		want = map[txDelivery]bool{ // true if broadcast, false if announced
+			{validator, txs[announced].Hash()}: false,
+			{regular, txs[announced].Hash()}:   false,
+			{validator, txs[sent].Hash()}:      true,
+			{regular, txs[sent].Hash()}:        false,
+		}
+		have = collectTxDeliveries(deliveries, time.Second)
+	)
+	for res, broadcast := range want {
+		got, ok := have[res]
+		switch {
+		case !ok:
+			t.Errorf("peer %v: transaction %x not propagated", res.peer, res.hash)
+		case got != broadcast:
+			t.Errorf("peer %v: transaction %x broadcast mismatch: have %v, want %v", res.peer, res.hash, got, broadcast)
+		}
+	}
+	for res := range have {
+		if res.hash == txs[withheld].Hash() {
+			t.Errorf("peer %v: withheld transaction propagated", res.peer)
+		}
+	}
+}
+
+// Tests that without a configured policy, transactions are withheld from EVN
+// peers, including proxied validators flagged as EVN peers, as before the
+// policy was introduced.
+func TestTransactionBroadcastDefaultPolicyEVN(t *testing.T) {
+	t.Parallel()
+
+	var (
+		validator = enode.ID{0x01}
+		regular   = enode.ID{0x02}
+	)
+	source := newTestHandler()
+	defer source.close()
+	source.handler.proxyedValidatorNodeIDMap[validator] = struct{}{}
+
+	deliveries := connectTxPolicyPeers(t, source, validator, regular)
+	source.handler.peers.peer(validator.String()).EVNPeerFlag.Store(true)
+
+	tx := types.NewTransaction(0, common.Address{0x01}, big.NewInt(0), 100000, big.NewInt(0), nil)
+	tx, _ = types.SignTx(tx, types.HomesteadSigner{}, testKey)
+	source.txpool.Add([]*types.Transaction{tx}, false)
+
+	have := collectTxDeliveries(deliveries, time.Second)
+	if _, ok := have[txDelivery{regular, tx.Hash()}]; !ok {
+		t.Errorf("transaction not propagated to regular peer")
+	}
+	if _, ok := have[txDelivery{validator, tx.Hash()}]; ok {
+		t.Errorf("transaction propagated to EVN flagged proxied validator")
+	}
+}
+
+// Tests that conditional transactions are kept local, as their conditions are
+// not part of the wire encoding and even validator peers would drop them.
+func TestConditionalTransactionRelay(t *testing.T) {
+	t.Parallel()
+
+	var (
+		validator = enode.ID{0x01}
+		regular   = enode.ID{0x02}
+	)
+	source := newTestHandler()
+	defer source.close()
+	source.handler.proxyedValidatorNodeIDMap[validator] = struct{}{}
+
+	deliveries := connectTxPolicyPeers(t, source, validator, regular)
+
+	var (
+		plain, _ = types.SignTx(types.NewTransaction(0, common.Address{0x01}, big.NewInt(0), 100000, big.NewInt(0), nil), types.HomesteadSigner{}, testKey)
+		cond, _  = types.SignTx(types.NewTransaction(1, common.Address{0x01}, big.NewInt(0), 100000, big.NewInt(0), nil), types.HomesteadSigner{}, testKey)
+		max      = hexutil.Uint64(1000)
+	)
+	cond = cond.WithOptions(&types.TransactionOpts{BlockNumberMax: &max})
+	source.txpool.Add([]*types.Transaction{plain, cond}, false)
+
+	have := collectTxDeliveries(deliveries, time.Second)
+	for _, peer := range []enode.ID{validator, regular} {
+		if _, ok := have[txDelivery{peer, plain.Hash()}]; !ok {
+			t.Errorf("peer %v: transaction not propagated", peer)
+		}
+		if _, ok := have[txDelivery{peer, cond.Hash()}]; ok {
+			t.Errorf("peer %v: conditional transaction propagated", peer)
+		}
+	}
+}
+
+// txDelivery is a transaction received by a test peer.
+type txDelivery struct {
+	peer enode.ID
+	hash common.Hash
+}
+
+// txDeliveryBatch is a batch of transactions received by a test peer, either
+// broadcast in full or announced.
+type txDeliveryBatch struct {
+	peer      enode.ID
+	hashes    []common.Hash
+	broadcast bool
+}
+
+// connectTxPolicyPeers connects test peers with the given IDs to the source
+// handler and streams the transactions they receive.
+func connectTxPolicyPeers(t *testing.T, source *testHandler, ids ...enode.ID) <-chan txDeliveryBatch {
+	deliveries := make(chan txDeliveryBatch, 16)
+	quit := make(chan struct{})
+	t.Cleanup(func() { close(quit) })
+
+	for _, id := range ids {
+		p2pSrc, p2pSink := p2p.MsgPipe()
+		t.Cleanup(func() {
+			p2pSrc.Close()
+			p2pSink.Close()
+		})
+		src := eth.NewPeer(eth.ETH68, p2p.NewPeerPipe(id, "", nil, p2pSrc), p2pSrc, source.txpool)
+		sink := eth.NewPeer(eth.ETH68, p2p.NewPeerPipe(enode.ID{}, "", nil, p2pSink), p2pSink, nil)
+		t.Cleanup(func() {
+			src.Close()
+			sink.Close()
+		})
+		go source.handler.runEthPeer(src, func(peer *eth.Peer) error {
+			return eth.Handle((*ethHandler)(source.handler), peer)
+		})
+		var (
+			genesis = source.chain.Genesis()
+			head    = source.chain.CurrentBlock()
+			td      = source.chain.GetTd(head.Hash(), head.Number.Uint64())
+		)
+		if err := sink.Handshake(1, td, head.Hash(), genesis.Hash(), forkid.NewIDWithChain(source.chain), forkid.NewFilter(source.chain), nil); err != nil {
+			t.Fatalf("failed to run protocol handshake: %v", err)
+		}
+		backend := new(testEthHandler)
+		anns := make(chan []common.Hash, 16)
+		bcasts := make(chan []*types.Transaction, 16)
+		annSub := backend.txAnnounces.Subscribe(anns)
+		bcastSub := backend.txBroadcasts.Subscribe(bcasts)
+		t.Cleanup(func() {
+			annSub.Unsubscribe()
+			bcastSub.Unsubscribe()
+		})
+		go eth.Handle(backend, sink)
+
+		go func(id enode.ID) {
+			for {
+				select {
+				case hashes := <-anns:
+					deliveries <- txDeliveryBatch{peer: id, hashes: hashes}
+				case txs := <-bcasts:
+					hashes := make([]common.Hash, len(txs))
+					for i, tx := range txs {
+						hashes[i] = tx.Hash()
+					}
+					deliveries <- txDeliveryBatch{peer: id, hashes: hashes, broadcast: true}
+				case <-quit:
+					return
+				}
+			}
+		}(id)
+	}
+	for source.handler.peers.len() < len(ids) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	return deliveries
+}
+
+// collectTxDeliveries gathers the transactions received by the test peers until
+// the timeout, reporting for each whether it was broadcast or announced.
+func collectTxDeliveries(deliveries <-chan txDeliveryBatch, timeout time.Duration) map[txDelivery]bool {
+	have := make(map[txDelivery]bool)
+	for deadline := time.After(timeout); ; {
+		select {
+		case d := <-deliveries:
+			for _, hash := range d.hashes {
+				have[txDelivery{d.peer, hash}] = d.broadcast
+			}
+		case <-deadline:
+			return have
+		}
+	}
+}
+
 // Tests that local pending transactions get propagated to peers.
 func TestTransactionPendingReannounce(t *testing.T) {
 	t.Parallel()
@@ -740,6 +965,79 @@ func testBroadcastMalformedBlock(t *testing.T, protocol uint) {
 	}
 }
 
+// Tests that the first arrival of block announcements and broadcasts is
+// recorded along with the delivering peer, followed by the import.
+func TestBlockArrivals(t *testing.T) {
+	t.Parallel()
+
+	source := newTestHandlerWithBlocks(1)
+	defer source.close()
+
+	recorder := arrivals.New(arrivals.Config{Enabled: true}, source.chain)
+	if err := recorder.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer recorder.Stop()
+	source.handler.arrivals = recorder
+	source.handler.synced.Store(true) // accept the propagated block
+
+	events := make(chan arrivals.Event, 16)
+	sub := recorder.SubscribeEvents(events)
+	defer sub.Unsubscribe()
+
+	p2pSrc, p2pSink := p2p.MsgPipe()
+	defer p2pSrc.Close()
+	defer p2pSink.Close()
+
+	src := eth.NewPeer(eth.ETH68, p2p.NewPeerPipe(enode.ID{1}, "", nil, p2pSrc), p2pSrc, source.txpool)
+	sink := eth.NewPeer(eth.ETH68, p2p.NewPeerPipe(enode.ID{2}, "", nil, p2pSink), p2pSink, source.txpool)
+	defer src.Close()
+	defer sink.Close()
+
+	go source.handler.runEthPeer(src, func(peer *eth.Peer) error {
+		return eth.Handle((*ethHandler)(source.handler), peer)
+	})
+	var (
+		genesis = source.chain.Genesis()
+		td      = source.chain.GetTd(genesis.Hash(), genesis.NumberU64())
+	)
+	if err := sink.Handshake(1, td, genesis.Hash(), genesis.Hash(), forkid.NewIDWithChain(source.chain), forkid.NewFilter(source.chain), nil); err != nil {
+		t.Fatalf("failed to run protocol handshake")
+	}
+	go eth.Handle(new(testEthHandler), sink)
+
+	// Extend the chain of the source handler by one block.
+	gspec := &core.Genesis{
+		Config: params.TestChainConfig,
+		Alloc:  types.GenesisAlloc{testAddr: {Balance: big.NewInt(1000000)}},
+	}
+	_, blocks, _ := core.GenerateChainWithGenesis(gspec, ethash.NewFaker(), 2, nil)
+	block := blocks[1]
+	if block.ParentHash() != source.chain.CurrentBlock().Hash() {
+		t.Fatal("generated block doesn't extend the source chain")
+	}
+	if err := sink.SendNewBlockHashes([]common.Hash{block.Hash()}, []uint64{block.NumberU64()}); err != nil {
+		t.Fatalf("failed to announce block: %v", err)
+	}
+	if err := sink.SendNewBlock(block, new(big.Int).Add(td, new(big.Int).Mul(block.Difficulty(), big.NewInt(2)))); err != nil {
+		t.Fatalf("failed to broadcast block: %v", err)
+	}
+	want := []arrivals.Kind{arrivals.Announce, arrivals.Header, arrivals.Body, arrivals.Import}
+	for _, kind := range want {
+		select {
+		case ev := <-events:
+			if ev.Kind != kind || ev.Hash != block.Hash() || ev.Number != block.NumberU64() {
+				t.Fatalf("wrong event: have %s %x #%d, want %s %x #%d", ev.Kind, ev.Hash, ev.Number, kind, block.Hash(), block.NumberU64())
+			}
+			if kind != arrivals.Import && ev.Peer != src.ID() {
+				t.Errorf("%s: wrong peer: have %s, want %s", kind, ev.Peer, src.ID())
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for %s event", kind)
+		}
+	}
+}
+
 func TestOptionMaxPeersPerIP(t *testing.T) {
 	t.Parallel()
 
@@ -845,3 +1143,33 @@ func TestOptionMaxPeersPerIP(t *testing.T) {
 	}
 	close(doneCh5)
 }
+
+// Tests that the transactions delivered by a peer beyond its rate limit are
+// rejected, without affecting the other peers.
+func TestPeerTxRateLimit(t *testing.T) {
+	t.Parallel()
+
+	handler := newTestHandler()
+	defer handler.close()
+
+	handler.handler.txRateLimiter = txpool.NewRateLimiter[string](0.001, 2, 8)
+
+	txs := make([]*types.Transaction, 3)
+	for i := range txs {
+		txs[i] = types.NewTransaction(uint64(i), common.Address{}, big.NewInt(0), 100000, big.NewInt(0), nil)
+	}
+	errs := handler.handler.addPeerTxs("a", txs)
+	if errs[0] != nil || errs[1] != nil {
+		t.Fatalf("failed to add transactions within the peer burst: %v", errs)
+	}
+	if !errors.Is(errs[2], txpool.ErrPeerRateLimited) {
+		t.Fatalf("peer rate limit error mismatch: have %v, want %v", errs[2], txpool.ErrPeerRateLimited)
+	}
+	if handler.txpool.Has(txs[2].Hash()) {
+		t.Fatalf("rate limited transaction added to the pool")
+	}
+	// Another peer has its own bucket
+	if errs := handler.handler.addPeerTxs("b", txs[2:]); errs[0] != nil {
+		t.Fatalf("failed to add transaction from another peer: %v", errs[0])
+	}
+}
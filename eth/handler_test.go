@@ -321,10 +321,12 @@ func (b *testHandler) close() {
 
 // newTestVotePool creates a mock vote pool.
 type testVotePool struct {
-	pool map[common.Hash]*types.VoteEnvelope // Hash map of collected votes
+	pool   map[common.Hash]*types.VoteEnvelope // Hash map of collected votes
+	verify func(*types.VoteEnvelope) error     // Optional check rejecting invalid votes
 
-	voteFeed event.Feed   // Notification feed to allow waiting for inclusion
-	lock     sync.RWMutex // Protects the vote pool
+	voteFeed        event.Feed   // Notification feed to allow waiting for inclusion
+	invalidVoteFeed event.Feed   // Notification feed of rejected votes
+	lock            sync.RWMutex // Protects the vote pool
 }
 
 // newTestVotePool creates a mock vote pool.
@@ -338,6 +340,10 @@ func (t *testVotePool) PutVote(vote *types.VoteEnvelope) {
 	t.lock.Lock()
 	defer t.lock.Unlock()
 
+	if t.verify != nil && t.verify(vote) != nil {
+		t.invalidVoteFeed.Send(core.InvalidVoteEvent{Vote: vote})
+		return
+	}
 	t.pool[vote.Hash()] = vote
 	t.voteFeed.Send(core.NewVoteEvent{Vote: vote})
 }
@@ -361,6 +367,10 @@ func (t *testVotePool) SubscribeNewVoteEvent(ch chan<- core.NewVoteEvent) event.
 	return t.voteFeed.Subscribe(ch)
 }
 
+func (t *testVotePool) SubscribeInvalidVoteEvent(ch chan<- core.InvalidVoteEvent) event.Subscription {
+	return t.invalidVoteFeed.Subscribe(ch)
+}
+
 var (
 	emptyBlob          = kzg4844.Blob{}
 	emptyBlobCommit, _ = kzg4844.BlobToCommitment(&emptyBlob)
@@ -6,6 +6,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/eth/protocols/trust"
+	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/p2p/enode"
 )
 
@@ -48,6 +49,7 @@ func (h *trustHandler) Handle(peer *trust.Peer, packet trust.Packet) error {
 		return errors.New("verify manager is nil which is unexpected")
 
 	default:
+		peer.Penalize(p2p.PenaltyMajor, "unexpected trust packet")
 		return fmt.Errorf("unexpected trust packet type: %T", packet)
 	}
 }
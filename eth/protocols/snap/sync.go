@@ -113,6 +113,10 @@ var (
 // terminated.
 var ErrCancelled = errors.New("sync cancelled")
 
+// ErrInvalidProof is returned when a range response fails Merkle proof
+// verification, i.e. the remote peer delivered provably invalid data.
+var ErrInvalidProof = errors.New("invalid range proof")
+
 // accountRequest tracks a pending account range request to ensure responses are
 // to actual requests and to validate any security constraints.
 //
@@ -2596,7 +2600,7 @@ func (s *Syncer) OnAccounts(peer SyncPeer, id uint64, hashes []common.Hash, acco
 		logger.Warn("Account range failed proof", "err", err)
 		// Signal this request as failed, and ready for rescheduling
 		s.scheduleRevertAccountRequest(req)
-		return err
+		return fmt.Errorf("%w: %v", ErrInvalidProof, err)
 	}
 	accs := make([]*types.StateAccount, len(accounts))
 	for i, account := range accounts {
@@ -2847,7 +2851,7 @@ func (s *Syncer) OnStorage(peer SyncPeer, id uint64, hashes [][]common.Hash, slo
 			if err != nil {
 				s.scheduleRevertStorageRequest(req) // reschedule request
 				logger.Warn("Storage slots failed proof", "err", err)
-				return err
+				return fmt.Errorf("%w: %v", ErrInvalidProof, err)
 			}
 		} else {
 			// A proof was attached, the response is only partial, check that the
@@ -2858,7 +2862,7 @@ func (s *Syncer) OnStorage(peer SyncPeer, id uint64, hashes [][]common.Hash, slo
 			if err != nil {
 				s.scheduleRevertStorageRequest(req) // reschedule request
 				logger.Warn("Storage range failed proof", "err", err)
-				return err
+				return fmt.Errorf("%w: %v", ErrInvalidProof, err)
 			}
 		}
 	}
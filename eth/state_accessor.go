@@ -183,16 +183,19 @@ func (eth *Ethereum) hashState(ctx context.Context, block *types.Block, reexec u
 	return statedb, func() { tdb.Dereference(block.Root()) }, nil
 }
 
-func (eth *Ethereum) pathState(block *types.Block) (*state.StateDB, func(), error) {
+func (eth *Ethereum) pathState(ctx context.Context, block *types.Block) (*state.StateDB, func(), error) {
 	// Check if the requested state is available in the live chain.
 	statedb, err := eth.blockchain.StateAt(block.Root())
 	if err == nil {
 		return statedb, noopReleaser, nil
 	}
-	// TODO historic state is not supported in path-based scheme.
-	// Fully archive node in pbss will be implemented by relying
-	// on state history, but needs more work on top.
-	return nil, nil, errors.New("historical state not available in path scheme yet")
+	// Resolve the historic state from the state histories if it's still
+	// covered by them.
+	statedb, err = eth.blockchain.HistoricState(ctx, block.Root())
+	if err != nil {
+		return nil, nil, fmt.Errorf("historical state not available in path scheme: %w", err)
+	}
+	return statedb, noopReleaser, nil
 }
 
 // stateAtBlock retrieves the state database associated with a certain block.
@@ -221,7 +224,7 @@ func (eth *Ethereum) stateAtBlock(ctx context.Context, block *types.Block, reexe
 	if eth.blockchain.TrieDB().Scheme() == rawdb.HashScheme {
 		return eth.hashState(ctx, block, reexec, base, readOnly, preferDisk)
 	}
-	return eth.pathState(block)
+	return eth.pathState(ctx, block)
 }
 
 // stateAtTransaction returns the execution environment of a certain transaction.
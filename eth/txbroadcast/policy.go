@@ -0,0 +1,279 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package txbroadcast implements the rule based policy deciding how the
+// transactions of the local pool are propagated to the connected peers.
+package txbroadcast
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Action is the way a transaction is propagated to a peer.
+type Action uint8
+
+const (
+	// Default sends the transaction directly to a square root of the peers and
+	// announces it to the rest.
+	Default Action = iota
+
+	// Send sends the full transaction to the peer. Blob and large transactions
+	// are never sent directly, they are announced instead.
+	Send
+
+	// Announce only announces the hash of the transaction to the peer.
+	Announce
+
+	// Withhold doesn't propagate the transaction to the peer at all.
+	Withhold
+)
+
+var actionNames = []string{"default", "send", "announce", "withhold"}
+
+func (a Action) String() string {
+	if int(a) < len(actionNames) {
+		return actionNames[a]
+	}
+	return fmt.Sprintf("Action(%d)", a)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (a Action) MarshalText() ([]byte, error) {
+	if int(a) >= len(actionNames) {
+		return nil, fmt.Errorf("unknown action %d", a)
+	}
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (a *Action) UnmarshalText(input []byte) error {
+	for i, name := range actionNames {
+		if name == string(input) {
+			*a = Action(i)
+			return nil
+		}
+	}
+	return fmt.Errorf(`unknown action %q, want "default", "send", "announce" or "withhold"`, input)
+}
+
+// PeerClass is the role of a peer, as far as transaction propagation goes.
+type PeerClass uint8
+
+const (
+	// Regular is any peer not falling into one of the classes below.
+	Regular PeerClass = iota
+
+	// EVN is a validator or whitelisted node of the enhanced validator network.
+	EVN
+
+	// Validator is a validator node this node is a sentry of. Proxied validators
+	// that are part of the enhanced validator network are of the EVN class.
+	Validator
+
+	numPeerClasses = iota
+)
+
+var peerClassNames = []string{"regular", "evn", "validator"}
+
+func (c PeerClass) String() string {
+	if int(c) < len(peerClassNames) {
+		return peerClassNames[c]
+	}
+	return fmt.Sprintf("PeerClass(%d)", c)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (c PeerClass) MarshalText() ([]byte, error) {
+	if int(c) >= len(peerClassNames) {
+		return nil, fmt.Errorf("unknown peer class %d", c)
+	}
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (c *PeerClass) UnmarshalText(input []byte) error {
+	for i, name := range peerClassNames {
+		if name == string(input) {
+			*c = PeerClass(i)
+			return nil
+		}
+	}
+	return fmt.Errorf(`unknown peer class %q, want "regular", "evn" or "validator"`, input)
+}
+
+// Rule selects the propagation action for the transactions and peers it
+// matches. Conditions left empty match everything.
+type Rule struct {
+	Name        string           `toml:",omitempty"` // Name of the rule, for logging only
+	From        []common.Address `toml:",omitempty"` // Senders of the transaction
+	To          []common.Address `toml:",omitempty"` // Recipients of the transaction
+	MinGasPrice *big.Int         `toml:",omitempty"` // Minimum gas price (fee cap) of the transaction
+	MaxGasPrice *big.Int         `toml:",omitempty"` // Maximum gas price (fee cap) of the transaction
+	Blob        *bool            `toml:",omitempty"` // Whether to match blob or non-blob transactions only
+	Peers       []PeerClass      `toml:",omitempty"` // Classes of the receiving peer
+	Action      Action
+}
+
+// Config is the transaction broadcast policy. For every transaction and peer
+// the first matching rule decides. If no rule matches, transactions are
+// withheld from EVN peers and propagated by default to all the others.
+type Config struct {
+	Rules []Rule
+}
+
+// Decision holds the propagation action of a transaction for every peer class.
+type Decision [numPeerClasses]Action
+
+// For returns the action for peers of the given class.
+func (d Decision) For(class PeerClass) Action {
+	if int(class) >= len(d) {
+		return Default
+	}
+	return d[class]
+}
+
+// defaultDecision is applied for the peer classes no rule matched.
+var defaultDecision = Decision{Regular: Default, EVN: Withhold, Validator: Default}
+
+type rule struct {
+	name        string
+	from        map[common.Address]struct{}
+	to          map[common.Address]struct{}
+	minGasPrice *big.Int
+	maxGasPrice *big.Int
+	blob        *bool
+	peers       [numPeerClasses]bool
+	action      Action
+}
+
+func (r *rule) matchTx(tx *types.Transaction, from common.Address) bool {
+	if r.from != nil {
+		if _, ok := r.from[from]; !ok {
+			return false
+		}
+	}
+	if r.to != nil {
+		if tx.To() == nil {
+			return false
+		}
+		if _, ok := r.to[*tx.To()]; !ok {
+			return false
+		}
+	}
+	if r.minGasPrice != nil && tx.GasFeeCapIntCmp(r.minGasPrice) < 0 {
+		return false
+	}
+	if r.maxGasPrice != nil && tx.GasFeeCapIntCmp(r.maxGasPrice) > 0 {
+		return false
+	}
+	if r.blob != nil && *r.blob != (tx.Type() == types.BlobTxType) {
+		return false
+	}
+	return true
+}
+
+// Policy decides how transactions are propagated. The nil policy applies the
+// fallback behaviour of an empty configuration.
+type Policy struct {
+	rules []*rule
+}
+
+// New creates a transaction broadcast policy from the given configuration.
+func New(config Config) (*Policy, error) {
+	p := &Policy{rules: make([]*rule, 0, len(config.Rules))}
+	for i, cfg := range config.Rules {
+		name := cfg.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i)
+		}
+		if cfg.Action > Withhold {
+			return nil, fmt.Errorf("rule %s: unknown action %d", name, cfg.Action)
+		}
+		if cfg.MinGasPrice != nil && cfg.MaxGasPrice != nil && cfg.MinGasPrice.Cmp(cfg.MaxGasPrice) > 0 {
+			return nil, fmt.Errorf("rule %s: minimum gas price %v above maximum %v", name, cfg.MinGasPrice, cfg.MaxGasPrice)
+		}
+		r := &rule{
+			name:        name,
+			from:        addressSet(cfg.From),
+			to:          addressSet(cfg.To),
+			minGasPrice: cfg.MinGasPrice,
+			maxGasPrice: cfg.MaxGasPrice,
+			blob:        cfg.Blob,
+			action:      cfg.Action,
+		}
+		if len(cfg.Peers) == 0 {
+			for class := range r.peers {
+				r.peers[class] = true
+			}
+		}
+		for _, class := range cfg.Peers {
+			if int(class) >= numPeerClasses {
+				return nil, fmt.Errorf("rule %s: unknown peer class %d", name, class)
+			}
+			r.peers[class] = true
+		}
+		p.rules = append(p.rules, r)
+	}
+	return p, nil
+}
+
+func addressSet(addrs []common.Address) map[common.Address]struct{} {
+	if len(addrs) == 0 {
+		return nil
+	}
+	set := make(map[common.Address]struct{}, len(addrs))
+	for _, addr := range addrs {
+		set[addr] = struct{}{}
+	}
+	return set
+}
+
+// Decide evaluates the rules for a transaction sent by the given account and
+// returns the propagation action for every peer class.
+func (p *Policy) Decide(tx *types.Transaction, from common.Address) Decision {
+	if p == nil || len(p.rules) == 0 {
+		return defaultDecision
+	}
+	var (
+		decision Decision
+		decided  [numPeerClasses]bool
+		pending  = numPeerClasses
+	)
+	for _, r := range p.rules {
+		if !r.matchTx(tx, from) {
+			continue
+		}
+		for class := range decision {
+			if r.peers[class] && !decided[class] {
+				decision[class], decided[class] = r.action, true
+				pending--
+			}
+		}
+		if pending == 0 {
+			return decision
+		}
+	}
+	for class := range decision {
+		if !decided[class] {
+			decision[class] = defaultDecision[class]
+		}
+	}
+	return decision
+}
@@ -0,0 +1,212 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package txbroadcast
+
+import (
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/naoina/toml"
+)
+
+var (
+	alice    = common.HexToAddress("0xa11ce")
+	bob      = common.HexToAddress("0xb0b")
+	contract = common.HexToAddress("0xc0ffee")
+)
+
+func legacyTx(to *common.Address, gasPrice int64) *types.Transaction {
+	return types.NewTx(&types.LegacyTx{To: to, Gas: 21000, GasPrice: big.NewInt(gasPrice)})
+}
+
+func blobTx() *types.Transaction {
+	return types.NewTx(&types.BlobTx{To: contract, Gas: 21000})
+}
+
+func TestNilPolicy(t *testing.T) {
+	var p *Policy
+	d := p.Decide(legacyTx(&bob, 1), alice)
+	if d.For(Regular) != Default || d.For(Validator) != Default {
+		t.Errorf("regular peers: have %v, want default", d)
+	}
+	if d.For(EVN) != Withhold {
+		t.Errorf("evn peers: have %v, want withhold", d.For(EVN))
+	}
+}
+
+func TestPolicyDecide(t *testing.T) {
+	yes := true
+	p, err := New(Config{Rules: []Rule{
+		{Name: "blobs", Blob: &yes, Action: Announce},
+		{Name: "alice to validators", From: []common.Address{alice}, Peers: []PeerClass{Validator}, Action: Send},
+		{Name: "contract", To: []common.Address{contract}, Action: Withhold},
+		{Name: "cheap", MaxGasPrice: big.NewInt(9), Peers: []PeerClass{Regular}, Action: Withhold},
+		{Name: "expensive", MinGasPrice: big.NewInt(100), Action: Send},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		tx   *types.Transaction
+		from common.Address
+		want Decision
+	}{
+		// Blob transactions are matched by the first rule only.
+		{blobTx(), alice, Decision{Regular: Announce, EVN: Announce, Validator: Announce}},
+		// Alice's transactions go to validators, the rest falls through.
+		{legacyTx(&bob, 10), alice, Decision{Regular: Default, EVN: Withhold, Validator: Send}},
+		{legacyTx(&contract, 10), alice, Decision{Regular: Withhold, EVN: Withhold, Validator: Send}},
+		{legacyTx(&contract, 10), bob, Decision{Regular: Withhold, EVN: Withhold, Validator: Withhold}},
+		// Contract creations don't match recipient rules.
+		{legacyTx(nil, 10), bob, Decision{Regular: Default, EVN: Withhold, Validator: Default}},
+		// Gas price bounds are inclusive.
+		{legacyTx(&bob, 9), bob, Decision{Regular: Withhold, EVN: Withhold, Validator: Default}},
+		{legacyTx(&bob, 100), bob, Decision{Regular: Send, EVN: Send, Validator: Send}},
+		{legacyTx(&bob, 99), bob, Decision{Regular: Default, EVN: Withhold, Validator: Default}},
+	}
+	for i, tt := range tests {
+		if have := p.Decide(tt.tx, tt.from); have != tt.want {
+			t.Errorf("test %d: decision mismatch: have %v, want %v", i, have, tt.want)
+		}
+	}
+}
+
+func TestPolicyInvalid(t *testing.T) {
+	tests := []Rule{
+		{Action: Withhold + 1},
+		{MinGasPrice: big.NewInt(2), MaxGasPrice: big.NewInt(1)},
+		{Peers: []PeerClass{numPeerClasses}},
+	}
+	for i, rule := range tests {
+		if _, err := New(Config{Rules: []Rule{rule}}); err == nil {
+			t.Errorf("test %d: expected error", i)
+		}
+	}
+}
+
+var tomlSettings = toml.Config{
+	NormFieldName: func(rt reflect.Type, key string) string { return key },
+	FieldToKey:    func(rt reflect.Type, field string) string { return field },
+}
+
+// Example policies for common node setups.
+const (
+	// An RPC node doesn't push transactions into the validator network, it
+	// leaves that to the EVN members and keeps the default propagation.
+	rpcPolicy = `
+[[Rules]]
+Name = "announce blobs"
+Blob = true
+Action = "announce"
+`
+	// A validator never gossips transactions itself, except for its own.
+	validatorPolicy = `
+[[Rules]]
+Name = "own transactions"
+From = ["0x00000000000000000000000000000000000a11ce"]
+Action = "send"
+
+[[Rules]]
+Name = "keep the rest"
+Action = "withhold"
+`
+	// A sentry forwards everything paying a decent price to its validator and
+	// doesn't waste bandwidth on dust.
+	sentryPolicy = `
+[[Rules]]
+Name = "feed the validator"
+Peers = ["validator"]
+MinGasPrice = 1000000000
+Blob = false
+Action = "send"
+
+[[Rules]]
+Name = "drop dust"
+MaxGasPrice = 999999999
+Action = "withhold"
+`
+)
+
+func TestPolicyTOML(t *testing.T) {
+	decode := func(input string) *Policy {
+		var config Config
+		if err := tomlSettings.NewDecoder(strings.NewReader(input)).Decode(&config); err != nil {
+			t.Fatalf("failed to decode policy: %v", err)
+		}
+		p, err := New(config)
+		if err != nil {
+			t.Fatalf("invalid policy: %v", err)
+		}
+		return p
+	}
+	var (
+		gwei      = int64(1_000_000_000)
+		rpc       = decode(rpcPolicy)
+		validator = decode(validatorPolicy)
+		sentry    = decode(sentryPolicy)
+	)
+	if d := rpc.Decide(blobTx(), bob); d.For(Regular) != Announce {
+		t.Errorf("rpc: blob tx: have %v, want announce", d.For(Regular))
+	}
+	if d := validator.Decide(legacyTx(&bob, gwei), alice); d.For(Regular) != Send || d.For(EVN) != Send {
+		t.Errorf("validator: own tx: have %v, want send", d)
+	}
+	if d := validator.Decide(legacyTx(&bob, gwei), bob); d.For(Regular) != Withhold {
+		t.Errorf("validator: foreign tx: have %v, want withhold", d.For(Regular))
+	}
+	if d := sentry.Decide(legacyTx(&bob, gwei), bob); d.For(Validator) != Send || d.For(Regular) != Default {
+		t.Errorf("sentry: priced tx: have %v", d)
+	}
+	if d := sentry.Decide(legacyTx(&bob, gwei-1), bob); d.For(Validator) != Withhold || d.For(Regular) != Withhold {
+		t.Errorf("sentry: dust tx: have %v", d)
+	}
+
+	// Round trip the configuration.
+	var config Config
+	tomlSettings.NewDecoder(strings.NewReader(sentryPolicy)).Decode(&config)
+	out, err := tomlSettings.Marshal(&config)
+	if err != nil {
+		t.Fatalf("failed to encode policy: %v", err)
+	}
+	var dec Config
+	if err := tomlSettings.NewDecoder(strings.NewReader(string(out))).Decode(&dec); err != nil {
+		t.Fatalf("failed to decode encoded policy: %v\n%s", err, out)
+	}
+	if !reflect.DeepEqual(config, dec) {
+		t.Errorf("round trip mismatch:\nhave %+v\nwant %+v", dec, config)
+	}
+}
+
+func TestActionText(t *testing.T) {
+	var a Action
+	if err := a.UnmarshalText([]byte("broadcast")); err == nil {
+		t.Error("expected error for unknown action")
+	}
+	for _, want := range []Action{Default, Send, Announce, Withhold} {
+		text, err := want.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := a.UnmarshalText(text); err != nil || a != want {
+			t.Errorf("round trip of %v failed: have %v, err %v", want, a, err)
+		}
+	}
+}
@@ -20,6 +20,8 @@ package ethdb
 import (
 	"io"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb/objstore"
 	"github.com/ethereum/go-ethereum/params"
 )
 
@@ -161,6 +163,15 @@ type AncientWriter interface {
 type FreezerEnv struct {
 	ChainCfg         *params.ChainConfig
 	BlobExtraReserve uint64
+	BlockHistory     uint64 // Number of recent blocks to retain in the freezer, 0 retains all
+
+	BlobKeepAddresses []common.Address // Sidecars of blob transactions from or to these addresses survive pruning
+	BlobKeepBlocks    uint64           // Number of recent blocks whose kept sidecars are retained, 0 retains them as long as the block
+	BlobArchiveDir    string           // Directory to export blob sidecars to before pruning them, empty disables exporting
+
+	ColdStore objstore.Store // Object store to offload old ancient segments to, nil disables offloading
+	ColdAge   uint64         // Number of recent blocks never offloaded to the cold store
+	ColdCache int            // Memory allowance in bytes for cached cold segments
 }
 
 // AncientFreezer defines the help functions for freezing ancient data
@@ -0,0 +1,71 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package objstore
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDirStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewDirStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get("headers/0"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("missing object: have %v, want %v", err, ErrNotFound)
+	}
+	if err := store.Put("headers/0", []byte{1, 2, 3}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put("headers/0", []byte{4, 5}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := store.Get("headers/0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, []byte{4, 5}) {
+		t.Fatalf("wrong object: have %x, want 0405", data)
+	}
+	// No temporary files are left behind.
+	entries, err := os.ReadDir(filepath.Join(dir, "headers"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("wrong number of files: have %d, want 1", len(entries))
+	}
+	if err := store.Delete("headers/0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete("headers/0"); err != nil {
+		t.Fatalf("deleting missing object failed: %v", err)
+	}
+	if _, err := store.Get("headers/0"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("deleted object: have %v, want %v", err, ErrNotFound)
+	}
+	for _, key := range []string{"", "/abs", "../escape", "a//b", "a/./b"} {
+		if err := store.Put(key, nil); err == nil {
+			t.Errorf("invalid key %q accepted", key)
+		}
+	}
+}
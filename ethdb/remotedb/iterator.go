@@ -0,0 +1,106 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package remotedb
+
+import (
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// iteratePage is a page of the key-value pairs returned by debug_dbIterate.
+type iteratePage struct {
+	Keys   []hexutil.Bytes `json:"keys"`
+	Values []hexutil.Bytes `json:"values"`
+	Next   hexutil.Bytes   `json:"next,omitempty"`
+}
+
+// iterator is an ethdb.Iterator over the remote database. The key-value pairs
+// are retrieved page by page via debug_dbIterate while iterating.
+type iterator struct {
+	db     *Database
+	prefix []byte
+	next   []byte // Start position of the next page
+	more   bool   // Whether there are more pages to retrieve
+
+	keys   []hexutil.Bytes
+	values []hexutil.Bytes
+	pos    int
+	err    error
+}
+
+// newIterator creates an iterator of the key-value pairs with the given prefix,
+// starting at the given position.
+func newIterator(db *Database, prefix []byte, start []byte) *iterator {
+	return &iterator{
+		db:     db,
+		prefix: prefix,
+		next:   start,
+		more:   true,
+		pos:    -1,
+	}
+}
+
+// Next moves the iterator to the next key/value pair, retrieving the next page
+// from the remote database if the current one is exhausted.
+func (it *iterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	it.pos++
+	for it.pos >= len(it.keys) {
+		if !it.more {
+			return false
+		}
+		var page iteratePage
+		if err := it.db.remote.Call(&page, "debug_dbIterate", hexutil.Bytes(it.prefix), hexutil.Bytes(it.next), it.db.pageSize); err != nil {
+			it.err = err
+			return false
+		}
+		if len(page.Keys) != len(page.Values) {
+			it.err = errInvalidPage
+			return false
+		}
+		it.keys, it.values, it.pos = page.Keys, page.Values, 0
+		it.next, it.more = page.Next, len(page.Next) > 0
+	}
+	return true
+}
+
+// Error returns any accumulated error.
+func (it *iterator) Error() error {
+	return it.err
+}
+
+// Key returns the key of the current key/value pair, or nil if done.
+func (it *iterator) Key() []byte {
+	if it.pos < 0 || it.pos >= len(it.keys) {
+		return nil
+	}
+	return it.keys[it.pos]
+}
+
+// Value returns the value of the current key/value pair, or nil if done.
+func (it *iterator) Value() []byte {
+	if it.pos < 0 || it.pos >= len(it.values) {
+		return nil
+	}
+	return it.values[it.pos]
+}
+
+// Release releases the retrieved key-value pairs.
+func (it *iterator) Release() {
+	it.keys, it.values, it.more = nil, nil, false
+}
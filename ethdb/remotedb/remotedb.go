@@ -15,21 +15,46 @@
 // along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
 
 // Package remotedb implements the key-value database layer based on a remote geth
-// node. Under the hood, it utilises the `debug_dbGet` method to implement a
-// read-only database.
+// node. Under the hood, it utilises the `debug_dbGet`, `debug_dbGetMany` and
+// `debug_dbIterate` methods to implement a read-only database. The database
+// writes are supported via the `dbwrite` namespace if a separate client of the
+// authenticated endpoint is given, as the remote node serves the write API there
+// only.
 // There really are no guarantees in this database, since the local geth does not
 // exclusive access, but it can be used for basic diagnostics of a remote node.
 package remotedb
 
 import (
+	"errors"
+
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
+// maxBatchItems is the maximum number of items the remote node accepts in a
+// single request.
+const maxBatchItems = 1024
+
+var (
+	// errInvalidPage is returned if the remote node returns a malformed page of
+	// the iterated key-value pairs.
+	errInvalidPage = errors.New("invalid iteration page")
+
+	// errReadOnly is returned on writes if the database has no client of the
+	// authenticated endpoint of the remote node.
+	errReadOnly = errors.New("remote database is read-only")
+
+	// errBatchTooLarge is returned if a batch has more items than the remote
+	// node accepts in a single atomic write.
+	errBatchTooLarge = errors.New("batch too large")
+)
+
 // Database is a key-value lookup for a remote database via debug_dbGet.
 type Database struct {
-	remote *rpc.Client
+	remote   *rpc.Client
+	writer   *rpc.Client // Client of the authenticated endpoint to write via, nil if read-only
+	pageSize int         // Number of key-value pairs retrieved in an iteration request
 }
 
 func (db *Database) BlockStoreReader() ethdb.Reader {
@@ -64,6 +89,37 @@ func (db *Database) Get(key []byte) ([]byte, error) {
 	return resp, nil
 }
 
+// GetMany retrieves the values of the given keys in batches, the value is nil
+// if the key is not present.
+func (db *Database) GetMany(keys [][]byte) ([][]byte, error) {
+	values := make([][]byte, 0, len(keys))
+	for len(keys) > 0 {
+		var (
+			n    = min(len(keys), maxBatchItems)
+			req  = make([]hexutil.Bytes, n)
+			resp []*hexutil.Bytes
+		)
+		for i, key := range keys[:n] {
+			req[i] = key
+		}
+		if err := db.remote.Call(&resp, "debug_dbGetMany", req); err != nil {
+			return nil, err
+		}
+		if len(resp) != n {
+			return nil, errors.New("mismatched number of values")
+		}
+		for _, value := range resp {
+			if value == nil {
+				values = append(values, nil)
+			} else {
+				values = append(values, *value)
+			}
+		}
+		keys = keys[n:]
+	}
+	return values, nil
+}
+
 func (db *Database) HasAncient(kind string, number uint64) (bool, error) {
 	if _, err := db.Ancient(kind, number); err != nil {
 		return false, nil
@@ -81,7 +137,15 @@ func (db *Database) Ancient(kind string, number uint64) ([]byte, error) {
 }
 
 func (db *Database) AncientRange(kind string, start, count, maxBytes uint64) ([][]byte, error) {
-	panic("not supported")
+	var resp []hexutil.Bytes
+	if err := db.remote.Call(&resp, "debug_dbAncientRange", kind, start, count, maxBytes); err != nil {
+		return nil, err
+	}
+	items := make([][]byte, len(resp))
+	for i, item := range resp {
+		items[i] = item
+	}
+	return items, nil
 }
 
 func (db *Database) ItemAmountInAncient() (uint64, error) {
@@ -135,15 +199,47 @@ func (db *Database) AncientOffSet() uint64 {
 }
 
 func (db *Database) Put(key []byte, value []byte) error {
-	panic("not supported")
+	if db.writer == nil {
+		return errReadOnly
+	}
+	return db.writer.Call(nil, "dbwrite_put", hexutil.Bytes(key), hexutil.Bytes(value))
 }
 
 func (db *Database) Delete(key []byte) error {
-	panic("not supported")
+	if db.writer == nil {
+		return errReadOnly
+	}
+	return db.writer.Call(nil, "dbwrite_delete", hexutil.Bytes(key))
 }
 
+// DeleteRange deletes all of the keys in the range [start,end), the keys are
+// iterated and deleted in batches. The deletion is not atomic.
 func (db *Database) DeleteRange(start, end []byte) error {
-	panic("not supported")
+	if db.writer == nil {
+		return errReadOnly
+	}
+	var (
+		it    = db.NewIterator(nil, start)
+		batch = &batch{db: db}
+	)
+	defer it.Release()
+
+	for it.Next() {
+		if end != nil && string(it.Key()) >= string(end) {
+			break
+		}
+		batch.Delete(it.Key())
+		if batch.ValueSize() >= ethdb.IdealBatchSize || len(batch.ops) >= maxBatchItems {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	return batch.Write()
 }
 
 func (db *Database) ModifyAncients(f func(ethdb.AncientWriteOp) error) (int64, error) {
@@ -177,15 +273,15 @@ func (db *Database) SyncAncient() error {
 }
 
 func (db *Database) NewBatch() ethdb.Batch {
-	panic("not supported")
+	return &batch{db: db}
 }
 
 func (db *Database) NewBatchWithSize(size int) ethdb.Batch {
-	panic("not supported")
+	return &batch{db: db}
 }
 
 func (db *Database) NewIterator(prefix []byte, start []byte) ethdb.Iterator {
-	panic("not supported")
+	return newIterator(db, prefix, start)
 }
 
 func (db *Database) Stat() (string, error) {
@@ -206,6 +302,9 @@ func (db *Database) SyncKeyValue() error {
 
 func (db *Database) Close() error {
 	db.remote.Close()
+	if db.writer != nil {
+		db.writer.Close()
+	}
 	return nil
 }
 
@@ -213,8 +312,17 @@ func (db *Database) SetupFreezerEnv(env *ethdb.FreezerEnv) error {
 	panic("not supported")
 }
 
+// New creates a read-only remote database on top of the given client.
 func New(client *rpc.Client) ethdb.Database {
+	return NewWithWriter(client, nil)
+}
+
+// NewWithWriter creates a remote database reading via the given client, and
+// writing via the client of the authenticated endpoint of the same node.
+func NewWithWriter(client *rpc.Client, writer *rpc.Client) ethdb.Database {
 	return &Database{
-		remote: client,
+		remote:   client,
+		writer:   writer,
+		pageSize: maxBatchItems,
 	}
 }
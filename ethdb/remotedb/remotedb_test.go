@@ -0,0 +1,212 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package remotedb
+
+import (
+	"bytes"
+	"context"
+	crand "crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// testBackend is an API backend only serving the database.
+type testBackend struct {
+	ethapi.Backend
+	db ethdb.Database
+}
+
+func (b *testBackend) ChainDb() ethdb.Database { return b.db }
+
+// newTestDatabase creates a remote database connected to a node serving the
+// given database, reading via its HTTP endpoint and writing via its authenticated
+// one if the write API is enabled.
+func newTestDatabase(t *testing.T, disk ethdb.Database, writable bool) *Database {
+	var secret [32]byte
+	if _, err := crand.Read(secret[:]); err != nil {
+		t.Fatalf("Failed to create jwt secret: %v", err)
+	}
+	jwtPath := filepath.Join(t.TempDir(), "jwt_secret")
+	if err := os.WriteFile(jwtPath, []byte(hexutil.Encode(secret[:])), 0600); err != nil {
+		t.Fatalf("Failed to write jwt secret: %v", err)
+	}
+	stack, err := node.New(&node.Config{
+		HTTPHost:    "127.0.0.1",
+		HTTPModules: []string{"debug", "dbwrite"},
+		AuthAddr:    "127.0.0.1",
+		JWTSecret:   jwtPath,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create node: %v", err)
+	}
+	backend := &testBackend{db: disk}
+	apis := []rpc.API{{Namespace: "debug", Service: ethapi.NewDebugAPI(backend)}}
+	if writable {
+		apis = append(apis, rpc.API{Namespace: "dbwrite", Service: ethapi.NewDbWriteAPI(backend), Authenticated: true})
+	}
+	stack.RegisterAPIs(apis)
+	if err := stack.Start(); err != nil {
+		t.Fatalf("Failed to start node: %v", err)
+	}
+	t.Cleanup(func() { stack.Close() })
+
+	client, err := rpc.Dial(stack.HTTPEndpoint())
+	if err != nil {
+		t.Fatalf("Failed to dial HTTP endpoint: %v", err)
+	}
+	writer, err := rpc.DialOptions(context.Background(), stack.HTTPAuthEndpoint(), rpc.WithHTTPAuth(node.NewJWTAuth(secret)))
+	if err != nil {
+		t.Fatalf("Failed to dial authenticated endpoint: %v", err)
+	}
+	db := NewWithWriter(client, writer).(*Database)
+	t.Cleanup(func() { db.Close() })
+
+	db.pageSize = 7 // Exercise the paging
+	return db
+}
+
+func TestRemoteRead(t *testing.T) {
+	disk := rawdb.NewMemoryDatabase()
+	for i := 0; i < 100; i++ {
+		disk.Put([]byte(fmt.Sprintf("a-%03d", i)), []byte(fmt.Sprintf("value-%d", i)))
+		disk.Put([]byte(fmt.Sprintf("b-%03d", i)), []byte(fmt.Sprintf("value-%d", i)))
+	}
+	disk.Put([]byte("empty"), []byte{})
+	db := newTestDatabase(t, disk, false)
+
+	// Batched retrieval, with the missing and empty values distinguished
+	values, err := db.GetMany([][]byte{[]byte("a-001"), []byte("missing"), []byte("empty"), []byte("b-099")})
+	if err != nil {
+		t.Fatalf("Failed to retrieve values: %v", err)
+	}
+	if string(values[0]) != "value-1" || values[1] != nil || values[2] == nil || len(values[2]) != 0 || string(values[3]) != "value-99" {
+		t.Fatalf("Unexpected values: %q", values)
+	}
+	// Iteration with prefix and start position across the pages
+	for _, c := range []struct {
+		prefix, start []byte
+		first, count  int
+	}{
+		{[]byte("a-"), nil, 0, 100},
+		{[]byte("b-"), []byte("050"), 50, 50},
+		{[]byte("b-"), []byte("0505"), 51, 49},
+		{[]byte("c-"), nil, 0, 0},
+	} {
+		var (
+			it = db.NewIterator(c.prefix, c.start)
+			n  int
+		)
+		for it.Next() {
+			want := fmt.Sprintf("%s%03d", c.prefix, c.first+n)
+			if !bytes.Equal(it.Key(), []byte(want)) {
+				t.Fatalf("Unexpected key, want %s, got %s", want, it.Key())
+			}
+			if want := fmt.Sprintf("value-%d", c.first+n); string(it.Value()) != want {
+				t.Fatalf("Unexpected value, want %s, got %s", want, it.Value())
+			}
+			n++
+		}
+		if err := it.Error(); err != nil {
+			t.Fatalf("Failed to iterate: %v", err)
+		}
+		it.Release()
+		if n != c.count {
+			t.Fatalf("Unexpected number of items with prefix %s start %s, want %d, got %d", c.prefix, c.start, c.count, n)
+		}
+	}
+	// The writes are rejected without the write API
+	if err := db.Put([]byte("key"), []byte("value")); err == nil {
+		t.Fatal("Expected write failure without the write API")
+	}
+	// And without the authenticated endpoint to write via
+	readonly := New(db.remote).(*Database)
+	if err := readonly.Put([]byte("key"), []byte("value")); !errors.Is(err, errReadOnly) {
+		t.Fatalf("Write error mismatch: have %v, want %v", err, errReadOnly)
+	}
+}
+
+func TestRemoteWrite(t *testing.T) {
+	disk := rawdb.NewMemoryDatabase()
+	db := newTestDatabase(t, disk, true)
+
+	if err := db.Put([]byte("key"), []byte("value")); err != nil {
+		t.Fatalf("Failed to put: %v", err)
+	}
+	if blob, _ := disk.Get([]byte("key")); string(blob) != "value" {
+		t.Fatalf("Unexpected value: %q", blob)
+	}
+	if err := db.Delete([]byte("key")); err != nil {
+		t.Fatalf("Failed to delete: %v", err)
+	}
+	if ok, _ := disk.Has([]byte("key")); ok {
+		t.Fatal("Deleted key still present")
+	}
+	// Batch writes, the ones with more items than allowed in a single request
+	// are rejected as a whole
+	batch := db.NewBatch()
+	for i := 0; i < maxBatchItems+1; i++ {
+		batch.Put([]byte(fmt.Sprintf("k-%05d", i)), []byte{byte(i)})
+	}
+	if err := batch.Write(); !errors.Is(err, errBatchTooLarge) {
+		t.Fatalf("Batch error mismatch: have %v, want %v", err, errBatchTooLarge)
+	}
+	if ok, _ := disk.Has([]byte("k-00000")); ok {
+		t.Fatal("Oversized batch partially written")
+	}
+	for i := 0; i < 2*maxBatchItems+1; i += maxBatchItems / 2 {
+		batch := db.NewBatch()
+		for j := i; j < min(i+maxBatchItems/2, 2*maxBatchItems+1); j++ {
+			batch.Put([]byte(fmt.Sprintf("k-%05d", j)), []byte{byte(j)})
+		}
+		if err := batch.Write(); err != nil {
+			t.Fatalf("Failed to write batch: %v", err)
+		}
+	}
+	batch = db.NewBatch()
+	batch.Delete([]byte("k-00000"))
+	if err := batch.Write(); err != nil {
+		t.Fatalf("Failed to write batch: %v", err)
+	}
+	for i := 1; i < 2*maxBatchItems+1; i++ {
+		if blob, _ := disk.Get([]byte(fmt.Sprintf("k-%05d", i))); !bytes.Equal(blob, []byte{byte(i)}) {
+			t.Fatalf("Unexpected value of item %d: %x", i, blob)
+		}
+	}
+	if ok, _ := disk.Has([]byte("k-00000")); ok {
+		t.Fatal("Deleted key still present")
+	}
+	// Range deletion, spanning more items than allowed in a single request
+	if err := db.DeleteRange([]byte("k-00100"), []byte("k-01500")); err != nil {
+		t.Fatalf("Failed to delete range: %v", err)
+	}
+	for i := 1; i < 2*maxBatchItems+1; i++ {
+		ok, _ := disk.Has([]byte(fmt.Sprintf("k-%05d", i)))
+		if deleted := i >= 100 && i < 1500; ok == deleted {
+			t.Fatalf("Unexpected presence of item %d: %v", i, ok)
+		}
+	}
+}
@@ -42,7 +42,7 @@ import (
 // The structure can be summarized through this definition:
 //
 //	era1 := Version | block-tuple* | other-entries* | Accumulator | BlockIndex
-//	block-tuple :=  CompressedHeader | CompressedBody | CompressedReceipts | TotalDifficulty
+//	block-tuple :=  CompressedHeader | CompressedBody | CompressedReceipts | CompressedSidecars? | TotalDifficulty
 //
 // Each basic element is its own entry:
 //
@@ -50,10 +50,15 @@ import (
 //	CompressedHeader   = { type: [0x03, 0x00], data: snappyFramed(rlp(header)) }
 //	CompressedBody     = { type: [0x04, 0x00], data: snappyFramed(rlp(body)) }
 //	CompressedReceipts = { type: [0x05, 0x00], data: snappyFramed(rlp(receipts)) }
+//	CompressedSidecars = { type: [0x08, 0x00], data: snappyFramed(rlp(sidecars)) }
 //	TotalDifficulty    = { type: [0x06, 0x00], data: uint256(header.total_difficulty) }
 //	AccumulatorRoot    = { type: [0x07, 0x00], data: accumulator-root }
 //	BlockIndex         = { type: [0x32, 0x66], data: block-index }
 //
+// CompressedSidecars is a BSC extension carrying the blob sidecars of a block.
+// It's only present for blocks after the Cancun fork, so archives of earlier
+// blocks are identical to upstream Era1 files.
+//
 // Accumulator is computed by constructing an SSZ list of header-records of length at most
 // 8192 and then calculating the hash_tree_root of that list.
 //
@@ -109,12 +114,19 @@ func (b *Builder) Add(block *types.Block, receipts types.Receipts, td *big.Int)
 	if err != nil {
 		return err
 	}
-	return b.AddRLP(eh, eb, er, block.NumberU64(), block.Hash(), td, block.Difficulty())
+	var es []byte
+	if sidecars := block.Sidecars(); sidecars != nil {
+		if es, err = rlp.EncodeToBytes(sidecars); err != nil {
+			return err
+		}
+	}
+	return b.AddRLP(eh, eb, er, es, block.NumberU64(), block.Hash(), td, block.Difficulty())
 }
 
 // AddRLP writes a compressed block entry and compressed receipts entry to the
-// underlying e2store file.
-func (b *Builder) AddRLP(header, body, receipts []byte, number uint64, hash common.Hash, td, difficulty *big.Int) error {
+// underlying e2store file. The blob sidecars entry is only written if sidecars
+// is non-nil.
+func (b *Builder) AddRLP(header, body, receipts, sidecars []byte, number uint64, hash common.Hash, td, difficulty *big.Int) error {
 	// Write Era1 version entry before first block.
 	if b.startNum == nil {
 		n, err := b.w.Write(TypeVersion, nil)
@@ -144,6 +156,11 @@ func (b *Builder) AddRLP(header, body, receipts []byte, number uint64, hash comm
 	if err := b.snappyWrite(TypeCompressedReceipts, receipts); err != nil {
 		return err
 	}
+	if sidecars != nil {
+		if err := b.snappyWrite(TypeCompressedSidecars, sidecars); err != nil {
+			return err
+		}
+	}
 
 	// Also write total difficulty, but don't snappy encode.
 	btd := bigToBytes32(td)
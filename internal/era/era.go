@@ -29,6 +29,7 @@ import (
 	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/internal/era/e2store"
 	"github.com/ethereum/go-ethereum/rlp"
@@ -42,6 +43,7 @@ var (
 	TypeCompressedReceipts uint16 = 0x05
 	TypeTotalDifficulty    uint16 = 0x06
 	TypeAccumulator        uint16 = 0x07
+	TypeCompressedSidecars uint16 = 0x08
 	TypeBlockIndex         uint16 = 0x3266
 
 	MaxEra1Size = 8192
@@ -154,6 +156,24 @@ func (e *Era) GetBlockByNumber(num uint64) (*types.Block, error) {
 	return types.NewBlockWithHeader(&header).WithBody(body), nil
 }
 
+// VerifySidecars checks that the blob sidecars carried by a block belong to its
+// blob transactions and match their blob hashes and KZG proofs.
+func VerifySidecars(block *types.Block) error {
+	txs := block.Transactions()
+	for _, sidecar := range block.Sidecars() {
+		if sidecar.BlockHash != block.Hash() {
+			return fmt.Errorf("sidecar of tx %s belongs to block %s, not %d", sidecar.TxHash, sidecar.BlockHash, block.NumberU64())
+		}
+		if sidecar.TxIndex >= uint64(len(txs)) || txs[sidecar.TxIndex].Hash() != sidecar.TxHash || txs[sidecar.TxIndex].Type() != types.BlobTxType {
+			return fmt.Errorf("sidecar of tx %s has no blob transaction in block %d", sidecar.TxHash, block.NumberU64())
+		}
+		if err := core.ValidateBlobSidecar(txs[sidecar.TxIndex].BlobHashes(), sidecar); err != nil {
+			return fmt.Errorf("invalid sidecar of tx %s in block %d: %w", sidecar.TxHash, block.NumberU64(), err)
+		}
+	}
+	return nil
+}
+
 // Accumulator reads the accumulator entry in the Era1 file.
 func (e *Era) Accumulator() (common.Hash, error) {
 	entry, err := e.s.Find(TypeAccumulator)
@@ -187,8 +207,15 @@ func (e *Era) InitialTD() (*big.Int, error) {
 	}
 	off += n
 
-	// Skip over next two records.
-	for i := 0; i < 2; i++ {
+	// Skip over the records up to the total difficulty.
+	for {
+		typ, _, err := e.s.ReadMetadataAt(off)
+		if err != nil {
+			return nil, err
+		}
+		if typ == TypeTotalDifficulty {
+			break
+		}
 		length, err := e.s.LengthAt(off)
 		if err != nil {
 			return nil, err
@@ -24,12 +24,15 @@ import (
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
 )
 
 type testchain struct {
 	headers  [][]byte
 	bodies   [][]byte
 	receipts [][]byte
+	sidecars [][]byte
 	tds      []*big.Int
 }
 
@@ -51,6 +54,12 @@ func TestEra1Builder(t *testing.T) {
 		chain.headers = append(chain.headers, []byte{byte('h'), byte(i)})
 		chain.bodies = append(chain.bodies, []byte{byte('b'), byte(i)})
 		chain.receipts = append(chain.receipts, []byte{byte('r'), byte(i)})
+		// Only the second half of the blocks carries sidecars.
+		var sidecars []byte
+		if i >= 64 {
+			sidecars = []byte{byte('s'), byte(i)}
+		}
+		chain.sidecars = append(chain.sidecars, sidecars)
 		chain.tds = append(chain.tds, big.NewInt(int64(i)))
 	}
 
@@ -63,7 +72,7 @@ func TestEra1Builder(t *testing.T) {
 			hash     = common.Hash{byte(i)}
 			td       = chain.tds[i]
 		)
-		if err = builder.AddRLP(header, body, receipts, uint64(i), hash, td, big.NewInt(1)); err != nil {
+		if err = builder.AddRLP(header, body, receipts, chain.sidecars[i], uint64(i), hash, td, big.NewInt(1)); err != nil {
 			t.Fatalf("error adding entry: %v", err)
 		}
 	}
@@ -113,6 +122,23 @@ func TestEra1Builder(t *testing.T) {
 		if !bytes.Equal(receipts, chain.receipts[i]) {
 			t.Fatalf("mismatched receipts: want %s, got %s", chain.receipts[i], receipts)
 		}
+		// Check sidecars.
+		if chain.sidecars[i] == nil {
+			if it.Sidecars != nil {
+				t.Fatalf("unexpected sidecars for block %d", i)
+			}
+		} else {
+			if it.Sidecars == nil {
+				t.Fatalf("missing sidecars for block %d", i)
+			}
+			sidecars, err := io.ReadAll(it.Sidecars)
+			if err != nil {
+				t.Fatalf("error reading sidecars: %v", err)
+			}
+			if !bytes.Equal(sidecars, chain.sidecars[i]) {
+				t.Fatalf("mismatched sidecars: want %s, got %s", chain.sidecars[i], sidecars)
+			}
+		}
 
 		// Check total difficulty.
 		rawTd, err := io.ReadAll(it.TotalDifficulty)
@@ -143,3 +169,44 @@ func TestEraFilename(t *testing.T) {
 		}
 	}
 }
+
+func TestVerifySidecars(t *testing.T) {
+	t.Parallel()
+
+	newSidecar := func(blob *kzg4844.Blob) *types.BlobTxSidecar {
+		commitment, _ := kzg4844.BlobToCommitment(blob)
+		proof, _ := kzg4844.ComputeBlobProof(blob, commitment)
+		return &types.BlobTxSidecar{
+			Blobs:       []kzg4844.Blob{*blob},
+			Commitments: []kzg4844.Commitment{commitment},
+			Proofs:      []kzg4844.Proof{proof},
+		}
+	}
+	var blob1, blob2 kzg4844.Blob
+	blob2[31] = 1
+	var (
+		sidecar1 = newSidecar(&blob1)
+		sidecar2 = newSidecar(&blob2)
+		tx       = types.NewTx(&types.BlobTx{BlobHashes: sidecar1.BlobHashes()})
+		block    = types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1)}).WithBody(types.Body{Transactions: types.Transactions{tx}})
+	)
+	for i, tt := range []struct {
+		sidecar *types.BlobTxSidecar
+		valid   bool
+	}{
+		{sidecar1, true},
+		// blobs swapped with the ones of another transaction
+		{sidecar2, false},
+		// blob not matching its proof
+		{&types.BlobTxSidecar{Blobs: sidecar2.Blobs, Commitments: sidecar1.Commitments, Proofs: sidecar1.Proofs}, false},
+	} {
+		sidecar := &types.BlobSidecar{BlobTxSidecar: *tt.sidecar, BlockNumber: block.Number(), BlockHash: block.Hash(), TxHash: tx.Hash()}
+		err := VerifySidecars(block.WithSidecars(types.BlobSidecars{sidecar}))
+		if tt.valid && err != nil {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("test %d: invalid sidecar accepted", i)
+		}
+	}
+}
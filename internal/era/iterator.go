@@ -73,7 +73,26 @@ func (it *Iterator) Block() (*types.Block, error) {
 	if err := rlp.Decode(it.inner.Body, &body); err != nil {
 		return nil, err
 	}
-	return types.NewBlockWithHeader(&header).WithBody(body), nil
+	block := types.NewBlockWithHeader(&header).WithBody(body)
+	if it.inner.Sidecars != nil {
+		sidecars, err := it.Sidecars()
+		if err != nil {
+			return nil, err
+		}
+		block = block.WithSidecars(sidecars)
+	}
+	return block, nil
+}
+
+// Sidecars returns the blob sidecars for the iterator's current position, nil
+// if the archive doesn't carry any for the block.
+func (it *Iterator) Sidecars() (types.BlobSidecars, error) {
+	if it.inner.Sidecars == nil {
+		return nil, nil
+	}
+	var sidecars types.BlobSidecars
+	err := rlp.Decode(it.inner.Sidecars, &sidecars)
+	return sidecars, err
 }
 
 // Receipts returns the receipts for the iterator's current position.
@@ -119,6 +138,7 @@ type RawIterator struct {
 	Header          io.Reader
 	Body            io.Reader
 	Receipts        io.Reader
+	Sidecars        io.Reader // nil if the block carries no blob sidecars
 	TotalDifficulty io.Reader
 }
 
@@ -133,7 +153,7 @@ func NewRawIterator(e *Era) (*RawIterator, error) {
 
 // Next moves the iterator to the next block entry. It returns false when all
 // items have been read or an error has halted its progress. Header, Body,
-// Receipts, TotalDifficulty will be set to nil in the case returning false or
+// Receipts, Sidecars, TotalDifficulty will be set to nil in the case returning false or
 // finding an error and should therefore no longer be read from.
 func (it *RawIterator) Next() bool {
 	// Clear old errors.
@@ -166,6 +186,19 @@ func (it *RawIterator) Next() bool {
 		return true
 	}
 	off += n
+	var typ uint16
+	if typ, _, it.err = it.e.s.ReadMetadataAt(off); it.err != nil {
+		it.clear()
+		return true
+	}
+	it.Sidecars = nil
+	if typ == TypeCompressedSidecars {
+		if it.Sidecars, n, it.err = newSnappyReader(it.e.s, TypeCompressedSidecars, off); it.err != nil {
+			it.clear()
+			return true
+		}
+		off += n
+	}
 	if it.TotalDifficulty, _, it.err = it.e.s.ReaderAt(TypeTotalDifficulty, off); it.err != nil {
 		it.clear()
 		return true
@@ -193,5 +226,6 @@ func (it *RawIterator) clear() {
 	it.Header = nil
 	it.Body = nil
 	it.Receipts = nil
+	it.Sidecars = nil
 	it.TotalDifficulty = nil
 }
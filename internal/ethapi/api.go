@@ -38,6 +38,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -283,6 +284,81 @@ func (api *TxPoolAPI) Inspect() map[string]map[string]map[string]string {
 	return content
 }
 
+// RPCTxEvent is an entry in the lifecycle log of a pooled transaction.
+type RPCTxEvent struct {
+	Time   hexutil.Uint64 `json:"time"` // Unix time of the event in milliseconds
+	Type   string         `json:"type"`
+	Reason string         `json:"reason,omitempty"`
+	Error  string         `json:"error,omitempty"`
+}
+
+// GetTransactionHistory returns the lifecycle log of a transaction seen by the
+// pool, explaining why it left the pool if it did. Null is returned if the pool
+// never saw the transaction or already forgot about it.
+func (api *TxPoolAPI) GetTransactionHistory(hash common.Hash) []*RPCTxEvent {
+	events := api.b.TxPoolHistory(hash)
+	if events == nil {
+		return nil
+	}
+	result := make([]*RPCTxEvent, len(events))
+	for i, ev := range events {
+		result[i] = &RPCTxEvent{
+			Time:   hexutil.Uint64(ev.Time.UnixMilli()),
+			Type:   string(ev.Type),
+			Reason: string(ev.Reason),
+			Error:  ev.Error,
+		}
+	}
+	return result
+}
+
+// RPCDroppedTx is a transaction removed from the pool, with the reason.
+type RPCDroppedTx struct {
+	Hash   common.Hash    `json:"hash"`
+	From   common.Address `json:"from"`
+	Nonce  hexutil.Uint64 `json:"nonce"`
+	Reason string         `json:"reason"`
+	Error  string         `json:"error,omitempty"`
+}
+
+// Dropped creates a subscription that is triggered each time a transaction is
+// removed from the pool without having been included in a block.
+func (api *TxPoolAPI) Dropped(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	gopool.Submit(func() {
+		drops := make(chan txpool.DroppedTxsEvent, 128)
+		dropSub := api.b.SubscribeDroppedTxsEvent(drops)
+		defer dropSub.Unsubscribe()
+
+		signer := types.LatestSigner(api.b.ChainConfig())
+		for {
+			select {
+			case ev := <-drops:
+				for _, drop := range ev.Txs {
+					from, _ := types.Sender(signer, drop.Tx)
+					notifier.Notify(rpcSub.ID, &RPCDroppedTx{
+						Hash:   drop.Tx.Hash(),
+						From:   from,
+						Nonce:  hexutil.Uint64(drop.Tx.Nonce()),
+						Reason: string(drop.Reason),
+						Error:  drop.Error,
+					})
+				}
+			case <-rpcSub.Err():
+				return
+			case <-dropSub.Err():
+				return
+			}
+		}
+	})
+	return rpcSub, nil
+}
+
 // EthereumAccountAPI provides an API to access accounts managed by this node.
 // It offers only methods that can retrieve accounts.
 type EthereumAccountAPI struct {
@@ -2209,7 +2285,8 @@ func (api *TransactionAPI) SendRawTransaction(ctx context.Context, input hexutil
 }
 
 // SendRawTransactionConditional will add the signed transaction to the transaction pool.
-// The sender/bundler is responsible for signing the transaction
+// The sender/bundler is responsible for signing the transaction. The conditions
+// are kept locally and not propagated, so only mining nodes accept the call.
 func (api *TransactionAPI) SendRawTransactionConditional(ctx context.Context, input hexutil.Bytes, opts types.TransactionOpts) (common.Hash, error) {
 	tx := new(types.Transaction)
 	if err := tx.UnmarshalBinary(input); err != nil {
@@ -2223,7 +2300,9 @@ func (api *TransactionAPI) SendRawTransactionConditional(ctx context.Context, in
 	if err := TxOptsCheck(opts, header.Number.Uint64(), header.Time, state); err != nil {
 		return common.Hash{}, err
 	}
-	return SubmitTransaction(ctx, api.b, tx)
+	// Keep the conditions on the transaction, the pool drops it once they can't
+	// be met anymore and the miner rechecks them against the block being built
+	return SubmitTransaction(ctx, api.b, tx.WithOptions(&opts))
 }
 
 // Sign calculates an ECDSA signature for:
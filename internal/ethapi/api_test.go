@@ -50,6 +50,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/bloombits"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -632,6 +633,10 @@ func (b testBackend) TxPoolContentFrom(addr common.Address) ([]*types.Transactio
 func (b testBackend) SubscribeNewTxsEvent(events chan<- core.NewTxsEvent) event.Subscription {
 	panic("implement me")
 }
+func (b testBackend) TxPoolHistory(hash common.Hash) []*txpool.TxEvent { panic("implement me") }
+func (b testBackend) SubscribeDroppedTxsEvent(ch chan<- txpool.DroppedTxsEvent) event.Subscription {
+	panic("implement me")
+}
 func (b testBackend) ChainConfig() *params.ChainConfig             { return b.chain.Config() }
 func (b testBackend) Engine() consensus.Engine                     { return b.chain.Engine() }
 func (b testBackend) CurrentValidators() ([]common.Address, error) { return []common.Address{}, nil }
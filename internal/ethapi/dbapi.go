@@ -17,10 +17,23 @@
 package ethapi
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
+const (
+	// maxDbItems is the maximum number of items retrieved or written in a
+	// single database request.
+	maxDbItems = 1024
+
+	// maxDbIterateBytes is the soft limit of the total size of the items
+	// returned in a single iteration request.
+	maxDbIterateBytes = 4 * 1024 * 1024
+)
+
 // DbGet returns the raw value of a key stored in the database.
 func (api *DebugAPI) DbGet(key string) (hexutil.Bytes, error) {
 	blob, err := common.ParseHexOrString(key)
@@ -41,3 +54,138 @@ func (api *DebugAPI) DbAncient(kind string, number uint64) (hexutil.Bytes, error
 func (api *DebugAPI) DbAncients() (uint64, error) {
 	return api.b.ChainDb().BlockStore().Ancients()
 }
+
+// DbGetMany returns the raw values of the given keys stored in the database.
+// The value is null if the key is not present.
+func (api *DebugAPI) DbGetMany(keys []hexutil.Bytes) ([]*hexutil.Bytes, error) {
+	if len(keys) > maxDbItems {
+		return nil, fmt.Errorf("too many keys, max %d", maxDbItems)
+	}
+	var (
+		db     = api.b.ChainDb()
+		values = make([]*hexutil.Bytes, len(keys))
+	)
+	for i, key := range keys {
+		blob, err := db.Get(key)
+		if err != nil {
+			if has, _ := db.Has(key); !has {
+				continue
+			}
+			return nil, err
+		}
+		values[i] = (*hexutil.Bytes)(&blob)
+	}
+	return values, nil
+}
+
+// DbIterateResult is a page of the key-value pairs iterated in the database.
+type DbIterateResult struct {
+	Keys   []hexutil.Bytes `json:"keys"`
+	Values []hexutil.Bytes `json:"values"`
+	Next   hexutil.Bytes   `json:"next,omitempty"` // Start position of the next page, omitted if the iteration is finished
+}
+
+// DbIterate iterates the key-value pairs with the given prefix in the database,
+// starting at the given position. It is a mapping to the `Iteratee.NewIterator`
+// method, the iteration is resumed by passing the returned position as the start
+// of the next request.
+func (api *DebugAPI) DbIterate(prefix hexutil.Bytes, start hexutil.Bytes, limit int) (*DbIterateResult, error) {
+	if limit <= 0 || limit > maxDbItems {
+		limit = maxDbItems
+	}
+	var (
+		it     = api.b.ChainDb().NewIterator(prefix, start)
+		result = &DbIterateResult{Keys: []hexutil.Bytes{}, Values: []hexutil.Bytes{}}
+		size   int
+	)
+	defer it.Release()
+
+	for it.Next() {
+		if len(result.Keys) >= limit || size >= maxDbIterateBytes {
+			// The start position is relative to the prefix, resume right
+			// after the last returned key.
+			last := result.Keys[len(result.Keys)-1]
+			result.Next = append(common.CopyBytes(last[len(prefix):]), 0)
+			break
+		}
+		result.Keys = append(result.Keys, common.CopyBytes(it.Key()))
+		result.Values = append(result.Values, common.CopyBytes(it.Value()))
+		size += len(it.Key()) + len(it.Value())
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// DbAncientRange retrieves multiple items in sequence from the append-only
+// immutable files. It is a mapping to the `AncientReaderOp.AncientRange` method.
+func (api *DebugAPI) DbAncientRange(kind string, start, count, maxBytes uint64) ([]hexutil.Bytes, error) {
+	if count > maxDbItems {
+		count = maxDbItems
+	}
+	if maxBytes == 0 || maxBytes > maxDbIterateBytes {
+		maxBytes = maxDbIterateBytes
+	}
+	blobs, err := api.b.ChainDb().BlockStore().AncientRange(kind, start, count, maxBytes)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]hexutil.Bytes, len(blobs))
+	for i, blob := range blobs {
+		items[i] = blob
+	}
+	return items, nil
+}
+
+// DbWriteAPI offers the methods for writing the database in the dbwrite
+// namespace. Since the database can be corrupted with it, the API is only
+// served on the authenticated RPC endpoint if enabled explicitly.
+type DbWriteAPI struct {
+	b Backend
+}
+
+// NewDbWriteAPI creates a new instance of DbWriteAPI.
+func NewDbWriteAPI(b Backend) *DbWriteAPI {
+	return &DbWriteAPI{b: b}
+}
+
+// DbBatchOp is a single write operation in a database batch.
+type DbBatchOp struct {
+	Key    hexutil.Bytes `json:"key"`
+	Value  hexutil.Bytes `json:"value"`
+	Delete bool          `json:"delete"`
+}
+
+// Put stores the raw value of a key into the database.
+func (api *DbWriteAPI) Put(key hexutil.Bytes, value hexutil.Bytes) error {
+	return api.b.ChainDb().Put(key, value)
+}
+
+// Delete removes a key from the database.
+func (api *DbWriteAPI) Delete(key hexutil.Bytes) error {
+	return api.b.ChainDb().Delete(key)
+}
+
+// Write applies a batch of write operations to the database atomically.
+func (api *DbWriteAPI) Write(ops []DbBatchOp) error {
+	if len(ops) > maxDbItems {
+		return fmt.Errorf("too many operations, max %d", maxDbItems)
+	}
+	batch := api.b.ChainDb().NewBatch()
+	for _, op := range ops {
+		if len(op.Key) == 0 {
+			return errors.New("empty key")
+		}
+		var err error
+		if op.Delete {
+			err = batch.Delete(op.Key)
+		} else {
+			err = batch.Put(op.Key, op.Value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return batch.Write()
+}
@@ -18,15 +18,16 @@
 package web3ext
 
 var Modules = map[string]string{
-	"admin":  AdminJs,
-	"parlia": ParliaJs,
-	"debug":  DebugJs,
-	"eth":    EthJs,
-	"miner":  MinerJs,
-	"net":    NetJs,
-	"rpc":    RpcJs,
-	"txpool": TxpoolJs,
-	"dev":    DevJs,
+	"admin":   AdminJs,
+	"parlia":  ParliaJs,
+	"debug":   DebugJs,
+	"eth":     EthJs,
+	"miner":   MinerJs,
+	"net":     NetJs,
+	"rpc":     RpcJs,
+	"txpool":  TxpoolJs,
+	"dev":     DevJs,
+	"dbwrite": DBWriteJs,
 }
 
 const ParliaJs = `
@@ -76,6 +77,21 @@ const AdminJs = `
 web3._extend({
 	property: 'admin',
 	methods: [
+		new web3._extend.Method({
+			name: 'untrackLocal',
+			call: 'admin_untrackLocal',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'cancelLocal',
+			call: 'admin_cancelLocal',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'setLocalPolicy',
+			call: 'admin_setLocalPolicy',
+			params: 2
+		}),
 		new web3._extend.Method({
 			name: 'addPeer',
 			call: 'admin_addPeer',
@@ -96,6 +112,11 @@ web3._extend({
 			call: 'admin_removeTrustedPeer',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'unban',
+			call: 'admin_unban',
+			params: 1
+		}),
 		new web3._extend.Method({
 			name: 'exportChain',
 			call: 'admin_exportChain',
@@ -154,6 +175,10 @@ web3._extend({
 			name: 'peers',
 			getter: 'admin_peers'
 		}),
+		new web3._extend.Property({
+			name: 'peerScores',
+			getter: 'admin_peerScores'
+		}),
 		new web3._extend.Property({
 			name: 'datadir',
 			getter: 'admin_datadir'
@@ -434,6 +459,18 @@ web3._extend({
 			params: 2,
 			inputFormatter:[web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter],
 		}),
+		new web3._extend.Method({
+			name: 'getAccountHistory',
+			call: 'debug_getAccountHistory',
+			params: 3,
+			inputFormatter: [null, web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter],
+		}),
+		new web3._extend.Method({
+			name: 'getStorageHistory',
+			call: 'debug_getStorageHistory',
+			params: 4,
+			inputFormatter: [null, null, web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter],
+		}),
 		new web3._extend.Method({
 			name: 'dbGet',
 			call: 'debug_dbGet',
@@ -449,6 +486,21 @@ web3._extend({
 			call: 'debug_dbAncients',
 			params: 0
 		}),
+		new web3._extend.Method({
+			name: 'dbGetMany',
+			call: 'debug_dbGetMany',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'dbIterate',
+			call: 'debug_dbIterate',
+			params: 3
+		}),
+		new web3._extend.Method({
+			name: 'dbAncientRange',
+			call: 'debug_dbAncientRange',
+			params: 4
+		}),
 		new web3._extend.Method({
 			name: 'setTrieFlushInterval',
 			call: 'debug_setTrieFlushInterval',
@@ -459,6 +511,11 @@ web3._extend({
 			call: 'debug_getTrieFlushInterval',
 			params: 0
 		}),
+		new web3._extend.Method({
+			name: 'prunedBlockRanges',
+			call: 'debug_prunedBlockRanges',
+			params: 0
+		}),
 	],
 	properties: []
 });
@@ -580,6 +637,16 @@ web3._extend({
 			call: 'eth_getBlockReceipts',
 			params: 1,
 		}),
+		new web3._extend.Method({
+			name: 'gasPriceEstimates',
+			call: 'eth_gasPriceEstimates',
+			params: 0,
+		}),
+		new web3._extend.Method({
+			name: 'getPendingBlockPreview',
+			call: 'eth_getPendingBlockPreview',
+			params: 0,
+		}),
 		new web3._extend.Method({
 			name: 'getBlobSidecars',
 			call: 'eth_getBlobSidecars',
@@ -731,11 +798,39 @@ web3._extend({
 				return status;
 			}
 		}),
+		new web3._extend.Property({
+			name: 'blobStatus',
+			getter: 'txpool_blobStatus'
+		}),
 		new web3._extend.Method({
 			name: 'contentFrom',
 			call: 'txpool_contentFrom',
 			params: 1,
 		}),
+		new web3._extend.Method({
+			name: 'getTransactionHistory',
+			call: 'txpool_getTransactionHistory',
+			params: 1,
+		}),
+		new web3._extend.Method({
+			name: 'exportAll',
+			call: 'txpool_exportAll',
+			params: 1,
+		}),
+		new web3._extend.Method({
+			name: 'importAll',
+			call: 'txpool_importAll',
+			params: 1,
+		}),
+		new web3._extend.Property({
+			name: 'localTransactions',
+			getter: 'txpool_localTransactions'
+		}),
+		new web3._extend.Method({
+			name: 'localPolicy',
+			call: 'txpool_localPolicy',
+			params: 1,
+		}),
 	]
 });
 `
@@ -758,3 +853,26 @@ web3._extend({
 	],
 });
 `
+
+const DBWriteJs = `
+web3._extend({
+	property: 'dbwrite',
+	methods: [
+		new web3._extend.Method({
+			name: 'put',
+			call: 'dbwrite_put',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'delete',
+			call: 'dbwrite_delete',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'write',
+			call: 'dbwrite_write',
+			params: 1
+		}),
+	],
+});
+`
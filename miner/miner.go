@@ -200,6 +200,13 @@ func (miner *Miner) Pending() (*types.Block, types.Receipts, *state.StateDB) {
 	return miner.worker.chain.GetBlockByHash(block.Hash()), miner.worker.chain.GetReceiptsByHash(block.Hash()), stateDb
 }
 
+// PendingBlockPreview returns the transaction ordering the miner would currently
+// produce for the next block, along with the pending transactions left out and
+// the reason why.
+func (miner *Miner) PendingBlockPreview() (*PendingBlockPreview, error) {
+	return miner.worker.pendingPreview()
+}
+
 // SetExtra sets the content used to initialize the block extra field.
 func (miner *Miner) SetExtra(extra []byte) error {
 	if uint64(len(extra)) > params.MaximumExtraDataSize {
@@ -0,0 +1,190 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// maxPreviewSkipped is the maximum number of transactions left out of the
+	// block reported in a preview, the rest is only counted.
+	maxPreviewSkipped = 1024
+
+	// minPreviewInterval is the minimum time between two preview builds, the
+	// last preview is served meanwhile even if the chain head has changed.
+	minPreviewInterval = time.Second
+)
+
+// SkipReason is the reason why a pending transaction was left out of the block.
+type SkipReason string
+
+const (
+	SkipNonceGap   SkipReason = "nonce-gap"  // Queued behind a missing nonce of the sender
+	SkipBelowTip   SkipReason = "below-tip"  // Tip below the miner's minimum, or fee cap below the base fee
+	SkipGasLimit   SkipReason = "gas-limit"  // Not enough gas left in the block
+	SkipBlobLimit  SkipReason = "blob-limit" // Not enough blob space left in the block
+	SkipConditions SkipReason = "conditions" // Inclusion conditions not met by the block
+	SkipReverted   SkipReason = "reverted"   // Reverts while asking for revert protection
+	SkipInvalid    SkipReason = "invalid"    // Failed to execute on top of the block
+)
+
+// PreviewTx is a transaction included in a pending block preview.
+type PreviewTx struct {
+	Hash    common.Hash    `json:"hash"`
+	From    common.Address `json:"from"`
+	Nonce   hexutil.Uint64 `json:"nonce"`
+	GasUsed hexutil.Uint64 `json:"gasUsed"` // Gas used by the simulated execution
+}
+
+// SkippedTx is a pending transaction left out of a pending block preview.
+type SkippedTx struct {
+	Hash   common.Hash `json:"hash"`
+	Reason SkipReason  `json:"reason"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// PendingBlockPreview is the transaction ordering the local worker would produce
+// for the next block, along with the pending transactions it would leave out.
+type PendingBlockPreview struct {
+	Number       hexutil.Uint64 `json:"number"`
+	ParentHash   common.Hash    `json:"parentHash"`
+	Timestamp    hexutil.Uint64 `json:"timestamp"`
+	GasLimit     hexutil.Uint64 `json:"gasLimit"`
+	GasUsed      hexutil.Uint64 `json:"gasUsed"`
+	BaseFee      *hexutil.Big   `json:"baseFeePerGas,omitempty"`
+	Transactions []*PreviewTx   `json:"transactions"`
+	Skipped      []*SkippedTx   `json:"skipped"`      // Capped at maxPreviewSkipped entries
+	SkippedCount hexutil.Uint64 `json:"skippedCount"` // Total number of transactions left out
+}
+
+// skip records a transaction left out of the block, if the environment is
+// building a preview.
+func (env *environment) skip(hash common.Hash, reason SkipReason, err error) {
+	if !env.preview {
+		return
+	}
+	env.skippedCount++
+	if len(env.skipped) >= maxPreviewSkipped {
+		return
+	}
+	skipped := &SkippedTx{Hash: hash, Reason: reason}
+	if err != nil {
+		skipped.Error = err.Error()
+	}
+	env.skipped = append(env.skipped, skipped)
+}
+
+// newPendingBlockPreview assembles the preview of the block being built in the
+// given environment.
+func newPendingBlockPreview(env *environment) *PendingBlockPreview {
+	preview := &PendingBlockPreview{
+		Number:       hexutil.Uint64(env.header.Number.Uint64()),
+		ParentHash:   env.header.ParentHash,
+		Timestamp:    hexutil.Uint64(env.header.Time),
+		GasLimit:     hexutil.Uint64(env.header.GasLimit),
+		GasUsed:      hexutil.Uint64(env.header.GasUsed),
+		Transactions: make([]*PreviewTx, len(env.txs)),
+		Skipped:      env.skipped,
+		SkippedCount: hexutil.Uint64(env.skippedCount),
+	}
+	if env.header.BaseFee != nil {
+		preview.BaseFee = (*hexutil.Big)(env.header.BaseFee)
+	}
+	for i, tx := range env.txs {
+		from, _ := types.Sender(env.signer, tx)
+		preview.Transactions[i] = &PreviewTx{
+			Hash:    tx.Hash(),
+			From:    from,
+			Nonce:   hexutil.Uint64(tx.Nonce()),
+			GasUsed: hexutil.Uint64(env.receipts[i].GasUsed),
+		}
+	}
+	if preview.Skipped == nil {
+		preview.Skipped = []*SkippedTx{}
+	}
+	return preview
+}
+
+// skipUnselected records the pool transactions the worker won't even consider
+// for the block: the pending ones filtered out by their fees and the queued ones.
+// Only the queued transactions that still fit the skipped list are retrieved,
+// the rest are just counted.
+func (w *worker) skipUnselected(env *environment, selected ...map[common.Address][]*txpool.LazyTransaction) {
+	known := make(map[common.Hash]struct{})
+	for _, txs := range selected {
+		for _, list := range txs {
+			for _, tx := range list {
+				known[tx.Hash] = struct{}{}
+			}
+		}
+	}
+	for _, list := range w.eth.TxPool().Pending(txpool.PendingFilter{}) {
+		for _, tx := range list {
+			if _, ok := known[tx.Hash]; !ok {
+				env.skip(tx.Hash, SkipBelowTip, nil)
+			}
+		}
+	}
+	_, queued := w.eth.TxPool().Stats()
+	hashes := w.eth.TxPool().Queued(max(maxPreviewSkipped-len(env.skipped), 0))
+	for _, hash := range hashes {
+		env.skip(hash, SkipNonceGap, nil)
+	}
+	if queued > len(hashes) {
+		env.skippedCount += queued - len(hashes)
+	}
+}
+
+// pendingPreview returns the preview of the block the worker would currently
+// produce on top of the chain head. Previews are rebuilt at most once per
+// recommit interval unless the head changes, and never more often than once
+// per minPreviewInterval.
+//
+// The preview is built on the caller's goroutine rather than the main loop, so
+// it never delays the sealing work. Concurrent callers wait for a single build.
+func (w *worker) pendingPreview() (*PendingBlockPreview, error) {
+	w.previewMu.Lock()
+	defer w.previewMu.Unlock()
+
+	if w.preview != nil {
+		elapsed := time.Since(w.previewTime)
+		if elapsed < minPreviewInterval {
+			return w.preview, nil
+		}
+		if w.preview.ParentHash == w.chain.CurrentBlock().Hash() && elapsed < w.recommit {
+			return w.preview, nil
+		}
+	}
+	head := w.chain.CurrentBlock()
+	res := w.generateWork(&generateParams{
+		timestamp:  uint64(time.Now().Unix()),
+		parentHash: head.Hash(),
+		coinbase:   w.etherbase(),
+		preview:    true,
+	}, false)
+	if res.err != nil {
+		return nil, res.err
+	}
+	w.preview, w.previewTime = res.preview, time.Now()
+	return w.preview, nil
+}
@@ -86,6 +86,10 @@ var (
 	errBlockInterruptedByTimeout   = errors.New("timeout while building block")
 	errBlockInterruptedByOutOfGas  = errors.New("out of gas while building block")
 	errBlockInterruptedByBetterBid = errors.New("better bid arrived while building block")
+
+	// errTxReverted is returned if a revert protected transaction fails, it is
+	// left out of the block.
+	errTxReverted = errors.New("revert protected transaction reverted")
 )
 
 // environment is the worker's current environment and holds all
@@ -105,6 +109,10 @@ type environment struct {
 	blobs    int
 
 	witness *stateless.Witness
+
+	preview      bool         // Whether the transactions left out are recorded
+	skipped      []*SkippedTx // Transactions left out of the block, if recorded
+	skippedCount int          // Number of transactions left out, including the unrecorded ones
 }
 
 // copy creates a deep copy of environment.
@@ -178,6 +186,7 @@ type newPayloadResult struct {
 	receipts []*types.Receipt       // Receipts collected during construction
 	requests [][]byte               // Consensus layer requests collected during block construction
 	witness  *stateless.Witness     // Witness is an optional stateless proof
+	preview  *PendingBlockPreview   // Transaction ordering preview, if requested
 }
 
 // getWorkReq represents a request for getting a new sealing work with provided parameters.
@@ -237,6 +246,10 @@ type worker struct {
 	snapshotReceipts types.Receipts
 	snapshotState    *state.StateDB
 
+	previewMu   sync.Mutex           // The lock used to serialize the pending block previews
+	preview     *PendingBlockPreview // Last pending block preview, reused within a recommit interval
+	previewTime time.Time            // Time the last pending block preview was built
+
 	// atomic status counters
 	running atomic.Bool // The indicator whether the consensus engine is running or not.
 	syncing atomic.Bool // The indicator whether the node is still syncing.
@@ -780,14 +793,21 @@ func (w *worker) commitBlobTransaction(env *environment, tx *types.Transaction,
 	return receipt.Logs, nil
 }
 
-// applyTransaction runs the transaction. If execution fails, state and gas pool are reverted.
+// applyTransaction runs the transaction. If execution fails, or a revert protected
+// transaction reverts, state and gas pool are reverted.
 func (w *worker) applyTransaction(env *environment, tx *types.Transaction, receiptProcessors ...core.ReceiptProcessor) (*types.Receipt, error) {
 	var (
 		snap = env.state.Snapshot()
 		gp   = env.gasPool.Gas()
-	)
 
-	receipt, err := core.ApplyTransaction(env.evm, env.gasPool, env.state, env.header, tx, &env.header.GasUsed, receiptProcessors...)
+		receipt *types.Receipt
+		err     error
+	)
+	if opts := tx.Options(); opts != nil && opts.RevertProtection {
+		receipt, err = w.applyRevertProtected(env, tx, receiptProcessors...)
+	} else {
+		receipt, err = core.ApplyTransaction(env.evm, env.gasPool, env.state, env.header, tx, &env.header.GasUsed, receiptProcessors...)
+	}
 	if err != nil {
 		env.state.RevertToSnapshot(snap)
 		env.gasPool.SetGas(gp)
@@ -795,6 +815,35 @@ func (w *worker) applyTransaction(env *environment, tx *types.Transaction, recei
 	return receipt, err
 }
 
+// applyRevertProtected runs a transaction asking not to be included if reverted.
+// Its execution result is checked before the state changes are finalised, as
+// they can't be reverted to the snapshot afterwards.
+func (w *worker) applyRevertProtected(env *environment, tx *types.Transaction, receiptProcessors ...core.ReceiptProcessor) (*types.Receipt, error) {
+	msg, err := core.TransactionToMessage(tx, env.signer, env.header.BaseFee)
+	if err != nil {
+		return nil, err
+	}
+	result, err := core.ApplyMessage(env.evm, msg, env.gasPool)
+	if err != nil {
+		return nil, err
+	}
+	if result.Failed() {
+		return nil, errTxReverted
+	}
+	var root []byte
+	if w.chainConfig.IsByzantium(env.header.Number) {
+		env.state.Finalise(true)
+	} else {
+		root = env.state.IntermediateRoot(w.chainConfig.IsEIP158(env.header.Number)).Bytes()
+	}
+	env.header.GasUsed += result.UsedGas
+
+	if env.state.GetTrie().IsVerkle() {
+		env.state.AccessEvents().Merge(env.evm.AccessEvents)
+	}
+	return core.MakeReceipt(env.evm, result, env.state, env.header.Number, env.header.Hash(), tx, env.header.GasUsed, root, receiptProcessors...), nil
+}
+
 func (w *worker) commitTransactions(env *environment, plainTxs, blobTxs *transactionsByPriceAndNonce,
 	interruptCh chan int32, stopTimer *time.Timer) error {
 	gasLimit := env.header.GasLimit
@@ -897,6 +946,7 @@ LOOP:
 		// If we don't have enough space for the next transaction, skip the account.
 		if env.gasPool.Gas() < ltx.Gas {
 			log.Trace("Not enough gas left for transaction", "hash", ltx.Hash, "left", env.gasPool.Gas(), "needed", ltx.Gas)
+			env.skip(ltx.Hash, SkipGasLimit, nil)
 			txs.Pop()
 			continue
 		}
@@ -908,6 +958,7 @@ LOOP:
 			left := eip4844.MaxBlobsPerBlock(w.chainConfig, env.header.Time) - env.blobs
 			if left < int(ltx.BlobGas/params.BlobTxBlobGasPerBlob) {
 				log.Trace("Not enough blob space left for transaction", "hash", ltx.Hash, "left", left, "needed", ltx.BlobGas/params.BlobTxBlobGasPerBlob)
+				env.skip(ltx.Hash, SkipBlobLimit, nil)
 				txs.Pop()
 				continue
 			}
@@ -931,6 +982,20 @@ LOOP:
 			txs.Pop()
 			continue
 		}
+		// Check the inclusion conditions of conditional transactions against the
+		// block being built, they may have changed since the pool accepted them.
+		if opts := tx.Options(); opts != nil {
+			err := opts.CheckBlock(env.header.Number.Uint64(), env.header.Time)
+			if err == nil {
+				err = opts.CheckStorage(env.state)
+			}
+			if err != nil {
+				log.Trace("Ignoring conditional transaction", "hash", ltx.Hash, "err", err)
+				env.skip(ltx.Hash, SkipConditions, err)
+				txs.Pop()
+				continue
+			}
+		}
 		// Start executing the transaction
 		env.state.SetTxContext(tx.Hash(), env.tcount)
 
@@ -939,8 +1004,15 @@ LOOP:
 		case errors.Is(err, core.ErrNonceTooLow):
 			// New head notification data race between the transaction pool and miner, shift
 			log.Trace("Skipping transaction with low nonce", "hash", ltx.Hash, "sender", from, "nonce", tx.Nonce())
+			env.skip(ltx.Hash, SkipInvalid, err)
 			txs.Shift()
 
+		case errors.Is(err, errTxReverted):
+			// Leave out the transactions asking not to be included if reverted
+			log.Trace("Ignoring reverting transaction", "hash", ltx.Hash)
+			env.skip(ltx.Hash, SkipReverted, nil)
+			txs.Pop()
+
 		case errors.Is(err, nil):
 			// Everything ok, collect the logs and shift in the next transaction from the same account
 			coalescedLogs = append(coalescedLogs, logs...)
@@ -951,6 +1023,7 @@ LOOP:
 			// Transaction is regarded as invalid, drop all consecutive transactions from
 			// the same sender because of `nonce-too-high` clause.
 			log.Debug("Transaction failed, account skipped", "hash", ltx.Hash, "err", err)
+			env.skip(ltx.Hash, SkipInvalid, err)
 			txs.Pop()
 		}
 	}
@@ -983,6 +1056,7 @@ type generateParams struct {
 	prevWork    *environment
 	beaconRoot  *common.Hash // The beacon root (cancun field).
 	noTxs       bool         // Flag whether an empty block without any transaction is expected
+	preview     bool         // Flag whether only a preview of the transaction ordering is expected
 }
 
 // prepareWork constructs the sealing task according to the given parameters,
@@ -1107,6 +1181,10 @@ func (w *worker) fillTransactions(interruptCh chan int32, env *environment, stop
 	filter.OnlyPlainTxs, filter.OnlyBlobTxs = false, true
 	pendingBlobTxs := w.eth.TxPool().Pending(filter)
 
+	if env.preview {
+		w.skipUnselected(env, pendingPlainTxs, pendingBlobTxs)
+	}
+
 	if bidTxs != nil {
 		filterBidTxs := func(commonTxs map[common.Address][]*txpool.LazyTransaction) {
 			for acc, txs := range commonTxs {
@@ -1171,6 +1249,8 @@ func (w *worker) generateWork(params *generateParams, witness bool) *newPayloadR
 	}
 	defer work.discard()
 
+	work.preview = params.preview
+
 	if !params.noTxs {
 		interrupt := new(atomic.Int32)
 		timer := time.AfterFunc(*w.config.Recommit, func() {
@@ -1183,6 +1263,10 @@ func (w *worker) generateWork(params *generateParams, witness bool) *newPayloadR
 			log.Warn("Block building is interrupted", "allowance", common.PrettyDuration(w.recommit))
 		}
 	}
+	// Previews only need the transaction ordering, skip the block assembly
+	if params.preview {
+		return &newPayloadResult{preview: newPendingBlockPreview(work)}
+	}
 	body := types.Body{Transactions: work.txs, Withdrawals: params.withdrawals}
 	allLogs := make([]*types.Log, 0)
 	for _, r := range work.receipts {
@@ -23,6 +23,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/consensus/clique"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
@@ -412,3 +413,138 @@ func testGetSealingWork(t *testing.T, chainConfig *params.ChainConfig, engine co
 		}
 	}
 }
+
+// Tests that the inclusion conditions of transactions are rechecked against the
+// block being built, and that reverting transactions asking for revert
+// protection are left out.
+func TestConditionalTransactions(t *testing.T) {
+	t.Parallel()
+
+	var (
+		gasPrice = big.NewInt(10 * params.InitialBaseFee)
+		transfer = types.NewTransaction(1, testUserAddress, big.NewInt(1000), params.TxGas, gasPrice, nil)
+		reverter = types.NewContractCreation(1, big.NewInt(0), 100000, gasPrice, []byte{byte(vm.INVALID)})
+		number   = hexutil.Uint64(1)
+		later    = hexutil.Uint64(2)
+	)
+	tests := []struct {
+		tx       *types.Transaction
+		opts     *types.TransactionOpts
+		included bool
+	}{
+		{transfer, &types.TransactionOpts{BlockNumberMin: &number}, true},
+		{transfer, &types.TransactionOpts{BlockNumberMin: &later}, false},
+		{transfer, &types.TransactionOpts{RevertProtection: true}, true},
+		{reverter, nil, true},
+		{reverter, &types.TransactionOpts{RevertProtection: true}, false},
+	}
+	for i, test := range tests {
+		engine := ethash.NewFaker()
+		w, b := newTestWorker(t, ethashChainConfig, engine, rawdb.NewMemoryDatabase(), 0)
+
+		tx, _ := types.SignTx(test.tx, types.HomesteadSigner{}, testBankKey)
+		if test.opts != nil {
+			tx = tx.WithOptions(test.opts)
+		}
+		if err := b.txPool.Add([]*types.Transaction{tx}, true)[0]; err != nil {
+			t.Fatalf("test %d: failed to add transaction: %v", i, err)
+		}
+		r := w.getSealingBlock(&generateParams{
+			parentHash: b.chain.Genesis().Hash(),
+			timestamp:  uint64(time.Now().Unix()),
+			coinbase:   testBankAddress,
+			forceTime:  true,
+		})
+		if r.err != nil {
+			t.Fatalf("test %d: failed to build block: %v", i, r.err)
+		}
+		if included := r.block.Transaction(tx.Hash()) != nil; included != test.included {
+			t.Errorf("test %d: inclusion mismatch: have %v, want %v", i, included, test.included)
+		}
+		if have := len(r.block.Transactions()); have < 1 {
+			t.Errorf("test %d: unconditional transaction missing from block", i)
+		}
+		w.close()
+		engine.Close()
+	}
+}
+
+// Tests that the pending block preview reports the transaction ordering of the
+// worker along with the pending transactions it leaves out.
+func TestPendingBlockPreview(t *testing.T) {
+	t.Parallel()
+
+	engine := ethash.NewFaker()
+	defer engine.Close()
+
+	w, b := newTestWorker(t, ethashChainConfig, engine, rawdb.NewMemoryDatabase(), 0)
+	defer w.close()
+
+	var (
+		gasPrice  = big.NewInt(10 * params.InitialBaseFee)
+		next, _   = types.SignTx(types.NewTransaction(1, testUserAddress, big.NewInt(1000), params.TxGas, gasPrice, nil), types.HomesteadSigner{}, testBankKey)
+		gapped, _ = types.SignTx(types.NewTransaction(3, testUserAddress, big.NewInt(1000), params.TxGas, gasPrice, nil), types.HomesteadSigner{}, testBankKey)
+	)
+	for i, err := range b.txPool.Add([]*types.Transaction{next, gapped}, true) {
+		if err != nil {
+			t.Fatalf("failed to add transaction %d: %v", i, err)
+		}
+	}
+	preview, err := w.pendingPreview()
+	if err != nil {
+		t.Fatalf("failed to preview pending block: %v", err)
+	}
+	if preview.Number != 1 || preview.ParentHash != b.chain.Genesis().Hash() {
+		t.Fatalf("preview block mismatch: number %d, parent %x", preview.Number, preview.ParentHash)
+	}
+	want := []common.Hash{pendingTxs[0].Hash(), next.Hash()}
+	if len(preview.Transactions) != len(want) {
+		t.Fatalf("previewed transaction count mismatch: have %d, want %d", len(preview.Transactions), len(want))
+	}
+	for i, tx := range preview.Transactions {
+		if tx.Hash != want[i] || tx.From != testBankAddress || tx.GasUsed != hexutil.Uint64(params.TxGas) {
+			t.Errorf("previewed transaction %d mismatch: %+v", i, tx)
+		}
+	}
+	if len(preview.Skipped) != 1 || preview.Skipped[0].Hash != gapped.Hash() || preview.Skipped[0].Reason != SkipNonceGap {
+		t.Fatalf("skipped transactions mismatch: %+v", preview.Skipped)
+	}
+	// Previews are reused within the recommit interval
+	if cached, _ := w.pendingPreview(); cached != preview {
+		t.Fatalf("preview not reused within the recommit interval")
+	}
+	// Raising the minimum tip leaves all the pending transactions out
+	w.setGasTip(new(big.Int).Mul(gasPrice, big.NewInt(2)))
+	w.preview = nil
+
+	if preview, err = w.pendingPreview(); err != nil {
+		t.Fatalf("failed to preview pending block: %v", err)
+	}
+	if len(preview.Transactions) != 0 {
+		t.Fatalf("previewed transaction count mismatch: have %d, want 0", len(preview.Transactions))
+	}
+	reasons := make(map[common.Hash]SkipReason)
+	for _, tx := range preview.Skipped {
+		reasons[tx.Hash] = tx.Reason
+	}
+	for hash, reason := range map[common.Hash]SkipReason{pendingTxs[0].Hash(): SkipBelowTip, next.Hash(): SkipBelowTip, gapped.Hash(): SkipNonceGap} {
+		if reasons[hash] != reason {
+			t.Errorf("skip reason mismatch for %x: have %q, want %q", hash, reasons[hash], reason)
+		}
+	}
+}
+
+// Tests that the transactions left out of a pending block preview are capped,
+// while still being counted.
+func TestPendingBlockPreviewSkipCap(t *testing.T) {
+	env := &environment{preview: true}
+	for i := 0; i < maxPreviewSkipped+10; i++ {
+		env.skip(common.Hash{byte(i), byte(i >> 8)}, SkipNonceGap, nil)
+	}
+	if len(env.skipped) != maxPreviewSkipped {
+		t.Fatalf("recorded skipped transactions mismatch: have %d, want %d", len(env.skipped), maxPreviewSkipped)
+	}
+	if env.skippedCount != maxPreviewSkipped+10 {
+		t.Fatalf("skipped transaction count mismatch: have %d, want %d", env.skippedCount, maxPreviewSkipped+10)
+	}
+}
@@ -309,6 +309,33 @@ func (api *adminAPI) Peers() ([]*p2p.PeerInfo, error) {
 	return server.PeersInfo(), nil
 }
 
+// PeerScores retrieves the misbehaviour scores of all penalized and banned peers.
+func (api *adminAPI) PeerScores() ([]p2p.PeerScore, error) {
+	server := api.node.Server()
+	if server == nil {
+		return nil, ErrNodeStopped
+	}
+	return server.PeerScores(), nil
+}
+
+// Unban lifts the ban of a remote node, given either as enode URL or node ID,
+// and resets its misbehaviour score.
+func (api *adminAPI) Unban(node string) (bool, error) {
+	server := api.node.Server()
+	if server == nil {
+		return false, ErrNodeStopped
+	}
+	id, err := enode.ParseID(node)
+	if err != nil {
+		n, perr := enode.Parse(enode.ValidSchemes, node)
+		if perr != nil {
+			return false, fmt.Errorf("invalid node: %v", err)
+		}
+		id = n.ID()
+	}
+	return server.Unban(id), nil
+}
+
 // NodeInfo retrieves all the information we know about the host node at the
 // protocol granularity.
 func (api *adminAPI) NodeInfo() (*p2p.NodeInfo, error) {
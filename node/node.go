@@ -435,7 +435,15 @@ func (n *Node) startRPC() error {
 	var (
 		servers           []*httpServer
 		openAPIs, allAPIs = n.getAPIs()
+		authModules       = slices.Clone(DefaultAuthModules)
 	)
+	// The namespaces of the authenticated APIs are served on the authenticated
+	// endpoints along with the default ones.
+	for _, api := range allAPIs {
+		if api.Authenticated && !slices.Contains(authModules, api.Namespace) {
+			authModules = append(authModules, api.Namespace)
+		}
+	}
 
 	rpcConfig := rpcEndpointConfig{
 		batchItemLimit:         n.config.BatchRequestLimit,
@@ -492,7 +500,7 @@ func (n *Node) startRPC() error {
 		err := server.enableRPC(allAPIs, httpConfig{
 			CorsAllowedOrigins: DefaultAuthCors,
 			Vhosts:             n.config.AuthVirtualHosts,
-			Modules:            DefaultAuthModules,
+			Modules:            authModules,
 			prefix:             DefaultAuthPrefix,
 			rpcEndpointConfig:  sharedConfig,
 		})
@@ -507,7 +515,7 @@ func (n *Node) startRPC() error {
 			return err
 		}
 		if err := server.enableWS(allAPIs, wsConfig{
-			Modules:           DefaultAuthModules,
+			Modules:           authModules,
 			Origins:           DefaultAuthOrigins,
 			prefix:            DefaultAuthPrefix,
 			rpcEndpointConfig: sharedConfig,
@@ -791,24 +799,36 @@ func (n *Node) OpenAndMergeDatabase(name string, namespace string, readonly bool
 	if config.PersistDiff {
 		diffStoreHandles = config.DatabaseHandles * diffStoreHandlesPercentage / 100
 	}
-	isMultiDatabase := n.CheckIfMultiDataBase()
-	// Open the separated state database if the state directory exists
-	if isMultiDatabase {
-		// Resource allocation rules:
-		// 1) Allocate a fixed percentage of memory for chainDb based on chainDbMemoryPercentage & chainDbHandlesPercentage.
-		// 2) Allocate a fixed size for blockDb based on blockDbCacheSize & blockDbHandlesSize.
-		// 3) Allocate the remaining resources to stateDb.
-		chainDbCache = int(float64(config.DatabaseCache) * chainDbMemoryPercentage / 100)
-		chainDataHandles = int(float64(config.DatabaseHandles) * chainDbHandlesPercentage / 100)
+	var (
+		isMultiDatabase    = n.CheckIfMultiDataBase()
+		separateBlockStore = n.CheckIfSeparateBlockStore()
+	)
+	// Resource allocation rules:
+	// 1) Allocate a fixed size for blockDb based on blockDbCacheSize & blockDbHandlesSize.
+	// 2) Allocate a fixed percentage of memory for chainDb based on chainDbMemoryPercentage & chainDbHandlesPercentage
+	//    if the state is separated, and the remaining resources to stateDb.
+	// 3) Otherwise allocate the remaining resources to chainDb.
+	if separateBlockStore {
 		if config.DatabaseHandles/10 > blockDbHandlesMaxSize {
 			blockDbHandlesSize = blockDbHandlesMaxSize
 		} else {
 			blockDbHandlesSize = blockDbHandlesMinSize
 		}
-		stateDbCache = config.DatabaseCache - chainDbCache - blockDbCacheSize
-		stateDbHandles = config.DatabaseHandles - chainDataHandles - blockDbHandlesSize
+		chainDbCache = config.DatabaseCache - blockDbCacheSize
+		chainDataHandles = config.DatabaseHandles - blockDbHandlesSize
 		disableChainDbFreeze = true
 	}
+	// Open the separated state database if the state directory exists
+	if isMultiDatabase {
+		chainDbCache = int(float64(config.DatabaseCache) * chainDbMemoryPercentage / 100)
+		chainDataHandles = int(float64(config.DatabaseHandles) * chainDbHandlesPercentage / 100)
+		stateDbCache = config.DatabaseCache - chainDbCache
+		stateDbHandles = config.DatabaseHandles - chainDataHandles
+		if separateBlockStore {
+			stateDbCache -= blockDbCacheSize
+			stateDbHandles -= blockDbHandlesSize
+		}
+	}
 
 	chainDB, err := n.OpenDatabaseWithFreezer(name, chainDbCache, chainDataHandles, config.DatabaseFreezer, namespace, readonly, disableChainDbFreeze, false, config.PruneAncientData)
 	if err != nil {
@@ -821,15 +841,23 @@ func (n *Node) OpenAndMergeDatabase(name string, namespace string, readonly bool
 		if err != nil {
 			return nil, err
 		}
-
+		chainDB.SetStateStore(stateDiskDb)
+	}
+	if separateBlockStore {
 		blockDb, err = n.OpenDatabaseWithFreezer(name+"/block", blockDbCacheSize, blockDbHandlesSize, "", "eth/db/blockdata/", readonly, false, false, config.PruneAncientData)
 		if err != nil {
 			return nil, err
 		}
-		log.Warn("Multi-database is an experimental feature")
-		chainDB.SetStateStore(stateDiskDb)
+		if err := n.CheckBlockStore(blockDb); err != nil {
+			blockDb.Close()
+			chainDB.Close()
+			return nil, err
+		}
 		chainDB.SetBlockStore(blockDb)
 	}
+	if isMultiDatabase || separateBlockStore {
+		log.Warn("Multi-database is an experimental feature")
+	}
 
 	if config.PersistDiff {
 		diffStore, err := n.OpenDiffDatabase(name, diffStoreHandles, config.DatabaseDiff, namespace, readonly)
@@ -878,7 +906,9 @@ func (n *Node) OpenDatabaseWithFreezer(name string, cache, handles int, ancient,
 	return db, err
 }
 
-// CheckIfMultiDataBase check the state and block subdirectory of db, if subdirectory exists, return true
+// CheckIfMultiDataBase check the state and block subdirectory of db, if subdirectory exists, return true.
+// A block subdirectory split off on its own by `db split-blockstore` doesn't count, see CheckIfSeparateBlockStore.
+// Whether such a block store was completely split off is checked when it's opened.
 func (n *Node) CheckIfMultiDataBase() bool {
 	var (
 		stateExist = true
@@ -900,11 +930,34 @@ func (n *Node) CheckIfMultiDataBase() bool {
 		return true
 	} else if !stateExist && !blockExist {
 		return false
+	} else if blockExist {
+		return false
 	} else {
 		panic("data corruption! missing block or state dir.")
 	}
 }
 
+// CheckIfSeparateBlockStore checks whether the block data is kept in a separate
+// database, either as part of a multi-database or split off on its own by
+// `db split-blockstore`.
+func (n *Node) CheckIfSeparateBlockStore() bool {
+	if n.CheckIfMultiDataBase() {
+		return true
+	}
+	fileInfo, err := os.Stat(filepath.Join(n.ResolvePath("chaindata"), "block"))
+	return err == nil && fileInfo.IsDir()
+}
+
+// CheckBlockStore verifies that the given block store, if it isn't part of a
+// multi-database, was completely split off, so that a partial copy is never
+// used as the block store.
+func (n *Node) CheckBlockStore(blockDb ethdb.KeyValueReader) error {
+	if n.CheckIfMultiDataBase() || rawdb.ReadBlockStoreSplit(blockDb) {
+		return nil
+	}
+	return errors.New("block store is incomplete, finish the split with `geth db split-blockstore`")
+}
+
 func (n *Node) OpenDiffDatabase(name string, handles int, diff, namespace string, readonly bool) (*leveldb.Database, error) {
 	n.lock.Lock()
 	defer n.lock.Unlock()
@@ -197,6 +197,67 @@ func TestAuthEndpoints(t *testing.T) {
 	}
 }
 
+// TestAuthEndpointNamespaces tests that the namespaces of the authenticated APIs
+// are served on the authenticated endpoint only, along with the default ones,
+// while the other namespaces aren't exposed there.
+func TestAuthEndpointNamespaces(t *testing.T) {
+	var secret [32]byte
+	if _, err := crand.Read(secret[:]); err != nil {
+		t.Fatalf("failed to create jwt secret: %v", err)
+	}
+	jwtPath := filepath.Join(t.TempDir(), "jwt_secret")
+	if err := os.WriteFile(jwtPath, []byte(hexutil.Encode(secret[:])), 0600); err != nil {
+		t.Fatalf("failed to prepare jwt secret file: %v", err)
+	}
+	node, err := New(&Config{
+		HTTPHost:    "127.0.0.1",
+		AuthAddr:    "127.0.0.1",
+		JWTSecret:   jwtPath,
+		HTTPModules: []string{"debug", "dbwrite"},
+	})
+	if err != nil {
+		t.Fatalf("could not create a new node: %v", err)
+	}
+	node.RegisterAPIs([]rpc.API{{
+		Namespace:     "dbwrite",
+		Service:       helloRPC("hello dbwrite"),
+		Authenticated: true,
+	}, {
+		Namespace: "debug",
+		Service:   helloRPC("hello debug"),
+	}})
+	if err := node.Start(); err != nil {
+		t.Fatalf("failed to start test node: %v", err)
+	}
+	defer node.Close()
+
+	ctx := context.Background()
+	cl, err := rpc.DialOptions(ctx, node.HTTPAuthEndpoint(), rpc.WithHTTPAuth(NewJWTAuth(secret)))
+	if err != nil {
+		t.Fatalf("failed to dial rpc endpoint: %v", err)
+	}
+	var x string
+	if err := cl.CallContext(ctx, &x, "dbwrite_helloWorld"); err != nil {
+		t.Fatalf("failed to call authenticated namespace: %v", err)
+	}
+	if x != "hello dbwrite" {
+		t.Fatalf("method was silent but did not return expected value: %q", x)
+	}
+	if err := cl.CallContext(ctx, &x, "debug_helloWorld"); err == nil {
+		t.Fatal("expected open namespace to be unavailable on the authenticated endpoint")
+	}
+	cl, err = rpc.DialOptions(ctx, node.HTTPEndpoint())
+	if err != nil {
+		t.Fatalf("failed to dial rpc endpoint: %v", err)
+	}
+	if err := cl.CallContext(ctx, &x, "debug_helloWorld"); err != nil {
+		t.Fatalf("failed to call open namespace: %v", err)
+	}
+	if err := cl.CallContext(ctx, &x, "dbwrite_helloWorld"); err == nil {
+		t.Fatal("expected authenticated method to be unavailable on the open endpoint")
+	}
+}
+
 func noneAuth(secret [32]byte) rpc.HTTPAuth {
 	return func(header http.Header) error {
 		token := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.MapClaims{
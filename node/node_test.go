@@ -22,11 +22,14 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"os"
+	"path/filepath"
 	"reflect"
 	"slices"
 	"strings"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/p2p"
@@ -167,6 +170,37 @@ func TestNodeCloseClosesDB(t *testing.T) {
 	}
 }
 
+// Tests that a block store split off on its own is only accepted once the split
+// has been completed, while the block store of a multi-database always is.
+func TestNodeCheckBlockStore(t *testing.T) {
+	config := testNodeConfig()
+	config.DataDir = t.TempDir()
+	stack, err := New(config)
+	if err != nil {
+		t.Fatalf("failed to create protocol stack: %v", err)
+	}
+	defer stack.Close()
+
+	chaindata := stack.ResolvePath("chaindata")
+	if err := os.MkdirAll(filepath.Join(chaindata, "block"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	blockDb := rawdb.NewMemoryDatabase()
+	if err := stack.CheckBlockStore(blockDb); err == nil {
+		t.Fatal("incomplete block store accepted")
+	}
+	rawdb.WriteBlockStoreSplit(blockDb)
+	if err := stack.CheckBlockStore(blockDb); err != nil {
+		t.Fatalf("split block store rejected: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(chaindata, "state"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := stack.CheckBlockStore(rawdb.NewMemoryDatabase()); err != nil {
+		t.Fatalf("multi-database block store rejected: %v", err)
+	}
+}
+
 // This test checks that OpenDatabase can be used from within a Lifecycle Start method.
 func TestNodeOpenDatabaseFromLifecycleStart(t *testing.T) {
 	stack, _ := New(testNodeConfig())
@@ -19,6 +19,7 @@ package p2p
 import (
 	"crypto/ecdsa"
 	"fmt"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/mclock"
@@ -96,6 +97,16 @@ type Config struct {
 	// it usually used for sentry nodes
 	ProxyedValidatorAddresses []common.Address `toml:",omitempty"`
 
+	// ProxyedValidatorNodeIDs is a list of NodeIDs of the validators that the local
+	// sentry node proxies. These nodes are never added to the discovery table, so
+	// their enodes are not handed out to other peers.
+	ProxyedValidatorNodeIDs []enode.ID `toml:",omitempty"`
+
+	// SentryNodes enables the sentry topology mode for a validator. If set, the
+	// server only maintains connections to the given sentries: discovery is
+	// disabled, no other nodes are dialed and all other peers are rejected.
+	SentryNodes []*enode.Node `toml:",omitempty"`
+
 	// Connectivity can be restricted to certain IP networks.
 	// If this option is set to a non-nil value, only hosts which match one of the
 	// IP networks contained in the list are considered.
@@ -143,6 +154,14 @@ type Config struct {
 
 	PeerFilterPatterns []string
 
+	// PeerBanThreshold is the misbehaviour score at which a remote peer gets banned.
+	// Zero defaults to preset values.
+	PeerBanThreshold int `toml:",omitempty"`
+
+	// PeerBanTime is the time a misbehaving peer stays banned. Bans are persisted
+	// in the node database. Zero defaults to preset values.
+	PeerBanTime time.Duration `toml:",omitempty"`
+
 	clock mclock.Clock
 }
 
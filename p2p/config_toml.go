@@ -4,6 +4,7 @@ package p2p
 
 import (
 	"crypto/ecdsa"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
@@ -33,6 +34,8 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		TrustedNodes              []*enode.Node
 		EVNNodeIdsWhitelist       []enode.ID       `toml:",omitempty"`
 		ProxyedValidatorAddresses []common.Address `toml:",omitempty"`
+		ProxyedValidatorNodeIDs   []enode.ID       `toml:",omitempty"`
+		SentryNodes               []*enode.Node    `toml:",omitempty"`
 		NetRestrict               *netutil.Netlist `toml:",omitempty"`
 		NodeDatabase              string           `toml:",omitempty"`
 		Protocols                 []Protocol       `toml:"-" json:"-"`
@@ -44,6 +47,8 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		EnableMsgEvents           bool
 		Logger                    log.Logger `toml:"-"`
 		PeerFilterPatterns        []string
+		PeerBanThreshold          int           `toml:",omitempty"`
+		PeerBanTime               time.Duration `toml:",omitempty"`
 	}
 	var enc Config
 	enc.PrivateKey = c.PrivateKey
@@ -62,6 +67,8 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.TrustedNodes = c.TrustedNodes
 	enc.EVNNodeIdsWhitelist = c.EVNNodeIdsWhitelist
 	enc.ProxyedValidatorAddresses = c.ProxyedValidatorAddresses
+	enc.ProxyedValidatorNodeIDs = c.ProxyedValidatorNodeIDs
+	enc.SentryNodes = c.SentryNodes
 	enc.NetRestrict = c.NetRestrict
 	enc.NodeDatabase = c.NodeDatabase
 	enc.Protocols = c.Protocols
@@ -73,6 +80,8 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.EnableMsgEvents = c.EnableMsgEvents
 	enc.Logger = c.Logger
 	enc.PeerFilterPatterns = c.PeerFilterPatterns
+	enc.PeerBanThreshold = c.PeerBanThreshold
+	enc.PeerBanTime = c.PeerBanTime
 	return &enc, nil
 }
 
@@ -95,6 +104,8 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		TrustedNodes              []*enode.Node
 		EVNNodeIdsWhitelist       []enode.ID       `toml:",omitempty"`
 		ProxyedValidatorAddresses []common.Address `toml:",omitempty"`
+		ProxyedValidatorNodeIDs   []enode.ID       `toml:",omitempty"`
+		SentryNodes               []*enode.Node    `toml:",omitempty"`
 		NetRestrict               *netutil.Netlist `toml:",omitempty"`
 		NodeDatabase              *string          `toml:",omitempty"`
 		Protocols                 []Protocol       `toml:"-" json:"-"`
@@ -106,6 +117,8 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		EnableMsgEvents           *bool
 		Logger                    log.Logger `toml:"-"`
 		PeerFilterPatterns        []string
+		PeerBanThreshold          *int           `toml:",omitempty"`
+		PeerBanTime               *time.Duration `toml:",omitempty"`
 	}
 	var dec Config
 	if err := unmarshal(&dec); err != nil {
@@ -159,6 +172,12 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.ProxyedValidatorAddresses != nil {
 		c.ProxyedValidatorAddresses = dec.ProxyedValidatorAddresses
 	}
+	if dec.ProxyedValidatorNodeIDs != nil {
+		c.ProxyedValidatorNodeIDs = dec.ProxyedValidatorNodeIDs
+	}
+	if dec.SentryNodes != nil {
+		c.SentryNodes = dec.SentryNodes
+	}
 	if dec.NetRestrict != nil {
 		c.NetRestrict = dec.NetRestrict
 	}
@@ -192,5 +211,11 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.PeerFilterPatterns != nil {
 		c.PeerFilterPatterns = dec.PeerFilterPatterns
 	}
+	if dec.PeerBanThreshold != nil {
+		c.PeerBanThreshold = *dec.PeerBanThreshold
+	}
+	if dec.PeerBanTime != nil {
+		c.PeerBanTime = *dec.PeerBanTime
+	}
 	return nil
 }
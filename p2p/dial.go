@@ -79,6 +79,7 @@ var (
 	errAlreadyConnected = errors.New("already connected")
 	errRecentlyDialed   = errors.New("recently dialed")
 	errNetRestrict      = errors.New("not contained in netrestrict list")
+	errBanned           = errors.New("is banned")
 	errNoPort           = errors.New("node does not provide TCP port")
 	errNoResolvedIP     = errors.New("node does not provide a resolved IP")
 )
@@ -131,10 +132,11 @@ type dialScheduler struct {
 type dialSetupFunc func(net.Conn, connFlag, *enode.Node) error
 
 type dialConfig struct {
-	self           enode.ID         // our own ID
-	maxDialPeers   int              // maximum number of dialed peers
-	maxActiveDials int              // maximum number of active dials
-	netRestrict    *netutil.Netlist // IP netrestrict list, disabled if nil
+	self           enode.ID            // our own ID
+	maxDialPeers   int                 // maximum number of dialed peers
+	maxActiveDials int                 // maximum number of active dials
+	netRestrict    *netutil.Netlist    // IP netrestrict list, disabled if nil
+	banned         func(enode.ID) bool // reports banned nodes, disabled if nil
 	resolver       nodeResolver
 	dialer         NodeDialer
 	log            log.Logger
@@ -291,7 +293,7 @@ loop:
 			}
 			task := newDialTask(node, staticDialedConn)
 			d.static[id] = task
-			if d.checkDial(node) == nil {
+			if d.checkStaticDial(task) == nil {
 				d.addToStaticPool(task)
 			}
 
@@ -402,9 +404,23 @@ func (d *dialScheduler) checkDial(n *enode.Node) error {
 	if d.history.contains(string(n.ID().Bytes())) {
 		return errRecentlyDialed
 	}
+	if d.banned != nil && d.banned(n.ID()) {
+		return errBanned
+	}
 	return nil
 }
 
+// checkStaticDial returns an error if the static dial task should not be started.
+// Banned nodes are put into the dial history, so they are checked again later and
+// dialed once their ban expires.
+func (d *dialScheduler) checkStaticDial(task *dialTask) error {
+	err := d.checkDial(task.dest())
+	if err == errBanned {
+		d.history.add(string(task.dest().ID().Bytes()), d.clock.Now().Add(dialHistoryExpiration))
+	}
+	return err
+}
+
 // startStaticDials starts n static dial tasks.
 func (d *dialScheduler) startStaticDials(n int) (started int) {
 	for started = 0; started < n && len(d.staticPool) > 0; started++ {
@@ -419,7 +435,7 @@ func (d *dialScheduler) startStaticDials(n int) (started int) {
 // updateStaticPool attempts to move the given static dial back into staticPool.
 func (d *dialScheduler) updateStaticPool(id enode.ID) {
 	task, ok := d.static[id]
-	if ok && task.staticPoolIndex < 0 && d.checkDial(task.dest()) == nil {
+	if ok && task.staticPoolIndex < 0 && d.checkStaticDial(task) == nil {
 		d.addToStaticPool(task)
 	}
 }
@@ -153,6 +153,60 @@ func TestDialSchedNetRestrict(t *testing.T) {
 	})
 }
 
+// This test checks that banned nodes are neither dialed from discovery results
+// nor as static nodes, and that static nodes are dialed once their ban is lifted.
+func TestDialSchedBanned(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu     sync.Mutex
+		banned = map[enode.ID]bool{uintID(0x01): true, uintID(0x03): true}
+	)
+	config := dialConfig{
+		maxActiveDials: 5,
+		maxDialPeers:   4,
+		banned: func(id enode.ID) bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return banned[id]
+		},
+	}
+	runDialTest(t, config, []dialTestRound{
+		{
+			update: func(d *dialScheduler) {
+				d.addStatic(newNode(uintID(0x03), "127.0.0.3:30303"))
+				d.addStatic(newNode(uintID(0x04), "127.0.0.4:30303"))
+			},
+			discovered: []*enode.Node{
+				newNode(uintID(0x01), "127.0.0.1:30303"), // not dialed because banned
+				newNode(uintID(0x02), "127.0.0.2:30303"),
+			},
+			wantNewDials: []*enode.Node{
+				newNode(uintID(0x02), "127.0.0.2:30303"),
+				newNode(uintID(0x04), "127.0.0.4:30303"),
+			},
+		},
+		// The ban of 0x03 is lifted, it's dialed when checked again.
+		{
+			update: func(d *dialScheduler) {
+				mu.Lock()
+				defer mu.Unlock()
+				delete(banned, uintID(0x03))
+			},
+			succeeded: []enode.ID{
+				uintID(0x02),
+				uintID(0x04),
+			},
+		},
+		{},
+		{
+			wantNewDials: []*enode.Node{
+				newNode(uintID(0x03), "127.0.0.3:30303"),
+			},
+		},
+	})
+}
+
 // This test checks that static dials work and obey the limits.
 func TestDialSchedStaticDial(t *testing.T) {
 	t.Parallel()
@@ -94,6 +94,7 @@ type Config struct {
 	V5ProtocolID *[6]byte
 
 	FilterFunction NodeFilterFunc     // function for filtering ENR entries
+	HiddenNodes    []enode.ID         // nodes never added to the table
 	Log            log.Logger         // if set, log messages go here
 	ValidSchemes   enr.IdentityScheme // allowed identity schemes
 	Clock          mclock.Clock
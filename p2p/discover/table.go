@@ -88,6 +88,7 @@ type Table struct {
 	closed          chan struct{}
 
 	enrFilter NodeFilterFunc
+	hidden    map[enode.ID]struct{}
 
 	nodeAddedHook   func(*bucket, *tableNode)
 	nodeRemovedHook func(*bucket, *tableNode)
@@ -146,6 +147,12 @@ func newTable(t transport, db *enode.DB, cfg Config) (*Table, error) {
 	if cfg.IsBootnode {
 		tab.bucketSize = bootNodeBucketSize
 	}
+	if len(cfg.HiddenNodes) > 0 {
+		tab.hidden = make(map[enode.ID]struct{}, len(cfg.HiddenNodes))
+		for _, id := range cfg.HiddenNodes {
+			tab.hidden[id] = struct{}{}
+		}
+	}
 	for i := range tab.buckets {
 		tab.buckets[i] = &bucket{
 			index: i,
@@ -555,6 +562,10 @@ func (tab *Table) handleAddNode(req addNodeOp) bool {
 	if req.node.ID() == tab.self().ID() {
 		return false
 	}
+	// Hidden nodes are dropped by ID, there is no point asking for their record.
+	if _, ok := tab.hidden[req.node.ID()]; ok {
+		return false
+	}
 
 	if tab.filterNode(req.node) {
 		return false
@@ -19,10 +19,12 @@ package discover
 import (
 	"crypto/ecdsa"
 	"fmt"
+	"net"
 	"net/netip"
 	"slices"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/p2p/discover/v4wire"
@@ -152,6 +154,87 @@ func TestUDPv4_LookupIteratorClose(t *testing.T) {
 	}
 }
 
+// TestUDPv4_LookupHiddenNode checks that a hidden node returned in a FINDNODE
+// response is never added to the table, and that its record is not requested.
+func TestUDPv4_LookupHiddenNode(t *testing.T) {
+	t.Parallel()
+
+	var (
+		seedKey, hiddenKey, visibleKey = newkey(), newkey(), newkey()
+		seed                           = enode.NewV4(&seedKey.PublicKey, net.IP{10, 0, 2, 1}, 30303, 30303)
+		hidden                         = enode.NewV4(&hiddenKey.PublicKey, net.IP{10, 0, 2, 2}, 30303, 30303)
+		visible                        = enode.NewV4(&visibleKey.PublicKey, net.IP{10, 0, 2, 3}, 30303, 30303)
+		keys                           = map[netip.AddrPort]*ecdsa.PrivateKey{}
+	)
+	test := newUDPTestWithConfig(t, Config{
+		FilterFunction: func(*enr.Record) bool { return true },
+		HiddenNodes:    []enode.ID{hidden.ID()},
+	})
+	for key, n := range map[*ecdsa.PrivateKey]*enode.Node{seedKey: seed, hiddenKey: hidden, visibleKey: visible} {
+		addr, _ := n.UDPEndpoint()
+		keys[addr] = key
+		test.db.UpdateLastPingReceived(n.ID(), n.IPAddr(), time.Now())
+	}
+	var (
+		mu        sync.Mutex
+		requested = make(map[enode.ID]bool)
+	)
+	// Answer the lookup and the record requests of the table.
+	go func() {
+		for closed := false; !closed; {
+			closed = test.waitPacketOut(func(p v4wire.Packet, to netip.AddrPort, hash []byte) {
+				key := keys[to]
+				if key == nil {
+					return
+				}
+				switch p.(type) {
+				case *v4wire.Findnode:
+					var nodes []v4wire.Node
+					if key == seedKey {
+						nodes = []v4wire.Node{nodeToRPC(hidden), nodeToRPC(visible)}
+					}
+					test.packetInFrom(nil, key, to, &v4wire.Neighbors{Expiration: futureExp, Nodes: nodes})
+				case *v4wire.ENRRequest:
+					var r enr.Record
+					r.Set(enr.IPv4Addr(to.Addr()))
+					r.Set(enr.UDP(to.Port()))
+					enode.SignV4(&r, key)
+					mu.Lock()
+					requested[enode.PubkeyToIDV4(&key.PublicKey)] = true
+					mu.Unlock()
+					test.packetInFrom(nil, key, to, &v4wire.ENRResponse{ReplyTok: hash, Record: r})
+				}
+			})
+		}
+	}()
+	fillTable(test.table, []*enode.Node{seed}, true)
+
+	// Run a lookup, the seed answers with both the hidden and the visible node.
+	test.udp.LookupPubkey(&newkey().PublicKey)
+
+	// Wait for the visible node to be resolved and added.
+	deadline := time.Now().Add(5 * time.Second)
+	for test.table.getNode(visible.ID()) == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("visible node was not added to the table")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	test.close()
+
+	if test.table.getNode(hidden.ID()) != nil {
+		t.Error("hidden node was added to the table")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if requested[hidden.ID()] {
+		t.Error("record of hidden node was requested")
+	}
+	if !requested[visible.ID()] {
+		t.Error("record of visible node was not requested")
+	}
+}
+
 func serveTestnet(test *udpTest, testnet *preminedTestnet) {
 	for done := false; !done; {
 		done = test.waitPacketOut(func(p v4wire.Packet, to netip.AddrPort, hash []byte) {
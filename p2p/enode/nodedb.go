@@ -42,6 +42,7 @@ const (
 	dbVersionKey   = "version" // Version of the database to flush if changes
 	dbNodePrefix   = "n:"      // Identifier to prefix node entries with
 	dbLocalPrefix  = "local:"
+	dbBanPrefix    = "ban:" // Identifier to prefix peer ban entries with, the full key is "ban:<ID>"
 	dbDiscoverRoot = "v4"
 	dbDiscv5Root   = "v5"
 
@@ -195,6 +196,11 @@ func localItemKey(id ID, field string) []byte {
 	return key
 }
 
+// banKey returns the database key for the ban entry of a node.
+func banKey(id ID) []byte {
+	return append([]byte(dbBanPrefix), id[:]...)
+}
+
 // fetchInt64 retrieves an integer associated with a particular key.
 func (db *DB) fetchInt64(key []byte) int64 {
 	blob, err := db.lvl.Get(key, nil)
@@ -447,6 +453,44 @@ func (db *DB) storeLocalSeq(id ID, n uint64) {
 	db.storeUint64(localItemKey(id, dbLocalSeq), n)
 }
 
+// BanExpiry retrieves the time until which a node is banned. The zero time is
+// returned if the node is not banned.
+func (db *DB) BanExpiry(id ID) time.Time {
+	expiry := db.fetchInt64(banKey(id))
+	if expiry == 0 {
+		return time.Time{}
+	}
+	return time.Unix(expiry, 0)
+}
+
+// UpdateBanExpiry stores the time until which a node is banned. Passing the zero
+// time lifts the ban.
+func (db *DB) UpdateBanExpiry(id ID, expiry time.Time) error {
+	if expiry.IsZero() {
+		return db.lvl.Delete(banKey(id), nil)
+	}
+	return db.storeInt64(banKey(id), expiry.Unix())
+}
+
+// Bans retrieves all stored node bans along with their expiry time.
+func (db *DB) Bans() map[ID]time.Time {
+	it := db.lvl.NewIterator(util.BytesPrefix([]byte(dbBanPrefix)), nil)
+	defer it.Release()
+
+	bans := make(map[ID]time.Time)
+	for it.Next() {
+		var id ID
+		if len(it.Key()) != len(dbBanPrefix)+len(id) {
+			continue
+		}
+		copy(id[:], it.Key()[len(dbBanPrefix):])
+		if expiry, read := binary.Varint(it.Value()); read > 0 {
+			bans[id] = time.Unix(expiry, 0)
+		}
+	}
+	return bans
+}
+
 // QuerySeeds retrieves random nodes to be used as potential seed nodes
 // for bootstrapping.
 func (db *DB) QuerySeeds(n int, maxAge time.Duration) []*Node {
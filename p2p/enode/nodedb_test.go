@@ -112,6 +112,31 @@ func TestDBInt64(t *testing.T) {
 	}
 }
 
+func TestDBBans(t *testing.T) {
+	db, _ := OpenDB("")
+	defer db.Close()
+
+	if expiry := db.BanExpiry(keytestID); !expiry.IsZero() {
+		t.Fatalf("unexpected ban for unknown node: %v", expiry)
+	}
+	expiry := time.Unix(time.Now().Add(time.Hour).Unix(), 0)
+	if err := db.UpdateBanExpiry(keytestID, expiry); err != nil {
+		t.Fatalf("failed to store ban: %v", err)
+	}
+	if have := db.BanExpiry(keytestID); !have.Equal(expiry) {
+		t.Errorf("ban expiry mismatch: have %v, want %v", have, expiry)
+	}
+	if bans := db.Bans(); len(bans) != 1 || !bans[keytestID].Equal(expiry) {
+		t.Errorf("ban list mismatch: %v", bans)
+	}
+	if err := db.UpdateBanExpiry(keytestID, time.Time{}); err != nil {
+		t.Fatalf("failed to lift ban: %v", err)
+	}
+	if bans := db.Bans(); len(bans) != 0 {
+		t.Errorf("ban not lifted: %v", bans)
+	}
+}
+
 func TestDBFetchStore(t *testing.T) {
 	node := NewV4(
 		hexPubkey("1dd9d65c4552b5eb43d5ad55a2ee3f56c6cbc1c64a5c8d659f51fcd51bace24351232b8d7821617d2b29b54b81cdefb9b3e9c37d7fd5f63270bcc9e1a6f6a439"),
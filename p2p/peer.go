@@ -126,6 +126,8 @@ type Peer struct {
 
 	latency atomic.Int64 // mill second latency, estimated by ping msg
 
+	reputation *reputation // Misbehaviour tracker of the server, nil for test peers
+
 	// it indicates the peer is in the validator network, it will directly broadcast when miner/sentry broadcast mined block,
 	// and won't broadcast any txs between EVN peers.
 	EVNPeerFlag atomic.Bool
@@ -252,6 +254,18 @@ func (p *Peer) Disconnect(reason DiscReason) {
 	}
 }
 
+// Penalize records a protocol misbehaviour of the peer. If the accumulated
+// penalties reach the ban threshold, the peer is banned and disconnected.
+// Trusted and static peers are never penalized.
+func (p *Peer) Penalize(penalty int, reason string) {
+	if p.reputation == nil || p.rw.is(trustedConn) || p.rw.is(staticDialedConn) {
+		return
+	}
+	if p.reputation.penalize(p.ID(), penalty, reason) {
+		p.Disconnect(DiscUselessPeer)
+	}
+}
+
 // String implements fmt.Stringer.
 func (p *Peer) String() string {
 	id := p.ID()
@@ -0,0 +1,216 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/mclock"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+// Penalties applied to peers for protocol misbehaviour. With the default ban
+// threshold, a fatal penalty bans the peer at once.
+const (
+	PenaltyMinor = 10  // e.g. stale or unverifiable data, which honest peers may occasionally send
+	PenaltyMajor = 50  // e.g. invalid blocks or responses violating the protocol
+	PenaltyFatal = 100 // e.g. forged signatures
+)
+
+const (
+	defaultPeerBanThreshold = 100
+	defaultPeerBanTime      = time.Hour
+
+	// scoreDecayInterval is the time it takes for one penalty point to be forgiven.
+	scoreDecayInterval = 6 * time.Second
+)
+
+var (
+	peerPenaltyMeter = metrics.NewRegisteredMeter("p2p/reputation/penalty", nil)
+	peerBanMeter     = metrics.NewRegisteredMeter("p2p/reputation/ban", nil)
+	peerBannedGauge  = metrics.NewRegisteredGauge("p2p/reputation/banned", nil)
+)
+
+// PeerScore is the reputation of a single remote node, as reported by the
+// admin_peerScores API.
+type PeerScore struct {
+	ID          string    `json:"id"`
+	Score       int       `json:"score"`
+	LastReason  string    `json:"lastReason,omitempty"`
+	BannedUntil time.Time `json:"bannedUntil,omitempty"`
+}
+
+type peerScore struct {
+	score   int
+	updated mclock.AbsTime
+	reason  string
+}
+
+// reputation accumulates protocol misbehaviour penalties per node and bans the
+// nodes whose score reaches the threshold. Bans are persisted in the node
+// database, so they survive restarts.
+type reputation struct {
+	db        *enode.DB
+	clock     mclock.Clock
+	threshold int
+	banTime   time.Duration
+	log       log.Logger
+
+	lock   sync.Mutex
+	scores map[enode.ID]*peerScore
+	bans   map[enode.ID]time.Time
+}
+
+func newReputation(db *enode.DB, clock mclock.Clock, threshold int, banTime time.Duration, logger log.Logger) *reputation {
+	if threshold <= 0 {
+		threshold = defaultPeerBanThreshold
+	}
+	if banTime <= 0 {
+		banTime = defaultPeerBanTime
+	}
+	r := &reputation{
+		db:        db,
+		clock:     clock,
+		threshold: threshold,
+		banTime:   banTime,
+		log:       logger,
+		scores:    make(map[enode.ID]*peerScore),
+		bans:      make(map[enode.ID]time.Time),
+	}
+	// Load the still active bans, dropping the expired ones.
+	now := time.Now()
+	for id, expiry := range db.Bans() {
+		if expiry.After(now) {
+			r.bans[id] = expiry
+		} else {
+			db.UpdateBanExpiry(id, time.Time{})
+		}
+	}
+	peerBannedGauge.Update(int64(len(r.bans)))
+	return r
+}
+
+// current returns the decayed score of a node. The lock must be held.
+func (r *reputation) current(s *peerScore) int {
+	forgiven := int(time.Duration(r.clock.Now()-s.updated) / scoreDecayInterval)
+	if forgiven >= s.score {
+		return 0
+	}
+	return s.score - forgiven
+}
+
+// penalize adds a penalty to the score of the given node and reports whether
+// the node got banned because of it.
+func (r *reputation) penalize(id enode.ID, penalty int, reason string) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	peerPenaltyMeter.Mark(1)
+	s, ok := r.scores[id]
+	if !ok {
+		s = new(peerScore)
+		r.scores[id] = s
+	}
+	s.score = r.current(s) + penalty
+	s.updated = r.clock.Now()
+	s.reason = reason
+	r.log.Debug("Penalized peer", "id", id, "penalty", penalty, "score", s.score, "reason", reason)
+
+	if s.score < r.threshold {
+		return false
+	}
+	expiry := time.Now().Add(r.banTime)
+	r.bans[id] = expiry
+	delete(r.scores, id)
+	if err := r.db.UpdateBanExpiry(id, expiry); err != nil {
+		r.log.Warn("Failed to persist peer ban", "id", id, "err", err)
+	}
+	peerBanMeter.Mark(1)
+	peerBannedGauge.Update(int64(len(r.bans)))
+	r.log.Info("Banned misbehaving peer", "id", id, "until", expiry, "reason", reason)
+	return true
+}
+
+// banned reports whether the given node is currently banned.
+func (r *reputation) banned(id enode.ID) bool {
+	if r == nil {
+		return false
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	expiry, ok := r.bans[id]
+	if !ok {
+		return false
+	}
+	if time.Now().Before(expiry) {
+		return true
+	}
+	delete(r.bans, id)
+	r.db.UpdateBanExpiry(id, time.Time{})
+	peerBannedGauge.Update(int64(len(r.bans)))
+	return false
+}
+
+// unban lifts the ban of the given node and resets its score. It reports
+// whether the node was known at all.
+func (r *reputation) unban(id enode.ID) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	_, scored := r.scores[id]
+	_, banned := r.bans[id]
+	delete(r.scores, id)
+	delete(r.bans, id)
+	if banned {
+		if err := r.db.UpdateBanExpiry(id, time.Time{}); err != nil {
+			r.log.Warn("Failed to remove peer ban", "id", id, "err", err)
+		}
+		peerBannedGauge.Update(int64(len(r.bans)))
+	}
+	return scored || banned
+}
+
+// list returns the scores of all penalized and banned nodes.
+func (r *reputation) list() []PeerScore {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	list := make([]PeerScore, 0, len(r.scores)+len(r.bans))
+	for id, s := range r.scores {
+		score := r.current(s)
+		if score == 0 {
+			delete(r.scores, id) // fully forgiven, drop it
+			continue
+		}
+		list = append(list, PeerScore{ID: id.String(), Score: score, LastReason: s.reason})
+	}
+	for id, expiry := range r.bans {
+		list = append(list, PeerScore{ID: id.String(), Score: r.threshold, BannedUntil: expiry})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Score != list[j].Score {
+			return list[i].Score > list[j].Score
+		}
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
@@ -0,0 +1,107 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/mclock"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+func TestReputationBan(t *testing.T) {
+	db, _ := enode.OpenDB("")
+	defer db.Close()
+
+	var (
+		clock = new(mclock.Simulated)
+		rep   = newReputation(db, clock, 0, 0, log.Root())
+		id    = randomID()
+	)
+	if rep.penalize(id, PenaltyMajor, "bad block") {
+		t.Fatal("peer banned below threshold")
+	}
+	if rep.banned(id) {
+		t.Fatal("peer reported banned below threshold")
+	}
+	if scores := rep.list(); len(scores) != 1 || scores[0].Score != PenaltyMajor || scores[0].LastReason != "bad block" {
+		t.Fatalf("unexpected scores: %+v", scores)
+	}
+	if !rep.penalize(id, PenaltyMajor, "bad block") {
+		t.Fatal("peer not banned at threshold")
+	}
+	if !rep.banned(id) {
+		t.Fatal("peer not reported banned")
+	}
+	// Bans survive a restart.
+	if !newReputation(db, clock, 0, 0, log.Root()).banned(id) {
+		t.Fatal("ban not persisted")
+	}
+	if !rep.unban(id) {
+		t.Fatal("unban of banned peer failed")
+	}
+	if rep.banned(id) || !db.BanExpiry(id).IsZero() {
+		t.Fatal("peer still banned after unban")
+	}
+	if rep.unban(id) {
+		t.Fatal("unban of unknown peer succeeded")
+	}
+}
+
+func TestReputationDecay(t *testing.T) {
+	db, _ := enode.OpenDB("")
+	defer db.Close()
+
+	var (
+		clock = new(mclock.Simulated)
+		rep   = newReputation(db, clock, 0, 0, log.Root())
+		id    = randomID()
+	)
+	rep.penalize(id, PenaltyMajor, "bad block")
+	clock.Run(10 * scoreDecayInterval)
+	if scores := rep.list(); len(scores) != 1 || scores[0].Score != PenaltyMajor-10 {
+		t.Fatalf("unexpected decayed scores: %+v", scores)
+	}
+	// A partially forgiven peer is not banned by a second major penalty.
+	if rep.penalize(id, PenaltyMajor, "bad block") {
+		t.Fatal("peer banned despite decay")
+	}
+	clock.Run(defaultPeerBanThreshold * scoreDecayInterval)
+	if scores := rep.list(); len(scores) != 0 {
+		t.Fatalf("score not fully forgiven: %+v", scores)
+	}
+}
+
+func TestReputationExpiry(t *testing.T) {
+	db, _ := enode.OpenDB("")
+	defer db.Close()
+
+	rep := newReputation(db, new(mclock.Simulated), 0, time.Millisecond, log.Root())
+	id := randomID()
+	if !rep.penalize(id, PenaltyFatal, "forged signature") {
+		t.Fatal("fatal penalty didn't ban the peer")
+	}
+	time.Sleep(5 * time.Millisecond)
+	if rep.banned(id) {
+		t.Fatal("ban did not expire")
+	}
+	if len(db.Bans()) != 0 {
+		t.Fatal("expired ban not removed from database")
+	}
+}
@@ -73,6 +73,8 @@ var (
 	errServerStopped       = errors.New("server stopped")
 	errEncHandshakeError   = errors.New("rlpx enc error")
 	errProtoHandshakeError = errors.New("rlpx proto error")
+	errNotSentryNode       = errors.New("not a sentry node")
+	errBannedPeer          = errors.New("peer is banned")
 
 	// magicEnodeID is a special enode ID that can be used to disconnect all peers
 	// enode://1dd9d65c4552b5eb43d5ad55a2ee3f56c6cbc1c64a5c8d659f51fcd51bace24351232b8d7821617d2b29b54b81cdefb9b3e9c37d7fd5f63270bcc9e1a6f6a439
@@ -109,6 +111,9 @@ type Server struct {
 	forkFilter     forkid.Filter
 	peerNameFilter []*regexp.Regexp
 
+	reputation  *reputation           // Misbehaviour scores and bans of remote peers
+	sentryNodes map[enode.ID]struct{} // Sentries guarding the local validator, if in sentry mode
+
 	// This is read by the NAT port mapping loop.
 	portMappingRegister chan *portMapping
 
@@ -431,6 +436,7 @@ func (srv *Server) Start() (err error) {
 	srv.peerOp = make(chan peerOpFunc)
 	srv.peerOpDone = make(chan struct{})
 	srv.disconnectEnodeSet = make(map[enode.ID]struct{})
+	srv.setupSentryTopology()
 
 	if err := srv.setupLocalNode(); err != nil {
 		return err
@@ -476,6 +482,7 @@ func (srv *Server) setupLocalNode() error {
 		return err
 	}
 	srv.nodedb = db
+	srv.reputation = newReputation(db, srv.clock, srv.PeerBanThreshold, srv.PeerBanTime, srv.log)
 	srv.localnode = enode.NewLocalNode(db, srv.PrivateKey)
 	srv.localnode.SetFallbackIP(net.IP{127, 0, 0, 1})
 	// TODO: check conflicts
@@ -487,11 +494,37 @@ func (srv *Server) setupLocalNode() error {
 	return nil
 }
 
+// setupSentryTopology prepares the lookup sets used by the sentry topology mode.
+func (srv *Server) setupSentryTopology() {
+	if len(srv.SentryNodes) > 0 {
+		srv.sentryNodes = make(map[enode.ID]struct{}, len(srv.SentryNodes))
+		for _, n := range srv.SentryNodes {
+			srv.sentryNodes[n.ID()] = struct{}{}
+		}
+		if len(srv.StaticNodes) > 0 || len(srv.VerifyNodes) > 0 {
+			srv.log.Warn("Static and verify nodes are ignored in sentry mode", "static", len(srv.StaticNodes), "verify", len(srv.VerifyNodes))
+		}
+		srv.log.Info("Running in sentry topology mode", "sentries", len(srv.SentryNodes))
+	}
+}
+
+// sentryMode reports whether the server only talks to its configured sentries.
+func (srv *Server) sentryMode() bool {
+	return srv.sentryNodes != nil
+}
+
+// isSentryNode reports whether the given node is one of the configured sentries.
+func (srv *Server) isSentryNode(id enode.ID) bool {
+	_, ok := srv.sentryNodes[id]
+	return ok
+}
+
 func (srv *Server) setupDiscovery() error {
 	srv.discmix = enode.NewFairMix(discmixTimeout)
 
-	// Don't listen on UDP endpoint if DHT is disabled.
-	if srv.NoDiscovery {
+	// Don't listen on UDP endpoint if DHT is disabled. Validators behind
+	// sentries must stay invisible, so discovery is never started for them.
+	if srv.NoDiscovery || srv.sentryMode() {
 		return nil
 	}
 	conn, err := srv.setupUDPListening()
@@ -534,6 +567,7 @@ func (srv *Server) setupDiscovery() error {
 			Unhandled:      unhandled,
 			Log:            srv.log,
 			FilterFunction: f,
+			HiddenNodes:    srv.ProxyedValidatorNodeIDs,
 		}
 		ntab, err := discover.ListenV4(conn, srv.localnode, cfg)
 		if err != nil {
@@ -549,6 +583,7 @@ func (srv *Server) setupDiscovery() error {
 			Bootnodes:      srv.BootstrapNodesV5,
 			Log:            srv.log,
 			FilterFunction: f,
+			HiddenNodes:    srv.ProxyedValidatorNodeIDs,
 		}
 		srv.discv5, err = discover.ListenV5(sconn, srv.localnode, cfg)
 		if err != nil {
@@ -574,6 +609,7 @@ func (srv *Server) setupDialScheduler() {
 		maxActiveDials: srv.MaxPendingPeers,
 		log:            srv.Logger,
 		netRestrict:    srv.NetRestrict,
+		banned:         srv.reputation.banned,
 		dialer:         srv.Dialer,
 		clock:          srv.clock,
 	}
@@ -584,6 +620,12 @@ func (srv *Server) setupDialScheduler() {
 		config.dialer = tcpDialer{&net.Dialer{Timeout: defaultDialTimeout}}
 	}
 	srv.dialsched = newDialScheduler(config, srv.discmix, srv.SetupConn)
+	if srv.sentryMode() {
+		for _, n := range srv.SentryNodes {
+			srv.dialsched.addStatic(n)
+		}
+		return
+	}
 	for _, n := range srv.StaticNodes {
 		srv.dialsched.addStatic(n)
 	}
@@ -601,6 +643,9 @@ func (srv *Server) SetFilter(f forkid.Filter) {
 }
 
 func (srv *Server) maxDialedConns() (limit int) {
+	if srv.sentryMode() {
+		return len(srv.SentryNodes)
+	}
 	if srv.NoDial {
 		return len(srv.StaticNodes) + len(srv.VerifyNodes)
 	}
@@ -809,6 +854,10 @@ running:
 
 func (srv *Server) postHandshakeChecks(peers map[enode.ID]*Peer, inboundCount int, c *conn) error {
 	switch {
+	case srv.sentryMode() && !srv.isSentryNode(c.node.ID()):
+		return errNotSentryNode
+	case srv.reputation.banned(c.node.ID()):
+		return errBannedPeer
 	case !c.is(trustedConn) && len(peers) >= srv.MaxPeers:
 		return DiscTooManyPeers
 	case !c.is(trustedConn) && c.is(inboundConn) && inboundCount >= srv.maxInboundConns():
@@ -1044,6 +1093,7 @@ func (srv *Server) checkpoint(c *conn, stage chan<- *conn) error {
 
 func (srv *Server) launchPeer(c *conn) *Peer {
 	p := newPeer(srv.log, c, srv.Protocols)
+	p.reputation = srv.reputation
 	if srv.EnableMsgEvents {
 		// If message events are enabled, pass the peerFeed
 		// to the peer.
@@ -1132,6 +1182,23 @@ func (srv *Server) NodeInfo() *NodeInfo {
 	return info
 }
 
+// PeerScores returns the misbehaviour scores of all penalized and banned nodes.
+func (srv *Server) PeerScores() []PeerScore {
+	if srv.reputation == nil {
+		return nil
+	}
+	return srv.reputation.list()
+}
+
+// Unban lifts the ban of a node and resets its misbehaviour score. It reports
+// whether the node had been penalized or banned.
+func (srv *Server) Unban(id enode.ID) bool {
+	if srv.reputation == nil {
+		return false
+	}
+	return srv.reputation.unban(id)
+}
+
 // PeersInfo returns an array of metadata objects describing connected peers.
 func (srv *Server) PeersInfo() []*PeerInfo {
 	// Gather all the generic and sub-protocol specific infos
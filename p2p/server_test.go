@@ -333,6 +333,94 @@ func TestServerAtCap(t *testing.T) {
 	}
 }
 
+func TestServerBannedPeer(t *testing.T) {
+	srv := &Server{
+		Config: Config{
+			PrivateKey:  newkey(),
+			MaxPeers:    10,
+			NoDial:      true,
+			NoDiscovery: true,
+			Logger:      testlog.Logger(t, log.LvlTrace),
+		},
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatalf("could not start: %v", err)
+	}
+	defer srv.Stop()
+
+	id := randomID()
+	newconn := func() *conn {
+		fd, _ := net.Pipe()
+		tx := newTestTransport(&newkey().PublicKey, fd, nil)
+		node := enode.SignNull(new(enr.Record), id)
+		return &conn{fd: fd, transport: tx, flags: inboundConn, node: node, cont: make(chan error)}
+	}
+	srv.reputation.penalize(id, PenaltyFatal, "test")
+	if err := srv.checkpoint(newconn(), srv.checkpointPostHandshake); err != errBannedPeer {
+		t.Errorf("wrong error for banned conn: %v", err)
+	}
+	if scores := srv.PeerScores(); len(scores) != 1 || scores[0].BannedUntil.IsZero() {
+		t.Errorf("unexpected peer scores: %+v", scores)
+	}
+	// Trusted peers are not let through either, the ban must be lifted explicitly
+	trusted := newconn()
+	trusted.flags |= trustedConn
+	if err := srv.checkpoint(trusted, srv.checkpointPostHandshake); err != errBannedPeer {
+		t.Errorf("wrong error for banned trusted conn: %v", err)
+	}
+	if !srv.Unban(id) {
+		t.Fatal("failed to unban peer")
+	}
+	if err := srv.checkpoint(newconn(), srv.checkpointPostHandshake); err != nil {
+		t.Errorf("unexpected error for unbanned conn: %v", err)
+	}
+}
+
+func TestServerSentryMode(t *testing.T) {
+	sentryKey, trustedKey := newkey(), newkey()
+	sentryID := enode.PubkeyToIDV4(&sentryKey.PublicKey)
+	trustedID := enode.PubkeyToIDV4(&trustedKey.PublicKey)
+	srv := &Server{
+		Config: Config{
+			PrivateKey:   newkey(),
+			MaxPeers:     10,
+			NoDial:       true,
+			SentryNodes:  []*enode.Node{newNode(sentryID, "")},
+			TrustedNodes: []*enode.Node{newNode(trustedID, "")},
+			Logger:       testlog.Logger(t, log.LvlTrace),
+		},
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatalf("could not start: %v", err)
+	}
+	defer srv.Stop()
+
+	if srv.discv4 != nil || srv.discv5 != nil {
+		t.Fatal("discovery running in sentry mode")
+	}
+	if n := srv.maxDialedConns(); n != 1 {
+		t.Errorf("wrong dial limit: have %d, want 1", n)
+	}
+	newconn := func(id enode.ID, flags connFlag) *conn {
+		fd, _ := net.Pipe()
+		tx := newTestTransport(&sentryKey.PublicKey, fd, nil)
+		node := enode.SignNull(new(enr.Record), id)
+		return &conn{fd: fd, transport: tx, flags: flags, node: node, cont: make(chan error)}
+	}
+	for _, flags := range []connFlag{inboundConn, dynDialedConn} {
+		if err := srv.checkpoint(newconn(randomID(), flags), srv.checkpointPostHandshake); err != errNotSentryNode {
+			t.Errorf("wrong error for random %v conn: %v", flags, err)
+		}
+		// Trusted nodes are not exempt in sentry mode.
+		if err := srv.checkpoint(newconn(trustedID, flags), srv.checkpointPostHandshake); err != errNotSentryNode {
+			t.Errorf("wrong error for trusted %v conn: %v", flags, err)
+		}
+	}
+	if err := srv.checkpoint(newconn(sentryID, inboundConn), srv.checkpointPostHandshake); err != nil {
+		t.Errorf("unexpected error for sentry conn: %v", err)
+	}
+}
+
 func TestServerPeerLimits(t *testing.T) {
 	srvkey := newkey()
 	clientkey := newkey()
@@ -2,242 +2,472 @@ package trie
 
 import (
 	"bytes"
+	"context"
+	"encoding/binary"
 	"errors"
 	"fmt"
-	"runtime"
+	"math/bits"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rlp"
-	"github.com/ethereum/go-ethereum/trie/trienode"
+	"github.com/ethereum/go-ethereum/triedb/database"
+	"golang.org/x/sync/errgroup"
 )
 
-type Hbss2Pbss struct {
-	trie            *Trie // traverse trie
-	db              Database
-	blocknum        uint64
-	root            node // root of triedb
-	stateRootHash   common.Hash
-	concurrentQueue chan struct{}
-	totalNum        uint64
-	wg              sync.WaitGroup
-}
-
 const (
-	DEFAULT_TRIEDBCACHE_SIZE = 1024 * 1024 * 1024
+	// hbss2pbssShards is the number of shards the account trie is split into
+	// by the first byte of the account hash. The shards are converted in
+	// parallel and checkpointed individually.
+	hbss2pbssShards = 256
+
+	// hbss2pbssLogInterval is the time interval between progress reports.
+	hbss2pbssLogInterval = 8 * time.Second
 )
 
-// NewHbss2Pbss return a hash2Path obj
-func NewHbss2Pbss(tr *Trie, db Database, stateRootHash common.Hash, blocknum uint64, jobnum uint64) (*Hbss2Pbss, error) {
-	if tr == nil {
-		return nil, errors.New("trie is nil")
-	}
+// Hbss2Pbss converts the state trie persisted in the hash scheme into the path
+// scheme. The account trie is split into shards which are converted in
+// parallel, along with the storage tries of the accounts in them. The finished
+// shards are checkpointed in the database, so that an interrupted conversion
+// can be resumed without starting over.
+type Hbss2Pbss struct {
+	db       database.NodeDatabase // Trie database of the hash scheme
+	disk     ethdb.KeyValueStore   // Key-value store for writing the path scheme nodes
+	root     common.Hash           // State root to convert
+	number   uint64                // Block number of the state
+	jobs     int                   // Number of concurrent shard and storage workers
+	progress *rawdb.Hbss2PbssProgress
+	lock     sync.Mutex // Lock protecting the progress
 
-	if tr.root == nil {
-		return nil, errors.New("trie root is nil")
+	markers      [hbss2pbssShards]atomic.Uint64 // Position of the last converted account in each shard
+	accounts     atomic.Uint64
+	accountNodes atomic.Uint64
+	storageNodes atomic.Uint64
+	size         atomic.Uint64
+	start        time.Time
+	startDone    float64 // Conversion progress when the run started
+}
+
+// NewHbss2Pbss creates the converter of the given state. The state is resumed
+// from the checkpoint in the database if any, a checkpoint of another state is
+// discarded.
+func NewHbss2Pbss(db database.NodeDatabase, disk ethdb.KeyValueStore, root common.Hash, number uint64, jobs int) (*Hbss2Pbss, error) {
+	if root == (common.Hash{}) {
+		return nil, errors.New("empty state root")
+	}
+	if jobs <= 0 {
+		jobs = 1
+	}
+	// Make sure the state is available before converting anything.
+	if _, err := New(StateTrieID(root), db); err != nil {
+		return nil, err
+	}
+	progress := rawdb.ReadHbss2PbssProgress(disk)
+	if progress != nil && (progress.Root != root || progress.Number != number || len(progress.Done) != hbss2pbssShards/8) {
+		log.Warn("Discarding stale hbss2pbss checkpoint", "root", progress.Root, "number", progress.Number)
+		progress = nil
 	}
+	if progress == nil {
+		progress = &rawdb.Hbss2PbssProgress{
+			Root:   root,
+			Number: number,
+			Done:   make([]byte, hbss2pbssShards/8),
+		}
+	} else {
+		log.Info("Resuming hbss2pbss conversion", "root", root, "number", number, "shards", fmt.Sprintf("%d/%d", convertedShards(progress.Done), hbss2pbssShards))
+	}
+	h2p := &Hbss2Pbss{
+		db:       db,
+		disk:     disk,
+		root:     root,
+		number:   number,
+		jobs:     jobs,
+		progress: progress,
+	}
+	h2p.accounts.Store(progress.Accounts)
+	h2p.accountNodes.Store(progress.AccountNodes)
+	h2p.storageNodes.Store(progress.StorageNodes)
+	return h2p, nil
+}
+
+// hbss2pbssShard is an account trie shard being converted.
+type hbss2pbssShard struct {
+	id           int
+	pending      sync.WaitGroup // Storage tries of the shard being converted
+	accounts     atomic.Uint64
+	accountNodes atomic.Uint64
+	storageNodes atomic.Uint64
 
-	ins := &Hbss2Pbss{
-		trie:            tr,
-		blocknum:        blocknum,
-		db:              db,
-		stateRootHash:   stateRootHash,
-		root:            tr.root,
-		concurrentQueue: make(chan struct{}, jobnum),
-		wg:              sync.WaitGroup{},
+	lock sync.Mutex
+	err  error // First failure in converting the storage tries
+}
+
+func (s *hbss2pbssShard) fail(err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.err == nil {
+		s.err = err
 	}
+}
 
-	return ins, nil
+// hbss2pbssStorage is a storage trie to convert.
+type hbss2pbssStorage struct {
+	shard *hbss2pbssShard
+	owner common.Hash
+	root  common.Hash
 }
 
-func (t *Trie) resloveWithoutTrack(n node, prefix []byte) (node, error) {
-	if n, ok := n.(hashNode); ok {
-		blob, err := t.reader.node(prefix, common.BytesToHash(n))
+// lastNode is a node resolver of the iterator, retaining the last resolved node
+// so that the blob retrieval of the node right after is served without reading
+// the database again.
+type lastNode struct {
+	reader database.NodeReader
+	hash   common.Hash
+	blob   []byte
+}
+
+func (n *lastNode) resolve(owner common.Hash, path []byte, hash common.Hash) []byte {
+	if hash != n.hash {
+		blob, err := n.reader.Node(owner, path, hash)
 		if err != nil {
-			return nil, err
+			return nil
 		}
-		return mustDecodeNode(n, blob), nil
+		n.hash, n.blob = hash, blob
 	}
-	return n, nil
+	return n.blob
 }
 
-func (h2p *Hbss2Pbss) writeNode(pathKey []byte, n *trienode.Node, owner common.Hash) {
-	if owner == (common.Hash{}) {
-		rawdb.WriteAccountTrieNode(h2p.db.Disk(), pathKey, n.Blob)
-		log.Debug("WriteNodes account node, ", "path: ", common.Bytes2Hex(pathKey), "Hash: ", n.Hash, "BlobHash: ", crypto.Keccak256Hash(n.Blob))
-	} else {
-		rawdb.WriteStorageTrieNode(h2p.db.Disk(), owner, pathKey, n.Blob)
-		log.Debug("WriteNodes storage node, ", "path: ", common.Bytes2Hex(pathKey), "owner: ", owner.String(), "Hash: ", n.Hash, "BlobHash: ", crypto.Keccak256Hash(n.Blob))
+// newIterator opens a node iterator of the given trie starting at the given key.
+func (h2p *Hbss2Pbss) newIterator(id *ID, start []byte) (NodeIterator, error) {
+	reader, err := h2p.db.NodeReader(h2p.root)
+	if err != nil {
+		return nil, err
+	}
+	tr, err := New(id, h2p.db)
+	if err != nil {
+		return nil, err
 	}
+	it, err := tr.NodeIterator(start)
+	if err != nil {
+		return nil, err
+	}
+	it.AddResolver((&lastNode{reader: reader}).resolve)
+	return it, nil
 }
 
-// Run statistics, external call
-func (h2p *Hbss2Pbss) Run() {
-	log.Debug("Find Account Trie Tree, rootHash: ", h2p.trie.Hash().String(), "BlockNum: ", h2p.blocknum)
-
-	h2p.ConcurrentTraversal(h2p.trie, h2p.root, []byte{})
-	h2p.wg.Wait()
-
-	log.Info("Total", "complete", h2p.totalNum, "go routines Num", runtime.NumGoroutine, "h2p concurrentQueue", len(h2p.concurrentQueue))
-
-	rawdb.WritePersistentStateID(h2p.db.Disk(), h2p.blocknum)
-	rawdb.WriteStateID(h2p.db.Disk(), h2p.stateRootHash, h2p.blocknum)
+// shardRange returns the account hash where the shard starts, and the path in
+// nibbles where the next shard starts. The first shard starts from the root to
+// include the nodes above the shard boundaries, and the end is nil for the last
+// shard.
+func shardRange(id int) ([]byte, []byte) {
+	var start []byte
+	if id > 0 {
+		start = []byte{byte(id)}
+	}
+	if id == hbss2pbssShards-1 {
+		return start, nil
+	}
+	next := id + 1
+	return start, []byte{byte(next >> 4), byte(next & 0xf)}
 }
 
-func (h2p *Hbss2Pbss) SubConcurrentTraversal(theTrie *Trie, theNode node, path []byte) {
-	h2p.concurrentQueue <- struct{}{}
-	h2p.ConcurrentTraversal(theTrie, theNode, path)
-	<-h2p.concurrentQueue
-	h2p.wg.Done()
+// done reports whether the shard has been converted.
+func (h2p *Hbss2Pbss) done(id int) bool {
+	h2p.lock.Lock()
+	defer h2p.lock.Unlock()
+
+	return h2p.progress.Done[id/8]&(1<<(id%8)) != 0
 }
 
-func (h2p *Hbss2Pbss) ConcurrentTraversal(theTrie *Trie, theNode node, path []byte) {
-	total_num := uint64(0)
-	// nil node
-	if theNode == nil {
-		return
+// convertedShards returns the number of the converted shards in the bitmap.
+func convertedShards(done []byte) int {
+	var n int
+	for _, b := range done {
+		n += bits.OnesCount8(b)
 	}
+	return n
+}
 
-	switch current := (theNode).(type) {
-	case *shortNode:
-		collapsed := current.copy()
-		collapsed.Key = hexToCompact(current.Key)
-		var hash, _ = current.cache()
-		h2p.writeNode(path, trienode.New(common.BytesToHash(hash), nodeToBytes(collapsed)), theTrie.owner)
-
-		h2p.ConcurrentTraversal(theTrie, current.Val, append(path, current.Key...))
-
-	case *fullNode:
-		// copy from trie/Committer (*committer).commit
-		collapsed := current.copy()
-		var hash, _ = collapsed.cache()
-		collapsed.Children = h2p.commitChildren(path, current)
+// Run converts the state trie and verifies the converted root. The persistent
+// state id is updated afterwards, marking the path scheme state available.
+func (h2p *Hbss2Pbss) Run() error {
+	h2p.start = time.Now()
+	h2p.startDone = h2p.fraction()
 
-		nodebytes := nodeToBytes(collapsed)
-		if common.BytesToHash(hash) != common.BytesToHash(crypto.Keccak256(nodebytes)) {
-			log.Error("Hash is inconsistent, hash: ", common.BytesToHash(hash), "node hash: ", common.BytesToHash(crypto.Keccak256(nodebytes)), "node: ", collapsed.fstring(""))
-			panic("hash inconsistent.")
+	if h2p.root != types.EmptyRootHash {
+		stop := make(chan struct{})
+		go h2p.report(stop)
+		err := h2p.convert()
+		close(stop)
+		if err != nil {
+			return err
 		}
+		// Verify the converted root node, the nodes beneath it are verified
+		// along the way.
+		blob := rawdb.ReadAccountTrieNode(h2p.disk, nil)
+		if hash := crypto.Keccak256Hash(blob); hash != h2p.root {
+			return fmt.Errorf("converted state root mismatch, want %#x, got %#x", h2p.root, hash)
+		}
+	}
+	rawdb.WritePersistentStateID(h2p.disk, h2p.number)
+	rawdb.WriteStateID(h2p.disk, h2p.root, h2p.number)
+	rawdb.DeleteHbss2PbssProgress(h2p.disk)
 
-		h2p.writeNode(path, trienode.New(common.BytesToHash(hash), nodeToBytes(collapsed)), theTrie.owner)
+	log.Info("Converted state trie to path scheme", "root", h2p.root, "number", h2p.number,
+		"accounts", h2p.accounts.Load(), "accountnodes", h2p.accountNodes.Load(), "storagenodes", h2p.storageNodes.Load(),
+		"elapsed", common.PrettyDuration(time.Since(h2p.start)))
+	return nil
+}
 
-		for idx, child := range current.Children {
-			if child == nil {
+// convert converts all the shards not yet converted, with the storage tries
+// converted concurrently by a separate group of workers.
+func (h2p *Hbss2Pbss) convert() error {
+	var (
+		g, ctx   = errgroup.WithContext(context.Background())
+		shards   = make(chan int)
+		storages = make(chan *hbss2pbssStorage, h2p.jobs)
+		workers  sync.WaitGroup
+	)
+	g.Go(func() error {
+		defer close(shards)
+		for id := 0; id < hbss2pbssShards; id++ {
+			if h2p.done(id) {
 				continue
 			}
-			childPath := append(path, byte(idx))
-			if len(h2p.concurrentQueue)*2 < cap(h2p.concurrentQueue) {
-				h2p.wg.Add(1)
-				dst := make([]byte, len(childPath))
-				copy(dst, childPath)
-				go h2p.SubConcurrentTraversal(theTrie, child, dst)
-			} else {
-				h2p.ConcurrentTraversal(theTrie, child, childPath)
+			select {
+			case shards <- id:
+			case <-ctx.Done():
+				return nil
 			}
 		}
-	case hashNode:
-		n, err := theTrie.resloveWithoutTrack(current, path)
-		if err != nil {
-			log.Error("Resolve HashNode", "error", err, "TrieRoot", theTrie.Hash(), "Path", path)
-			return
+		return nil
+	})
+	for i := 0; i < h2p.jobs; i++ {
+		workers.Add(1)
+		g.Go(func() error {
+			defer workers.Done()
+			for id := range shards {
+				if err := h2p.convertShard(ctx, id, storages); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	// The storage workers never bail out, all the scheduled storage tries are
+	// drained so that no shard worker is left waiting. The failures are reported
+	// by the shards owning the storage tries instead.
+	var storageWorkers sync.WaitGroup
+	for i := 0; i < h2p.jobs; i++ {
+		storageWorkers.Add(1)
+		go func() {
+			defer storageWorkers.Done()
+			for task := range storages {
+				if ctx.Err() == nil {
+					if err := h2p.convertStorage(task); err != nil {
+						task.shard.fail(err)
+					}
+				}
+				task.shard.pending.Done()
+			}
+		}()
+	}
+	go func() {
+		workers.Wait()
+		close(storages)
+	}()
+	err := g.Wait()
+	storageWorkers.Wait()
+	return err
+}
+
+// convertShard converts the account trie nodes in the specified shard, along
+// with the storage tries of the accounts in it. The shard is checkpointed once
+// all of them are converted.
+func (h2p *Hbss2Pbss) convertShard(ctx context.Context, id int, storages chan *hbss2pbssStorage) error {
+	start, end := shardRange(id)
+	it, err := h2p.newIterator(StateTrieID(h2p.root), start)
+	if err != nil {
+		return err
+	}
+	var (
+		shard = &hbss2pbssShard{id: id}
+		batch = h2p.disk.NewBatch()
+	)
+	// Wait for the scheduled storage tries in any case, they are referencing
+	// the shard.
+	defer shard.pending.Wait()
+
+	for it.Next(true) {
+		// The nodes are iterated in the order of their paths, the ones in the
+		// following shard are reached once the path exceeds the boundary.
+		if end != nil && bytes.Compare(it.Path(), end) >= 0 {
+			break
 		}
-		h2p.ConcurrentTraversal(theTrie, n, path)
-		total_num = atomic.AddUint64(&h2p.totalNum, 1)
-		if total_num%100000 == 0 {
-			log.Info("Converting ", "Complete progress", total_num, "go routines Num", runtime.NumGoroutine(), "h2p concurrentQueue", len(h2p.concurrentQueue))
+		if ctx.Err() != nil {
+			return ctx.Err()
 		}
-		return
-	case valueNode:
-		if !hasTerm(path) {
-			log.Info("ValueNode miss path term", "path", common.Bytes2Hex(path))
-			break
+		if it.Leaf() {
+			var account types.StateAccount
+			if err := rlp.DecodeBytes(it.LeafBlob(), &account); err != nil {
+				return fmt.Errorf("invalid account %x: %v", it.LeafKey(), err)
+			}
+			owner := common.BytesToHash(it.LeafKey())
+			h2p.markers[id].Store(binary.BigEndian.Uint64(owner[:8]))
+			shard.accounts.Add(1)
+			h2p.accounts.Add(1)
+
+			if account.Root != types.EmptyRootHash && account.Root != (common.Hash{}) {
+				shard.pending.Add(1)
+				select {
+				case storages <- &hbss2pbssStorage{shard: shard, owner: owner, root: account.Root}:
+				case <-ctx.Done():
+					shard.pending.Done()
+					return ctx.Err()
+				}
+			}
+			continue
 		}
-		var account types.StateAccount
-		if err := rlp.Decode(bytes.NewReader(current), &account); err != nil {
-			// log.Info("Rlp decode account failed.", "err", err)
-			break
+		// The embedded nodes are stored along with their parents.
+		hash := it.Hash()
+		if hash == (common.Hash{}) {
+			continue
 		}
-		if account.Root == (common.Hash{}) || account.Root == types.EmptyRootHash {
-			// log.Info("Not a storage trie.", "account", common.BytesToHash(path).String())
-			break
+		blob := it.NodeBlob()
+		if crypto.Keccak256Hash(blob) != hash {
+			return fmt.Errorf("corrupted account trie node, path: %x, hash: %#x", it.Path(), hash)
 		}
+		rawdb.WriteAccountTrieNode(batch, it.Path(), blob)
+		shard.accountNodes.Add(1)
+		h2p.accountNodes.Add(1)
+		h2p.size.Add(uint64(len(blob)))
 
-		ownerAddress := common.BytesToHash(hexToCompact(path))
-		tr, err := New(StorageTrieID(h2p.stateRootHash, ownerAddress, account.Root), h2p.db)
-		if err != nil {
-			log.Error("New Storage trie error", "err", err, "root", account.Root.String(), "owner", ownerAddress.String())
-			break
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
 		}
-		log.Debug("Find Contract Trie Tree", "rootHash: ", tr.Hash().String(), "")
-		h2p.wg.Add(1)
-		go h2p.SubConcurrentTraversal(tr, tr.root, []byte{})
-	default:
-		panic(errors.New("Invalid node type to traverse."))
 	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	shard.pending.Wait()
+	if shard.err != nil {
+		return shard.err
+	}
+	h2p.commit(shard)
+	return nil
 }
 
-// copy from trie/Committer (*committer).commit
-func (h2p *Hbss2Pbss) commitChildren(path []byte, n *fullNode) [17]node {
-	var children [17]node
-	for i := 0; i < 16; i++ {
-		child := n.Children[i]
-		if child == nil {
+// convertStorage converts the nodes of the specified storage trie, and checks
+// the converted root afterwards.
+func (h2p *Hbss2Pbss) convertStorage(task *hbss2pbssStorage) error {
+	it, err := h2p.newIterator(StorageTrieID(h2p.root, task.owner, task.root), nil)
+	if err != nil {
+		return err
+	}
+	batch := h2p.disk.NewBatch()
+	for it.Next(true) {
+		hash := it.Hash()
+		if hash == (common.Hash{}) {
 			continue
 		}
-		// If it's the hashed child, save the hash value directly.
-		// Note: it's impossible that the child in range [0, 15]
-		// is a valueNode.
-		if hn, ok := child.(hashNode); ok {
-			children[i] = hn
+		blob := it.NodeBlob()
+		if crypto.Keccak256Hash(blob) != hash {
+			return fmt.Errorf("corrupted storage trie node, owner: %#x, path: %x, hash: %#x", task.owner, it.Path(), hash)
+		}
+		rawdb.WriteStorageTrieNode(batch, task.owner, it.Path(), blob)
+		task.shard.storageNodes.Add(1)
+		h2p.storageNodes.Add(1)
+		h2p.size.Add(uint64(len(blob)))
+
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	if hash := crypto.Keccak256Hash(rawdb.ReadStorageTrieNode(h2p.disk, task.owner, nil)); hash != task.root {
+		return fmt.Errorf("converted storage root mismatch, owner: %#x, want %#x, got %#x", task.owner, task.root, hash)
+	}
+	return nil
+}
+
+// commit marks the shard as converted and persists the checkpoint.
+func (h2p *Hbss2Pbss) commit(shard *hbss2pbssShard) {
+	h2p.lock.Lock()
+	defer h2p.lock.Unlock()
+
+	h2p.progress.Done[shard.id/8] |= 1 << (shard.id % 8)
+	h2p.progress.Accounts += shard.accounts.Load()
+	h2p.progress.AccountNodes += shard.accountNodes.Load()
+	h2p.progress.StorageNodes += shard.storageNodes.Load()
+	rawdb.WriteHbss2PbssProgress(h2p.disk, h2p.progress)
+}
+
+// fraction returns the estimated progress of the conversion in the range of
+// [0, 1], the shards being converted are accounted by the position of their
+// last converted accounts.
+func (h2p *Hbss2Pbss) fraction() float64 {
+	var done float64
+	for id := 0; id < hbss2pbssShards; id++ {
+		if h2p.done(id) {
+			done += 1
 			continue
 		}
+		if marker := h2p.markers[id].Load(); marker > uint64(id)<<56 {
+			done += float64(marker-uint64(id)<<56) / float64(uint64(1)<<56)
+		}
+	}
+	return done / hbss2pbssShards
+}
+
+// report periodically logs the conversion progress along with the estimated
+// remaining time, until the stop channel is closed.
+func (h2p *Hbss2Pbss) report(stop chan struct{}) {
+	ticker := time.NewTicker(hbss2pbssLogInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+		var (
+			done    = h2p.fraction()
+			elapsed = time.Since(h2p.start)
+			eta     time.Duration
+		)
+		if done > h2p.startDone {
+			eta = time.Duration(float64(elapsed) / (done - h2p.startDone) * (1 - done))
+		}
+		h2p.lock.Lock()
+		shards := convertedShards(h2p.progress.Done)
+		h2p.lock.Unlock()
 
-		children[i] = h2p.commit(append(path, byte(i)), child)
-	}
-	// For the 17th child, it's possible the type is valuenode.
-	if n.Children[16] != nil {
-		children[16] = n.Children[16]
-	}
-	return children
-}
-
-// commit collapses a node down into a hash node and returns it.
-func (h2p *Hbss2Pbss) commit(path []byte, n node) node {
-	// if this path is clean, use available cached data
-	hash, dirty := n.cache()
-	if hash != nil && !dirty {
-		return hash
-	}
-	// Commit children, then parent, and remove the dirty flag.
-	switch cn := n.(type) {
-	case *shortNode:
-		// Commit child
-		collapsed := cn.copy()
-
-		// If the child is fullNode, recursively commit,
-		// otherwise it can only be hashNode or valueNode.
-		if _, ok := cn.Val.(*fullNode); ok {
-			collapsed.Val = h2p.commit(append(path, cn.Key...), cn.Val)
-		}
-		// The key needs to be copied, since we're adding it to the
-		// modified nodeset.
-		collapsed.Key = hexToCompact(cn.Key)
-		return collapsed
-	case *fullNode:
-		hashedKids := h2p.commitChildren(path, cn)
-		collapsed := cn.copy()
-		collapsed.Children = hashedKids
-
-		return collapsed
-	case hashNode:
-		return cn
-	default:
-		// nil, valuenode shouldn't be committed
-		panic(fmt.Sprintf("%T: invalid node: %v", n, n))
+		log.Info("Converting state trie to path scheme", "shards", fmt.Sprintf("%d/%d", shards, hbss2pbssShards),
+			"accounts", h2p.accounts.Load(), "accountnodes", h2p.accountNodes.Load(), "storagenodes", h2p.storageNodes.Load(),
+			"size", common.StorageSize(h2p.size.Load()), "progress", fmt.Sprintf("%.2f%%", done*100),
+			"elapsed", common.PrettyDuration(elapsed), "eta", common.PrettyDuration(eta))
 	}
 }
@@ -0,0 +1,238 @@
+package trie
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/internal/testrand"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie/trienode"
+	"github.com/holiman/uint256"
+)
+
+// makeHbss2PbssState creates a state with the given number of accounts, a part
+// of them having storage tries, persisted in both the hash and path scheme.
+func makeHbss2PbssState(t *testing.T, accounts int) (common.Hash, *testDb, ethdb.Database) {
+	var (
+		hdb   = newTestDatabase(rawdb.NewMemoryDatabase(), rawdb.HashScheme)
+		pdb   = newTestDatabase(rawdb.NewMemoryDatabase(), rawdb.PathScheme)
+		set   = trienode.NewMergedNodeSet()
+		tr, _ = New(TrieID(types.EmptyRootHash), hdb)
+	)
+	for i := 0; i < accounts; i++ {
+		var (
+			owner   = testrand.Hash()
+			account = types.StateAccount{
+				Nonce:    uint64(i),
+				Balance:  uint256.NewInt(uint64(i)),
+				Root:     types.EmptyRootHash,
+				CodeHash: types.EmptyCodeHash.Bytes(),
+			}
+		)
+		if i%3 == 0 {
+			st, _ := New(StorageTrieID(types.EmptyRootHash, owner, types.EmptyRootHash), hdb)
+			for j := 0; j < 1+i%40; j++ {
+				st.MustUpdate(testrand.Bytes(32), testrand.Bytes(1+j%32))
+			}
+			root, nodes := st.Commit(false)
+			if err := set.Merge(nodes); err != nil {
+				t.Fatalf("Failed to merge nodes: %v", err)
+			}
+			account.Root = root
+		}
+		blob, _ := rlp.EncodeToBytes(&account)
+		tr.MustUpdate(owner.Bytes(), blob)
+	}
+	root, nodes := tr.Commit(false)
+	if err := set.Merge(nodes); err != nil {
+		t.Fatalf("Failed to merge nodes: %v", err)
+	}
+	for _, db := range []*testDb{hdb, pdb} {
+		db.Update(root, types.EmptyRootHash, set)
+		db.Commit(root)
+	}
+	return root, hdb, pdb.disk
+}
+
+// nodeShard returns the account trie shard the path scheme node belongs to.
+func nodeShard(key []byte) int {
+	if ok, owner, _ := rawdb.ResolveStorageTrieNode(key); ok {
+		return int(owner[0])
+	}
+	_, path := rawdb.ResolveAccountTrieNodeKey(key)
+	for id := 0; id < hbss2pbssShards; id++ {
+		if _, end := shardRange(id); end == nil || bytes.Compare(path, end) < 0 {
+			return id
+		}
+	}
+	panic("unreachable")
+}
+
+// checkTrieNodes ensures the path scheme nodes in the two databases are the same.
+func checkTrieNodes(want, got ethdb.Database) error {
+	var wantNodes, gotNodes int
+	it := want.NewIterator(nil, nil)
+	defer it.Release()
+	for it.Next() {
+		if !rawdb.IsAccountTrieNode(it.Key()) && !rawdb.IsStorageTrieNode(it.Key()) {
+			continue
+		}
+		wantNodes++
+		blob, _ := got.Get(it.Key())
+		if !bytes.Equal(blob, it.Value()) {
+			return fmt.Errorf("node %x mismatch, want %x, got %x", it.Key(), it.Value(), blob)
+		}
+	}
+	it2 := got.NewIterator(nil, nil)
+	defer it2.Release()
+	for it2.Next() {
+		if rawdb.IsAccountTrieNode(it2.Key()) || rawdb.IsStorageTrieNode(it2.Key()) {
+			gotNodes++
+		}
+	}
+	if wantNodes != gotNodes {
+		return fmt.Errorf("node count mismatch, want %d, got %d", wantNodes, gotNodes)
+	}
+	return nil
+}
+
+func TestHbss2Pbss(t *testing.T) {
+	for _, accounts := range []int{1, 2, 20, 2000} {
+		root, hdb, want := makeHbss2PbssState(t, accounts)
+
+		disk := rawdb.NewMemoryDatabase()
+		h2p, err := NewHbss2Pbss(hdb, disk, root, 100, 4)
+		if err != nil {
+			t.Fatalf("Failed to create converter: %v", err)
+		}
+		if err := h2p.Run(); err != nil {
+			t.Fatalf("Failed to convert state with %d accounts: %v", accounts, err)
+		}
+		if err := checkTrieNodes(want, disk); err != nil {
+			t.Fatalf("Unexpected conversion of %d accounts: %v", accounts, err)
+		}
+		if h2p.accounts.Load() != uint64(accounts) {
+			t.Fatalf("Unexpected account count, want %d, got %d", accounts, h2p.accounts.Load())
+		}
+		if id := rawdb.ReadPersistentStateID(disk); id != 100 {
+			t.Fatalf("Unexpected persistent state id, want %d, got %d", 100, id)
+		}
+		if id := rawdb.ReadStateID(disk, root); id == nil || *id != 100 {
+			t.Fatalf("Unexpected state id, want %d, got %v", 100, id)
+		}
+		if rawdb.ReadHbss2PbssProgress(disk) != nil {
+			t.Fatal("Unexpected checkpoint after conversion")
+		}
+	}
+}
+
+func TestHbss2PbssResume(t *testing.T) {
+	root, hdb, want := makeHbss2PbssState(t, 2000)
+
+	// Pretend an interrupted conversion with the even shards converted.
+	var (
+		disk     = rawdb.NewMemoryDatabase()
+		progress = &rawdb.Hbss2PbssProgress{
+			Root:   root,
+			Number: 100,
+			Done:   make([]byte, hbss2pbssShards/8),
+		}
+		accountNodes, storageNodes uint64
+	)
+	for id := 0; id < hbss2pbssShards; id += 2 {
+		progress.Done[id/8] |= 1 << (id % 8)
+	}
+	it := want.NewIterator(nil, nil)
+	for it.Next() {
+		isAccount, isStorage := rawdb.IsAccountTrieNode(it.Key()), rawdb.IsStorageTrieNode(it.Key())
+		if !isAccount && !isStorage {
+			continue
+		}
+		if isAccount {
+			accountNodes++
+		} else {
+			storageNodes++
+		}
+		if nodeShard(it.Key())%2 == 0 {
+			disk.Put(it.Key(), it.Value())
+			if isAccount {
+				progress.AccountNodes++
+			} else {
+				progress.StorageNodes++
+			}
+		}
+	}
+	it.Release()
+	rawdb.WriteHbss2PbssProgress(disk, progress)
+
+	h2p, err := NewHbss2Pbss(hdb, disk, root, 100, 4)
+	if err != nil {
+		t.Fatalf("Failed to create converter: %v", err)
+	}
+	if err := h2p.Run(); err != nil {
+		t.Fatalf("Failed to resume conversion: %v", err)
+	}
+	if err := checkTrieNodes(want, disk); err != nil {
+		t.Fatalf("Unexpected resumed conversion: %v", err)
+	}
+	// The converted shards should be skipped.
+	if h2p.accountNodes.Load() != accountNodes || h2p.storageNodes.Load() != storageNodes {
+		t.Fatalf("Unexpected node count, want %d/%d, got %d/%d", accountNodes, storageNodes, h2p.accountNodes.Load(), h2p.storageNodes.Load())
+	}
+	if rawdb.ReadHbss2PbssProgress(disk) != nil {
+		t.Fatal("Unexpected checkpoint after conversion")
+	}
+}
+
+func TestHbss2PbssStaleCheckpoint(t *testing.T) {
+	root, hdb, want := makeHbss2PbssState(t, 200)
+
+	// The checkpoint of another state should be discarded.
+	disk := rawdb.NewMemoryDatabase()
+	done := bytes.Repeat([]byte{0xff}, hbss2pbssShards/8)
+	rawdb.WriteHbss2PbssProgress(disk, &rawdb.Hbss2PbssProgress{Root: common.Hash{0x1}, Number: 100, Done: done})
+
+	h2p, err := NewHbss2Pbss(hdb, disk, root, 100, 4)
+	if err != nil {
+		t.Fatalf("Failed to create converter: %v", err)
+	}
+	if err := h2p.Run(); err != nil {
+		t.Fatalf("Failed to convert state: %v", err)
+	}
+	if err := checkTrieNodes(want, disk); err != nil {
+		t.Fatalf("Unexpected conversion: %v", err)
+	}
+}
+
+func TestHbss2PbssCorrupted(t *testing.T) {
+	root, hdb, _ := makeHbss2PbssState(t, 200)
+
+	// Replace a node with another one in the hash scheme database.
+	var keys [][]byte
+	it := hdb.disk.NewIterator(nil, nil)
+	for it.Next() {
+		if rawdb.IsLegacyTrieNode(it.Key(), it.Value()) && common.BytesToHash(it.Key()) != root {
+			keys = append(keys, common.CopyBytes(it.Key()))
+		}
+	}
+	it.Release()
+	blob, _ := hdb.disk.Get(keys[1])
+	hdb.disk.Put(keys[0], blob)
+
+	disk := rawdb.NewMemoryDatabase()
+	h2p, err := NewHbss2Pbss(hdb, disk, root, 100, 4)
+	if err != nil {
+		t.Fatalf("Failed to create converter: %v", err)
+	}
+	if err := h2p.Run(); err == nil {
+		t.Fatal("Expected failure for corrupted state")
+	}
+	if rawdb.ReadPersistentStateID(disk) != 0 {
+		t.Fatal("Unexpected persistent state id after failed conversion")
+	}
+}
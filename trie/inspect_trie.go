@@ -31,7 +31,10 @@ type Database interface {
 	Disk() ethdb.Database
 }
 
-const TopN = 3
+const (
+	TopN                     = 3
+	DEFAULT_TRIEDBCACHE_SIZE = 1024 * 1024 * 1024
+)
 
 type Inspector struct {
 	trie           *Trie // traverse trie
@@ -211,6 +214,17 @@ func (s *Inspector) Run() {
 	s.wg.Wait()
 }
 
+func (t *Trie) resloveWithoutTrack(n node, prefix []byte) (node, error) {
+	if n, ok := n.(hashNode); ok {
+		blob, err := t.reader.node(prefix, common.BytesToHash(n))
+		if err != nil {
+			return nil, err
+		}
+		return mustDecodeNode(n, blob), nil
+	}
+	return n, nil
+}
+
 func (s *Inspector) traversal(trie *Trie, ts *trieStat, n node, height int, path []byte) {
 	// nil node
 	if n == nil {
@@ -17,6 +17,7 @@
 package triedb
 
 import (
+	"context"
 	"errors"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -70,3 +71,65 @@ func (db *Database) HistoryRange() (uint64, uint64, error) {
 	}
 	return pdb.HistoryRange()
 }
+
+// ChangesRange returns the block numbers associated with the earliest and latest
+// state history which can be queried by AccountChanges and StorageChanges.
+//
+// This function is only supported by path mode database.
+func (db *Database) ChangesRange() (uint64, uint64, error) {
+	pdb, ok := db.backend.(*pathdb.Database)
+	if !ok {
+		return 0, 0, errors.New("not supported")
+	}
+	return pdb.ChangesRange()
+}
+
+// HistoryIndex returns the id of the first state history whose associated
+// block number is not lower than the given one.
+//
+// This function is only supported by path mode database.
+func (db *Database) HistoryIndex(number uint64) (uint64, error) {
+	pdb, ok := db.backend.(*pathdb.Database)
+	if !ok {
+		return 0, errors.New("not supported")
+	}
+	return pdb.HistoryIndex(number)
+}
+
+// AccountChanges inspects the mutations of the account within the specified
+// range, resolving the account value after each mutation as well. Limit is
+// the maximum number of histories beyond the range to inspect for resolving
+// the value after the last mutation.
+//
+// This function is only supported by path mode database.
+func (db *Database) AccountChanges(address common.Address, start, end, limit uint64) (*pathdb.StateChanges, error) {
+	pdb, ok := db.backend.(*pathdb.Database)
+	if !ok {
+		return nil, errors.New("not supported")
+	}
+	return pdb.AccountChanges(address, start, end, limit)
+}
+
+// StorageChanges inspects the mutations of the storage slot within the specified
+// range, resolving the slot value after each mutation as well.
+//
+// This function is only supported by path mode database.
+func (db *Database) StorageChanges(address common.Address, slot common.Hash, start, end, limit uint64) (*pathdb.StateChanges, error) {
+	pdb, ok := db.backend.(*pathdb.Database)
+	if !ok {
+		return nil, errors.New("not supported")
+	}
+	return pdb.StorageChanges(address, slot, start, end, limit)
+}
+
+// HistoricReader constructs a reader for accessing the requested historical
+// state, which is resolved from the local state histories.
+//
+// This function is only supported by path mode database.
+func (db *Database) HistoricReader(ctx context.Context, root common.Hash) (*pathdb.HistoricalStateReader, error) {
+	pdb, ok := db.backend.(*pathdb.Database)
+	if !ok {
+		return nil, errors.New("not supported")
+	}
+	return pdb.HistoricReader(ctx, root)
+}
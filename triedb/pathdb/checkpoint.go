@@ -0,0 +1,633 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package pathdb
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie/trienode"
+)
+
+// The checkpoint journal is maintained next to the layer journal while the node
+// is running, so that the in-memory layers survive an unclean shutdown. It's a
+// sequence of entries, each encoded as an RLP byte blob followed by its sha256
+// checksum:
+//
+//   - the journal version
+//   - a base entry, snapshotting the disk layer along with its node buffer and
+//     the persistent state it was taken on top of
+//   - any number of diff entries, appended incrementally as diff layers are
+//     created, each one recording a single diff layer and its parent
+//
+// Diff entries outlive the diff layers they describe, which get merged into the
+// node buffer and eventually flushed. Recovery therefore resolves the disk layer
+// from the persistent state, then stacks all recorded diffs on top which are
+// continuous with it. A torn or corrupted tail left by a crash in the middle of
+// an append is discarded, falling back to the last valid checkpoint before it.
+// Once the obsolete entries dominate, the journal is rewritten from scratch by
+// atomically replacing the file. The rewrite runs in the background on a copy of
+// the layers taken at the checkpoint, the diff layers checkpointed meanwhile are
+// appended once it's in place.
+const (
+	checkpointBase uint8 = iota // Entry holding the disk layer and its node buffer
+	checkpointDiff              // Entry holding a single diff layer
+)
+
+// checkpointCompactSize is the minimum size of the checkpoint journal before
+// it's considered for being rewritten to drop the obsolete entries.
+const checkpointCompactSize = 64 * 1024 * 1024
+
+// errCheckpointUnmatched is returned if neither the base entry of the checkpoint
+// journal nor any recorded layer is continuous with the persistent state.
+var errCheckpointUnmatched = errors.New("checkpoint unmatched with persistent state")
+
+// checkpointer tracks the checkpoint journal being written.
+type checkpointer struct {
+	path     string   // Filesystem path of the checkpoint journal
+	interval uint64   // Number of state transitions between two checkpoints
+	file     *os.File // Checkpoint journal opened for appending, nil if not yet created
+	size     uint64   // Size of the checkpoint journal in bytes
+	pending  uint64   // Number of state transitions since the last checkpoint
+
+	journaled map[common.Hash]struct{} // Diff layers already recorded in the journal
+	rewrite   *checkpointRewrite       // Rewrite of the journal in progress, nil if none
+}
+
+// checkpointRewrite is a rewrite of the checkpoint journal running in the
+// background.
+type checkpointRewrite struct {
+	done    chan error   // Result of the rewrite, delivered once finished
+	file    *os.File     // Rewritten journal opened for appending, set before done
+	size    uint64       // Size of the rewritten journal in bytes, set before done
+	layers  int          // Number of diff layers in the rewritten journal
+	backlog bytes.Buffer // Entries of the diff layers checkpointed during the rewrite
+}
+
+// finishRewrite installs the journal rewritten in the background, appending the
+// diff layers checkpointed in the meantime.
+func (cp *checkpointer) finishRewrite(err error) error {
+	rw := cp.rewrite
+	cp.rewrite = nil
+	if err != nil {
+		return err
+	}
+	cp.file, cp.size = rw.file, rw.size
+	checkpointBytesMeter.Mark(int64(rw.size))
+	log.Debug("Rewrote checkpoint journal", "layers", rw.layers, "size", common.StorageSize(rw.size))
+
+	if rw.backlog.Len() == 0 {
+		return nil
+	}
+	if _, err := cp.file.Write(rw.backlog.Bytes()); err != nil {
+		return err
+	}
+	if err := cp.file.Sync(); err != nil {
+		return err
+	}
+	cp.size += uint64(rw.backlog.Len())
+	checkpointBytesMeter.Mark(int64(rw.backlog.Len()))
+	return nil
+}
+
+// close releases the file handle of the checkpoint journal, the next checkpoint
+// will rewrite it from scratch. A rewrite in progress is waited for and installed
+// first, so that no layers checkpointed during it are lost.
+func (cp *checkpointer) close() {
+	if cp.rewrite != nil {
+		if err := cp.finishRewrite(<-cp.rewrite.done); err != nil {
+			log.Error("Failed to rewrite checkpoint journal", "err", err)
+		}
+	}
+	if cp.file != nil {
+		cp.file.Close()
+		cp.file = nil
+	}
+	cp.size = 0
+	cp.journaled = make(map[common.Hash]struct{})
+}
+
+// checkpointPath returns the path of the checkpoint journal, or an empty string
+// if there is no file system location configured for the layer journal.
+func (db *Database) checkpointPath() string {
+	if db.config.JournalFilePath == "" {
+		return ""
+	}
+	return db.config.JournalFilePath + ".ckpt"
+}
+
+// checkpoint is invoked after each state transition, writing out the layers to
+// the checkpoint journal every configured number of transitions. Failures are
+// not fatal, they merely leave the journal behind until the next attempt.
+//
+// It assumes the db.lock is already held.
+func (db *Database) checkpoint() {
+	cp := db.checkpointer
+	if cp == nil {
+		return
+	}
+	cp.pending++
+
+	// Install the journal if the rewrite in the background finished
+	if cp.rewrite != nil {
+		select {
+		case err := <-cp.rewrite.done:
+			if err := cp.finishRewrite(err); err != nil {
+				cp.close()
+				log.Error("Failed to rewrite checkpoint journal", "err", err)
+				return
+			}
+		default:
+		}
+	}
+	if (cp.file != nil || cp.rewrite != nil) && cp.pending < cp.interval {
+		return
+	}
+	cp.pending = 0
+
+	var (
+		start = time.Now()
+		err   error
+	)
+	diffs, nodes, immutableNodes := db.Size()
+	if live := uint64(diffs + nodes + immutableNodes); cp.rewrite == nil && (cp.file == nil || (cp.size > checkpointCompactSize && cp.size > 2*live)) {
+		err = db.writeCheckpoint()
+	} else {
+		err = db.appendCheckpoint()
+	}
+	if err != nil {
+		cp.close()
+		log.Error("Failed to checkpoint layer journal", "err", err)
+		return
+	}
+	checkpointTimeTimer.UpdateSince(start)
+}
+
+// writeCheckpoint starts rewriting a fresh checkpoint journal in the background
+// with the current disk layer and all diff layers on top, atomically replacing
+// the existing one. Only the node buffer of the disk layer is copied here, the
+// diff layers are immutable.
+func (db *Database) writeCheckpoint() error {
+	cp := db.checkpointer
+	cp.close()
+
+	base, err := db.tree.bottom().captureCheckpoint()
+	if err != nil {
+		return err
+	}
+	diffs := db.diffLayers()
+	for _, dl := range diffs {
+		cp.journaled[dl.root] = struct{}{}
+	}
+	rw := &checkpointRewrite{done: make(chan error, 1), layers: len(diffs)}
+	cp.rewrite = rw
+
+	go func() {
+		rw.done <- rw.write(cp.path, base, diffs)
+	}()
+	return nil
+}
+
+// write encodes the captured layers into a fresh checkpoint journal and replaces
+// the existing one with it.
+func (rw *checkpointRewrite) write(path string, base *diskCheckpoint, diffs []*diffLayer) error {
+	start := time.Now()
+
+	var buf bytes.Buffer
+	if err := rlp.Encode(&buf, journalVersion); err != nil {
+		return err
+	}
+	if err := base.encode(&buf); err != nil {
+		return err
+	}
+	for _, dl := range diffs {
+		if err := dl.checkpoint(&buf); err != nil {
+			return err
+		}
+	}
+	tmp := path + ".tmp"
+	if err := writeFileSync(tmp, buf.Bytes()); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+	if err := syncDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	rw.file, rw.size = file, uint64(buf.Len())
+	checkpointTimeTimer.UpdateSince(start)
+	return nil
+}
+
+// appendCheckpoint appends the diff layers not yet recorded to the checkpoint
+// journal.
+func (db *Database) appendCheckpoint() error {
+	var (
+		cp     = db.checkpointer
+		buf    bytes.Buffer
+		roots  []common.Hash
+		layers = make(map[common.Hash]struct{})
+	)
+	for _, dl := range db.diffLayers() {
+		layers[dl.root] = struct{}{}
+		if _, ok := cp.journaled[dl.root]; ok {
+			continue
+		}
+		if err := dl.checkpoint(&buf); err != nil {
+			return err
+		}
+		roots = append(roots, dl.root)
+	}
+	// Forget about the layers no longer tracked, they will never be recorded again
+	for root := range cp.journaled {
+		if _, ok := layers[root]; !ok {
+			delete(cp.journaled, root)
+		}
+	}
+	if buf.Len() == 0 {
+		return nil
+	}
+	// Keep the entries until the journal being rewritten is in place
+	if cp.rewrite != nil {
+		cp.rewrite.backlog.Write(buf.Bytes())
+		for _, root := range roots {
+			cp.journaled[root] = struct{}{}
+		}
+		return nil
+	}
+	if _, err := cp.file.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	if err := cp.file.Sync(); err != nil {
+		return err
+	}
+	cp.size += uint64(buf.Len())
+	for _, root := range roots {
+		cp.journaled[root] = struct{}{}
+	}
+	checkpointBytesMeter.Mark(int64(buf.Len()))
+	log.Debug("Appended checkpoint journal", "layers", len(roots), "size", common.StorageSize(cp.size))
+	return nil
+}
+
+// removeCheckpoint deletes the checkpoint journal, used once the layers are
+// journaled on shutdown or the layers recorded got invalidated.
+func (db *Database) removeCheckpoint() {
+	if db.checkpointer != nil {
+		db.checkpointer.close()
+		db.checkpointer.pending = 0
+	}
+	path := db.checkpointPath()
+	if path == "" {
+		return
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Error("Failed to remove checkpoint journal", "path", path, "err", err)
+	}
+}
+
+// diffLayers returns all diff layers in the tree, ordered by state id so that
+// parents always precede their children.
+func (db *Database) diffLayers() []*diffLayer {
+	var diffs []*diffLayer
+	db.tree.forEach(func(l layer) {
+		if dl, ok := l.(*diffLayer); ok {
+			diffs = append(diffs, dl)
+		}
+	})
+	sort.Slice(diffs, func(i, j int) bool {
+		if diffs[i].id != diffs[j].id {
+			return diffs[i].id < diffs[j].id
+		}
+		return bytes.Compare(diffs[i].root[:], diffs[j].root[:]) < 0
+	})
+	return diffs
+}
+
+// diskCheckpoint is the disk layer along with its node buffer, captured as the
+// base entry of the checkpoint journal.
+type diskCheckpoint struct {
+	stored   uint64      // Persistent state id the node buffer is on top of
+	diskRoot common.Hash // Persistent state root the node buffer is on top of
+	root     common.Hash
+	id       uint64
+	nodes    *nodeSet
+	states   *stateSet
+}
+
+// captureCheckpoint captures the disk layer along with its node buffer, to be
+// encoded while the layer keeps being updated.
+func (dl *diskLayer) captureCheckpoint() (*diskCheckpoint, error) {
+	dl.lock.RLock()
+	defer dl.lock.RUnlock()
+
+	if dl.stale {
+		return nil, errSnapshotStale
+	}
+	// Snapshot the buffer before resolving the persistent state. A background
+	// flush completing in between leaves already persisted nodes in the snapshot,
+	// which is harmless as writing them again is idempotent. The async buffer
+	// hands out a merged copy, the sync one is updated in place.
+	nodes, states := dl.buffer.getAllNodesAndStates()
+	if _, ok := dl.buffer.(*buffer); ok {
+		nodes, states = copyNodesAndStates(nodes, states)
+	}
+	stored := rawdb.ReadPersistentStateID(dl.db.diskdb)
+	diskRoot, err := dl.db.hasher(rawdb.ReadAccountTrieNode(dl.db.diskdb, nil))
+	if err != nil {
+		return nil, err
+	}
+	if rawdb.ReadPersistentStateID(dl.db.diskdb) != stored {
+		return nil, errors.New("node buffer flushed during checkpoint")
+	}
+	return &diskCheckpoint{
+		stored:   stored,
+		diskRoot: diskRoot,
+		root:     dl.root,
+		id:       dl.id,
+		nodes:    nodes,
+		states:   states,
+	}, nil
+}
+
+// encode writes the captured disk layer as the base entry of the checkpoint
+// journal.
+func (c *diskCheckpoint) encode(w io.Writer) error {
+	payload := new(bytes.Buffer)
+	for _, v := range []interface{}{checkpointBase, c.stored, c.diskRoot, c.root, c.id} {
+		if err := rlp.Encode(payload, v); err != nil {
+			return err
+		}
+	}
+	if err := c.nodes.encode(payload); err != nil {
+		return err
+	}
+	if err := c.states.encode(payload); err != nil {
+		return err
+	}
+	return writeCheckpointEntry(w, payload.Bytes())
+}
+
+// copyNodesAndStates copies the node and state sets of a node buffer, sharing
+// the immutable nodes and values.
+func copyNodesAndStates(nodes *nodeSet, states *stateSet) (*nodeSet, *stateSet) {
+	nodesCopy := make(map[common.Hash]map[string]*trienode.Node, len(nodes.nodes))
+	for owner, subset := range nodes.nodes {
+		nodesCopy[owner] = maps.Clone(subset)
+	}
+	storages := make(map[common.Hash]map[common.Hash][]byte, len(states.storageData))
+	for accountHash, storage := range states.storageData {
+		storages[accountHash] = maps.Clone(storage)
+	}
+	return &nodeSet{size: nodes.size, nodes: nodesCopy}, newStates(maps.Clone(states.accountData), storages, states.rawStorageKey)
+}
+
+// checkpoint writes the diff layer as a diff entry of the checkpoint journal.
+func (dl *diffLayer) checkpoint(w io.Writer) error {
+	dl.lock.RLock()
+	parent := dl.parent.rootHash()
+	dl.lock.RUnlock()
+
+	payload := new(bytes.Buffer)
+	for _, v := range []interface{}{checkpointDiff, parent, dl.root, dl.id, dl.block} {
+		if err := rlp.Encode(payload, v); err != nil {
+			return err
+		}
+	}
+	if err := dl.nodes.encode(payload); err != nil {
+		return err
+	}
+	if err := dl.states.encode(payload); err != nil {
+		return err
+	}
+	return writeCheckpointEntry(w, payload.Bytes())
+}
+
+// writeCheckpointEntry writes the payload of a checkpoint entry followed by its
+// checksum.
+func writeCheckpointEntry(w io.Writer, payload []byte) error {
+	if err := rlp.Encode(w, payload); err != nil {
+		return err
+	}
+	return rlp.Encode(w, sha256.Sum256(payload))
+}
+
+// readCheckpointEntry reads the next checkpoint entry, verifying its checksum.
+func readCheckpointEntry(r *rlp.Stream) (*rlp.Stream, error) {
+	var payload []byte
+	if err := r.Decode(&payload); err != nil {
+		return nil, err
+	}
+	var sum [32]byte
+	if err := r.Decode(&sum); err != nil {
+		return nil, err
+	}
+	if sum != sha256.Sum256(payload) {
+		return nil, fmt.Errorf("checksum mismatch, want %x got %x", sha256.Sum256(payload), sum)
+	}
+	return rlp.NewStream(bytes.NewReader(payload), uint64(len(payload))), nil
+}
+
+// loadCheckpoint reconstructs the layers from the checkpoint journal left behind
+// by an unclean shutdown. The disk layer is resolved from the base entry if it's
+// still continuous with the persistent state, or otherwise directly from the
+// persistent state, with all recorded diff layers stacked on top. The deepest
+// resolvable diff layer is returned as the head.
+func (db *Database) loadCheckpoint(diskRoot common.Hash) (layer, error) {
+	path := db.checkpointPath()
+	if path == "" {
+		return nil, errMissJournal
+	}
+	start := time.Now()
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, errMissJournal
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	r := rlp.NewStream(file, uint64(stat.Size()))
+
+	version, err := r.Uint64()
+	if err != nil {
+		return nil, errMissVersion
+	}
+	if version != journalVersion {
+		return nil, fmt.Errorf("%w want %d got %d", errUnexpectedVersion, journalVersion, version)
+	}
+	entry, err := readCheckpointEntry(r)
+	if err != nil {
+		return nil, fmt.Errorf("load checkpoint base: %v", err)
+	}
+	base, err := db.loadCheckpointBase(entry, diskRoot)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		layers  = map[common.Hash]layer{base.rootHash(): base}
+		head    = base
+		entries int
+	)
+	for {
+		entry, err := readCheckpointEntry(r)
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				log.Warn("Discarded corrupted checkpoint tail", "entries", entries, "err", err)
+			}
+			break
+		}
+		entries++
+
+		var (
+			kind      uint8
+			parent    common.Hash
+			root      common.Hash
+			id, block uint64
+			nodes     nodeSet
+			stateSet  StateSetWithOrigin
+		)
+		if err := entry.Decode(&kind); err != nil {
+			return nil, fmt.Errorf("load checkpoint diff: %v", err)
+		}
+		if kind != checkpointDiff {
+			return nil, fmt.Errorf("load checkpoint diff: unexpected entry kind %d", kind)
+		}
+		for _, v := range []interface{}{&parent, &root, &id, &block} {
+			if err := entry.Decode(v); err != nil {
+				return nil, fmt.Errorf("load checkpoint diff: %v", err)
+			}
+		}
+		// Skip the layers below the disk layer or not continuous with it
+		p, ok := layers[parent]
+		if !ok || p.stateID()+1 != id {
+			continue
+		}
+		if _, ok := layers[root]; ok {
+			continue
+		}
+		if err := nodes.decode(entry); err != nil {
+			return nil, err
+		}
+		if err := stateSet.decode(entry); err != nil {
+			return nil, err
+		}
+		dl := newDiffLayer(p, root, id, block, &nodes, &stateSet)
+		layers[root] = dl
+		if dl.id >= head.stateID() {
+			head = dl
+		}
+	}
+	log.Info("Loaded checkpoint journal", "diskroot", diskRoot, "diffhead", head.rootHash(), "layers", head.stateID()-base.stateID(), "elapsed", common.PrettyDuration(time.Since(start)))
+	return head, nil
+}
+
+// loadCheckpointBase resolves the disk layer the recorded diff layers are to be
+// stacked upon.
+func (db *Database) loadCheckpointBase(r *rlp.Stream, diskRoot common.Hash) (layer, error) {
+	var (
+		kind         uint8
+		stored       uint64
+		baseDiskRoot common.Hash
+		root         common.Hash
+		id           uint64
+		nodes        nodeSet
+		states       = newStates(nil, nil, false)
+		persisted    = rawdb.ReadPersistentStateID(db.diskdb)
+		diskRootID   = rawdb.ReadStateID(db.diskdb, diskRoot)
+	)
+	if err := r.Decode(&kind); err != nil {
+		return nil, fmt.Errorf("load checkpoint base: %v", err)
+	}
+	if kind != checkpointBase {
+		return nil, fmt.Errorf("load checkpoint base: unexpected entry kind %d", kind)
+	}
+	for _, v := range []interface{}{&stored, &baseDiskRoot, &root, &id} {
+		if err := r.Decode(v); err != nil {
+			return nil, fmt.Errorf("load checkpoint base: %v", err)
+		}
+	}
+	// The node buffer holds all transitions on top of the state it was taken
+	// on, it can be applied upon any persistent state in between.
+	if stored <= persisted && persisted <= id {
+		if (persisted == stored && diskRoot == baseDiskRoot) || (diskRootID != nil && *diskRootID == persisted) {
+			if err := nodes.decode(r); err != nil {
+				return nil, err
+			}
+			if err := states.decode(r); err != nil {
+				return nil, err
+			}
+			return newDiskLayer(root, id, db, nil, NewTrieNodeBuffer(db.config.SyncFlush, db.config.WriteBufferSize, &nodes, states, id-persisted)), nil
+		}
+	}
+	// The node buffer was flushed after the checkpoint, resolve the layers
+	// recorded afterwards upon the persistent state.
+	if persisted > id && diskRootID != nil && *diskRootID == persisted {
+		return newDiskLayer(diskRoot, persisted, db, nil, NewTrieNodeBuffer(db.config.SyncFlush, db.config.WriteBufferSize, nil, nil, 0)), nil
+	}
+	return nil, fmt.Errorf("%w: base %d-%d, persisted %d", errCheckpointUnmatched, stored, id, persisted)
+}
+
+// writeFileSync writes the data into the named file and syncs it to the disk.
+func writeFileSync(name string, data []byte) error {
+	file, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// syncDir ensures the entries of the given directory are persisted.
+func syncDir(dir string) error {
+	f, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return f.Sync()
+}
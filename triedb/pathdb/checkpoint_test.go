@@ -0,0 +1,314 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package pathdb
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func newCheckpointConfig(t *testing.T, syncFlush bool, bufferSize int) *Config {
+	return &Config{
+		SyncFlush:          syncFlush,
+		CleanCacheSize:     256 * 1024,
+		WriteBufferSize:    bufferSize,
+		JournalFilePath:    filepath.Join(t.TempDir(), "trie.journal"),
+		CheckpointInterval: 1,
+	}
+}
+
+// crash simulates an unclean shutdown, reopening the database without the
+// layers being journaled.
+func (t *tester) crash(config *Config) {
+	t.db.Close()
+	t.db = New(t.db.diskdb, config, false)
+}
+
+// waitCheckpoint waits for the checkpoint journal being rewritten in the
+// background, if any, and installs it.
+func (t *tester) waitCheckpoint() error {
+	t.db.lock.Lock()
+	defer t.db.lock.Unlock()
+
+	cp := t.db.checkpointer
+	if cp == nil || cp.rewrite == nil {
+		return nil
+	}
+	return cp.finishRewrite(<-cp.rewrite.done)
+}
+
+// checkRecovered ensures the given state is the head of the recovered layers
+// and all states from the disk layer up to it are accessible.
+func (t *tester) checkRecovered(head common.Hash) error {
+	if t.db.tree.get(head) == nil {
+		return fmt.Errorf("head layer %x missing", head)
+	}
+	bottom := t.bottomIndex()
+	if bottom < 0 && t.db.tree.bottom().rootHash() != types.EmptyRootHash {
+		return fmt.Errorf("unknown disk layer %x", t.db.tree.bottom().rootHash())
+	}
+	for i := max(bottom, 0); i < len(t.roots); i++ {
+		if err := t.verifyState(t.roots[i]); err != nil {
+			return fmt.Errorf("state %d: %v", i, err)
+		}
+		if t.roots[i] == head {
+			return nil
+		}
+	}
+	return fmt.Errorf("head layer %x below disk layer", head)
+}
+
+func TestCheckpointRecovery(t *testing.T) {
+	// Redefine the diff layer depth allowance for faster testing.
+	maxDiffLayers = 4
+	defer func() {
+		maxDiffLayers = 128
+	}()
+
+	for _, c := range []struct {
+		name       string
+		syncFlush  bool
+		bufferSize int
+	}{
+		{"buffered", true, 256 * 1024 * 1024}, // node buffer never flushed
+		{"flushed", true, 1},                  // node buffer flushed on each transition
+		{"async", false, 1},                   // node buffer flushed in background
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			config := newCheckpointConfig(t, c.syncFlush, c.bufferSize)
+			tester := newTesterWithConfig(t, config, false, 12)
+			defer tester.release()
+
+			tester.crash(config)
+			if err := tester.checkRecovered(tester.lastHash()); err != nil {
+				t.Fatalf("Failed to recover layers: %v", err)
+			}
+			// Keep going on top of the recovered layers and crash again
+			tester.extend(6)
+			tester.crash(config)
+			if err := tester.checkRecovered(tester.lastHash()); err != nil {
+				t.Fatalf("Failed to recover layers after restart: %v", err)
+			}
+		})
+	}
+}
+
+func TestCheckpointInterval(t *testing.T) {
+	// Redefine the diff layer depth allowance for faster testing.
+	maxDiffLayers = 4
+	defer func() {
+		maxDiffLayers = 128
+	}()
+
+	config := newCheckpointConfig(t, true, 256*1024*1024)
+	config.CheckpointInterval = 2
+
+	// The first transition rewrites the checkpoint journal, every other one
+	// appends to it. The last transition is not checkpointed.
+	tester := newTesterWithConfig(t, config, false, 12)
+	defer tester.release()
+
+	tester.crash(config)
+	if err := tester.checkRecovered(tester.roots[10]); err != nil {
+		t.Fatalf("Failed to recover layers: %v", err)
+	}
+	if tester.db.tree.get(tester.roots[11]) != nil {
+		t.Fatal("Unexpected layer recovered")
+	}
+}
+
+func TestCheckpointTornTail(t *testing.T) {
+	// Redefine the diff layer depth allowance for faster testing.
+	maxDiffLayers = 4
+	defer func() {
+		maxDiffLayers = 128
+	}()
+
+	for _, c := range []struct {
+		name   string
+		damage func(blob []byte) []byte
+	}{
+		// Crash in the middle of appending the last entry
+		{"truncated", func(blob []byte) []byte { return blob[:len(blob)-10] }},
+		// Last entry written partially, with garbage in the remaining space
+		{"corrupted", func(blob []byte) []byte { blob[len(blob)-40] ^= 0xff; return blob }},
+		// Crash after writing the last entry without its checksum
+		{"checksum", func(blob []byte) []byte { return blob[:len(blob)-33] }},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			config := newCheckpointConfig(t, true, 1)
+			tester := newTesterWithConfig(t, config, false, 12)
+			defer tester.release()
+
+			tester.db.Close()
+			path := tester.db.checkpointPath()
+			blob, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, c.damage(blob), 0644); err != nil {
+				t.Fatal(err)
+			}
+			// The damaged entry is discarded, the layers recorded before survive
+			tester.crash(config)
+			if err := tester.checkRecovered(tester.roots[10]); err != nil {
+				t.Fatalf("Failed to recover layers: %v", err)
+			}
+			if tester.db.tree.get(tester.roots[11]) != nil {
+				t.Fatal("Damaged layer recovered")
+			}
+		})
+	}
+}
+
+func TestCheckpointCorruptedBase(t *testing.T) {
+	// Redefine the diff layer depth allowance for faster testing.
+	maxDiffLayers = 4
+	defer func() {
+		maxDiffLayers = 128
+	}()
+
+	config := newCheckpointConfig(t, true, 256*1024*1024)
+	tester := newTesterWithConfig(t, config, false, 12)
+	defer tester.release()
+
+	tester.db.Close()
+	path := tester.db.checkpointPath()
+	blob, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blob[16] ^= 0xff
+	if err := os.WriteFile(path, blob, 0644); err != nil {
+		t.Fatal(err)
+	}
+	// All not-yet-written states should be discarded
+	tester.crash(config)
+	root, _ := tester.db.hasher(rawdb.ReadAccountTrieNode(tester.db.diskdb, nil))
+	if tester.db.tree.len() != 1 || tester.db.tree.bottom().rootHash() != root {
+		t.Fatalf("Unexpected layers recovered: %d", tester.db.tree.len())
+	}
+}
+
+func TestCheckpointInterruptedRewrite(t *testing.T) {
+	// Redefine the diff layer depth allowance for faster testing.
+	maxDiffLayers = 4
+	defer func() {
+		maxDiffLayers = 128
+	}()
+
+	config := newCheckpointConfig(t, true, 1)
+	tester := newTesterWithConfig(t, config, false, 12)
+	defer tester.release()
+
+	// Crash in the middle of rewriting the journal, before it replaces the
+	// previous one.
+	tester.db.Close()
+	path := tester.db.checkpointPath()
+	if err := os.WriteFile(path+".tmp", []byte{0x01, 0x02, 0x03}, 0644); err != nil {
+		t.Fatal(err)
+	}
+	tester.crash(config)
+	if err := tester.checkRecovered(tester.lastHash()); err != nil {
+		t.Fatalf("Failed to recover layers: %v", err)
+	}
+	// The next rewrite overwrites the leftover
+	tester.extend(1)
+	if err := tester.waitCheckpoint(); err != nil {
+		t.Fatalf("Failed to rewrite checkpoint journal: %v", err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Fatalf("Leftover journal not replaced: %v", err)
+	}
+	tester.crash(config)
+	if err := tester.checkRecovered(tester.lastHash()); err != nil {
+		t.Fatalf("Failed to recover layers: %v", err)
+	}
+}
+
+// Tests that the layers checkpointed while the journal is being rewritten in the
+// background are appended to it once it's in place.
+func TestCheckpointBackgroundRewrite(t *testing.T) {
+	// Redefine the diff layer depth allowance for faster testing.
+	maxDiffLayers = 4
+	defer func() {
+		maxDiffLayers = 128
+	}()
+
+	config := newCheckpointConfig(t, true, 256*1024*1024)
+	tester := newTesterWithConfig(t, config, false, 4)
+	defer tester.release()
+
+	if err := tester.waitCheckpoint(); err != nil {
+		t.Fatalf("Failed to rewrite checkpoint journal: %v", err)
+	}
+	// Force a rewrite and hold it back, the transitions meanwhile are kept
+	// in memory instead of being appended to the journal being replaced
+	tester.db.lock.Lock()
+	tester.db.checkpointer.close()
+	tester.db.checkpoint()
+	rw := tester.db.checkpointer.rewrite
+	if rw == nil {
+		t.Fatal("Checkpoint journal not being rewritten")
+	}
+	err := <-rw.done
+	tester.db.lock.Unlock()
+
+	tester.extend(3)
+	if rw.backlog.Len() == 0 {
+		t.Fatal("No layers checkpointed during the rewrite")
+	}
+	rw.done <- err
+	tester.crash(config)
+	if err := tester.checkRecovered(tester.lastHash()); err != nil {
+		t.Fatalf("Failed to recover layers: %v", err)
+	}
+}
+
+func TestCheckpointCleanShutdown(t *testing.T) {
+	// Redefine the diff layer depth allowance for faster testing.
+	maxDiffLayers = 4
+	defer func() {
+		maxDiffLayers = 128
+	}()
+
+	config := newCheckpointConfig(t, true, 256*1024*1024)
+	config.JournalFile = true
+	tester := newTesterWithConfig(t, config, false, 12)
+	defer tester.release()
+
+	path := tester.db.checkpointPath()
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("Checkpoint journal missing: %v", err)
+	}
+	if err := tester.db.Journal(tester.lastHash()); err != nil {
+		t.Fatalf("Failed to journal: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("Checkpoint journal not removed: %v", err)
+	}
+	tester.crash(config)
+	if err := tester.checkRecovered(tester.lastHash()); err != nil {
+		t.Fatalf("Failed to recover layers: %v", err)
+	}
+}
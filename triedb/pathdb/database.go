@@ -131,6 +131,15 @@ type Config struct {
 	NoTries         bool
 	JournalFilePath string
 	JournalFile     bool
+
+	// CheckpointInterval is the number of state transitions between two incremental
+	// checkpoints of the in-memory layers, written next to the journal file so
+	// that they survive an unclean shutdown. Zero disables checkpointing.
+	CheckpointInterval uint64
+
+	// HistoricStateDepth is the maximum number of state histories aggregated to
+	// serve a historical state, older states are rejected. Zero means no limit.
+	HistoricStateDepth uint64
 }
 
 // sanitize checks the provided user configurations and changes anything that's
@@ -141,6 +150,12 @@ func (c *Config) sanitize() *Config {
 		log.Warn("Sanitizing invalid node buffer size", "provided", common.StorageSize(conf.WriteBufferSize), "updated", common.StorageSize(MaxDirtyBufferSize))
 		conf.WriteBufferSize = MaxDirtyBufferSize
 	}
+	// Each diff layer must be checkpointed before it's merged into the node
+	// buffer, otherwise the recorded layers can't be linked after a crash.
+	if limit := uint64(maxDiffLayers / 2); conf.CheckpointInterval > limit {
+		log.Warn("Sanitizing invalid checkpoint interval", "provided", conf.CheckpointInterval, "updated", limit)
+		conf.CheckpointInterval = limit
+	}
 	return &conf
 }
 
@@ -153,6 +168,9 @@ func (c *Config) fields() []interface{} {
 	list = append(list, "cache", common.StorageSize(c.CleanCacheSize))
 	list = append(list, "buffer", common.StorageSize(c.WriteBufferSize))
 	list = append(list, "history", c.StateHistory)
+	if c.CheckpointInterval != 0 {
+		list = append(list, "checkpoint", c.CheckpointInterval)
+	}
 	return list
 }
 
@@ -215,6 +233,9 @@ type Database struct {
 	tree    *layerTree                   // The group for all known layers
 	freezer ethdb.ResettableAncientStore // Freezer for storing trie histories, nil possible in tests
 	lock    sync.RWMutex                 // Lock to prevent mutations from happening at the same time
+
+	checkpointer    *checkpointer        // Incremental checkpoints of the layers, nil if disabled
+	historicReaders *historicReaderCache // Recently used historical state readers
 }
 
 // New attempts to load an already existing layer from a persistent key-value
@@ -232,6 +253,8 @@ func New(diskdb ethdb.Database, config *Config, isVerkle bool) *Database {
 		config:   config,
 		diskdb:   diskdb,
 		hasher:   merkleNodeHasher,
+
+		historicReaders: newHistoricReaderCache(historicReaderCacheSize),
 	}
 	// Establish a dedicated database namespace tailored for verkle-specific
 	// data, ensuring the isolation of both verkle and merkle tree data. It's
@@ -251,6 +274,17 @@ func New(diskdb ethdb.Database, config *Config, isVerkle bool) *Database {
 	if err := db.repairHistory(); err != nil {
 		log.Crit("Failed to repair state history", "err", err)
 	}
+	if config.CheckpointInterval != 0 && !db.readOnly && !config.NoTries {
+		if path := db.checkpointPath(); path != "" {
+			db.checkpointer = &checkpointer{
+				path:      path,
+				interval:  config.CheckpointInterval,
+				journaled: make(map[common.Hash]struct{}),
+			}
+		} else {
+			log.Warn("Disabled layer checkpoints without journal file path")
+		}
+	}
 	// Disable database in case node is still in the initial state sync stage.
 	if rawdb.ReadSnapSyncStatusFlag(diskdb) == rawdb.StateSyncRunning && !db.readOnly {
 		if err := db.Disable(); err != nil {
@@ -344,7 +378,11 @@ func (db *Database) Update(root common.Hash, parentRoot common.Hash, block uint6
 	// - head-1 layer is paired with HEAD-1 state
 	// - head-127 layer(bottom-most diff layer) is paired with HEAD-127 state
 	// - head-128 layer(disk layer) is paired with HEAD-128 state
-	return db.tree.cap(root, maxDiffLayers)
+	if err := db.tree.cap(root, maxDiffLayers); err != nil {
+		return err
+	}
+	db.checkpoint()
+	return nil
 }
 
 // Commit traverses downwards the layer tree from a specified layer with the
@@ -382,6 +420,8 @@ func (db *Database) Disable() error {
 
 	// Mark the disk layer as stale to prevent access to persistent state.
 	db.tree.bottom().markStale()
+	db.removeCheckpoint()
+	db.historicReaders.purge()
 
 	// Write the initial sync flag to persist it across restarts.
 	rawdb.WriteSnapSyncStatusFlag(db.diskdb, rawdb.StateSyncRunning)
@@ -411,6 +451,8 @@ func (db *Database) Enable(root common.Hash) error {
 	// reset the persistent state id back to zero.
 	batch := db.diskdb.NewBatch()
 	db.DeleteTrieJournal(batch)
+	db.removeCheckpoint()
+	db.historicReaders.purge()
 	rawdb.WritePersistentStateID(batch, 0)
 	if err := batch.Write(); err != nil {
 		return err
@@ -475,6 +517,7 @@ func (db *Database) Recover(root common.Hash) error {
 		db.tree.reset(dl)
 	}
 	db.DeleteTrieJournal(db.diskdb)
+	db.removeCheckpoint()
 
 	// Explicitly sync the key-value store to ensure all recent writes are
 	// flushed to disk. This step is crucial to prevent a scenario where
@@ -538,6 +581,12 @@ func (db *Database) Close() error {
 	// Release the memory held by clean cache.
 	db.tree.bottom().resetCache()
 
+	// Release the checkpoint journal, it's left in place in case the layers
+	// were not journaled.
+	if db.checkpointer != nil {
+		db.checkpointer.close()
+	}
+
 	// Close the attached state history freezer.
 	if db.freezer == nil {
 		return nil
@@ -672,6 +721,25 @@ func (db *Database) HistoryRange() (uint64, uint64, error) {
 	return historyRange(db.freezer)
 }
 
+// ChangesRange returns the block numbers associated with the earliest and
+// latest state history which can be queried by AccountChanges and StorageChanges.
+func (db *Database) ChangesRange() (uint64, uint64, error) {
+	if db.freezer == nil {
+		return 0, 0, errors.New("state history is not available")
+	}
+	return changesBlockRange(db.freezer)
+}
+
+// HistoryIndex returns the id of the first state history whose associated
+// block number is not lower than the given one. The id next to the latest
+// history is returned if all the histories are below the given block.
+func (db *Database) HistoryIndex(number uint64) (uint64, error) {
+	if db.freezer == nil {
+		return 0, errors.New("state history is not available")
+	}
+	return historyIndex(db.freezer, number)
+}
+
 // AccountIterator creates a new account iterator for the specified root hash and
 // seeks to a starting account hash.
 func (db *Database) AccountIterator(root common.Hash, seek common.Hash) (AccountIterator, error) {
@@ -122,13 +122,17 @@ type tester struct {
 }
 
 func newTester(t *testing.T, historyLimit uint64, isVerkle bool, layers int) *tester {
+	return newTesterWithConfig(t, &Config{
+		StateHistory:    historyLimit,
+		CleanCacheSize:  256 * 1024,
+		WriteBufferSize: 256 * 1024,
+	}, isVerkle, layers)
+}
+
+func newTesterWithConfig(t *testing.T, config *Config, isVerkle bool, layers int) *tester {
 	var (
 		disk, _ = rawdb.NewDatabaseWithFreezer(rawdb.NewMemoryDatabase(), t.TempDir(), "", false, false, false, false, false)
-		db      = New(disk, &Config{
-			StateHistory:    historyLimit,
-			CleanCacheSize:  256 * 1024,
-			WriteBufferSize: 256 * 1024,
-		}, isVerkle)
+		db      = New(disk, config, isVerkle)
 
 		obj = &tester{
 			db:           db,
@@ -139,19 +143,25 @@ func newTester(t *testing.T, historyLimit uint64, isVerkle bool, layers int) *te
 			snapStorages: make(map[common.Hash]map[common.Hash]map[common.Hash][]byte),
 		}
 	)
+	obj.extend(layers)
+	return obj
+}
+
+// extend applies the given number of random state transitions on top of the
+// latest state.
+func (t *tester) extend(layers int) {
 	for i := 0; i < layers; i++ {
 		var parent = types.EmptyRootHash
-		if len(obj.roots) != 0 {
-			parent = obj.roots[len(obj.roots)-1]
+		if len(t.roots) != 0 {
+			parent = t.roots[len(t.roots)-1]
 		}
-		root, nodes, states := obj.generate(parent, i > 6)
+		root, nodes, states := t.generate(parent, len(t.roots) > 6)
 
-		if err := db.Update(root, parent, uint64(i), nodes, states); err != nil {
+		if err := t.db.Update(root, parent, uint64(len(t.roots)), nodes, states); err != nil {
 			panic(fmt.Errorf("failed to update state changes, err: %w", err))
 		}
-		obj.roots = append(obj.roots, root)
+		t.roots = append(t.roots, root)
 	}
-	return obj
 }
 
 func (t *tester) accountPreimage(hash common.Hash) common.Address {
@@ -508,6 +508,20 @@ func (h *history) decode(accountData, storageData, accountIndexes, storageIndexe
 	return nil
 }
 
+// readHistoryMeta reads and decodes the metadata of the state history object
+// by the given id.
+func readHistoryMeta(reader ethdb.AncientReader, id uint64) (*meta, error) {
+	blob := rawdb.ReadStateHistoryMeta(reader, id)
+	if len(blob) == 0 {
+		return nil, fmt.Errorf("state history not found %d", id)
+	}
+	var m meta
+	if err := m.decode(blob); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
 // readHistory reads and decodes the state history object by the given id.
 func readHistory(reader ethdb.AncientReader, id uint64) (*history, error) {
 	blob := rawdb.ReadStateHistoryMeta(reader, id)
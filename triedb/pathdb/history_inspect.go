@@ -17,13 +17,18 @@
 package pathdb
 
 import (
+	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
 )
 
 // HistoryStats wraps the history inspection statistics.
@@ -61,16 +66,47 @@ func sanitizeRange(start, end uint64, freezer ethdb.AncientReader) (uint64, uint
 	return first, last, nil
 }
 
+// changesRange limits the given range to fit within the local history store.
+// Unlike sanitizeRange, the latest history is included.
+func changesRange(start, end uint64, freezer ethdb.AncientReader) (uint64, uint64, error) {
+	tail, err := freezer.Tail()
+	if err != nil {
+		return 0, 0, err
+	}
+	first := tail + 1
+	if start != 0 && start > first {
+		first = start
+	}
+	head, err := freezer.Ancients()
+	if err != nil {
+		return 0, 0, err
+	}
+	last := head
+	if end != 0 && end < last {
+		last = end
+	}
+	if first > last {
+		return 0, 0, fmt.Errorf("range is invalid, first: %d, last: %d", first, last)
+	}
+	return first, last, nil
+}
+
 func inspectHistory(freezer ethdb.AncientReader, start, end uint64, onHistory func(*history, *HistoryStats)) (*HistoryStats, error) {
+	start, end, err := sanitizeRange(start, end, freezer)
+	if err != nil {
+		return nil, err
+	}
+	return walkHistory(freezer, start, end, onHistory)
+}
+
+// walkHistory inspects the histories within the given range, which must be
+// available in the local store.
+func walkHistory(freezer ethdb.AncientReader, start, end uint64, onHistory func(*history, *HistoryStats)) (*HistoryStats, error) {
 	var (
 		stats  = &HistoryStats{}
 		init   = time.Now()
 		logged = time.Now()
 	)
-	start, end, err := sanitizeRange(start, end, freezer)
-	if err != nil {
-		return nil, err
-	}
 	for id := start; id <= end; id += 1 {
 		// The entire history object is decoded, although it's unnecessary for
 		// account inspection. TODO(rjl493456442) optimization is worthwhile.
@@ -96,36 +132,180 @@ func inspectHistory(freezer ethdb.AncientReader, start, end uint64, onHistory fu
 	return stats, nil
 }
 
-// accountHistory inspects the account history within the range.
-func accountHistory(freezer ethdb.AncientReader, address common.Address, start, end uint64) (*HistoryStats, error) {
-	return inspectHistory(freezer, start, end, func(h *history, stats *HistoryStats) {
+// historyLookup resolves the original value of the inspected state from the
+// history, reporting whether the state is mutated in it.
+type historyLookup func(h *history) ([]byte, bool)
+
+// accountLookup returns the lookup function of the given account.
+func accountLookup(address common.Address) historyLookup {
+	return func(h *history) ([]byte, bool) {
 		blob, exists := h.accounts[address]
-		if !exists {
-			return
-		}
-		stats.Blocks = append(stats.Blocks, h.meta.block)
-		stats.Origins = append(stats.Origins, blob)
-	})
+		return blob, exists
+	}
 }
 
-// storageHistory inspects the storage history within the range.
-func storageHistory(freezer ethdb.AncientReader, address common.Address, slot common.Hash, start uint64, end uint64) (*HistoryStats, error) {
+// storageLookup returns the lookup function of the given storage slot.
+func storageLookup(address common.Address, slot common.Hash) historyLookup {
 	slotHash := crypto.Keccak256Hash(slot.Bytes())
-	return inspectHistory(freezer, start, end, func(h *history, stats *HistoryStats) {
+	return func(h *history) ([]byte, bool) {
 		slots, exists := h.storages[address]
 		if !exists {
-			return
+			return nil, false
 		}
 		key := slotHash
 		if h.meta.version != stateHistoryV0 {
 			key = slot
 		}
 		blob, exists := slots[key]
+		return blob, exists
+	}
+}
+
+// collectHistory returns the history inspection callback recording the
+// mutations resolved by the lookup function.
+func collectHistory(lookup historyLookup) func(*history, *HistoryStats) {
+	return func(h *history, stats *HistoryStats) {
+		blob, exists := lookup(h)
 		if !exists {
 			return
 		}
 		stats.Blocks = append(stats.Blocks, h.meta.block)
 		stats.Origins = append(stats.Origins, blob)
+	}
+}
+
+// accountHistory inspects the account history within the range.
+func accountHistory(freezer ethdb.AncientReader, address common.Address, start, end uint64) (*HistoryStats, error) {
+	return inspectHistory(freezer, start, end, collectHistory(accountLookup(address)))
+}
+
+// storageHistory inspects the storage history within the range.
+func storageHistory(freezer ethdb.AncientReader, address common.Address, slot common.Hash, start uint64, end uint64) (*HistoryStats, error) {
+	return inspectHistory(freezer, start, end, collectHistory(storageLookup(address, slot)))
+}
+
+// StateChanges wraps the mutations of a state within a range of histories,
+// along with the value of the state after each mutation.
+type StateChanges struct {
+	HistoryStats
+
+	// Values refers to the value of the state after its mutation. The value
+	// after the last mutation is nil if it can't be resolved within the limit.
+	Values   [][]byte
+	Resolved bool // Whether the value after the last mutation is resolved
+}
+
+// inspectChanges inspects the mutations of a state within the range. The value
+// after each mutation is the original value of the next one. For the last one,
+// the histories beyond the range are inspected, up to the given limit, falling
+// back to the value in the disk layer if the state is not mutated anymore.
+func (db *Database) inspectChanges(start, end, limit uint64, lookup historyLookup, current func(root common.Hash) ([]byte, error)) (*StateChanges, error) {
+	if db.freezer == nil {
+		return nil, errors.New("state history is not available")
+	}
+	first, last, err := changesRange(start, end, db.freezer)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := walkHistory(db.freezer, first, last, collectHistory(lookup))
+	if err != nil {
+		return nil, err
+	}
+	changes := &StateChanges{HistoryStats: *stats, Resolved: true}
+	for i := 1; i < len(stats.Origins); i++ {
+		changes.Values = append(changes.Values, stats.Origins[i])
+	}
+	if len(stats.Blocks) == 0 {
+		return changes, nil
+	}
+	next := last + 1
+	for {
+		dl := db.tree.bottom()
+		for ; next <= dl.stateID(); next++ {
+			if next-last > limit {
+				changes.Values = append(changes.Values, nil)
+				changes.Resolved = false
+				return changes, nil
+			}
+			h, err := readHistory(db.freezer, next)
+			if err != nil {
+				return nil, err
+			}
+			if blob, exists := lookup(h); exists {
+				changes.Values = append(changes.Values, blob)
+				return changes, nil
+			}
+		}
+		// The state is not mutated after the range, resolve it from the disk
+		// layer. Retry if the disk layer moves on in the meantime.
+		blob, err := current(dl.rootHash())
+		if err != nil {
+			if db.tree.bottom() != dl {
+				continue
+			}
+			return nil, err
+		}
+		changes.Values = append(changes.Values, blob)
+		return changes, nil
+	}
+}
+
+// AccountChanges inspects the mutations of the account within the specified
+// range, resolving the account value after each mutation as well. The values
+// are in the slim format, empty if the account is not existent.
+//
+// Start: State ID of the first history object for the query. 0 implies the first
+// available object is selected as the starting point.
+//
+// End: State ID of the last history for the query. 0 implies the last available
+// object is selected as the ending point. Note end is included in the query.
+//
+// Limit: The maximum number of histories beyond the range to inspect for
+// resolving the value after the last mutation.
+func (db *Database) AccountChanges(address common.Address, start, end, limit uint64) (*StateChanges, error) {
+	return db.inspectChanges(start, end, limit, accountLookup(address), func(root common.Hash) ([]byte, error) {
+		tr, err := trie.New(trie.StateTrieID(root), db)
+		if err != nil {
+			return nil, err
+		}
+		blob, err := tr.Get(crypto.Keccak256(address.Bytes()))
+		if err != nil || len(blob) == 0 {
+			return nil, err
+		}
+		var account types.StateAccount
+		if err := rlp.DecodeBytes(blob, &account); err != nil {
+			return nil, err
+		}
+		return types.SlimAccountRLP(account), nil
+	})
+}
+
+// StorageChanges inspects the mutations of the storage slot within the specified
+// range, resolving the slot value after each mutation as well. The values are
+// RLP encoded, empty if the slot is not existent.
+//
+// The range is specified in the same way as AccountChanges. Note, slot refers
+// to the raw slot key.
+func (db *Database) StorageChanges(address common.Address, slot common.Hash, start, end, limit uint64) (*StateChanges, error) {
+	return db.inspectChanges(start, end, limit, storageLookup(address, slot), func(root common.Hash) ([]byte, error) {
+		tr, err := trie.New(trie.StateTrieID(root), db)
+		if err != nil {
+			return nil, err
+		}
+		addrHash := crypto.Keccak256Hash(address.Bytes())
+		blob, err := tr.Get(addrHash.Bytes())
+		if err != nil || len(blob) == 0 {
+			return nil, err
+		}
+		var account types.StateAccount
+		if err := rlp.DecodeBytes(blob, &account); err != nil {
+			return nil, err
+		}
+		st, err := trie.New(trie.StorageTrieID(root, addrHash, account.Root), db)
+		if err != nil {
+			return nil, err
+		}
+		return st.Get(crypto.Keccak256(slot.Bytes()))
 	})
 }
 
@@ -155,3 +335,51 @@ func historyRange(freezer ethdb.AncientReader) (uint64, uint64, error) {
 	}
 	return fh.meta.block, lh.meta.block, nil
 }
+
+// changesBlockRange returns the block number range of local state histories,
+// including the latest one.
+func changesBlockRange(freezer ethdb.AncientReader) (uint64, uint64, error) {
+	first, last, err := changesRange(0, 0, freezer)
+	if err != nil {
+		return 0, 0, err
+	}
+	fh, err := readHistoryMeta(freezer, first)
+	if err != nil {
+		return 0, 0, err
+	}
+	lh, err := readHistoryMeta(freezer, last)
+	if err != nil {
+		return 0, 0, err
+	}
+	return fh.block, lh.block, nil
+}
+
+// historyIndex returns the id of the first state history whose associated
+// block number is not lower than the given one. The id next to the latest
+// history is returned if all the histories are below the given block.
+func historyIndex(freezer ethdb.AncientReader, number uint64) (uint64, error) {
+	tail, err := freezer.Tail()
+	if err != nil {
+		return 0, err
+	}
+	head, err := freezer.Ancients()
+	if err != nil {
+		return 0, err
+	}
+	var failed error
+	n := sort.Search(int(head-tail), func(i int) bool {
+		if failed != nil {
+			return true
+		}
+		m, err := readHistoryMeta(freezer, tail+uint64(i)+1)
+		if err != nil {
+			failed = err
+			return true
+		}
+		return m.block >= number
+	})
+	if failed != nil {
+		return 0, failed
+	}
+	return tail + uint64(n) + 1, nil
+}
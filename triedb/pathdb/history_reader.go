@@ -0,0 +1,425 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package pathdb
+
+import (
+	gocontext "context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// historicReaderCacheSize is the maximum memory allowance (in bytes) of the
+// aggregated state diffs held by the cached historical state readers.
+const historicReaderCacheSize = 256 * 1024 * 1024
+
+// historicSlotOverhead is the approximate memory used by a recorded state on
+// top of its key and value, for the map entries and slices.
+const historicSlotOverhead = 64
+
+// historicSlot is the original value of a storage slot before its first
+// mutation after the target state, along with the id of the mutation.
+type historicSlot struct {
+	id   uint64
+	blob []byte
+}
+
+// HistoricalStateReader is a reader for accessing the state which is no longer
+// kept in the layer tree, but is still covered by the local state histories.
+//
+// The reader aggregates the reverse diffs from the state histories on top of
+// the target state. For each mutated state, the original value before its first
+// mutation is the value in the target state. For the states never mutated, the
+// values are resolved from the disk layer.
+//
+// The reader is safe for concurrent use.
+type HistoricalStateReader struct {
+	db   *Database
+	root common.Hash // State root of the target state
+	id   uint64      // State id of the target state
+
+	lock     sync.Mutex
+	disk     *diskLayer                                      // The disk layer which the diffs are aggregated against
+	roots    []common.Hash                                   // State roots of the aggregated histories, following the target state
+	accounts map[common.Address][]byte                       // Account values in slim format, keyed by address
+	storages map[common.Address]map[common.Hash]historicSlot // Storage values, keyed by raw slot key
+	hashed   map[common.Address]map[common.Hash]historicSlot // Storage values, keyed by slot hash (legacy histories)
+	tries    map[common.Address]*trie.Trie                   // Storage tries of the disk layer, keyed by address
+	accTrie  *trie.Trie                                      // Account trie of the disk layer
+	size     uint64                                          // Approximate memory used by the aggregated diffs
+}
+
+// HistoricReader constructs a reader for accessing the requested historical
+// state. An error is returned if the state is not covered by the local state
+// histories, or if it's deeper than the configured limit.
+//
+// Aggregating the histories is aborted if the given context is cancelled.
+func (db *Database) HistoricReader(ctx gocontext.Context, root common.Hash) (*HistoricalStateReader, error) {
+	if db.freezer == nil {
+		return nil, errors.New("state history is not available")
+	}
+	if r, ok := db.historicReaders.get(root); ok {
+		return r, nil
+	}
+	id := rawdb.ReadStateID(db.diskdb, root)
+	if id == nil {
+		return nil, fmt.Errorf("state %#x is not available", root)
+	}
+	// The histories since the target state are all required.
+	tail, err := db.freezer.Tail()
+	if err != nil {
+		return nil, err
+	}
+	if *id < tail {
+		return nil, fmt.Errorf("state %#x is not available, history pruned", root)
+	}
+	r := &HistoricalStateReader{
+		db:       db,
+		root:     root,
+		id:       *id,
+		accounts: make(map[common.Address][]byte),
+		storages: make(map[common.Address]map[common.Hash]historicSlot),
+		hashed:   make(map[common.Address]map[common.Hash]historicSlot),
+	}
+	if err := r.update(ctx); err != nil {
+		return nil, err
+	}
+	db.historicReaders.add(root, r, r.size)
+	return r, nil
+}
+
+// update aggregates the state histories up to the current disk layer. The
+// original values are only recorded for the first mutation after the target
+// state, so they are not affected by any change above.
+//
+// The caller must hold the lock.
+func (r *HistoricalStateReader) update(ctx gocontext.Context) error {
+	// Ensure the target state is still followed by the histories. The root->id
+	// mappings are not cleaned up after the state sync, and the histories can
+	// be replaced after reverting the disk layer below the target state.
+	dl := r.db.tree.bottom()
+	switch {
+	case dl.stateID() < r.id:
+		return fmt.Errorf("state %#x is not available", r.root)
+	case dl.stateID() == r.id:
+		if dl.rootHash() != r.root {
+			return fmt.Errorf("state %#x is not available", r.root)
+		}
+	case r.db.config.HistoricStateDepth != 0 && dl.stateID()-r.id > r.db.config.HistoricStateDepth:
+		return fmt.Errorf("state %#x is too old, %d histories to aggregate exceed the limit %d", r.root, dl.stateID()-r.id, r.db.config.HistoricStateDepth)
+	default:
+		m, err := readHistoryMeta(r.db.freezer, r.id+1)
+		if err != nil {
+			return err
+		}
+		if m.parent != r.root {
+			return fmt.Errorf("state %#x is not available, mismatched history", r.root)
+		}
+	}
+	// Resume the aggregation from the last aggregated history, or the disk
+	// layer if it has been reverted. The recorded values from the reverted
+	// histories are still valid, as they are all the values in the target
+	// state. However, the histories might be replaced by new ones after the
+	// revert, aggregate them from scratch if so.
+	start := min(r.id+uint64(len(r.roots)), dl.stateID())
+	if start > r.id {
+		m, err := readHistoryMeta(r.db.freezer, start)
+		if err != nil {
+			return err
+		}
+		if m.root == r.roots[start-r.id-1] {
+			r.roots = r.roots[:start-r.id]
+		} else {
+			start = r.id
+			r.roots = nil
+			r.accounts = make(map[common.Address][]byte)
+			r.storages = make(map[common.Address]map[common.Hash]historicSlot)
+			r.hashed = make(map[common.Address]map[common.Hash]historicSlot)
+			r.size = 0
+		}
+	}
+	var (
+		now = time.Now()
+		end = dl.stateID()
+	)
+	for id := start + 1; id <= end; id++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		h, err := readHistory(r.db.freezer, id)
+		if err != nil {
+			return err
+		}
+		r.roots = append(r.roots, h.meta.root)
+		r.size += common.HashLength
+		for addr, blob := range h.accounts {
+			if _, ok := r.accounts[addr]; !ok {
+				r.accounts[addr] = blob
+				r.size += uint64(common.AddressLength + len(blob) + historicSlotOverhead)
+			}
+		}
+		storages := r.storages
+		if h.meta.version == stateHistoryV0 {
+			storages = r.hashed
+		}
+		for addr, slots := range h.storages {
+			if _, ok := storages[addr]; !ok {
+				storages[addr] = make(map[common.Hash]historicSlot)
+			}
+			for key, blob := range slots {
+				if _, ok := storages[addr][key]; !ok {
+					storages[addr][key] = historicSlot{id: id, blob: blob}
+					r.size += uint64(common.HashLength + len(blob) + historicSlotOverhead)
+				}
+			}
+		}
+	}
+	if end > start+1 {
+		log.Debug("Aggregated state histories", "root", r.root, "from", start+1, "to", end, "elapsed", common.PrettyDuration(time.Since(now)))
+	}
+	r.disk, r.accTrie, r.tries = dl, nil, nil
+	return nil
+}
+
+// refresh aggregates the diffs again if the disk layer has moved on since the
+// given retrieval failure, so that the retrieval can be retried against the
+// new disk layer. Otherwise the failure is returned.
+//
+// The caller must hold the lock.
+func (r *HistoricalStateReader) refresh(err error) error {
+	if r.db.tree.bottom() == r.disk {
+		return err
+	}
+	if err := r.update(gocontext.Background()); err != nil {
+		return err
+	}
+	r.db.historicReaders.resize(r.root, r.size)
+	return nil
+}
+
+// account retrieves the account in the full RLP format from the disk layer.
+//
+// The caller must hold the lock.
+func (r *HistoricalStateReader) account(address common.Address) ([]byte, error) {
+	if r.accTrie == nil {
+		tr, err := trie.New(trie.StateTrieID(r.disk.rootHash()), r.db)
+		if err != nil {
+			return nil, err
+		}
+		r.accTrie = tr
+	}
+	return r.accTrie.Get(crypto.Keccak256(address.Bytes()))
+}
+
+// Account retrieves the account in the target state. Nil is returned if the
+// account is not existent.
+func (r *HistoricalStateReader) Account(address common.Address) (*types.StateAccount, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for {
+		if blob, ok := r.accounts[address]; ok {
+			if len(blob) == 0 {
+				return nil, nil
+			}
+			return types.FullAccount(blob)
+		}
+		blob, err := r.account(address)
+		if err == nil {
+			if len(blob) == 0 {
+				return nil, nil
+			}
+			account := new(types.StateAccount)
+			if err := rlp.DecodeBytes(blob, account); err != nil {
+				return nil, err
+			}
+			return account, nil
+		}
+		if err := r.refresh(err); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// lookup returns the original value of the storage slot recorded in the
+// aggregated diffs.
+//
+// The caller must hold the lock.
+func (r *HistoricalStateReader) lookup(address common.Address, key common.Hash) ([]byte, bool) {
+	raw, rawOK := r.storages[address][key]
+	if len(r.hashed) == 0 {
+		return raw.blob, rawOK
+	}
+	hashed, hashedOK := r.hashed[address][crypto.Keccak256Hash(key.Bytes())]
+	switch {
+	case rawOK && hashedOK:
+		if hashed.id < raw.id {
+			return hashed.blob, true
+		}
+		return raw.blob, true
+	case hashedOK:
+		return hashed.blob, true
+	default:
+		return raw.blob, rawOK
+	}
+}
+
+// storage retrieves the storage slot from the disk layer.
+//
+// The caller must hold the lock.
+func (r *HistoricalStateReader) storage(address common.Address, key common.Hash) ([]byte, error) {
+	tr, ok := r.tries[address]
+	if !ok {
+		blob, err := r.account(address)
+		if err != nil {
+			return nil, err
+		}
+		root := types.EmptyRootHash
+		if len(blob) != 0 {
+			var account types.StateAccount
+			if err := rlp.DecodeBytes(blob, &account); err != nil {
+				return nil, err
+			}
+			root = account.Root
+		}
+		tr, err = trie.New(trie.StorageTrieID(r.disk.rootHash(), crypto.Keccak256Hash(address.Bytes()), root), r.db)
+		if err != nil {
+			return nil, err
+		}
+		if r.tries == nil {
+			r.tries = make(map[common.Address]*trie.Trie)
+		}
+		r.tries[address] = tr
+	}
+	return tr.Get(crypto.Keccak256(key.Bytes()))
+}
+
+// Storage retrieves the storage slot in the target state, with the raw slot
+// key specified. The returned value is RLP-encoded, empty if the slot is not
+// existent.
+func (r *HistoricalStateReader) Storage(address common.Address, key common.Hash) ([]byte, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for {
+		if blob, ok := r.lookup(address, key); ok {
+			return blob, nil
+		}
+		blob, err := r.storage(address, key)
+		if err == nil {
+			return blob, nil
+		}
+		if err := r.refresh(err); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// historicReaderCache is an LRU cache of historical state readers, bounded by
+// the approximate memory used by their aggregated diffs.
+type historicReaderCache struct {
+	lock    sync.Mutex
+	readers lru.BasicLRU[common.Hash, *HistoricalStateReader]
+	sizes   map[common.Hash]uint64 // Accounted memory of the cached readers
+	size    uint64                 // Total accounted memory
+	maxSize uint64                 // Memory allowance of the cache
+}
+
+// newHistoricReaderCache creates a historical state reader cache with the given
+// memory allowance in bytes.
+func newHistoricReaderCache(maxSize uint64) *historicReaderCache {
+	return &historicReaderCache{
+		readers: lru.NewBasicLRU[common.Hash, *HistoricalStateReader](math.MaxInt),
+		sizes:   make(map[common.Hash]uint64),
+		maxSize: maxSize,
+	}
+}
+
+// get looks up the reader of the given state, marking it as recently used.
+func (c *historicReaderCache) get(root common.Hash) (*HistoricalStateReader, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.readers.Get(root)
+}
+
+// add caches the reader of the given state, evicting the least recently used
+// readers until the memory allowance is met. Readers larger than the whole
+// allowance are not cached.
+func (c *historicReaderCache) add(root common.Hash, r *HistoricalStateReader, size uint64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.remove(root)
+	if size > c.maxSize {
+		return
+	}
+	for c.size+size > c.maxSize {
+		old, _, ok := c.readers.RemoveOldest()
+		if !ok {
+			break
+		}
+		c.size -= c.sizes[old]
+		delete(c.sizes, old)
+	}
+	c.readers.Add(root, r)
+	c.sizes[root] = size
+	c.size += size
+}
+
+// resize updates the accounted memory of a cached reader after it aggregated
+// more histories. It's a noop if the reader has been evicted meanwhile.
+func (c *historicReaderCache) resize(root common.Hash, size uint64) {
+	c.lock.Lock()
+	r, ok := c.readers.Peek(root)
+	c.lock.Unlock()
+
+	if ok {
+		c.add(root, r, size)
+	}
+}
+
+// remove drops the reader of the given state from the cache.
+//
+// The caller must hold the lock.
+func (c *historicReaderCache) remove(root common.Hash) {
+	if c.readers.Remove(root) {
+		c.size -= c.sizes[root]
+		delete(c.sizes, root)
+	}
+}
+
+// purge drops all the cached readers.
+func (c *historicReaderCache) purge() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.readers.Purge()
+	clear(c.sizes)
+	c.size = 0
+}
@@ -0,0 +1,231 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package pathdb
+
+import (
+	"bytes"
+	gocontext "context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// verifyHistoricState ensures the historical state reader returns the same
+// state as recorded for the given root. All the accounts and slots ever known
+// are checked, including the ones not existent in the given state.
+func (t *tester) verifyHistoricState(r *HistoricalStateReader, root common.Hash) error {
+	var (
+		accounts = make(map[common.Hash]struct{})
+		storages = make(map[common.Hash]map[common.Hash]struct{})
+	)
+	for _, snap := range t.snapAccounts {
+		for addrHash := range snap {
+			accounts[addrHash] = struct{}{}
+		}
+	}
+	for _, snap := range t.snapStorages {
+		for addrHash, slots := range snap {
+			if _, ok := storages[addrHash]; !ok {
+				storages[addrHash] = make(map[common.Hash]struct{})
+			}
+			for slotHash := range slots {
+				storages[addrHash][slotHash] = struct{}{}
+			}
+		}
+	}
+	for addrHash := range accounts {
+		account, err := r.Account(t.accountPreimage(addrHash))
+		if err != nil {
+			return err
+		}
+		var blob []byte
+		if account != nil {
+			blob = types.SlimAccountRLP(*account)
+		}
+		if want := t.snapAccounts[root][addrHash]; !bytes.Equal(blob, want) {
+			return fmt.Errorf("account %x mismatch, want %x, got %x", addrHash, want, blob)
+		}
+	}
+	for addrHash, slots := range storages {
+		for slotHash := range slots {
+			blob, err := r.Storage(t.accountPreimage(addrHash), t.hashPreimage(slotHash))
+			if err != nil {
+				return err
+			}
+			if want := t.snapStorages[root][addrHash][slotHash]; !bytes.Equal(blob, want) {
+				return fmt.Errorf("storage %x/%x mismatch, want %x, got %x", addrHash, slotHash, want, blob)
+			}
+		}
+	}
+	return nil
+}
+
+// rewind resets the tester to the given state, discarding all the states above.
+func (t *tester) rewind(root common.Hash) {
+	for i, r := range t.roots {
+		if r == root {
+			t.roots = t.roots[:i+1]
+			break
+		}
+	}
+	t.accounts = copyAccounts(t.snapAccounts[root])
+	t.storages = copyStorages(t.snapStorages[root])
+}
+
+func TestHistoricReader(t *testing.T) {
+	// Redefine the diff layer depth allowance for faster testing.
+	maxDiffLayers = 4
+	defer func() {
+		maxDiffLayers = 128
+	}()
+
+	tester := newTester(t, 0, false, 16)
+	defer tester.release()
+
+	// The states below the disk layer are only reachable via the histories,
+	// including the ones with legacy histories keyed by slot hash.
+	bottom := tester.bottomIndex()
+	for i := 0; i < bottom; i++ {
+		r, err := tester.db.HistoricReader(gocontext.Background(), tester.roots[i])
+		if err != nil {
+			t.Fatalf("Failed to open historic reader of state %d: %v", i, err)
+		}
+		if err := tester.verifyHistoricState(r, tester.roots[i]); err != nil {
+			t.Fatalf("Unexpected historic state %d: %v", i, err)
+		}
+	}
+	// The states not covered by the histories are rejected.
+	if _, err := tester.db.HistoricReader(gocontext.Background(), tester.lastHash()); err == nil {
+		t.Fatal("Expected error for state in memory")
+	}
+	if _, err := tester.db.HistoricReader(gocontext.Background(), common.Hash{0x1}); err == nil {
+		t.Fatal("Expected error for unknown state")
+	}
+}
+
+func TestHistoricReaderDiskLayerMoved(t *testing.T) {
+	// Redefine the diff layer depth allowance for faster testing.
+	maxDiffLayers = 4
+	defer func() {
+		maxDiffLayers = 128
+	}()
+
+	tester := newTester(t, 0, false, 12)
+	defer tester.release()
+
+	root := tester.roots[2]
+	r, err := tester.db.HistoricReader(gocontext.Background(), root)
+	if err != nil {
+		t.Fatalf("Failed to open historic reader: %v", err)
+	}
+	// Move the disk layer forward, the reader should pick up the new histories.
+	tester.extend(8)
+	if err := tester.verifyHistoricState(r, root); err != nil {
+		t.Fatalf("Unexpected historic state after extension: %v", err)
+	}
+	// Revert the disk layer, the reader should be still usable on top of the
+	// reverted disk layer.
+	target := tester.roots[tester.bottomIndex()-3]
+	if err := tester.db.Recover(target); err != nil {
+		t.Fatalf("Failed to recover state: %v", err)
+	}
+	if err := tester.verifyHistoricState(r, root); err != nil {
+		t.Fatalf("Unexpected historic state after rollback: %v", err)
+	}
+	// Revert the disk layer again and replace the reverted histories with new
+	// ones before the reader is used again.
+	target = tester.roots[tester.bottomIndex()-2]
+	if err := tester.db.Recover(target); err != nil {
+		t.Fatalf("Failed to recover state: %v", err)
+	}
+	tester.rewind(target)
+	tester.extend(8)
+	if err := tester.verifyHistoricState(r, root); err != nil {
+		t.Fatalf("Unexpected historic state after reorg: %v", err)
+	}
+}
+
+func TestHistoricReaderLimits(t *testing.T) {
+	// Redefine the diff layer depth allowance for faster testing.
+	maxDiffLayers = 4
+	defer func() {
+		maxDiffLayers = 128
+	}()
+
+	tester := newTesterWithConfig(t, &Config{
+		CleanCacheSize:     256 * 1024,
+		WriteBufferSize:    256 * 1024,
+		HistoricStateDepth: 4,
+	}, false, 16)
+	defer tester.release()
+
+	// The states deeper than the limit are rejected.
+	bottom := tester.bottomIndex()
+	if _, err := tester.db.HistoricReader(gocontext.Background(), tester.roots[bottom-5]); err == nil {
+		t.Fatal("Expected error for state beyond the depth limit")
+	}
+	r, err := tester.db.HistoricReader(gocontext.Background(), tester.roots[bottom-4])
+	if err != nil {
+		t.Fatalf("Failed to open historic reader within the depth limit: %v", err)
+	}
+	if err := tester.verifyHistoricState(r, tester.roots[bottom-4]); err != nil {
+		t.Fatalf("Unexpected historic state: %v", err)
+	}
+	// The aggregation is aborted if the context is cancelled.
+	ctx, cancel := gocontext.WithCancel(gocontext.Background())
+	cancel()
+	if _, err := tester.db.HistoricReader(ctx, tester.roots[bottom-3]); !errors.Is(err, gocontext.Canceled) {
+		t.Fatalf("Unexpected error with cancelled context: have %v, want %v", err, gocontext.Canceled)
+	}
+}
+
+func TestHistoricReaderCache(t *testing.T) {
+	var (
+		cache = newHistoricReaderCache(100)
+		roots = []common.Hash{{0x1}, {0x2}, {0x3}}
+	)
+	cache.add(roots[0], new(HistoricalStateReader), 40)
+	cache.add(roots[1], new(HistoricalStateReader), 40)
+
+	// Touch the first reader, the second one is evicted to make room.
+	if _, ok := cache.get(roots[0]); !ok {
+		t.Fatal("Reader missing from cache")
+	}
+	cache.add(roots[2], new(HistoricalStateReader), 40)
+	if _, ok := cache.get(roots[1]); ok {
+		t.Fatal("Least recently used reader not evicted")
+	}
+	if cache.size != 80 {
+		t.Fatalf("Unexpected cache size: have %d, want %d", cache.size, 80)
+	}
+	// Growing a reader beyond the allowance evicts the others.
+	cache.resize(roots[2], 90)
+	if _, ok := cache.get(roots[0]); ok {
+		t.Fatal("Reader not evicted after resize")
+	}
+	if cache.size != 90 {
+		t.Fatalf("Unexpected cache size: have %d, want %d", cache.size, 90)
+	}
+	// Readers larger than the whole allowance are not cached.
+	cache.add(roots[1], new(HistoricalStateReader), 200)
+	if _, ok := cache.get(roots[1]); ok {
+		t.Fatal("Oversized reader cached")
+	}
+}
@@ -333,3 +333,140 @@ func compareStorageList(a, b map[common.Address][]common.Hash) bool {
 	}
 	return true
 }
+
+func TestStateChanges(t *testing.T) {
+	// Redefine the diff layer depth allowance for faster testing.
+	maxDiffLayers = 4
+	defer func() {
+		maxDiffLayers = 128
+	}()
+
+	tester := newTester(t, 0, false, 32)
+	defer tester.release()
+
+	// Histories are identified by the id starting from 1, each one transiting
+	// the state from roots[id-2] to roots[id-1].
+	var (
+		last   = uint64(tester.bottomIndex() + 1)
+		rootOf = func(id uint64) common.Hash {
+			if id == 0 {
+				return types.EmptyRootHash
+			}
+			return tester.roots[id-1]
+		}
+	)
+	check := func(changes *StateChanges, start, end, limit uint64, value func(root common.Hash) []byte) error {
+		var (
+			blocks []uint64
+			values [][]byte
+		)
+		for id := start; id <= end; id++ {
+			if !bytes.Equal(value(rootOf(id-1)), value(rootOf(id))) {
+				blocks = append(blocks, id-1)
+				values = append(values, value(rootOf(id)))
+			}
+		}
+		resolved := true
+		if len(blocks) != 0 {
+			// The value after the last mutation is only resolved if the next
+			// mutation, or the disk layer if there is none, is within the limit.
+			next := last + 1
+			for id := end + 1; id <= last; id++ {
+				if !bytes.Equal(value(rootOf(id-1)), value(rootOf(id))) {
+					next = id
+					break
+				}
+			}
+			if next == last+1 {
+				resolved = last-end <= limit
+			} else {
+				resolved = next-end <= limit
+			}
+			if !resolved {
+				values[len(values)-1] = nil
+			}
+		}
+		if !reflect.DeepEqual(changes.Blocks, blocks) {
+			return fmt.Errorf("blocks mismatch, want %v, got %v", blocks, changes.Blocks)
+		}
+		if changes.Resolved != resolved {
+			return fmt.Errorf("resolved flag mismatch, want %v, got %v", resolved, changes.Resolved)
+		}
+		for i := range blocks {
+			if !bytes.Equal(changes.Origins[i], value(rootOf(blocks[i]))) {
+				return fmt.Errorf("origin mismatch at block %d", blocks[i])
+			}
+			if !bytes.Equal(changes.Values[i], values[i]) {
+				return fmt.Errorf("value mismatch at block %d", blocks[i])
+			}
+		}
+		return nil
+	}
+	for _, r := range [][3]uint64{{1, last, 0}, {5, 15, last}, {5, 15, 0}, {10, 10, 3}} {
+		start, end, limit := r[0], r[1], r[2]
+		for addrHash := range tester.snapAccounts[rootOf(last)] {
+			address := tester.accountPreimage(addrHash)
+			changes, err := tester.db.AccountChanges(address, start, end, limit)
+			if err != nil {
+				t.Fatalf("Failed to inspect account changes: %v", err)
+			}
+			err = check(changes, start, end, limit, func(root common.Hash) []byte {
+				return tester.snapAccounts[root][addrHash]
+			})
+			if err != nil {
+				t.Fatalf("Unexpected account changes of %x in [%d-%d]: %v", address, start, end, err)
+			}
+			for slotHash := range tester.snapStorages[rootOf(last)][addrHash] {
+				slot := tester.hashPreimage(slotHash)
+				changes, err := tester.db.StorageChanges(address, slot, start, end, limit)
+				if err != nil {
+					t.Fatalf("Failed to inspect storage changes: %v", err)
+				}
+				err = check(changes, start, end, limit, func(root common.Hash) []byte {
+					return tester.snapStorages[root][addrHash][slotHash]
+				})
+				if err != nil {
+					t.Fatalf("Unexpected storage changes of %x/%x in [%d-%d]: %v", address, slot, start, end, err)
+				}
+			}
+		}
+	}
+}
+
+func TestHistoryIndex(t *testing.T) {
+	// Redefine the diff layer depth allowance for faster testing.
+	maxDiffLayers = 4
+	defer func() {
+		maxDiffLayers = 128
+	}()
+
+	tester := newTester(t, 0, false, 12)
+	defer tester.release()
+
+	// The history with id N is associated with the block N-1.
+	last := uint64(tester.bottomIndex() + 1)
+	for number := uint64(0); number <= last+1; number++ {
+		id, err := tester.db.HistoryIndex(number)
+		if err != nil {
+			t.Fatalf("Failed to find history of block %d: %v", number, err)
+		}
+		want := min(number+1, last+1)
+		if id != want {
+			t.Fatalf("Unexpected history index of block %d, want %d, got %d", number, want, id)
+		}
+	}
+	first, latest, err := tester.db.HistoryRange()
+	if err != nil {
+		t.Fatalf("Failed to retrieve history range: %v", err)
+	}
+	if first != 0 || latest != last-2 {
+		t.Fatalf("Unexpected history range, want [0-%d], got [%d-%d]", last-2, first, latest)
+	}
+	first, latest, err = tester.db.ChangesRange()
+	if err != nil {
+		t.Fatalf("Failed to retrieve changes range: %v", err)
+	}
+	if first != 0 || latest != last-1 {
+		t.Fatalf("Unexpected changes range, want [0-%d], got [%d-%d]", last-1, first, latest)
+	}
+}
@@ -227,8 +227,18 @@ func (db *Database) loadLayers() layer {
 	if err != nil {
 		log.Crit("Failed to compute node hash", "err", err)
 	}
+	// Load the layers by resolving the checkpoint journal first. It's removed
+	// once the layers are journaled on shutdown, so if it's present the node
+	// was shut down uncleanly and it's newer than any layer journal.
+	head, err := db.loadCheckpoint(root)
+	if err == nil {
+		return head
+	}
+	if !errors.Is(err, errMissJournal) {
+		log.Info("Failed to load checkpoint journal, discard it", "err", err)
+	}
 	// Load the layers by resolving the journal
-	head, err := db.loadJournal(root)
+	head, err = db.loadJournal(root)
 	if err == nil {
 		return head
 	}
@@ -511,7 +521,7 @@ func (dl *diffLayer) journal(w io.Writer, journalType JournalType) error {
 // database as read-only to prevent all following mutation to disk.
 //
 // The supplied root must be a valid trie hash value.
-func (db *Database) Journal(root common.Hash) error {
+func (db *Database) Journal(root common.Hash) (err error) {
 	// Run the journaling
 	db.lock.Lock()
 	defer db.lock.Unlock()
@@ -538,7 +548,14 @@ func (db *Database) Journal(root common.Hash) error {
 	// Firstly write out the metadata of journal
 	db.DeleteTrieJournal(db.diskdb)
 	journal := newJournalWriter(db.config.JournalFilePath, db.diskdb, db.DetermineJournalTypeForWriter())
-	defer journal.Close()
+	defer func() {
+		journal.Close()
+
+		// Drop the checkpoint journal once the layers are all persisted
+		if err == nil {
+			db.removeCheckpoint()
+		}
+	}()
 
 	if err := rlp.Encode(journal, journalVersion); err != nil {
 		return err
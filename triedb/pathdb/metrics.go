@@ -50,6 +50,9 @@ var (
 	commitNodesMeter = metrics.NewRegisteredMeter("pathdb/commit/nodes", nil)
 	commitBytesMeter = metrics.NewRegisteredMeter("pathdb/commit/bytes", nil)
 
+	checkpointTimeTimer  = metrics.NewRegisteredTimer("pathdb/checkpoint/time", nil)
+	checkpointBytesMeter = metrics.NewRegisteredMeter("pathdb/checkpoint/bytes", nil)
+
 	gcTrieNodeMeter      = metrics.NewRegisteredMeter("pathdb/gc/node/count", nil)
 	gcTrieNodeBytesMeter = metrics.NewRegisteredMeter("pathdb/gc/node/bytes", nil)
 	gcAccountMeter       = metrics.NewRegisteredMeter("pathdb/gc/account/count", nil)